@@ -0,0 +1,73 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/security"
+)
+
+// runAudit 构建堆栈（不合成模板），输出所有安全组规则的审计报告
+func runAudit(args []string) int {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	configFile := fs.String("config", "config.json", "Configuration file to audit")
+	format := fs.String("format", "table", "Output format: table, json or csv")
+	output := fs.String("output", "", "Write the report to a file instead of stdout")
+	failOnFindings := fs.Bool("fail-on-findings", false, "Exit with status 2 when overly broad rules are found")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+	// 构建堆栈耗时较长，先检查输出格式
+	switch *format {
+	case "table", "json", "csv":
+	default:
+		fmt.Fprintf(os.Stderr, "Unsupported audit format '%s', expected table, json or csv\n", *format)
+		return 1
+	}
+
+	infraConfig, err := config.LoadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	// 构建过程中 Forge 会输出大量日志，json/csv 建议配合 --output 使用
+	if _, err := buildApp(infraConfig); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return writeAudit(security.GlobalRuleRegistry.Records(), *format, *output, *failOnFindings)
+}
+
+// writeAudit 生成审计报告并写入 output（为空时写入标准输出），返回命令退出码
+func writeAudit(records []security.RuleRecord, format, output string, failOnFindings bool) int {
+	report := security.BuildAuditReport(records)
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		f, err := os.Create(output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", output, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := security.WriteAuditReport(w, report, format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing audit report: %v\n", err)
+		return 1
+	}
+
+	if failOnFindings && len(report.Findings) > 0 {
+		return 2
+	}
+	return 0
+}
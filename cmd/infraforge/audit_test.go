@@ -0,0 +1,74 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awslabs/InfraForge/core/security"
+)
+
+func TestRunAuditArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unknown flag", []string{"--unknown"}},
+		{"unsupported format", []string{"--format", "yaml"}},
+		{"missing config", []string{"--config", filepath.Join(t.TempDir(), "missing.json")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := runAudit(tt.args); code != 1 {
+				t.Errorf("Expected exit code 1, got %d", code)
+			}
+		})
+	}
+}
+
+func TestWriteAudit(t *testing.T) {
+	records := []security.RuleRecord{
+		{SecurityGroup: "Stack/PublicSG", Direction: "ingress", SourceType: "cidr", Source: "0.0.0.0/0", Protocol: "tcp", FromPort: 22, ToPort: 22, Forge: "EC2:bastion"},
+		{SecurityGroup: "Stack/PublicSG", Direction: "ingress", SourceType: "cidr", Source: "0.0.0.0/0", Protocol: "tcp", FromPort: 443, ToPort: 443, Forge: "VPC"},
+	}
+	safe := records[1:]
+
+	tests := []struct {
+		name           string
+		records        []security.RuleRecord
+		failOnFindings bool
+		expected       int
+	}{
+		{"findings without fail flag", records, false, 0},
+		{"findings with fail flag", records, true, 2},
+		{"no findings with fail flag", safe, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := filepath.Join(t.TempDir(), "audit.json")
+			if code := writeAudit(tt.records, "json", output, tt.failOnFindings); code != tt.expected {
+				t.Errorf("Expected exit code %d, got %d", tt.expected, code)
+			}
+
+			data, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatalf("Expected report file: %v", err)
+			}
+			var report security.AuditReport
+			if err := json.Unmarshal(data, &report); err != nil {
+				t.Fatalf("Invalid JSON report: %v", err)
+			}
+			if len(report.SecurityGroups) != 1 || len(report.SecurityGroups[0].Rules) != len(tt.records) {
+				t.Errorf("Unexpected report: %+v", report)
+			}
+		})
+	}
+
+	if code := writeAudit(records, "json", filepath.Join(t.TempDir(), "missing", "audit.json"), false); code != 1 {
+		t.Errorf("Expected exit code 1 when the output file cannot be created, got %d", code)
+	}
+}
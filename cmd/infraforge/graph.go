@@ -0,0 +1,54 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/graph"
+)
+
+// runGraph 静态解析配置（不构建堆栈），输出 Forge 实例之间的依赖图
+func runGraph(args []string) int {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	configFile := fs.String("config", "config.json", "Configuration file to graph")
+	format := fs.String("format", "dot", "Output format: dot, mermaid or json")
+	output := fs.String("output", "", "Write the graph to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	infraConfig, err := config.LoadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	g, err := graph.Build(infraConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error building graph: %v\n", err)
+		return 1
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", *output, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := graph.Write(w, g, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing graph: %v\n", err)
+		return 1
+	}
+	return 0
+}
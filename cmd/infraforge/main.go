@@ -22,6 +22,27 @@ var (
 )
 
 func main() {
+	// 子命令：infraforge audit
+	if len(os.Args) > 1 && os.Args[1] == "audit" {
+		code := runAudit(os.Args[2:])
+		jsii.Close()
+		os.Exit(code)
+	}
+
+	// 子命令：infraforge graph
+	if len(os.Args) > 1 && os.Args[1] == "graph" {
+		code := runGraph(os.Args[2:])
+		jsii.Close()
+		os.Exit(code)
+	}
+
+	// 子命令：infraforge upgrade-plan
+	if len(os.Args) > 1 && os.Args[1] == "upgrade-plan" {
+		code := runUpgradePlan(os.Args[2:])
+		jsii.Close()
+		os.Exit(code)
+	}
+
 	defer jsii.Close()
 
 	// Parse command line flags
@@ -50,26 +71,44 @@ func main() {
 	}
 
 	// 创建 CDK 应用和堆栈
+	app, err := buildApp(infraConfig)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// 合成 CloudFormation 模板
+	app.Synth(nil)
+}
+
+// buildApp 创建 CDK 应用和堆栈，并按 enabledForges 顺序创建所有 Forge
+func buildApp(infraConfig *config.Config) (awscdk.App, error) {
 	app := awscdk.NewApp(nil)
 	stack := awscdk.NewStack(app, jsii.String(infraConfig.Global.StackName), &awscdk.StackProps{})
 
 	// 创建 ForgeManager
 	forgeManager := manager.NewForgeManager(stack, infraConfig.Global.DualStack)
+	if err := forgeManager.ConfigureExternalDependencies(infraConfig.Global.ExternalDependencies); err != nil {
+		return nil, fmt.Errorf("Error configuring external dependencies: %v", err)
+	}
+	if err := forgeManager.ConfigureBackup(infraConfig.Backup); err != nil {
+		return nil, fmt.Errorf("Error configuring backup: %v", err)
+	}
 
 	// 创建 VPC
 	if err := forgeManager.CreateVPC(infraConfig); err != nil {
-		fmt.Printf("Error creating VPC: %v\n", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("Error creating VPC: %v", err)
 	}
 
 	// 处理所有启用的 forges
 	for _, instanceId := range infraConfig.EnabledForges {
 		if err := forgeManager.CreateForge(instanceId, infraConfig); err != nil {
-			fmt.Printf("Error creating forge %s: %v\n", instanceId, err)
-			os.Exit(1)
+			return nil, fmt.Errorf("Error creating forge %s: %v", instanceId, err)
 		}
 	}
 
-	// 合成 CloudFormation 模板
-	app.Synth(nil)
+	// 创建备份计划
+	forgeManager.CreateBackupPlans()
+
+	return app, nil
 }
@@ -0,0 +1,135 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/manager"
+	"github.com/awslabs/InfraForge/forges/aws/eks"
+	"github.com/awslabs/InfraForge/registry"
+)
+
+// runUpgradePlan 先对配置执行升级预检，通过后构建并合成堆栈，输出 EKS 集群的分阶段升级计划、预检结果和已弃用 API 报告
+// 预检存在 ERROR 时只输出预检报告并返回 2
+func runUpgradePlan(args []string) int {
+	fs := flag.NewFlagSet("upgrade-plan", flag.ContinueOnError)
+	configFile := fs.String("config", "config.json", "Configuration file to check")
+	format := fs.String("format", "table", "Output format: table or json")
+	output := fs.String("output", "", "Write the report to a file instead of stdout")
+	failOnFindings := fs.Bool("fail-on-findings", false, "Exit with status 2 when a preflight check fails or a removed API is found")
+	if err := fs.Parse(args); err != nil {
+		return 1
+	}
+
+	infraConfig, err := config.LoadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		return 1
+	}
+
+	// 构建前先对配置执行预检，预检 ERROR 会使 buildApp 失败，此时只输出预检结果
+	plans, err := preflightUpgradePlans(infraConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	blocked := false
+	for _, plan := range plans {
+		if plan.HasErrors() {
+			blocked = true
+		}
+	}
+
+	if !blocked {
+		app, err := buildApp(infraConfig)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		// 已弃用 API 需要扫描合成后的模板
+		assembly := app.Synth(nil)
+		plans = eks.RegisteredUpgradePlans()
+		for _, stack := range *assembly.Stacks() {
+			template, ok := stack.Template().(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, plan := range plans {
+				plan.ScanTemplate(template)
+			}
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", *output, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := eks.WriteUpgradeReport(w, plans, *format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing upgrade report: %v\n", err)
+		return 1
+	}
+
+	if blocked {
+		fmt.Fprintln(os.Stderr, "Upgrade preflight failed, the stack was not synthesized and deprecated APIs were not scanned")
+		return 2
+	}
+	if *failOnFindings {
+		for _, plan := range plans {
+			if plan.HasErrors() {
+				return 2
+			}
+		}
+	}
+	return 0
+}
+
+// preflightUpgradePlans 合并已启用 EKS 实例的配置并生成升级计划，不构建堆栈
+func preflightUpgradePlans(infraConfig *config.Config) ([]*eks.UpgradePlan, error) {
+	forgeConfig, ok := infraConfig.Forges["eks"]
+	if !ok {
+		return nil, nil
+	}
+	constructor, ok := registry.ForgeConstructors["eks"]
+	if !ok {
+		return nil, fmt.Errorf("unknown forge type: eks")
+	}
+
+	enabled := make(map[string]bool)
+	for _, id := range infraConfig.EnabledForges {
+		enabled[id] = true
+	}
+
+	var plans []*eks.UpgradePlan
+	for _, rawInst := range forgeConfig.Instances {
+		inst := registry.CreateInstance("eks")
+		if err := json.Unmarshal(rawInst, inst); err != nil {
+			return nil, fmt.Errorf("error parsing eks instance: %v", err)
+		}
+		if !enabled[inst.GetID()] {
+			continue
+		}
+		merged, err := manager.MergeInstanceConfig(constructor(), "eks", forgeConfig.Defaults, inst)
+		if err != nil {
+			return nil, fmt.Errorf("error merging %s: %v", inst.GetID(), err)
+		}
+		if eksInstance, ok := merged.(*eks.EksInstanceConfig); ok {
+			plans = append(plans, eksInstance.BuildUpgradePlan())
+		}
+	}
+	return plans, nil
+}
@@ -0,0 +1,50 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awslabs/InfraForge/forges/aws/eks"
+)
+
+func TestRunUpgradePlanPreflightErrors(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.json")
+	reportFile := filepath.Join(dir, "report.json")
+	content := `{
+		"global": {"stackName": "upgrade-test"},
+		"enabledForges": ["eks1"],
+		"forges": {"eks": {"defaults": {}, "instances": [
+			{"id": "eks1", "type": "EKS", "eksVersion": "1.33", "upgrade": {"fromVersion": "1.31", "stage": "controlPlane"}},
+			{"id": "eks2", "type": "EKS", "eksVersion": "1.40"}
+		]}}
+	}`
+	if err := os.WriteFile(configFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 预检 ERROR 时不构建堆栈，输出报告并返回 2
+	if code := runUpgradePlan([]string{"--config", configFile, "--format", "json", "--output", reportFile}); code != 2 {
+		t.Fatalf("Expected exit code 2, got %d", code)
+	}
+
+	report, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var plans []eks.UpgradePlan
+	if err := json.Unmarshal(report, &plans); err != nil {
+		t.Fatalf("Expected a JSON report, got %v", err)
+	}
+	if len(plans) != 1 || plans[0].Cluster != "eks1" {
+		t.Fatalf("Expected a plan for the enabled cluster only, got %+v", plans)
+	}
+	if !plans[0].HasErrors() {
+		t.Errorf("Expected the report to include the preflight ERROR, got %+v", plans[0].Findings)
+	}
+}
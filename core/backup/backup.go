@@ -0,0 +1,199 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/utils/types"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsbackup"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsevents"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsiam"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awskms"
+	"github.com/aws/jsii-runtime-go"
+)
+
+// Protectable 由可被 AWS Backup 保护的 Forge 实现，返回需要备份的资源 ARN
+type Protectable interface {
+	BackupResourceArns() []*string
+}
+
+// protectedResource 加入备份计划的资源，key 为 "TYPE:id"
+type protectedResource struct {
+	key  string
+	arns []*string
+}
+
+// Manager 收集各 Forge 引用的备份计划，在所有 Forge 创建完成后生成保管库、计划和资源选择
+type Manager struct {
+	stack     awscdk.Stack
+	config    *config.BackupConfig
+	protected map[string][]protectedResource
+	vaults    map[string]awsbackup.IBackupVault
+}
+
+// NewManager 校验备份配置并创建备份管理器，config 为 nil 时任何 backupPlan 引用都会报错
+func NewManager(stack awscdk.Stack, backupConfig *config.BackupConfig) (*Manager, error) {
+	if backupConfig != nil {
+		if err := backupConfig.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &Manager{
+		stack:     stack,
+		config:    backupConfig,
+		protected: make(map[string][]protectedResource),
+		vaults:    make(map[string]awsbackup.IBackupVault),
+	}, nil
+}
+
+// CheckPlan 确认备份计划已在顶层 backup.plans 中定义
+func (m *Manager) CheckPlan(name string) error {
+	if _, ok := m.config.Plan(name); !ok {
+		return fmt.Errorf("backupPlan '%s' is not defined in backup.plans", name)
+	}
+	return nil
+}
+
+// Protect 将 Forge 创建的资源加入备份计划
+func (m *Manager) Protect(planName, key string, forge interface{}) error {
+	if err := m.CheckPlan(planName); err != nil {
+		return err
+	}
+	protectable, ok := forge.(Protectable)
+	if !ok {
+		return fmt.Errorf("%s does not support backupPlan", strings.SplitN(key, ":", 2)[0])
+	}
+	arns := protectable.BackupResourceArns()
+	if len(arns) == 0 {
+		return fmt.Errorf("%s has no resources to back up", key)
+	}
+	m.protected[planName] = append(m.protected[planName], protectedResource{key: key, arns: arns})
+	return nil
+}
+
+// CreatePlans 为引用了资源的备份计划创建保管库、计划、资源选择和输出
+func (m *Manager) CreatePlans() {
+	if m.config == nil {
+		return
+	}
+	for i := range m.config.Plans {
+		plan := &m.config.Plans[i]
+		resources := m.protected[plan.Name]
+		if len(resources) == 0 {
+			fmt.Printf("Warning: backup plan '%s' is not referenced by any enabled instance\n", plan.Name)
+			continue
+		}
+		m.createPlan(plan, resources)
+	}
+}
+
+func (m *Manager) createPlan(plan *config.BackupPlanConfig, resources []protectedResource) {
+	// 名称编码后再以 "-" 拼接后缀，避免 "daily-plan" 与 "dailyplan"、"daily" 与 "dailyId" 冲突
+	id := types.EncodeConstructId(plan.Name)
+	vault := m.getOrCreateVault(plan)
+
+	schedule, _ := config.BackupScheduleExpression(plan.Schedule)
+	ruleProps := &awsbackup.BackupPlanRuleProps{
+		RuleName:           jsii.String(plan.Name),
+		BackupVault:        vault,
+		ScheduleExpression: awsevents.Schedule_Expression(jsii.String(schedule)),
+		DeleteAfter:        awscdk.Duration_Days(jsii.Number(float64(plan.GetRetentionDays()))),
+	}
+	if plan.MoveToColdStorageAfterDays > 0 {
+		ruleProps.MoveToColdStorageAfter = awscdk.Duration_Days(jsii.Number(float64(plan.MoveToColdStorageAfterDays)))
+	}
+	if plan.CopyToVaultArn != "" {
+		copyRetentionDays := plan.CopyRetentionDays
+		if copyRetentionDays == 0 {
+			copyRetentionDays = plan.GetRetentionDays()
+		}
+		ruleProps.CopyActions = &[]*awsbackup.BackupPlanCopyActionProps{
+			{
+				DestinationBackupVault: awsbackup.BackupVault_FromBackupVaultArn(m.stack, jsii.String("BackupCopyVault-"+id), jsii.String(plan.CopyToVaultArn)),
+				DeleteAfter:            awscdk.Duration_Days(jsii.Number(float64(copyRetentionDays))),
+			},
+		}
+	}
+
+	backupPlan := awsbackup.NewBackupPlan(m.stack, jsii.String("BackupPlan-"+id), &awsbackup.BackupPlanProps{
+		BackupPlanName:  jsii.String(fmt.Sprintf("%s-%s", *m.stack.StackName(), plan.Name)),
+		BackupVault:     vault,
+		BackupPlanRules: &[]awsbackup.BackupPlanRule{awsbackup.NewBackupPlanRule(ruleProps)},
+	})
+
+	// 使用独立角色，S3 备份需要额外的托管策略
+	role := awsiam.NewRole(m.stack, jsii.String("BackupRole-"+id), &awsiam.RoleProps{
+		AssumedBy: awsiam.NewServicePrincipal(jsii.String("backup.amazonaws.com"), nil),
+		ManagedPolicies: &[]awsiam.IManagedPolicy{
+			awsiam.ManagedPolicy_FromAwsManagedPolicyName(jsii.String("service-role/AWSBackupServiceRolePolicyForBackup")),
+			awsiam.ManagedPolicy_FromAwsManagedPolicyName(jsii.String("service-role/AWSBackupServiceRolePolicyForRestores")),
+		},
+	})
+
+	var backupResources []awsbackup.BackupResource
+	var arns []*string
+	includesS3 := false
+	for _, resource := range resources {
+		for _, arn := range resource.arns {
+			backupResources = append(backupResources, awsbackup.BackupResource_FromArn(arn))
+			arns = append(arns, arn)
+		}
+		if strings.HasPrefix(resource.key, "S3:") {
+			includesS3 = true
+		}
+	}
+	if includesS3 {
+		role.AddManagedPolicy(awsiam.ManagedPolicy_FromAwsManagedPolicyName(jsii.String("AWSBackupServiceRolePolicyForS3Backup")))
+		role.AddManagedPolicy(awsiam.ManagedPolicy_FromAwsManagedPolicyName(jsii.String("AWSBackupServiceRolePolicyForS3Restore")))
+	}
+
+	backupPlan.AddSelection(jsii.String("Selection"), &awsbackup.BackupSelectionOptions{
+		BackupSelectionName:        jsii.String(plan.Name),
+		Resources:                  &backupResources,
+		Role:                       role,
+		DisableDefaultBackupPolicy: jsii.Bool(true),
+	})
+
+	awscdk.NewCfnOutput(m.stack, jsii.String("BackupPlan-"+id+"-Id"), &awscdk.CfnOutputProps{
+		Value:       backupPlan.BackupPlanId(),
+		Description: jsii.String(fmt.Sprintf("Backup plan %s ID", plan.Name)),
+	})
+	awscdk.NewCfnOutput(m.stack, jsii.String("BackupPlan-"+id+"-ProtectedResources"), &awscdk.CfnOutputProps{
+		Value:       awscdk.Fn_Join(jsii.String(","), &arns),
+		Description: jsii.String(fmt.Sprintf("Resources protected by backup plan %s", plan.Name)),
+	})
+}
+
+// getOrCreateVault 创建或复用同名保管库，默认名称为 <stackName>-<plan>
+func (m *Manager) getOrCreateVault(plan *config.BackupPlanConfig) awsbackup.IBackupVault {
+	vaultName := plan.Vault
+	if vaultName == "" {
+		vaultName = fmt.Sprintf("%s-%s", *m.stack.StackName(), plan.Name)
+	}
+	if vault, ok := m.vaults[vaultName]; ok {
+		return vault
+	}
+
+	props := &awsbackup.BackupVaultProps{
+		BackupVaultName: jsii.String(vaultName),
+		RemovalPolicy:   awscdk.RemovalPolicy_RETAIN,
+	}
+	id := types.EncodeConstructId(vaultName)
+	if plan.VaultKmsKeyArn != "" {
+		props.EncryptionKey = awskms.Key_FromKeyArn(m.stack, jsii.String("BackupVaultKey-"+id), jsii.String(plan.VaultKmsKeyArn))
+	}
+	vault := awsbackup.NewBackupVault(m.stack, jsii.String("BackupVault-"+id), props)
+	m.vaults[vaultName] = vault
+
+	awscdk.NewCfnOutput(m.stack, jsii.String("BackupVault-"+id+"-Name"), &awscdk.CfnOutputProps{
+		Value:       vault.BackupVaultName(),
+		Description: jsii.String("Backup vault name"),
+	})
+	return vault
+}
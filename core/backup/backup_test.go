@@ -0,0 +1,140 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package backup
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/assertions"
+	"github.com/aws/jsii-runtime-go"
+	"github.com/awslabs/InfraForge/core/config"
+)
+
+type testProtectable struct {
+	arns []*string
+}
+
+func (p *testProtectable) BackupResourceArns() []*string {
+	return p.arns
+}
+
+func newTestManager(t *testing.T, backupConfig *config.BackupConfig) (*Manager, awscdk.Stack) {
+	t.Helper()
+	stack := awscdk.NewStack(awscdk.NewApp(nil), jsii.String("BackupTest"), nil)
+	manager, err := NewManager(stack, backupConfig)
+	if err != nil {
+		t.Fatalf("Expected backup config to be valid, got %v", err)
+	}
+	return manager, stack
+}
+
+func TestNewManagerInvalidConfig(t *testing.T) {
+	stack := awscdk.NewStack(awscdk.NewApp(nil), jsii.String("BackupTest"), nil)
+	if _, err := NewManager(stack, &config.BackupConfig{Plans: []config.BackupPlanConfig{{Name: "daily"}, {Name: "daily"}}}); err == nil {
+		t.Errorf("Expected duplicate plan names to be rejected")
+	}
+}
+
+func TestProtect(t *testing.T) {
+	manager, _ := newTestManager(t, &config.BackupConfig{Plans: []config.BackupPlanConfig{{Name: "daily"}}})
+
+	tests := []struct {
+		name    string
+		plan    string
+		forge   interface{}
+		wantErr bool
+	}{
+		{"protectable", "daily", &testProtectable{arns: []*string{jsii.String("arn:aws:elasticfilesystem:us-east-1:123456789012:file-system/fs-123")}}, false},
+		{"undefined plan", "weekly", &testProtectable{arns: []*string{jsii.String("arn")}}, true},
+		{"not protectable", "daily", &struct{}{}, true},
+		{"no resources", "daily", &testProtectable{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := manager.Protect(tt.plan, "EFS:efs1", tt.forge)
+			if tt.wantErr && err == nil {
+				t.Errorf("Expected an error for %s", tt.name)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected %s to be protected, got %v", tt.name, err)
+			}
+		})
+	}
+
+	unconfigured, _ := newTestManager(t, nil)
+	if err := unconfigured.CheckPlan("daily"); err == nil {
+		t.Errorf("Expected CheckPlan to fail without a backup section")
+	}
+}
+
+func TestCreatePlans(t *testing.T) {
+	manager, stack := newTestManager(t, &config.BackupConfig{Plans: []config.BackupPlanConfig{
+		{Name: "daily-plan"},
+		{Name: "dailyplan", Vault: "shared"},
+		{Name: "daily", Vault: "shared"},
+		{Name: "dailyId", Schedule: "weekly", CopyToVaultArn: "arn:aws:backup:us-west-2:123456789012:backup-vault:dr"},
+		{Name: "unused"},
+	}})
+	efs := &testProtectable{arns: []*string{jsii.String("arn:aws:elasticfilesystem:us-east-1:123456789012:file-system/fs-123")}}
+	bucket := &testProtectable{arns: []*string{jsii.String("arn:aws:s3:::datasets")}}
+	for _, plan := range []string{"daily-plan", "dailyplan", "daily", "dailyId"} {
+		if err := manager.Protect(plan, "EFS:efs1", efs); err != nil {
+			t.Fatalf("Expected EFS:efs1 to be protected by %s, got %v", plan, err)
+		}
+	}
+	if err := manager.Protect("daily", "S3:datasets", bucket); err != nil {
+		t.Fatalf("Expected S3:datasets to be protected, got %v", err)
+	}
+	manager.CreatePlans()
+
+	template := assertions.Template_FromStack(stack, nil)
+	template.ResourceCountIs(jsii.String("AWS::Backup::BackupPlan"), jsii.Number(4))
+	template.ResourceCountIs(jsii.String("AWS::Backup::BackupSelection"), jsii.Number(4))
+	// daily-plan、dailyId 使用默认保管库，dailyplan 与 daily 共享 "shared"
+	template.ResourceCountIs(jsii.String("AWS::Backup::BackupVault"), jsii.Number(3))
+	template.HasResourceProperties(jsii.String("AWS::Backup::BackupVault"), map[string]interface{}{
+		"BackupVaultName": "BackupTest-daily-plan",
+	})
+	template.HasResourceProperties(jsii.String("AWS::Backup::BackupPlan"), map[string]interface{}{
+		"BackupPlan": map[string]interface{}{
+			"BackupPlanName": "BackupTest-dailyId",
+			"BackupPlanRule": assertions.Match_ArrayWith(&[]interface{}{
+				assertions.Match_ObjectLike(&map[string]interface{}{
+					"CopyActions": assertions.Match_ArrayWith(&[]interface{}{
+						assertions.Match_ObjectLike(&map[string]interface{}{
+							"DestinationBackupVaultArn": "arn:aws:backup:us-west-2:123456789012:backup-vault:dr",
+						}),
+					}),
+				}),
+			}),
+		},
+	})
+
+	// 只有包含 S3 资源的计划角色附加 S3 备份策略
+	roles := template.FindResources(jsii.String("AWS::IAM::Role"), nil)
+	s3Roles := 0
+	for _, role := range *roles {
+		content, _ := json.Marshal(role)
+		if strings.Contains(string(content), "AWSBackupServiceRolePolicyForS3Backup") {
+			s3Roles++
+		}
+	}
+	if s3Roles != 1 {
+		t.Errorf("Expected 1 backup role with the S3 backup policy, got %d", s3Roles)
+	}
+
+	outputs := template.FindOutputs(jsii.String("*"), nil)
+	if len(*outputs) != 4*2+3 {
+		t.Errorf("Expected 11 outputs for 4 plans and 3 vaults, got %d", len(*outputs))
+	}
+	for logicalId := range *outputs {
+		if strings.Contains(logicalId, "unused") {
+			t.Errorf("Expected no output for the unreferenced plan, got %s", logicalId)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BackupConfig AWS Backup 配置
+type BackupConfig struct {
+	Plans []BackupPlanConfig `json:"plans"`
+}
+
+// BackupPlanConfig 备份计划：调度、保留期、保管库和跨区域复制
+type BackupPlanConfig struct {
+	Name                       string `json:"name"`
+	Schedule                   string `json:"schedule,omitempty"`                   // daily（默认）、weekly、monthly 或 cron(...) 表达式
+	RetentionDays              int    `json:"retentionDays,omitempty"`              // 恢复点保留天数，默认 35
+	MoveToColdStorageAfterDays int    `json:"moveToColdStorageAfterDays,omitempty"` // 转入冷存储的天数，保留期需至少再多 90 天
+	Vault                      string `json:"vault,omitempty"`                      // 保管库名称，默认 <stackName>-<name>，同名计划共享保管库
+	VaultKmsKeyArn             string `json:"vaultKmsKeyArn,omitempty"`             // 保管库加密密钥，默认使用 AWS 托管密钥
+	CopyToVaultArn             string `json:"copyToVaultArn,omitempty"`             // 跨区域/跨账户复制的目标保管库 ARN
+	CopyRetentionDays          int    `json:"copyRetentionDays,omitempty"`          // 副本保留天数，默认与 retentionDays 相同
+}
+
+// BackupTarget 由支持 AWS Backup 的实例配置实现，返回引用的备份计划名称
+type BackupTarget interface {
+	GetBackupPlan() string
+}
+
+var backupNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,50}$`)
+
+// Validate 校验备份计划名称、调度、保留期和复制目标
+func (c *BackupConfig) Validate() error {
+	names := make(map[string]bool)
+	for i, plan := range c.Plans {
+		if !backupNamePattern.MatchString(plan.Name) {
+			return fmt.Errorf("backup.plans[%d]: name '%s' must be 1-50 letters, digits, '_', '.' or '-'", i, plan.Name)
+		}
+		if names[plan.Name] {
+			return fmt.Errorf("backup.plans[%d]: duplicate plan name '%s'", i, plan.Name)
+		}
+		names[plan.Name] = true
+
+		if _, err := BackupScheduleExpression(plan.Schedule); err != nil {
+			return fmt.Errorf("backup plan %s: %v", plan.Name, err)
+		}
+		if plan.RetentionDays < 0 || plan.MoveToColdStorageAfterDays < 0 || plan.CopyRetentionDays < 0 {
+			return fmt.Errorf("backup plan %s: retentionDays, moveToColdStorageAfterDays and copyRetentionDays must not be negative", plan.Name)
+		}
+		if plan.MoveToColdStorageAfterDays > 0 && plan.GetRetentionDays() < plan.MoveToColdStorageAfterDays+90 {
+			return fmt.Errorf("backup plan %s: retentionDays must be at least moveToColdStorageAfterDays + 90 (%d)", plan.Name, plan.MoveToColdStorageAfterDays+90)
+		}
+		if plan.Vault != "" && !backupNamePattern.MatchString(plan.Vault) {
+			return fmt.Errorf("backup plan %s: vault '%s' must be 1-50 letters, digits, '_', '.' or '-'", plan.Name, plan.Vault)
+		}
+		if plan.CopyToVaultArn != "" && (!strings.HasPrefix(plan.CopyToVaultArn, "arn:") || !strings.Contains(plan.CopyToVaultArn, ":backup-vault:")) {
+			return fmt.Errorf("backup plan %s: copyToVaultArn '%s' must be a backup vault ARN", plan.Name, plan.CopyToVaultArn)
+		}
+		if plan.CopyRetentionDays > 0 && plan.CopyToVaultArn == "" {
+			return fmt.Errorf("backup plan %s: copyRetentionDays requires copyToVaultArn", plan.Name)
+		}
+	}
+	return nil
+}
+
+// Plan 按名称查找备份计划
+func (c *BackupConfig) Plan(name string) (*BackupPlanConfig, bool) {
+	if c == nil {
+		return nil, false
+	}
+	for i := range c.Plans {
+		if c.Plans[i].Name == name {
+			return &c.Plans[i], true
+		}
+	}
+	return nil, false
+}
+
+// GetRetentionDays 返回恢复点保留天数，默认 35
+func (p *BackupPlanConfig) GetRetentionDays() int {
+	if p.RetentionDays > 0 {
+		return p.RetentionDays
+	}
+	return 35
+}
+
+// BackupScheduleExpression 将 daily、weekly、monthly 转换为 cron 表达式，cron(...) 原样返回
+// AWS Backup 计划只支持 cron 表达式，rate(...) 会被拒绝
+func BackupScheduleExpression(schedule string) (string, error) {
+	switch strings.ToLower(schedule) {
+	case "", "daily":
+		return "cron(0 5 * * ? *)", nil
+	case "weekly":
+		return "cron(0 5 ? * SAT *)", nil
+	case "monthly":
+		return "cron(0 5 1 * ? *)", nil
+	}
+	if strings.HasPrefix(schedule, "rate(") {
+		return "", fmt.Errorf("invalid schedule '%s', AWS Backup plans do not support rate(...), use a cron(...) expression", schedule)
+	}
+	if strings.HasPrefix(schedule, "cron(") && strings.HasSuffix(schedule, ")") {
+		return schedule, nil
+	}
+	return "", fmt.Errorf("invalid schedule '%s', expected daily, weekly, monthly or a cron(...) expression", schedule)
+}
@@ -5,18 +5,50 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 type Config struct {
 	Global        GlobalConfig   `json:"global"`
 	EnabledForges []string       `json:"enabledForges"`
 	Forges        map[string]ForgeConfig `json:"forges"`
+	Backup        *BackupConfig  `json:"backup,omitempty"` // AWS Backup 计划，存储和数据库实例通过 backupPlan 引用
 }
 
 type GlobalConfig struct {
 	StackName	string `json:"stackName"`
 	Description	string `json:"description"`
 	DualStack	bool   `json:"dualStack"`
+	ExternalDependencies	*ExternalDependenciesConfig `json:"externalDependencies,omitempty"`
+}
+
+// ExternalDependenciesConfig 跨堆栈依赖配置
+// dependsOn 中的 "TYPE:ext/<id>" 或 "TYPE:ext/<stack>/<id>" 引用其他 InfraForge 堆栈创建的资源
+type ExternalDependenciesConfig struct {
+	Source    string   `json:"source,omitempty"`    // ssm（默认）、export 或 file
+	StackName string   `json:"stackName,omitempty"` // "ext/<id>" 未指定堆栈时使用的生产者堆栈
+	File      string   `json:"file,omitempty"`      // source 为 file 时读取的 JSON 文件，格式与 magic token 相同
+	SsmPrefix string   `json:"ssmPrefix,omitempty"` // SSM 参数前缀，默认 /infraforge
+	Publish   []string `json:"publish,omitempty"`   // 将本堆栈所有 Forge 的属性发布到 ssm 和/或 export
+}
+
+// Validate 校验外部依赖来源和发布目标
+func (c *ExternalDependenciesConfig) Validate() error {
+	switch c.Source {
+	case "", "ssm", "export":
+	case "file":
+		if c.File == "" {
+			return fmt.Errorf("externalDependencies.file is required when source is file")
+		}
+	default:
+		return fmt.Errorf("invalid externalDependencies.source '%s', must be ssm, export or file", c.Source)
+	}
+	for _, target := range c.Publish {
+		if target != "ssm" && target != "export" {
+			return fmt.Errorf("invalid externalDependencies.publish target '%s', must be ssm or export", target)
+		}
+	}
+	return nil
 }
 
 type ForgeConfig struct {
@@ -29,13 +61,35 @@ type InstanceConfig interface {
 	GetType() string
 	GetSubnet() string
 	GetSecurityGroup() string
+	GetIngress() []IngressRule
+	GetDedicatedSecurityGroup() bool
+}
+
+// Validator 由需要在合成前校验配置的实例实现，ForgeManager 在合并配置后调用
+type Validator interface {
+	Validate() error
+}
+
+// IngressRule 声明式入站规则
+// source 支持以下几种形式：
+//   - IPv4/IPv6 CIDR，如 "10.0.0.0/8"、"::/0"
+//   - 托管前缀列表 ID，如 "pl-0123456789abcdef0"
+//   - 安全组层级，如 "public"、"private"、"isolated"
+//   - 其他 Forge 实例，如 "EC2:bastion"
+type IngressRule struct {
+	Protocol    string `json:"protocol,omitempty"`    // tcp, udp, both, icmp, all，默认为 tcp
+	Ports       string `json:"ports,omitempty"`       // 格式: "22"、"80,443"、"8000-8999"，protocol 为 icmp 或 all 时可为空
+	Source      string `json:"source"`
+	Description string `json:"description,omitempty"`
 }
 
 type BaseInstanceConfig struct {
-	ID            string `json:"id"`
-	Type          string `json:"type"`
-	Subnet        string `json:"subnet"`
-	SecurityGroup string `json:"security"`
+	ID                     string        `json:"id"`
+	Type                   string        `json:"type"`
+	Subnet                 string        `json:"subnet"`
+	SecurityGroup          string        `json:"security"`
+	Ingress                []IngressRule `json:"ingress,omitempty"`                // 声明式入站规则
+	DedicatedSecurityGroup *bool         `json:"dedicatedSecurityGroup,omitempty"` // 是否为实例创建独立安全组，而不是共享层级安全组
 }
 
 func (c *BaseInstanceConfig) GetID() string {
@@ -58,3 +112,35 @@ func (c *BaseInstanceConfig) GetSubnet() string {
 func (c *BaseInstanceConfig) GetSecurityGroup() string {
 	return c.SecurityGroup
 }
+
+func (c *BaseInstanceConfig) GetIngress() []IngressRule {
+	return c.Ingress
+}
+
+func (c *BaseInstanceConfig) GetDedicatedSecurityGroup() bool {
+	return c.DedicatedSecurityGroup != nil && *c.DedicatedSecurityGroup
+}
+
+func (c *BaseInstanceConfig) base() *BaseInstanceConfig {
+	return c
+}
+
+// MergeSecurityFields 将实例配置中的 ingress 和 dedicatedSecurityGroup 覆盖到合并结果
+// 各 Forge 的 MergeConfigs 只合并自身关心的字段，这里统一处理安全相关的基础字段
+func MergeSecurityFields(merged, instance InstanceConfig) {
+	dst, ok := merged.(interface{ base() *BaseInstanceConfig })
+	if !ok {
+		return
+	}
+	src, ok := instance.(interface{ base() *BaseInstanceConfig })
+	if !ok {
+		return
+	}
+
+	if len(src.base().Ingress) > 0 {
+		dst.base().Ingress = src.base().Ingress
+	}
+	if src.base().DedicatedSecurityGroup != nil {
+		dst.base().DedicatedSecurityGroup = src.base().DedicatedSecurityGroup
+	}
+}
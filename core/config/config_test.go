@@ -153,3 +153,86 @@ func TestWriteConfigFile(t *testing.T) {
 		t.Errorf("Expected EnabledForges to be %v, got %v", expectedEnabledForges, loadedConfig.EnabledForges)
 	}
 }
+
+func TestMergeSecurityFields(t *testing.T) {
+	dedicated := true
+	merged := &BaseInstanceConfig{
+		ID:      "bastion",
+		Ingress: []IngressRule{{Ports: "22", Source: "10.0.0.0/8"}},
+	}
+	instance := &BaseInstanceConfig{
+		ID:                     "bastion",
+		Ingress:                []IngressRule{{Protocol: "tcp", Ports: "443", Source: "EC2:web"}},
+		DedicatedSecurityGroup: &dedicated,
+	}
+
+	MergeSecurityFields(merged, instance)
+
+	if len(merged.GetIngress()) != 1 || merged.GetIngress()[0].Source != "EC2:web" {
+		t.Errorf("Expected instance ingress to override defaults, got %+v", merged.GetIngress())
+	}
+	if !merged.GetDedicatedSecurityGroup() {
+		t.Errorf("Expected dedicatedSecurityGroup to be true")
+	}
+
+	// 实例未配置时保留默认值
+	MergeSecurityFields(merged, &BaseInstanceConfig{})
+	if len(merged.GetIngress()) != 1 || !merged.GetDedicatedSecurityGroup() {
+		t.Errorf("Expected defaults to be kept when instance has no security fields")
+	}
+}
+
+func TestIngressRuleUnmarshal(t *testing.T) {
+	data := `{"id": "web", "ingress": [{"protocol": "both", "ports": "53", "source": "private"}], "dedicatedSecurityGroup": true}`
+
+	var cfg BaseInstanceConfig
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+
+	expected := []IngressRule{{Protocol: "both", Ports: "53", Source: "private"}}
+	if !reflect.DeepEqual(cfg.Ingress, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, cfg.Ingress)
+	}
+	if !cfg.GetDedicatedSecurityGroup() {
+		t.Errorf("Expected dedicatedSecurityGroup to be true")
+	}
+}
+
+func TestBackupConfigValidate(t *testing.T) {
+	valid := &BackupConfig{Plans: []BackupPlanConfig{
+		{Name: "daily"},
+		{Name: "archive", Schedule: "cron(0 3 ? * SUN *)", RetentionDays: 365, MoveToColdStorageAfterDays: 30, CopyToVaultArn: "arn:aws:backup:us-west-2:123456789012:backup-vault:dr"},
+	}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected valid backup config, got %v", err)
+	}
+	if plan, ok := valid.Plan("daily"); !ok || plan.GetRetentionDays() != 35 {
+		t.Errorf("Expected plan 'daily' with default retention 35, got %+v", plan)
+	}
+
+	tests := []struct {
+		name string
+		plan BackupPlanConfig
+	}{
+		{"invalid name", BackupPlanConfig{Name: "daily plan"}},
+		{"invalid schedule", BackupPlanConfig{Name: "p", Schedule: "hourly"}},
+		{"rate schedule", BackupPlanConfig{Name: "p", Schedule: "rate(12 hours)"}},
+		{"cold storage too close to expiry", BackupPlanConfig{Name: "p", RetentionDays: 100, MoveToColdStorageAfterDays: 30}},
+		{"invalid copy vault", BackupPlanConfig{Name: "p", CopyToVaultArn: "dr-vault"}},
+		{"copy retention without vault", BackupPlanConfig{Name: "p", CopyRetentionDays: 7}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &BackupConfig{Plans: []BackupPlanConfig{tt.plan}}
+			if err := config.Validate(); err == nil {
+				t.Errorf("Expected error for %+v", tt.plan)
+			}
+		})
+	}
+
+	duplicate := &BackupConfig{Plans: []BackupPlanConfig{{Name: "p"}, {Name: "p"}}}
+	if err := duplicate.Validate(); err == nil {
+		t.Error("Expected error for duplicate plan names")
+	}
+}
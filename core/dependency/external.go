@@ -0,0 +1,340 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependency
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsssm"
+	"github.com/aws/constructs-go/constructs/v10"
+	"github.com/aws/jsii-runtime-go"
+	"github.com/awslabs/InfraForge/core/utils/types"
+)
+
+// ExternalPrefix 外部依赖的 ID 前缀，如 "EFS:ext/shared-efs" 或 "EFS:ext/producer-stack/shared-efs"
+const ExternalPrefix = "ext/"
+
+// 外部依赖来源
+const (
+	ExternalSourceSsm    = "ssm"
+	ExternalSourceExport = "export"
+	ExternalSourceFile   = "file"
+)
+
+// DefaultSsmPrefix 发布和读取依赖属性的 SSM 参数前缀
+const DefaultSsmPrefix = "/infraforge"
+
+// ExternalProperties 各类型通过 SSM 参数或 CloudFormation 导出跨堆栈共享的属性
+// 只包含所有实例都会生成的字符串属性，可选属性（如 RDS readEndpoint）和列表属性需使用 file 来源
+var ExternalProperties = map[string][]string{
+	"EFS":     {"fileSystemId", "fileSystemArn", "mountPoint"},
+	"LUSTRE":  {"fileSystemId", "dnsName", "mountName", "mountPoint"},
+	"OPENZFS": {"fileSystemId", "dnsName", "mountPoint", "volumeId", "exportPath", "protocol", "mountOptions"},
+	"ONTAP":   {"fileSystemId", "dnsName", "mountPoint", "svmId", "volumeId", "exportPath", "protocol", "mountOptions", "secretARN"},
+	"S3":      {"bucketName", "bucketArn", "region", "s3Uri", "mountPoint"},
+	"RDS":     {"username", "engine", "port", "secretArn", "endpoint"},
+	"DS":      {"attrId", "domainName", "shortName", "name", "secretARN"},
+	"EKS":     {"clusterName", "clusterArn", "clusterEndpoint", "eksVersion"},
+	"ECS":     {"clusterName", "clusterArn"},
+}
+
+// ExternalProvider 解析其他堆栈创建的依赖属性
+type ExternalProvider interface {
+	Properties(resourceType, stackName, id string) (map[string]interface{}, error)
+}
+
+// IsExternal 判断依赖 ID 是否为外部依赖
+func IsExternal(id string) bool {
+	return strings.HasPrefix(id, ExternalPrefix)
+}
+
+// ParseExternalID 解析外部依赖 ID，"ext/<id>" 使用默认堆栈，"ext/<stack>/<id>" 指定生产者堆栈
+func ParseExternalID(id string, defaultStack string) (string, string, error) {
+	parts := strings.Split(strings.TrimPrefix(id, ExternalPrefix), "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		if defaultStack == "" {
+			return "", "", fmt.Errorf("%w: external dependency '%s' needs a stack name, use ext/<stack>/<id> or set global.externalDependencies.stackName", ErrInvalidFormat, id)
+		}
+		return defaultStack, parts[0], nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return parts[0], parts[1], nil
+	}
+	return "", "", fmt.Errorf("%w: invalid external dependency '%s'", ErrInvalidFormat, id)
+}
+
+// SsmParameterName 返回依赖属性的 SSM 参数名，如 /infraforge/<stack>/EFS/<id>/fileSystemId
+func SsmParameterName(prefix, stackName, resourceType, id, key string) string {
+	if prefix == "" {
+		prefix = DefaultSsmPrefix
+	}
+	return fmt.Sprintf("%s/%s/%s/%s/%s", strings.TrimSuffix(prefix, "/"), stackName, resourceType, id, key)
+}
+
+var exportNamePattern = regexp.MustCompile(`[^A-Za-z0-9:-]`)
+
+// ExportName 返回依赖属性的 CloudFormation 导出名称，如 <stack>-EFS-<id>-fileSystemId
+func ExportName(stackName, resourceType, id, key string) string {
+	return exportNamePattern.ReplaceAllString(fmt.Sprintf("%s-%s-%s-%s", stackName, resourceType, id, key), "-")
+}
+
+// SsmProvider 在部署时通过 SSM 参数读取依赖属性
+type SsmProvider struct {
+	Scope  constructs.Construct
+	Prefix string
+}
+
+func (p *SsmProvider) Properties(resourceType, stackName, id string) (map[string]interface{}, error) {
+	keys, ok := ExternalProperties[resourceType]
+	if !ok {
+		return nil, fmt.Errorf("external %s dependencies are not supported by the ssm source", resourceType)
+	}
+	properties := make(map[string]interface{})
+	for _, key := range keys {
+		name := SsmParameterName(p.Prefix, stackName, resourceType, id, key)
+		properties[key] = *awsssm.StringParameter_ValueForStringParameter(p.Scope, jsii.String(name), nil)
+	}
+	return properties, nil
+}
+
+// ExportProvider 在部署时通过 Fn::ImportValue 读取依赖属性
+type ExportProvider struct{}
+
+func (p *ExportProvider) Properties(resourceType, stackName, id string) (map[string]interface{}, error) {
+	keys, ok := ExternalProperties[resourceType]
+	if !ok {
+		return nil, fmt.Errorf("external %s dependencies are not supported by the export source", resourceType)
+	}
+	properties := make(map[string]interface{})
+	for _, key := range keys {
+		properties[key] = *awscdk.Fn_ImportValue(jsii.String(ExportName(stackName, resourceType, id, key)))
+	}
+	return properties, nil
+}
+
+// FileProvider 从本地 JSON 文件读取依赖属性，文件格式与 magic token 相同，key 为 "TYPE:id" 或 "TYPE:<stack>/id"
+type FileProvider struct {
+	Path string
+
+	once     sync.Once
+	response DependenciesResponse
+	err      error
+}
+
+func (p *FileProvider) Properties(resourceType, stackName, id string) (map[string]interface{}, error) {
+	p.once.Do(func() {
+		data, err := os.ReadFile(p.Path)
+		if err != nil {
+			p.err = fmt.Errorf("error reading external dependencies file: %w", err)
+			return
+		}
+		if err := json.Unmarshal(data, &p.response); err != nil {
+			p.err = fmt.Errorf("error parsing external dependencies file %s: %w", p.Path, err)
+		}
+	})
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	for _, key := range []string{resourceType + ":" + stackName + "/" + id, resourceType + ":" + id} {
+		if info, ok := p.response.Dependencies[key]; ok {
+			return info.Properties, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s:%s not found in %s", ErrResourceNotFound, resourceType, id, p.Path)
+}
+
+// PublishProperties 将 Forge 属性发布到 SSM 参数和/或 CloudFormation 导出，供其他堆栈通过 ext/ 依赖引用
+func PublishProperties(scope constructs.Construct, targets []string, prefix, stackName, key string, properties map[string]interface{}) {
+	parts := strings.SplitN(key, ":", 2)
+	if len(parts) != 2 {
+		return
+	}
+	resourceType, id := parts[0], parts[1]
+
+	for _, propertyKey := range ExternalProperties[resourceType] {
+		value := publishableValue(properties[propertyKey])
+		if value == nil {
+			continue
+		}
+		// CfnOutput 位于堆栈顶层，逻辑 ID 直接由 construct ID 去掉非字母数字字符得到，因此整体编码
+		constructId := types.EncodeConstructId(resourceType + ":" + id + ":" + propertyKey)
+		for _, target := range targets {
+			switch target {
+			case ExternalSourceSsm:
+				awsssm.NewStringParameter(scope, jsii.String("Publish"+constructId), &awsssm.StringParameterProps{
+					ParameterName: jsii.String(SsmParameterName(prefix, stackName, resourceType, id, propertyKey)),
+					StringValue:   value,
+					Tier:          awsssm.ParameterTier_STANDARD,
+				})
+			case ExternalSourceExport:
+				awscdk.NewCfnOutput(scope, jsii.String("Export"+constructId), &awscdk.CfnOutputProps{
+					Value:      value,
+					ExportName: jsii.String(ExportName(stackName, resourceType, id, propertyKey)),
+				})
+			}
+		}
+	}
+}
+
+// publishableValue 将属性值转换为 SSM 参数/导出可用的字符串，空值返回 nil
+func publishableValue(value interface{}) *string {
+	switch v := value.(type) {
+	case *string:
+		if v != nil && *v != "" {
+			return v
+		}
+	case string:
+		if v != "" {
+			return jsii.String(v)
+		}
+	case int, int64, float64, bool:
+		return jsii.String(fmt.Sprintf("%v", v))
+	}
+	return nil
+}
+
+// newExternalForge 根据资源类型包装外部依赖属性，使其同样支持 Resolve[T]
+func newExternalForge(resourceType string, properties map[string]interface{}) interface{} {
+	resource := externalResource{resourceType: resourceType, properties: properties}
+	switch resourceType {
+	case "EFS", "LUSTRE", "OPENZFS", "ONTAP", "S3":
+		return &externalFileSystem{resource}
+	case "RDS":
+		return &externalDatabase{resource}
+	case "DS":
+		return &externalDirectory{resource}
+	case "EKS", "ECS":
+		return &externalCluster{resource}
+	}
+	return &resource
+}
+
+// externalResource 外部依赖，属性由 ExternalProvider 提供
+type externalResource struct {
+	resourceType string
+	properties   map[string]interface{}
+}
+
+func (r *externalResource) GetProperties() map[string]interface{} {
+	return r.properties
+}
+
+// get 读取字符串属性，兼容 string 和 *string
+func (r *externalResource) get(key string) string {
+	switch v := r.properties[key].(type) {
+	case string:
+		return v
+	case *string:
+		if v != nil {
+			return *v
+		}
+	case float64, int:
+		return fmt.Sprintf("%v", v)
+	}
+	return ""
+}
+
+type externalFileSystem struct{ externalResource }
+
+func (r *externalFileSystem) FileSystem() FileSystemInfo {
+	info := FileSystemInfo{
+		Type:         r.resourceType,
+		FileSystemId: r.get("fileSystemId"),
+		DnsName:      r.get("dnsName"),
+		MountPoint:   r.get("mountPoint"),
+		MountName:    r.get("mountName"),
+		ExportPath:   r.get("exportPath"),
+		VolumeId:     r.get("volumeId"),
+		Protocol:     r.get("protocol"),
+		MountOptions: r.get("mountOptions"),
+	}
+	switch r.resourceType {
+	case "EFS":
+		info.Protocol = "efs"
+	case "LUSTRE":
+		info.Protocol = "lustre"
+	case "S3":
+		info.FileSystemId = r.get("bucketName")
+		info.Protocol = "s3"
+	}
+	// 以下属性只有 file 来源提供
+	if capacity, ok := r.properties["storageCapacityGiB"].(float64); ok {
+		info.StorageCapacityGiB = int(capacity)
+	}
+	if accessPoints, ok := r.properties["accessPoints"].(map[string]interface{}); ok {
+		info.AccessPoints = make(map[string]string)
+		for name, id := range accessPoints {
+			if idStr, ok := id.(string); ok {
+				info.AccessPoints[name] = idStr
+			}
+		}
+	}
+	if repositories, ok := r.properties["dataRepositories"].([]interface{}); ok {
+		for _, item := range repositories {
+			if repo, ok := item.(map[string]interface{}); ok {
+				fileSystemPath, _ := repo["fileSystemPath"].(string)
+				dataRepositoryPath, _ := repo["dataRepositoryPath"].(string)
+				info.DataRepositories = append(info.DataRepositories, DataRepositoryInfo{
+					FileSystemPath:     fileSystemPath,
+					DataRepositoryPath: dataRepositoryPath,
+				})
+			}
+		}
+	}
+	return info
+}
+
+type externalDatabase struct{ externalResource }
+
+func (r *externalDatabase) Database() DatabaseInfo {
+	return DatabaseInfo{
+		Endpoint:     r.get("endpoint"),
+		ReadEndpoint: r.get("readEndpoint"),
+		Port:         r.get("port"),
+		Engine:       r.get("engine"),
+		Username:     r.get("username"),
+		DatabaseName: r.get("databaseName"),
+		SecretArn:    r.get("secretArn"),
+	}
+}
+
+type externalDirectory struct{ externalResource }
+
+func (r *externalDirectory) Directory() DirectoryInfo {
+	info := DirectoryInfo{
+		DirectoryId: r.get("attrId"),
+		DomainName:  r.get("domainName"),
+		ShortName:   r.get("shortName"),
+		Edition:     r.get("edition"),
+		SecretArn:   r.get("secretARN"),
+		UnixHome:    r.get("unixHome"),
+	}
+	if ips, ok := r.properties["attrDnsIpAddresses"].([]interface{}); ok {
+		for _, ip := range ips {
+			if ipStr, ok := ip.(string); ok {
+				info.DnsIps = append(info.DnsIps, ipStr)
+			}
+		}
+	}
+	return info
+}
+
+type externalCluster struct{ externalResource }
+
+func (r *externalCluster) Cluster() ClusterInfo {
+	return ClusterInfo{
+		Type:        r.resourceType,
+		ClusterName: r.get("clusterName"),
+		ClusterArn:  r.get("clusterArn"),
+		Endpoint:    r.get("clusterEndpoint"),
+		Version:     r.get("eksVersion"),
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependency
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/assertions"
+	"github.com/aws/jsii-runtime-go"
+)
+
+func TestParseExternalID(t *testing.T) {
+	tests := []struct {
+		id           string
+		defaultStack string
+		stack        string
+		name         string
+		wantErr      bool
+	}{
+		{"ext/shared-efs", "producer", "producer", "shared-efs", false},
+		{"ext/other/shared-efs", "producer", "other", "shared-efs", false},
+		{"ext/shared-efs", "", "", "", true},
+		{"ext/a/b/c", "producer", "", "", true},
+	}
+
+	for _, tt := range tests {
+		stack, name, err := ParseExternalID(tt.id, tt.defaultStack)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: expected error %v, got %v", tt.id, tt.wantErr, err)
+			continue
+		}
+		if stack != tt.stack || name != tt.name {
+			t.Errorf("%s: expected %s/%s, got %s/%s", tt.id, tt.stack, tt.name, stack, name)
+		}
+	}
+}
+
+func TestExternalFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "external.json")
+	content := `{"dependencies":{
+		"EFS:producer/shared-efs":{"type":"EFS","id":"shared-efs","properties":{"fileSystemId":"fs-abc","mountPoint":"/shared-efs","accessPoints":{"data":"fsap-1"}}},
+		"RDS:db":{"type":"RDS","id":"db","properties":{"endpoint":"db.example.com","port":5432}}
+	}}`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewForgeManager()
+	manager.SetExternalProvider(&FileProvider{Path: path}, "producer")
+
+	forge, err := manager.Get("EFS:ext/shared-efs")
+	if err != nil {
+		t.Fatalf("Expected EFS:ext/shared-efs to resolve from file, got %v", err)
+	}
+	fileSystem, ok := forge.(FileSystemProvider)
+	if !ok || fileSystem.FileSystem().FileSystemId != "fs-abc" {
+		t.Errorf("Expected external EFS to provide file system fs-abc")
+	}
+	if id := fileSystem.FileSystem().AccessPoints["data"]; id != "fsap-1" {
+		t.Errorf("Expected access point data to be fsap-1, got %q", id)
+	}
+
+	forge, err = manager.Get("RDS:ext/other/db")
+	if err != nil {
+		t.Fatalf("Expected RDS:ext/other/db to fall back to the TYPE:id entry, got %v", err)
+	}
+	if port := forge.(DatabaseProvider).Database().Port; port != "5432" {
+		t.Errorf("Expected port 5432, got %q", port)
+	}
+
+	if _, err := manager.Get("EFS:ext/missing"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("Expected missing external dependency to return ErrResourceNotFound, got %v", err)
+	}
+	if _, err := manager.Get("EFS:local"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("Expected missing local dependency to return ErrResourceNotFound, got %v", err)
+	}
+	if _, err := (&FileProvider{Path: path}).Properties("EFS", "producer", "missing"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("Expected ErrResourceNotFound, got %v", err)
+	}
+}
+
+func TestExternalProviderNotConfigured(t *testing.T) {
+	manager := NewForgeManager()
+	if _, err := manager.Get("EFS:ext/shared-efs"); err == nil || errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("Expected a configuration error for an external dependency without provider, got %v", err)
+	}
+}
+
+func TestPublishProperties(t *testing.T) {
+	stack := awscdk.NewStack(awscdk.NewApp(nil), jsii.String("Producer"), nil)
+	for _, key := range []string{"EFS:a-b", "EFS:ab", "EFS:a_b", "EFS:aZ2Db"} {
+		PublishProperties(stack, []string{ExternalSourceSsm, ExternalSourceExport}, "", "Producer", key,
+			map[string]interface{}{"fileSystemId": "fs-123"})
+	}
+
+	template := assertions.Template_FromStack(stack, nil)
+	template.ResourceCountIs(jsii.String("AWS::SSM::Parameter"), jsii.Number(4))
+	if outputs := template.FindOutputs(jsii.String("*"), nil); len(*outputs) != 4 {
+		t.Errorf("Expected 4 distinct exports, got %d", len(*outputs))
+	}
+}
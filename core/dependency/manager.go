@@ -4,6 +4,8 @@
 package dependency
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 )
 
@@ -11,6 +13,17 @@ import (
 type ForgeManager struct {
 	forges map[string]interface{} // 存储 Forge 实例
 	mutex  sync.RWMutex
+
+	external      ExternalProvider // 解析 "TYPE:ext/..." 外部依赖，为空时不支持外部依赖
+	externalStack string           // "ext/<id>" 未指定堆栈时使用的生产者堆栈
+}
+
+// SetExternalProvider 设置外部依赖的解析来源和默认生产者堆栈
+func (fm *ForgeManager) SetExternalProvider(provider ExternalProvider, defaultStack string) {
+	fm.mutex.Lock()
+	defer fm.mutex.Unlock()
+	fm.external = provider
+	fm.externalStack = defaultStack
 }
 
 // NewForgeManager 创建新的 Forge 管理器
@@ -27,28 +40,66 @@ func (fm *ForgeManager) Store(key string, forge interface{}) {
 	fm.forges[key] = forge
 }
 
-// Get 获取 Forge 实例，支持 "type:id" 格式的 key
-func (fm *ForgeManager) Get(key string) (interface{}, bool) {
+// Get 获取 Forge 实例，支持 "type:id" 格式的 key，外部依赖在首次访问时解析
+// 未找到时返回包装 ErrResourceNotFound 的错误，外部依赖解析失败时返回包装后的原始错误
+func (fm *ForgeManager) Get(key string) (interface{}, error) {
 	fm.mutex.RLock()
-	defer fm.mutex.RUnlock()
 	forge, exists := fm.forges[key]
-	return forge, exists
+	fm.mutex.RUnlock()
+	if exists {
+		return forge, nil
+	}
+
+	forge, err := fm.resolveExternal(key)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving external dependency %s: %w", key, err)
+	}
+	if forge == nil {
+		return nil, fmt.Errorf("%w: resource '%s' not found", ErrResourceNotFound, key)
+	}
+	fm.Store(key, forge)
+	return forge, nil
+}
+
+// resolveExternal 通过 ExternalProvider 解析外部依赖，非外部依赖返回 nil
+func (fm *ForgeManager) resolveExternal(key string) (interface{}, error) {
+	parts := strings.SplitN(key, ":", 2)
+	if len(parts) != 2 || !IsExternal(parts[1]) {
+		return nil, nil
+	}
+
+	fm.mutex.RLock()
+	provider, defaultStack := fm.external, fm.externalStack
+	fm.mutex.RUnlock()
+	if provider == nil {
+		return nil, fmt.Errorf("external dependencies are not configured, set global.externalDependencies")
+	}
+
+	resourceType := strings.ToUpper(parts[0])
+	stackName, id, err := ParseExternalID(parts[1], defaultStack)
+	if err != nil {
+		return nil, err
+	}
+	properties, err := provider.Properties(resourceType, stackName, id)
+	if err != nil {
+		return nil, err
+	}
+	return newExternalForge(resourceType, properties), nil
 }
 
-// GetProperties 获取 Forge 的属性（如果支持）
 // GetProperties 获取 Forge 实例的属性，支持 "type:id" 格式的 key
-func (fm *ForgeManager) GetProperties(key string) (map[string]interface{}, bool) {
-	forge, exists := fm.Get(key)
-	if !exists {
-		return nil, false
+func (fm *ForgeManager) GetProperties(key string) (map[string]interface{}, error) {
+	forge, err := fm.Get(key)
+	if err != nil {
+		return nil, err
 	}
-	
+
 	// 尝试调用 GetProperties 方法
 	if propertiesGetter, ok := forge.(interface{ GetProperties() map[string]interface{} }); ok {
-		return propertiesGetter.GetProperties(), true
+		return propertiesGetter.GetProperties(), nil
 	}
-	
-	return nil, false
+
+	return nil, fmt.Errorf("%w: resource '%s' (%T) does not expose properties", ErrCapabilityNotProvided, key, forge)
 }
 
 var GlobalManager *ForgeManager
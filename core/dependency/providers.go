@@ -0,0 +1,167 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependency
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrCapabilityNotProvided 依赖资源存在，但没有实现所请求的能力接口
+var ErrCapabilityNotProvided = errors.New("capability not provided")
+
+// FileSystemInfo 文件存储类依赖（EFS、Lustre、OpenZFS、ONTAP、S3）的类型化信息
+type FileSystemInfo struct {
+	Type         string // 资源类型，如 EFS、LUSTRE、OPENZFS、ONTAP、S3
+	FileSystemId string // 文件系统 ID，S3 为存储桶名称
+	DnsName      string
+	MountPoint   string
+	MountName    string // Lustre 挂载名称
+	ExportPath   string // NFS 导出路径
+	VolumeId     string // OpenZFS/ONTAP 卷 ID
+	Protocol     string // efs、lustre、nfs、s3
+	MountOptions string
+
+	StorageCapacityGiB int                  // Lustre 存储容量
+	AccessPoints       map[string]string    // EFS 访问点名称 -> 访问点 ID
+	DataRepositories   []DataRepositoryInfo // Lustre 数据仓库关联
+	IamRequired        bool                 // EFS 禁止匿名访问，客户端需要通过 IAM 授权挂载才能写入
+}
+
+// DataRepositoryInfo Lustre 文件系统路径与 S3 路径的关联
+type DataRepositoryInfo struct {
+	FileSystemPath     string
+	DataRepositoryPath string
+}
+
+// DatabaseInfo 数据库类依赖（RDS）的类型化信息
+type DatabaseInfo struct {
+	Endpoint     string
+	ReadEndpoint string
+	Port         string
+	Engine       string
+	Username     string
+	DatabaseName string
+	SecretArn    string
+}
+
+// DirectoryInfo 目录服务类依赖（DS）的类型化信息
+type DirectoryInfo struct {
+	DirectoryId string
+	DomainName  string
+	ShortName   string
+	Edition     string
+	DnsIps      []string
+	SecretArn   string
+	UnixHome    string
+}
+
+// ClusterInfo 集群类依赖（EKS、ECS）的类型化信息
+type ClusterInfo struct {
+	Type        string // 资源类型，如 EKS、ECS
+	ClusterName string
+	ClusterArn  string
+	Endpoint    string
+	Version     string
+}
+
+// FileSystemProvider 提供文件存储的 Forge 实现此接口
+type FileSystemProvider interface {
+	FileSystem() FileSystemInfo
+}
+
+// DatabaseProvider 提供数据库的 Forge 实现此接口
+type DatabaseProvider interface {
+	Database() DatabaseInfo
+}
+
+// DirectoryProvider 提供目录服务的 Forge 实现此接口
+type DirectoryProvider interface {
+	Directory() DirectoryInfo
+}
+
+// ClusterProvider 提供容器集群的 Forge 实现此接口
+type ClusterProvider interface {
+	Cluster() ClusterInfo
+}
+
+// Resolve 按 "type:id" 获取依赖 Forge 并断言为能力接口 T，如 Resolve[FileSystemProvider]("EFS:shared")
+func Resolve[T any](key string) (T, error) {
+	var zero T
+	key, err := normalizeKey(key)
+	if err != nil {
+		return zero, err
+	}
+
+	forge, err := GlobalManager.Get(key)
+	if err != nil {
+		return zero, err
+	}
+
+	provider, ok := forge.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: resource '%s' (%T) does not implement %s",
+			ErrCapabilityNotProvided, key, forge, reflect.TypeOf((*T)(nil)).Elem().Name())
+	}
+	return provider, nil
+}
+
+// ResolveAll 解析逗号分隔的 dependsOn 中所有实现能力接口 T 的依赖，未实现 T 的依赖被跳过
+func ResolveAll[T any](dependsOn string) ([]T, error) {
+	var providers []T
+	for _, dep := range strings.Split(dependsOn, ",") {
+		if strings.TrimSpace(dep) == "" {
+			continue
+		}
+		provider, err := Resolve[T](dep)
+		if errors.Is(err, ErrCapabilityNotProvided) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// ResolveFirst 返回 dependsOn 中第一个实现能力接口 T 的依赖，没有时返回 ErrResourceNotFound
+func ResolveFirst[T any](dependsOn string) (T, error) {
+	var zero T
+	providers, err := ResolveAll[T](dependsOn)
+	if err != nil {
+		return zero, err
+	}
+	if len(providers) == 0 {
+		return zero, fmt.Errorf("%w: no dependency in '%s' implements %s",
+			ErrResourceNotFound, dependsOn, reflect.TypeOf((*T)(nil)).Elem().Name())
+	}
+	return providers[0], nil
+}
+
+// ResolveFileSystem 返回 dependsOn 中第一个指定类型（如 LUSTRE、EFS）的文件存储依赖，没有时返回 ErrResourceNotFound
+func ResolveFileSystem(dependsOn, resourceType string) (FileSystemInfo, error) {
+	providers, err := ResolveAll[FileSystemProvider](dependsOn)
+	if err != nil {
+		return FileSystemInfo{}, err
+	}
+	for _, provider := range providers {
+		if fileSystem := provider.FileSystem(); fileSystem.Type == resourceType {
+			return fileSystem, nil
+		}
+	}
+	return FileSystemInfo{}, fmt.Errorf("%w: no %s dependency in '%s'", ErrResourceNotFound, resourceType, dependsOn)
+}
+
+// normalizeKey 清理空格并将类型转换为大写，与 ForgeManager 中存储的 key 保持一致
+func normalizeKey(key string) (string, error) {
+	key = strings.TrimSpace(key)
+	parts := strings.Split(key, ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("%w: invalid dependency format '%s'", ErrInvalidFormat, key)
+	}
+	return strings.ToUpper(parts[0]) + ":" + parts[1], nil
+}
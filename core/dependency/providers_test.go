@@ -0,0 +1,64 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependency
+
+import (
+	"errors"
+	"testing"
+)
+
+type testFileSystemForge struct{}
+
+func (f *testFileSystemForge) FileSystem() FileSystemInfo {
+	return FileSystemInfo{Type: "EFS", FileSystemId: "fs-123", MountPoint: "/shared"}
+}
+
+func (f *testFileSystemForge) GetProperties() map[string]interface{} {
+	return map[string]interface{}{"fileSystemId": "fs-123", "mountPoint": "/shared"}
+}
+
+type testClusterForge struct{}
+
+func (f *testClusterForge) Cluster() ClusterInfo {
+	return ClusterInfo{Type: "EKS", ClusterName: "eks"}
+}
+
+func TestResolve(t *testing.T) {
+	GlobalManager.Store("EFS:shared", &testFileSystemForge{})
+	GlobalManager.Store("EKS:eks", &testClusterForge{})
+
+	provider, err := Resolve[FileSystemProvider]("efs:shared")
+	if err != nil {
+		t.Fatalf("Expected EFS:shared to resolve, got %v", err)
+	}
+	if got := provider.FileSystem().MountPoint; got != "/shared" {
+		t.Errorf("Expected mount point '/shared', got %q", got)
+	}
+
+	if _, err := Resolve[FileSystemProvider]("EFS:missing"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("Expected ErrResourceNotFound, got %v", err)
+	}
+	if _, err := Resolve[DatabaseProvider]("EFS:shared"); !errors.Is(err, ErrCapabilityNotProvided) {
+		t.Errorf("Expected ErrCapabilityNotProvided, got %v", err)
+	}
+	if _, err := Resolve[FileSystemProvider]("EFS"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Expected ErrInvalidFormat, got %v", err)
+	}
+
+	fileSystems, err := ResolveAll[FileSystemProvider]("EKS:eks, EFS:shared")
+	if err != nil || len(fileSystems) != 1 {
+		t.Errorf("Expected one file system from dependsOn, got %d (%v)", len(fileSystems), err)
+	}
+	if _, err := ResolveFirst[DirectoryProvider]("EKS:eks,EFS:shared"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("Expected ErrResourceNotFound without a directory dependency, got %v", err)
+	}
+
+	fileSystem, err := ResolveFileSystem("EKS:eks,EFS:shared", "EFS")
+	if err != nil || fileSystem.FileSystemId != "fs-123" {
+		t.Errorf("Expected EFS fs-123 from dependsOn, got %q (%v)", fileSystem.FileSystemId, err)
+	}
+	if _, err := ResolveFileSystem("EKS:eks,EFS:shared", "LUSTRE"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("Expected ErrResourceNotFound without a Lustre dependency, got %v", err)
+	}
+}
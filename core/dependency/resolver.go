@@ -36,9 +36,9 @@ func GetDependencyInfo(dependency string) (string, error) {
 		resourceID := parts[1]
 
 		// 直接从 ForgeManager 获取属性（使用完整的 "type:id" 格式）
-		properties, exists := GlobalManager.GetProperties(dep)
-		if !exists {
-			return "", fmt.Errorf("%w: resource '%s' not found", ErrResourceNotFound, dep)
+		properties, err := GlobalManager.GetProperties(dep)
+		if err != nil {
+			return "", err
 		}
 
 		// 创建资源信息并存储
@@ -121,3 +121,37 @@ func ExtractDependencyProperties(magicTokenStr string, resourceType string) (map
 
 	return nil, fmt.Errorf("%s dependency not found", resourceType)
 }
+
+// ResolveS3BucketName 通过 FileSystemProvider 获取 dependsOn 中 S3 依赖的存储桶名称，没有 S3 依赖时返回空字符串
+func ResolveS3BucketName(dependsOn string) (string, error) {
+	for _, dep := range strings.Split(dependsOn, ",") {
+		if strings.TrimSpace(dep) == "" {
+			continue
+		}
+		key, err := normalizeKey(dep)
+		if err != nil {
+			return "", err
+		}
+		if !strings.HasPrefix(key, "S3:") {
+			continue
+		}
+		provider, err := Resolve[FileSystemProvider](key)
+		if err != nil {
+			return "", err
+		}
+		return provider.FileSystem().FileSystemId, nil
+	}
+	return "", nil
+}
+
+// ResolveS3Location 未指定s3Location时使用S3依赖的存储桶，如 "s3://<bucketName>"
+func ResolveS3Location(s3Location string, dependsOn string) (string, error) {
+	if s3Location != "" {
+		return s3Location, nil
+	}
+	bucketName, err := ResolveS3BucketName(dependsOn)
+	if err != nil || bucketName == "" {
+		return "", err
+	}
+	return "s3://" + bucketName, nil
+}
@@ -0,0 +1,48 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package dependency
+
+import (
+	"errors"
+	"testing"
+)
+
+type testBucketForge struct{}
+
+func (f *testBucketForge) FileSystem() FileSystemInfo {
+	return FileSystemInfo{Type: "S3", FileSystemId: "my-datasets", Protocol: "s3"}
+}
+
+func TestResolveS3Location(t *testing.T) {
+	GlobalManager.Store("S3:datasets", &testBucketForge{})
+	GlobalManager.Store("EFS:shared", &testFileSystemForge{})
+
+	tests := []struct {
+		name       string
+		s3Location string
+		dependsOn  string
+		expected   string
+	}{
+		{"explicit location wins", "s3://scripts/app", "S3:datasets", "s3://scripts/app"},
+		{"bucket from S3 dependency", "", "EFS:shared, s3:datasets", "s3://my-datasets"},
+		{"no S3 dependency", "", "EFS:shared", ""},
+		{"no dependencies", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveS3Location(tt.s3Location, tt.dependsOn)
+			if err != nil {
+				t.Fatalf("Expected %s to resolve, got %v", tt.dependsOn, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := ResolveS3BucketName("S3:missing"); !errors.Is(err, ErrResourceNotFound) {
+		t.Errorf("Expected ErrResourceNotFound for a missing S3 dependency, got %v", err)
+	}
+}
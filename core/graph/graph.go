@@ -0,0 +1,241 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+// Package graph 根据配置文件静态构建依赖图，不创建 CDK 资源
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/dependency"
+	"github.com/awslabs/InfraForge/core/manager"
+	utilsSecurity "github.com/awslabs/InfraForge/core/utils/security"
+	"github.com/awslabs/InfraForge/registry"
+)
+
+// 节点类型
+const (
+	KindVpc            = "vpc"
+	KindSecurityGroup  = "securityGroup"
+	KindForge          = "forge"
+	KindSharedResource = "sharedResource"
+	KindExternal       = "external" // 其他堆栈创建的 "TYPE:ext/..." 依赖
+)
+
+// 边类型
+const (
+	EdgeSubnet    = "subnet"
+	EdgeSecurity  = "security"
+	EdgeDependsOn = "dependsOn"
+	EdgeIngress   = "ingress"
+	EdgeShared    = "shared"
+)
+
+// Node 图中的节点
+type Node struct {
+	ID      string `json:"id"`
+	Kind    string `json:"kind"`
+	Label   string `json:"label"`
+	Type    string `json:"type,omitempty"`    // Forge 类型，如 ec2、efs
+	Enabled bool   `json:"enabled"`           // 是否在 enabledForges 中
+	Missing bool   `json:"missing,omitempty"` // 被依赖但配置中不存在
+}
+
+// Edge 图中的边，From 依赖或挂载到 To
+type Edge struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Kind       string   `json:"kind"`
+	Label      string   `json:"label,omitempty"`
+	Properties []string `json:"properties,omitempty"` // dependsOn 边消费的依赖属性
+}
+
+// Graph 配置的依赖图
+type Graph struct {
+	StackName string `json:"stackName"`
+	Nodes     []Node `json:"nodes"`
+	Edges     []Edge `json:"edges"`
+
+	index map[string]int
+}
+
+// consumedProperties 各类依赖被消费的属性，与各 Forge 的 GetProperties 保持一致
+var consumedProperties = map[string][]string{
+	"EFS":     {"fileSystemId", "mountPoint"},
+	"LUSTRE":  {"fileSystemId", "dnsName", "mountName", "mountPoint"},
+	"OPENZFS": {"dnsName", "exportPath", "volumeId", "mountPoint"},
+	"ONTAP":   {"dnsName", "exportPath", "volumeId", "mountPoint"},
+	"S3":      {"bucketName", "mountPoint"},
+	"RDS":     {"endpoint", "username", "secretArn"},
+	"DS":      {"domainName", "shortName", "secretARN", "attrDnsIpAddresses"},
+	"EKS":     {"clusterName", "clusterArn"},
+	"ECS":     {"clusterName", "clusterArn"},
+}
+
+// Build 解析配置中所有 Forge 实例（包括未启用的），构建依赖图
+func Build(infraConfig *config.Config) (*Graph, error) {
+	g := &Graph{StackName: infraConfig.Global.StackName, index: make(map[string]int)}
+
+	enabled := make(map[string]bool)
+	for _, id := range infraConfig.EnabledForges {
+		enabled[id] = true
+	}
+
+	g.addNode(Node{ID: "VPC", Kind: KindVpc, Label: "VPC", Type: "vpc", Enabled: true})
+
+	// 按类型排序，保证输出稳定
+	types := make([]string, 0, len(infraConfig.Forges))
+	for typ := range infraConfig.Forges {
+		if typ != "vpc" {
+			types = append(types, typ)
+		}
+	}
+	sort.Strings(types)
+
+	var pending []Edge
+	for _, typ := range types {
+		forgeConfig := infraConfig.Forges[typ]
+		constructor, ok := registry.ForgeConstructors[typ]
+		if !ok {
+			return nil, fmt.Errorf("unknown forge type: %s", typ)
+		}
+
+		for _, rawInst := range forgeConfig.Instances {
+			inst := registry.CreateInstance(typ)
+			if err := json.Unmarshal(rawInst, inst); err != nil {
+				return nil, fmt.Errorf("error parsing %s instance: %v", typ, err)
+			}
+
+			merged, err := manager.MergeInstanceConfig(constructor(), typ, forgeConfig.Defaults, inst)
+			if err != nil {
+				return nil, fmt.Errorf("error merging %s: %v", inst.GetID(), err)
+			}
+
+			ref := fmt.Sprintf("%s:%s", strings.ToUpper(typ), merged.GetID())
+			g.addNode(Node{ID: ref, Kind: KindForge, Label: ref, Type: typ, Enabled: enabled[inst.GetID()]})
+
+			g.addTierEdges(ref, merged)
+			g.addSharedResourceEdges(ref, merged, infraConfig.Global.StackName)
+			pending = append(pending, dependsOnEdges(ref, merged)...)
+			pending = append(pending, ingressEdges(ref, merged)...)
+		}
+	}
+
+	// 依赖和 ingress 可能指向后面才解析的实例，最后统一添加，不存在的目标标记为 missing
+	for _, edge := range pending {
+		if _, ok := g.index[edge.To]; !ok {
+			if dependency.IsExternal(strings.SplitN(edge.To, ":", 2)[1]) {
+				g.addNode(Node{ID: edge.To, Kind: KindExternal, Label: edge.To, Enabled: true})
+			} else {
+				g.addNode(Node{ID: edge.To, Kind: KindForge, Label: edge.To, Missing: true})
+			}
+		}
+		g.Edges = append(g.Edges, edge)
+	}
+
+	return g, nil
+}
+
+// addNode 添加节点，已存在时忽略
+func (g *Graph) addNode(node Node) {
+	if _, ok := g.index[node.ID]; ok {
+		return
+	}
+	g.index[node.ID] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, node)
+}
+
+// addTierEdges 添加实例到子网层级和安全组的边
+func (g *Graph) addTierEdges(ref string, merged config.InstanceConfig) {
+	subnet := merged.GetSubnet()
+	if subnet == "" {
+		subnet = "private"
+	}
+	g.Edges = append(g.Edges, Edge{From: ref, To: "VPC", Kind: EdgeSubnet, Label: subnet})
+
+	if merged.GetDedicatedSecurityGroup() {
+		sgID := "SG:" + ref
+		g.addNode(Node{ID: sgID, Kind: KindSecurityGroup, Label: ref + " (dedicated)", Enabled: true})
+		g.Edges = append(g.Edges, Edge{From: ref, To: sgID, Kind: EdgeSecurity})
+		return
+	}
+
+	tier := merged.GetSecurityGroup()
+	if tier != "public" && tier != "isolated" {
+		tier = "private"
+	}
+	sgID := "SG:" + tier
+	g.addNode(Node{ID: sgID, Kind: KindSecurityGroup, Label: tier, Enabled: true})
+	g.Edges = append(g.Edges, Edge{From: ref, To: sgID, Kind: EdgeSecurity})
+}
+
+// addSharedResourceEdges 添加 createSharedResourcesForInstance 创建的共享资源
+func (g *Graph) addSharedResourceEdges(ref string, merged config.InstanceConfig, stackName string) {
+	for _, resource := range manager.SharedResourcesForInstance(merged) {
+		name := resource.Name
+		if name == "" {
+			name = stackName
+		}
+		id := resource.Kind + ":" + name
+		g.addNode(Node{ID: id, Kind: KindSharedResource, Label: id, Type: resource.Kind, Enabled: true})
+		g.Edges = append(g.Edges, Edge{From: ref, To: id, Kind: EdgeShared, Label: resource.Detail})
+	}
+}
+
+// dependsOnEdges 解析实例的 dependsOn，各 Forge 的 dependsOn 字段统一通过 JSON 读取
+func dependsOnEdges(ref string, merged config.InstanceConfig) []Edge {
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return nil
+	}
+	var fields struct {
+		DependsOn string `json:"dependsOn"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil || fields.DependsOn == "" {
+		return nil
+	}
+
+	var edges []Edge
+	for _, dep := range strings.Split(fields.DependsOn, ",") {
+		target := normalizeRef(dep)
+		if target == "" {
+			continue
+		}
+		depType := strings.SplitN(target, ":", 2)[0]
+		edges = append(edges, Edge{From: ref, To: target, Kind: EdgeDependsOn, Properties: consumedProperties[depType]})
+	}
+	return edges
+}
+
+// ingressEdges 解析 ingress 中引用其他 Forge 实例的规则
+func ingressEdges(ref string, merged config.InstanceConfig) []Edge {
+	var edges []Edge
+	for _, rule := range merged.GetIngress() {
+		if !strings.Contains(rule.Source, ":") || strings.Contains(rule.Source, "/") || utilsSecurity.IsPrefixListSource(rule.Source) {
+			continue // CIDR、前缀列表或安全组层级
+		}
+		protocol := rule.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		label := protocol
+		if rule.Ports != "" {
+			label += "/" + rule.Ports
+		}
+		edges = append(edges, Edge{From: normalizeRef(rule.Source), To: ref, Kind: EdgeIngress, Label: label})
+	}
+	return edges
+}
+
+// normalizeRef 将 "efs:shared" 规范为 "EFS:shared"
+func normalizeRef(ref string) string {
+	parts := strings.SplitN(strings.TrimSpace(ref), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return ""
+	}
+	return strings.ToUpper(parts[0]) + ":" + strings.TrimSpace(parts[1])
+}
@@ -0,0 +1,100 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/awslabs/InfraForge/core/config"
+)
+
+func testConfig() *config.Config {
+	return &config.Config{
+		Global:        config.GlobalConfig{StackName: "graph-test"},
+		EnabledForges: []string{"shared", "web"},
+		Forges: map[string]config.ForgeConfig{
+			"efs": {
+				Defaults:  json.RawMessage(`{"subnet":"isolated","security":"isolated"}`),
+				Instances: []json.RawMessage{json.RawMessage(`{"id":"shared"}`)},
+			},
+			"ec2": {
+				Defaults: json.RawMessage(`{"subnet":"public","security":"public"}`),
+				Instances: []json.RawMessage{
+					json.RawMessage(`{"id":"web","dependsOn":"EFS:shared,RDS:db","placementGroup":"pg1"}`),
+					json.RawMessage(`{"id":"bastion","ingress":[{"ports":"22","source":"EC2:web"},{"ports":"22","source":"pl:corp"},{"ports":"443","source":"pl-0123456789abcdef0"}]}`),
+				},
+			},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	g, err := Build(testConfig())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	nodes := make(map[string]Node)
+	for _, node := range g.Nodes {
+		nodes[node.ID] = node
+	}
+	if !nodes["EC2:web"].Enabled || nodes["EC2:bastion"].Enabled {
+		t.Errorf("Expected EC2:web enabled and EC2:bastion disabled")
+	}
+	if !nodes["RDS:db"].Missing {
+		t.Errorf("Expected RDS:db to be marked missing")
+	}
+	// 前缀列表来源不是 Forge 引用
+	for _, id := range []string{"PL:corp", "pl:corp", "pl-0123456789abcdef0"} {
+		if _, ok := nodes[id]; ok {
+			t.Errorf("Expected no node for prefix list source %s", id)
+		}
+	}
+	for _, id := range []string{"SG:public", "SG:isolated", "keyPair:graph-test", "placementGroup:pg1"} {
+		if _, ok := nodes[id]; !ok {
+			t.Errorf("Expected node %s", id)
+		}
+	}
+
+	found := map[string]bool{}
+	for _, edge := range g.Edges {
+		found[edge.Kind+" "+edge.From+"->"+edge.To] = true
+		if edge.Kind == EdgeDependsOn && edge.To == "EFS:shared" && len(edge.Properties) == 0 {
+			t.Errorf("Expected consumed properties on EFS dependency edge")
+		}
+	}
+	for _, key := range []string{"dependsOn EC2:web->EFS:shared", "ingress EC2:web->EC2:bastion", "security EFS:shared->SG:isolated"} {
+		if !found[key] {
+			t.Errorf("Expected edge %s", key)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	g, err := Build(testConfig())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	for format, expected := range map[string]string{
+		"dot":     `"EC2:web" -> "EFS:shared"`,
+		"mermaid": "EC2_web -->",
+		"json":    `"kind": "dependsOn"`,
+	} {
+		var buf bytes.Buffer
+		if err := Write(&buf, g, format); err != nil {
+			t.Fatalf("Write %s failed: %v", format, err)
+		}
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("Expected %s output to contain %q, got:\n%s", format, expected, buf.String())
+		}
+	}
+
+	if err := Write(&bytes.Buffer{}, g, "svg"); err == nil {
+		t.Errorf("Expected error for unsupported format")
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var mermaidIDPattern = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// Write 按格式输出依赖图，支持 dot、mermaid 和 json
+func Write(w io.Writer, g *Graph, format string) error {
+	switch strings.ToLower(format) {
+	case "dot", "":
+		return writeDot(w, g)
+	case "mermaid":
+		return writeMermaid(w, g)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(g)
+	default:
+		return fmt.Errorf("unsupported graph format %q, expected dot, mermaid or json", format)
+	}
+}
+
+// edgeLabel 组合边的标签和消费的属性
+func edgeLabel(edge Edge) string {
+	label := edge.Label
+	if len(edge.Properties) > 0 {
+		if label != "" {
+			label += " "
+		}
+		label += strings.Join(edge.Properties, ", ")
+	}
+	return label
+}
+
+func writeDot(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", g.StackName)
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\"];\n")
+
+	for _, node := range g.Nodes {
+		attrs := []string{fmt.Sprintf("label=%q", node.Label)}
+		switch node.Kind {
+		case KindVpc:
+			attrs = append(attrs, "shape=box3d")
+		case KindSecurityGroup:
+			attrs = append(attrs, "shape=hexagon")
+		case KindSharedResource:
+			attrs = append(attrs, "shape=note")
+		case KindExternal:
+			attrs = append(attrs, "shape=component")
+		default:
+			attrs = append(attrs, "shape=box")
+		}
+		if node.Missing {
+			attrs = append(attrs, "style=dashed", "color=red")
+		} else if !node.Enabled {
+			attrs = append(attrs, "style=dashed", "color=gray", "fontcolor=gray")
+		}
+		fmt.Fprintf(&b, "  %q [%s];\n", node.ID, strings.Join(attrs, ", "))
+	}
+
+	for _, edge := range g.Edges {
+		attrs := []string{}
+		if label := edgeLabel(edge); label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%q", label))
+		}
+		switch edge.Kind {
+		case EdgeSubnet, EdgeSecurity, EdgeShared:
+			attrs = append(attrs, "style=dotted")
+		case EdgeIngress:
+			attrs = append(attrs, "color=blue")
+		}
+		fmt.Fprintf(&b, "  %q -> %q [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMermaid(w io.Writer, g *Graph) error {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+
+	mermaidID := func(id string) string {
+		return mermaidIDPattern.ReplaceAllString(id, "_")
+	}
+	escape := func(label string) string {
+		return strings.ReplaceAll(label, "\"", "#quot;")
+	}
+
+	for _, node := range g.Nodes {
+		id, label := mermaidID(node.ID), escape(node.Label)
+		switch node.Kind {
+		case KindVpc:
+			fmt.Fprintf(&b, "  %s[(\"%s\")]\n", id, label)
+		case KindSecurityGroup:
+			fmt.Fprintf(&b, "  %s{{\"%s\"}}\n", id, label)
+		case KindSharedResource:
+			fmt.Fprintf(&b, "  %s>\"%s\"]\n", id, label)
+		case KindExternal:
+			fmt.Fprintf(&b, "  %s[/\"%s\"/]\n", id, label)
+		default:
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", id, label)
+		}
+		if node.Missing {
+			fmt.Fprintf(&b, "  class %s missing\n", id)
+		} else if !node.Enabled {
+			fmt.Fprintf(&b, "  class %s disabled\n", id)
+		}
+	}
+
+	for _, edge := range g.Edges {
+		arrow := "-->"
+		switch edge.Kind {
+		case EdgeSubnet, EdgeSecurity, EdgeShared:
+			arrow = "-.->"
+		case EdgeIngress:
+			arrow = "==>"
+		}
+		if label := edgeLabel(edge); label != "" {
+			fmt.Fprintf(&b, "  %s %s|\"%s\"| %s\n", mermaidID(edge.From), arrow, escape(label), mermaidID(edge.To))
+		} else {
+			fmt.Fprintf(&b, "  %s %s %s\n", mermaidID(edge.From), arrow, mermaidID(edge.To))
+		}
+	}
+
+	b.WriteString("  classDef disabled stroke-dasharray: 5 5,color:#999\n")
+	b.WriteString("  classDef missing stroke:#f00,stroke-dasharray: 5 5\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -8,10 +8,12 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/awslabs/InfraForge/core/backup"
 	"github.com/awslabs/InfraForge/core/config"
 	"github.com/awslabs/InfraForge/core/interfaces"
 	"github.com/awslabs/InfraForge/core/dependency"
 	"github.com/awslabs/InfraForge/core/partition"
+	"github.com/awslabs/InfraForge/core/security"
 	"github.com/awslabs/InfraForge/core/utils/aws"
 	"github.com/awslabs/InfraForge/forges/aws/ec2"
 	"github.com/awslabs/InfraForge/forges/aws/eks"
@@ -22,6 +24,7 @@ import (
 
 	"github.com/aws/aws-cdk-go/awscdk/v2"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/jsii-runtime-go"
 )
 
 type ForgeManager struct {
@@ -30,6 +33,12 @@ type ForgeManager struct {
 	securityGroups *interfaces.SecurityGroups
 	subnetTypeMap map[string]awsec2.SubnetType
 	dualStack     bool
+	// 每个 Forge 实例实际使用的安全组，key 为 "TYPE:id"，用于解析 ingress 中的 Forge 引用
+	instanceSecurityGroups map[string]awsec2.SecurityGroup
+	// 跨堆栈依赖配置，Publish 非空时将 Forge 属性发布给其他堆栈
+	external *config.ExternalDependenciesConfig
+	// AWS Backup 计划，实例通过 backupPlan 引用
+	backup *backup.Manager
 }
 
 func NewForgeManager(stack awscdk.Stack, dualStack bool) *ForgeManager {
@@ -37,6 +46,9 @@ func NewForgeManager(stack awscdk.Stack, dualStack bool) *ForgeManager {
 	partition.DefaultManagedPolicy = iam.CreateDCVLicensingPolicy(stack)
 	iam.CreateDCVOutputs(stack, partition.DefaultManagedPolicy)
 
+	// 每个堆栈使用独立的规则注册表，避免跨堆栈去重和审计记录串扰
+	security.GlobalRuleRegistry.Reset()
+
 	return &ForgeManager{
 		stack:     stack,
 		dualStack: dualStack,
@@ -45,6 +57,48 @@ func NewForgeManager(stack awscdk.Stack, dualStack bool) *ForgeManager {
 			"private":   awsec2.SubnetType_PRIVATE_WITH_EGRESS,
 			"isolated":  awsec2.SubnetType_PRIVATE_ISOLATED,
 		},
+		instanceSecurityGroups: make(map[string]awsec2.SecurityGroup),
+	}
+}
+
+// ConfigureExternalDependencies 设置 "TYPE:ext/..." 依赖的解析来源，需在创建 Forge 之前调用
+func (fm *ForgeManager) ConfigureExternalDependencies(external *config.ExternalDependenciesConfig) error {
+	if external == nil {
+		dependency.GlobalManager.SetExternalProvider(nil, "")
+		return nil
+	}
+	if err := external.Validate(); err != nil {
+		return err
+	}
+	fm.external = external
+
+	var provider dependency.ExternalProvider
+	switch external.Source {
+	case dependency.ExternalSourceExport:
+		provider = &dependency.ExportProvider{}
+	case dependency.ExternalSourceFile:
+		provider = &dependency.FileProvider{Path: external.File}
+	default:
+		provider = &dependency.SsmProvider{Scope: fm.stack, Prefix: external.SsmPrefix}
+	}
+	dependency.GlobalManager.SetExternalProvider(provider, external.StackName)
+	return nil
+}
+
+// ConfigureBackup 校验顶层 backup 配置，需在创建 Forge 之前调用
+func (fm *ForgeManager) ConfigureBackup(backupConfig *config.BackupConfig) error {
+	backupManager, err := backup.NewManager(fm.stack, backupConfig)
+	if err != nil {
+		return err
+	}
+	fm.backup = backupManager
+	return nil
+}
+
+// CreateBackupPlans 在所有 Forge 创建完成后生成备份保管库、计划和资源选择
+func (fm *ForgeManager) CreateBackupPlans() {
+	if fm.backup != nil {
+		fm.backup.CreatePlans()
 	}
 }
 
@@ -65,6 +119,12 @@ func (fm *ForgeManager) CreateVPC(infraConfig *config.Config) error {
 	if err := json.Unmarshal(vpcConfig, vpcInst); err != nil {
 		return fmt.Errorf("error parsing VPC config: %v", err)
 	}
+
+	if validator, ok := vpcInst.(config.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return err
+		}
+	}
 	
 	// 创建 VPC 上下文
 	vpcCtx := &interfaces.ForgeContext{
@@ -77,8 +137,11 @@ func (fm *ForgeManager) CreateVPC(infraConfig *config.Config) error {
 	ivpc := vpcForge.Create(vpcCtx)
 	vpcForgeResult := ivpc.(*vpc.VpcForge)
 	fm.vpc = vpcForgeResult.GetVpc()
+	aws.SetVpcAvailabilityZones(fm.vpc)
 
 	// 创建安全组
+	security.GlobalRuleRegistry.SetCurrentForge("VPC")
+	defer security.GlobalRuleRegistry.SetCurrentForge("")
 	publicSG, privateSG, isolatedSG := vpc.CreateSecurityGroups(fm.stack, fm.vpc, fm.dualStack)
 	
 	fm.securityGroups = &interfaces.SecurityGroups{
@@ -119,18 +182,44 @@ func (fm *ForgeManager) processForge(typ string, rawDefaults json.RawMessage, in
 	}
 	forge := constructor()
 
-	defaults := registry.CreateInstance(typ)
-	if err := json.Unmarshal(rawDefaults, defaults); err != nil {
-		return fmt.Errorf("error parsing defaults: %v", err)
+	merged, err := MergeInstanceConfig(forge, typ, rawDefaults, inst)
+	if err != nil {
+		return err
 	}
 
-	merged := forge.MergeConfigs(defaults, inst)
+	// 合成前校验配置，避免错误在 CloudFormation 部署阶段才暴露
+	if validator, ok := merged.(config.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			return fmt.Errorf("invalid config for %s: %v", merged.GetID(), err)
+		}
+	}
+
+	backupPlan := ""
+	if target, ok := merged.(config.BackupTarget); ok {
+		backupPlan = target.GetBackupPlan()
+	}
+	if backupPlan != "" {
+		if fm.backup == nil {
+			return fmt.Errorf("invalid config for %s: backupPlan requires a top-level backup section", merged.GetID())
+		}
+		if err := fm.backup.CheckPlan(backupPlan); err != nil {
+			return fmt.Errorf("invalid config for %s: %v", merged.GetID(), err)
+		}
+	}
 
 	// 按需创建共享资源（简化版）
 	fm.createSharedResourcesForInstance(merged)
 
 	// 创建 ForgeContext
-	ctx := fm.createForgeContext(merged)
+	ctx := fm.createForgeContext(typ, merged)
+
+	// 记录实例使用的安全组，需在 Create 之前获取原始 ID（EC2 多实例会修改 ID）
+	forgeRef := fmt.Sprintf("%s:%s", strings.ToUpper(typ), merged.GetID())
+	fm.instanceSecurityGroups[forgeRef] = ctx.SecurityGroups.Default
+
+	// 之后添加的安全组规则均记录为该实例所添加，用于审计报告
+	security.GlobalRuleRegistry.SetCurrentForge(forgeRef)
+	defer security.GlobalRuleRegistry.SetCurrentForge("")
 
 	// 执行 forge 操作
 	iforge := forge.Create(ctx)
@@ -155,45 +244,112 @@ func (fm *ForgeManager) processForge(typ string, rawDefaults json.RawMessage, in
 	dependency.GlobalManager.Store(storeKey, iforge)
 	
 	forge.ConfigureRules(ctx)
+
+	// 应用声明式入站规则
+	if err := security.ApplyIngressRules(ctx.SecurityGroups.Default, merged.GetIngress(), fm.securityGroups, fm.resolveInstanceSecurityGroup, fm.dualStack); err != nil {
+		return fmt.Errorf("error applying ingress rules for %s: %v", merged.GetID(), err)
+	}
+
 	forge.CreateOutputs(ctx)
 
+	// 加入备份计划，保管库和计划在所有 Forge 创建后统一生成
+	if backupPlan != "" {
+		if err := fm.backup.Protect(backupPlan, storeKey, iforge); err != nil {
+			return fmt.Errorf("error adding %s to backup plan: %v", merged.GetID(), err)
+		}
+	}
+
+	// 发布属性供其他堆栈通过 "TYPE:ext/<stack>/<id>" 引用
+	if fm.external != nil && len(fm.external.Publish) > 0 {
+		if properties, err := dependency.GlobalManager.GetProperties(storeKey); err == nil {
+			dependency.PublishProperties(fm.stack, fm.external.Publish, fm.external.SsmPrefix, *fm.stack.StackName(), storeKey, properties)
+		}
+	}
+
 	return nil
 }
 
-// createSharedResourcesForInstance 为单个实例创建所需的共享资源
-func (fm *ForgeManager) createSharedResourcesForInstance(instance config.InstanceConfig) {
+// resolveInstanceSecurityGroup 根据 "type:id" 查找已创建 Forge 实例使用的安全组
+func (fm *ForgeManager) resolveInstanceSecurityGroup(forgeRef string) (awsec2.SecurityGroup, error) {
+	parts := strings.SplitN(forgeRef, ":", 2)
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid forge reference '%s'", forgeRef)
+	}
+	key := fmt.Sprintf("%s:%s", strings.ToUpper(strings.TrimSpace(parts[0])), strings.TrimSpace(parts[1]))
+	sg, ok := fm.instanceSecurityGroups[key]
+	if !ok {
+		return nil, fmt.Errorf("forge '%s' not found, make sure it is listed before this instance in enabledForges", forgeRef)
+	}
+	return sg, nil
+}
+
+// MergeInstanceConfig 使用 Forge 的 MergeConfigs 合并默认配置和实例配置
+func MergeInstanceConfig(forge interfaces.Forge, typ string, rawDefaults json.RawMessage, inst config.InstanceConfig) (config.InstanceConfig, error) {
+	defaults := registry.CreateInstance(typ)
+	if err := json.Unmarshal(rawDefaults, defaults); err != nil {
+		return nil, fmt.Errorf("error parsing defaults: %v", err)
+	}
+
+	merged := forge.MergeConfigs(defaults, inst)
+	config.MergeSecurityFields(merged, inst)
+	return merged, nil
+}
+
+// 共享资源类型
+const (
+	SharedKeyPair         = "keyPair"
+	SharedPlacementGroup  = "placementGroup"
+	SharedInstanceProfile = "instanceProfile"
+)
+
+// SharedResource 实例按需创建的共享资源，Name 为空表示使用堆栈名称
+type SharedResource struct {
+	Kind   string `json:"kind"`
+	Name   string `json:"name"`
+	Detail string `json:"detail,omitempty"` // 密钥对的操作系统类型或放置组策略
+}
+
+// SharedResourcesForInstance 返回实例需要的共享资源（密钥对、放置组、实例配置文件）
+func SharedResourcesForInstance(instance config.InstanceConfig) []SharedResource {
+	var resources []SharedResource
 	switch inst := instance.(type) {
 	case *ec2.Ec2InstanceConfig:
-		keyName := inst.KeyName
-		if keyName == "" {
-			keyName = *awscdk.Aws_STACK_NAME()
-		}
-		aws.CreateOrGetKeyPair(fm.stack, keyName, inst.OsType)
-		
+		resources = append(resources, SharedResource{Kind: SharedKeyPair, Name: inst.KeyName, Detail: inst.OsType})
 		if inst.PlacementGroup != "" {
-			aws.CreateOrGetPlacementGroup(fm.stack, inst.PlacementGroup, inst.PlacementGroupStrategy)
+			resources = append(resources, SharedResource{Kind: SharedPlacementGroup, Name: inst.PlacementGroup, Detail: inst.PlacementGroupStrategy})
 		}
 		if inst.Policies != "" {
-			aws.CreateOrGetInstanceProfile(fm.stack, inst.Policies)
+			resources = append(resources, SharedResource{Kind: SharedInstanceProfile, Name: inst.Policies})
 		}
-		
+
 	case *eks.EksInstanceConfig:
-		keyName := inst.KeyName
-		if keyName == "" {
-			keyName = *awscdk.Aws_STACK_NAME()
-		}
-		aws.CreateOrGetKeyPair(fm.stack, keyName, inst.OsType)
-		
+		resources = append(resources, SharedResource{Kind: SharedKeyPair, Name: inst.KeyName, Detail: inst.OsType})
+
 	case *parallelcluster.ParallelClusterInstanceConfig:
-		keyName := inst.KeyName
-		if keyName == "" {
-			keyName = *awscdk.Aws_STACK_NAME()
+		resources = append(resources, SharedResource{Kind: SharedKeyPair, Name: inst.KeyName, Detail: "Linux"})
+	}
+	return resources
+}
+
+// createSharedResourcesForInstance 为单个实例创建所需的共享资源
+func (fm *ForgeManager) createSharedResourcesForInstance(instance config.InstanceConfig) {
+	for _, resource := range SharedResourcesForInstance(instance) {
+		switch resource.Kind {
+		case SharedKeyPair:
+			keyName := resource.Name
+			if keyName == "" {
+				keyName = *awscdk.Aws_STACK_NAME()
+			}
+			aws.CreateOrGetKeyPair(fm.stack, keyName, resource.Detail)
+		case SharedPlacementGroup:
+			aws.CreateOrGetPlacementGroup(fm.stack, resource.Name, resource.Detail)
+		case SharedInstanceProfile:
+			aws.CreateOrGetInstanceProfile(fm.stack, resource.Name)
 		}
-		aws.CreateOrGetKeyPair(fm.stack, keyName, "Linux")
 	}
 }
 
-func (fm *ForgeManager) createForgeContext(merged config.InstanceConfig) *interfaces.ForgeContext {
+func (fm *ForgeManager) createForgeContext(typ string, merged config.InstanceConfig) *interfaces.ForgeContext {
 	subnetType := fm.getSubnetType(merged.GetSubnet())
 	
 	// 根据实例配置动态选择默认安全组
@@ -207,6 +363,19 @@ func (fm *ForgeManager) createForgeContext(merged config.InstanceConfig) *interf
 		defaultSG = fm.securityGroups.Private
 	}
 
+	// 独立安全组：入站规则不与同层级的其他实例共享，构造 ID 包含类型以区分同名的不同类型实例
+	// 登记为层级成员后，存储、数据库等授予层级的访问同样授予独立安全组
+	if merged.GetDedicatedSecurityGroup() {
+		tierSG := defaultSG
+		defaultSG = awsec2.NewSecurityGroup(fm.stack, jsii.String(fmt.Sprintf("%s-%s-SecurityGroup", strings.ToUpper(typ), merged.GetID())), &awsec2.SecurityGroupProps{
+			Vpc:                  fm.vpc,
+			Description:          jsii.String(fmt.Sprintf("Dedicated security group for %s:%s", strings.ToUpper(typ), merged.GetID())),
+			AllowAllIpv6Outbound: jsii.Bool(fm.dualStack),
+			AllowAllOutbound:     jsii.Bool(true),
+		})
+		security.GlobalRuleRegistry.AddSecurityGroupMember(tierSG, defaultSG)
+	}
+
 	// 为这个特定的 forge 创建定制的 SecurityGroups
 	forgeSecurityGroups := &interfaces.SecurityGroups{
 		Default:  defaultSG,  // 根据配置动态设置
@@ -0,0 +1,187 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	utilsSecurity "github.com/awslabs/InfraForge/core/utils/security"
+)
+
+// 审计发现的严重级别
+const (
+	SeverityHigh   = "HIGH"
+	SeverityMedium = "MEDIUM"
+)
+
+// AuditFinding 审计发现的问题规则
+type AuditFinding struct {
+	Severity string     `json:"severity"`
+	Message  string     `json:"message"`
+	Rule     RuleRecord `json:"rule"`
+}
+
+// SecurityGroupAudit 单个安全组的规则矩阵
+type SecurityGroupAudit struct {
+	SecurityGroup string       `json:"securityGroup"`
+	Rules         []RuleRecord `json:"rules"`
+}
+
+// AuditReport 安全组规则审计报告
+type AuditReport struct {
+	SecurityGroups []SecurityGroupAudit `json:"securityGroups"`
+	Findings       []AuditFinding       `json:"findings"`
+}
+
+// BuildAuditReport 根据规则记录生成审计报告，按安全组分组并标记过于宽泛的规则
+func BuildAuditReport(records []RuleRecord) *AuditReport {
+	report := &AuditReport{
+		SecurityGroups: []SecurityGroupAudit{},
+		Findings:       []AuditFinding{},
+	}
+
+	groups := make(map[string][]RuleRecord)
+	for _, record := range records {
+		groups[record.SecurityGroup] = append(groups[record.SecurityGroup], record)
+		if finding := auditRule(record); finding != nil {
+			report.Findings = append(report.Findings, *finding)
+		}
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		report.SecurityGroups = append(report.SecurityGroups, SecurityGroupAudit{
+			SecurityGroup: name,
+			Rules:         groups[name],
+		})
+	}
+
+	return report
+}
+
+// auditRule 检查单条规则是否对公网过度开放
+func auditRule(record RuleRecord) *AuditFinding {
+	if record.Direction != "ingress" {
+		return nil
+	}
+	if record.Source != "0.0.0.0/0" && record.Source != "::/0" {
+		return nil
+	}
+
+	if record.Protocol == "all" {
+		return &AuditFinding{
+			Severity: SeverityHigh,
+			Message:  fmt.Sprintf("all traffic is open to %s", record.Source),
+			Rule:     record,
+		}
+	}
+
+	// 管理端口对公网开放复用 allowedPorts 的检查逻辑
+	warnings := utilsSecurity.LintPortRules([]utilsSecurity.PortRule{{
+		FromPort: record.FromPort,
+		ToPort:   record.ToPort,
+		Cidr:     record.Source,
+		Protocol: record.Protocol,
+	}}, nil)
+	if len(warnings) > 0 {
+		return &AuditFinding{
+			Severity: SeverityMedium,
+			Message:  fmt.Sprintf("admin port %s is open to %s", formatPorts(record), record.Source),
+			Rule:     record,
+		}
+	}
+	return nil
+}
+
+// formatPorts 将端口范围格式化为可读字符串
+func formatPorts(record RuleRecord) string {
+	switch {
+	case record.Protocol == "all" || record.Protocol == "icmp":
+		return "all"
+	case record.FromPort == record.ToPort:
+		return strconv.Itoa(record.FromPort)
+	default:
+		return fmt.Sprintf("%d-%d", record.FromPort, record.ToPort)
+	}
+}
+
+// WriteAuditReport 按指定格式（table、json、csv）输出审计报告
+func WriteAuditReport(w io.Writer, report *AuditReport, format string) error {
+	switch format {
+	case "", "table":
+		return writeAuditTable(w, report)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case "csv":
+		return writeAuditCSV(w, report)
+	default:
+		return fmt.Errorf("unsupported audit format '%s', expected table, json or csv", format)
+	}
+}
+
+func writeAuditTable(w io.Writer, report *AuditReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, group := range report.SecurityGroups {
+		fmt.Fprintf(tw, "Security Group: %s\n", group.SecurityGroup)
+		fmt.Fprintln(tw, "DIRECTION\tPROTOCOL\tPORTS\tSOURCE\tFORGE\tDESCRIPTION")
+		for _, rule := range group.Rules {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+				rule.Direction, rule.Protocol, formatPorts(rule), rule.Source, rule.Forge, rule.Description)
+		}
+		fmt.Fprintln(tw)
+	}
+
+	if len(report.Findings) > 0 {
+		fmt.Fprintln(tw, "Findings:")
+		fmt.Fprintln(tw, "SEVERITY\tSECURITY GROUP\tFORGE\tMESSAGE")
+		for _, finding := range report.Findings {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+				finding.Severity, finding.Rule.SecurityGroup, finding.Rule.Forge, finding.Message)
+		}
+	} else {
+		fmt.Fprintln(tw, "No overly broad rules found.")
+	}
+	return tw.Flush()
+}
+
+func writeAuditCSV(w io.Writer, report *AuditReport) error {
+	// 每条规则一行，finding 列为空表示无问题
+	findings := make(map[RuleRecord]string)
+	for _, finding := range report.Findings {
+		findings[finding.Rule] = finding.Severity + ": " + finding.Message
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"securityGroup", "direction", "sourceType", "source", "protocol", "fromPort", "toPort", "description", "forge", "finding"})
+	for _, group := range report.SecurityGroups {
+		for _, rule := range group.Rules {
+			writer.Write([]string{
+				rule.SecurityGroup,
+				rule.Direction,
+				rule.SourceType,
+				rule.Source,
+				rule.Protocol,
+				strconv.Itoa(rule.FromPort),
+				strconv.Itoa(rule.ToPort),
+				rule.Description,
+				rule.Forge,
+				findings[rule],
+			})
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
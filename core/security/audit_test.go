@@ -0,0 +1,139 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testAuditRecords() []RuleRecord {
+	return []RuleRecord{
+		{SecurityGroup: "Stack/PublicSG", Direction: "ingress", SourceType: "cidr", Source: "0.0.0.0/0", Protocol: "tcp", FromPort: 22, ToPort: 22, Description: "SSH", Forge: "EC2:bastion"},
+		{SecurityGroup: "Stack/PublicSG", Direction: "ingress", SourceType: "cidr", Source: "0.0.0.0/0", Protocol: "tcp", FromPort: 443, ToPort: 443, Description: "HTTPS", Forge: "VPC"},
+		{SecurityGroup: "Stack/PublicSG", Direction: "ingress", SourceType: "cidrIpv6", Source: "::/0", Protocol: "all", Description: "Everything"},
+		{SecurityGroup: "Stack/PrivateSG", Direction: "ingress", SourceType: "cidr", Source: "0.0.0.0/0", Protocol: "tcp", FromPort: 3000, ToPort: 3400, Description: "Range with RDP"},
+		{SecurityGroup: "Stack/PrivateSG", Direction: "ingress", SourceType: "securityGroup", Source: "Stack/PublicSG", Protocol: "all", Description: "From public"},
+		{SecurityGroup: "Stack/PrivateSG", Direction: "egress", SourceType: "securityGroup", Source: "Stack/PrivateSG", Protocol: "all", Description: "EFA"},
+		{SecurityGroup: "Stack/PrivateSG", Direction: "ingress", SourceType: "cidr", Source: "0.0.0.0/0", Protocol: "icmp", Description: "Ping"},
+	}
+}
+
+func TestBuildAuditReport(t *testing.T) {
+	report := BuildAuditReport(testAuditRecords())
+
+	if len(report.SecurityGroups) != 2 {
+		t.Fatalf("Expected 2 security groups, got %d", len(report.SecurityGroups))
+	}
+	// 安全组按名称排序，组内保持添加顺序
+	if report.SecurityGroups[0].SecurityGroup != "Stack/PrivateSG" || report.SecurityGroups[1].SecurityGroup != "Stack/PublicSG" {
+		t.Errorf("Unexpected security group order: %s, %s", report.SecurityGroups[0].SecurityGroup, report.SecurityGroups[1].SecurityGroup)
+	}
+	if len(report.SecurityGroups[1].Rules) != 3 || report.SecurityGroups[1].Rules[0].Description != "SSH" {
+		t.Errorf("Unexpected rules for PublicSG: %+v", report.SecurityGroups[1].Rules)
+	}
+
+	expected := []struct {
+		severity string
+		message  string
+	}{
+		{SeverityMedium, "admin port 22 is open to 0.0.0.0/0"},
+		{SeverityHigh, "all traffic is open to ::/0"},
+		{SeverityMedium, "admin port 3000-3400 is open to 0.0.0.0/0"},
+	}
+	if len(report.Findings) != len(expected) {
+		t.Fatalf("Expected %d findings, got %d: %+v", len(expected), len(report.Findings), report.Findings)
+	}
+	for i, want := range expected {
+		if report.Findings[i].Severity != want.severity || report.Findings[i].Message != want.message {
+			t.Errorf("Finding %d: expected %s %q, got %s %q", i, want.severity, want.message, report.Findings[i].Severity, report.Findings[i].Message)
+		}
+	}
+}
+
+func TestBuildAuditReportEmpty(t *testing.T) {
+	report := BuildAuditReport(nil)
+	var buf bytes.Buffer
+	if err := WriteAuditReport(&buf, report, "json"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	// 空报告输出空数组而不是 null
+	if !strings.Contains(buf.String(), `"securityGroups": []`) || !strings.Contains(buf.String(), `"findings": []`) {
+		t.Errorf("Expected empty arrays, got %s", buf.String())
+	}
+}
+
+func TestWriteAuditReport(t *testing.T) {
+	report := BuildAuditReport(testAuditRecords())
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteAuditReport(&buf, report, "table"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, want := range []string{"Security Group: Stack/PublicSG", "EC2:bastion", "Findings:", "admin port 22 is open to 0.0.0.0/0"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("Expected table to contain %q:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteAuditReport(&buf, report, "json"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		var decoded AuditReport
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if len(decoded.SecurityGroups) != 2 || len(decoded.Findings) != 3 {
+			t.Errorf("Unexpected decoded report: %+v", decoded)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteAuditReport(&buf, report, "csv"); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		rows, err := csv.NewReader(&buf).ReadAll()
+		if err != nil {
+			t.Fatalf("Invalid CSV: %v", err)
+		}
+		if len(rows) != 8 {
+			t.Fatalf("Expected header and 7 rows, got %d", len(rows))
+		}
+		// 第一个安全组为 PrivateSG，其第一条规则包含 RDP 端口
+		if rows[1][0] != "Stack/PrivateSG" || rows[1][9] != "MEDIUM: admin port 3000-3400 is open to 0.0.0.0/0" {
+			t.Errorf("Unexpected first row: %v", rows[1])
+		}
+		if rows[2][9] != "" {
+			t.Errorf("Expected no finding for security group source, got %q", rows[2][9])
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := WriteAuditReport(&buf, report, "yaml"); err == nil {
+			t.Errorf("Expected error for unsupported format")
+		}
+	})
+}
+
+func TestNoFindingsTable(t *testing.T) {
+	report := BuildAuditReport([]RuleRecord{
+		{SecurityGroup: "Stack/PrivateSG", Direction: "ingress", SourceType: "cidr", Source: "10.0.0.0/8", Protocol: "tcp", FromPort: 22, ToPort: 22},
+	})
+	var buf bytes.Buffer
+	if err := WriteAuditReport(&buf, report, ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(buf.String(), "No overly broad rules found.") {
+		t.Errorf("Expected no findings message, got:\n%s", buf.String())
+	}
+}
@@ -10,103 +10,137 @@ import (
 // 安全组到安全组的规则
 
 // AddTcpIngressRule 是一个辅助函数，用于添加 TCP 入站规则
-func AddTcpIngressRule(targetSG, sourceSG awsec2.SecurityGroup, port int, description string) {
+func AddTcpIngressRule(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, port int, description string) {
 	GlobalRuleRegistry.AddTcpIngressRuleSafely(targetSG, sourceSG, port, description)
 }
 
 // AddTcpRangeIngressRule 是一个辅助函数，用于添加 TCP 端口范围入站规则
-func AddTcpRangeIngressRule(targetSG, sourceSG awsec2.SecurityGroup, fromPort, toPort int, description string) {
+func AddTcpRangeIngressRule(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, fromPort, toPort int, description string) {
 	GlobalRuleRegistry.AddTcpRangeIngressRuleSafely(targetSG, sourceSG, fromPort, toPort, description)
 }
 
 // AddUdpIngressRule 是一个辅助函数，用于添加 UDP 入站规则
-func AddUdpIngressRule(targetSG, sourceSG awsec2.SecurityGroup, port int, description string) {
+func AddUdpIngressRule(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, port int, description string) {
 	GlobalRuleRegistry.AddUdpIngressRuleSafely(targetSG, sourceSG, port, description)
 }
 
 // AddUdpRangeIngressRule 是一个辅助函数，用于添加 UDP 端口范围入站规则
-func AddUdpRangeIngressRule(targetSG, sourceSG awsec2.SecurityGroup, fromPort, toPort int, description string) {
+func AddUdpRangeIngressRule(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, fromPort, toPort int, description string) {
 	GlobalRuleRegistry.AddUdpRangeIngressRuleSafely(targetSG, sourceSG, fromPort, toPort, description)
 }
 
 // AddAllTrafficIngressRule 是一个辅助函数，用于添加允许所有流量的入站规则
-func AddAllTrafficIngressRule(targetSG, sourceSG awsec2.SecurityGroup, description string) {
+func AddAllTrafficIngressRule(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, description string) {
 	GlobalRuleRegistry.AddAllTrafficIngressRuleSafely(targetSG, sourceSG, description)
 }
 
 // IPv4 CIDR 到安全组的规则
 
 // AddTcpIngressRuleFromCidr 是一个辅助函数，用于添加来自特定 IPv4 CIDR 的 TCP 入站规则
-func AddTcpIngressRuleFromCidr(targetSG awsec2.SecurityGroup, cidr string, port int, description string) {
+func AddTcpIngressRuleFromCidr(targetSG awsec2.ISecurityGroup, cidr string, port int, description string) {
 	GlobalRuleRegistry.AddTcpIngressRuleFromCidrSafely(targetSG, cidr, port, description)
 }
 
 // AddTcpRangeIngressRuleFromCidr 是一个辅助函数，用于添加来自特定 IPv4 CIDR 的 TCP 端口范围入站规则
-func AddTcpRangeIngressRuleFromCidr(targetSG awsec2.SecurityGroup, cidr string, fromPort, toPort int, description string) {
+func AddTcpRangeIngressRuleFromCidr(targetSG awsec2.ISecurityGroup, cidr string, fromPort, toPort int, description string) {
 	GlobalRuleRegistry.AddTcpRangeIngressRuleFromCidrSafely(targetSG, cidr, fromPort, toPort, description)
 }
 
 // AddUdpIngressRuleFromCidr 是一个辅助函数，用于添加来自特定 IPv4 CIDR 的 UDP 入站规则
-func AddUdpIngressRuleFromCidr(targetSG awsec2.SecurityGroup, cidr string, port int, description string) {
+func AddUdpIngressRuleFromCidr(targetSG awsec2.ISecurityGroup, cidr string, port int, description string) {
 	GlobalRuleRegistry.AddUdpIngressRuleFromCidrSafely(targetSG, cidr, port, description)
 }
 
 // AddUdpRangeIngressRuleFromCidr 是一个辅助函数，用于添加来自特定 IPv4 CIDR 的 UDP 端口范围入站规则
-func AddUdpRangeIngressRuleFromCidr(targetSG awsec2.SecurityGroup, cidr string, fromPort, toPort int, description string) {
+func AddUdpRangeIngressRuleFromCidr(targetSG awsec2.ISecurityGroup, cidr string, fromPort, toPort int, description string) {
 	GlobalRuleRegistry.AddUdpRangeIngressRuleFromCidrSafely(targetSG, cidr, fromPort, toPort, description)
 }
 
 // AddAllTrafficIngressRuleFromCidr 是一个辅助函数，用于添加允许所有流量的入站规则
-func AddAllTrafficIngressRuleFromCidr(targetSG awsec2.SecurityGroup, cidr string, description string) {
+func AddAllTrafficIngressRuleFromCidr(targetSG awsec2.ISecurityGroup, cidr string, description string) {
 	GlobalRuleRegistry.AddAllTrafficIngressRuleFromCidrSafely(targetSG, cidr, description)
 }
 
 // IPv6 CIDR 到安全组的规则
 
 // AddTcpIngressRuleFromCidrIpv6 是一个辅助函数，用于添加来自特定 IPv6 CIDR 的 TCP 入站规则
-func AddTcpIngressRuleFromCidrIpv6(targetSG awsec2.SecurityGroup, cidr string, port int, description string) {
+func AddTcpIngressRuleFromCidrIpv6(targetSG awsec2.ISecurityGroup, cidr string, port int, description string) {
 	GlobalRuleRegistry.AddTcpIngressRuleFromCidrIpv6Safely(targetSG, cidr, port, description)
 }
 
 // AddTcpRangeIngressRuleFromCidrIpv6 是一个辅助函数，用于添加来自特定 IPv6 CIDR 的 TCP 端口范围入站规则
-func AddTcpRangeIngressRuleFromCidrIpv6(targetSG awsec2.SecurityGroup, cidr string, fromPort, toPort int, description string) {
+func AddTcpRangeIngressRuleFromCidrIpv6(targetSG awsec2.ISecurityGroup, cidr string, fromPort, toPort int, description string) {
 	GlobalRuleRegistry.AddTcpRangeIngressRuleFromCidrIpv6Safely(targetSG, cidr, fromPort, toPort, description)
 }
 
 // AddUdpIngressRuleFromCidrIpv6 是一个辅助函数，用于添加来自特定 IPv6 CIDR 的 UDP 入站规则
-func AddUdpIngressRuleFromCidrIpv6(targetSG awsec2.SecurityGroup, cidr string, port int, description string) {
+func AddUdpIngressRuleFromCidrIpv6(targetSG awsec2.ISecurityGroup, cidr string, port int, description string) {
 	GlobalRuleRegistry.AddUdpIngressRuleFromCidrIpv6Safely(targetSG, cidr, port, description)
 }
 
 // AddUdpRangeIngressRuleFromCidrIpv6 是一个辅助函数，用于添加来自特定 IPv6 CIDR 的 UDP 端口范围入站规则
-func AddUdpRangeIngressRuleFromCidrIpv6(targetSG awsec2.SecurityGroup, cidr string, fromPort, toPort int, description string) {
+func AddUdpRangeIngressRuleFromCidrIpv6(targetSG awsec2.ISecurityGroup, cidr string, fromPort, toPort int, description string) {
 	GlobalRuleRegistry.AddUdpRangeIngressRuleFromCidrIpv6Safely(targetSG, cidr, fromPort, toPort, description)
 }
 
 // AddAllTrafficIngressRuleFromCidrIpv6 是一个辅助函数，用于添加允许所有流量的入站规则
-func AddAllTrafficIngressRuleFromCidrIpv6(targetSG awsec2.SecurityGroup, cidr string, description string) {
+func AddAllTrafficIngressRuleFromCidrIpv6(targetSG awsec2.ISecurityGroup, cidr string, description string) {
 	GlobalRuleRegistry.AddAllTrafficIngressRuleFromCidrIpv6Safely(targetSG, cidr, description)
 }
 
+// ICMP 规则
+
+// AddIcmpIngressRule 是一个辅助函数，用于添加来自安全组的 ICMP 入站规则
+func AddIcmpIngressRule(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, description string) {
+	GlobalRuleRegistry.AddIcmpIngressRuleSafely(targetSG, sourceSG, description)
+}
+
+// AddIcmpIngressRuleFromCidr 是一个辅助函数，用于添加来自特定 IPv4 CIDR 的 ICMP 入站规则
+func AddIcmpIngressRuleFromCidr(targetSG awsec2.ISecurityGroup, cidr string, description string) {
+	GlobalRuleRegistry.AddIcmpIngressRuleFromCidrSafely(targetSG, cidr, description)
+}
+
+// AddIcmpIngressRuleFromCidrIpv6 是一个辅助函数，用于添加来自特定 IPv6 CIDR 的 ICMPv6 入站规则
+func AddIcmpIngressRuleFromCidrIpv6(targetSG awsec2.ISecurityGroup, cidr string, description string) {
+	GlobalRuleRegistry.AddIcmpIngressRuleFromCidrIpv6Safely(targetSG, cidr, description)
+}
+
+// 托管前缀列表到安全组的规则
+
+// AddTcpRangeIngressRuleFromPrefixList 是一个辅助函数，用于添加来自托管前缀列表的 TCP 端口范围入站规则
+func AddTcpRangeIngressRuleFromPrefixList(targetSG awsec2.ISecurityGroup, prefixListId string, fromPort, toPort int, description string) {
+	GlobalRuleRegistry.AddTcpRangeIngressRuleFromPrefixListSafely(targetSG, prefixListId, fromPort, toPort, description)
+}
+
+// AddUdpRangeIngressRuleFromPrefixList 是一个辅助函数，用于添加来自托管前缀列表的 UDP 端口范围入站规则
+func AddUdpRangeIngressRuleFromPrefixList(targetSG awsec2.ISecurityGroup, prefixListId string, fromPort, toPort int, description string) {
+	GlobalRuleRegistry.AddUdpRangeIngressRuleFromPrefixListSafely(targetSG, prefixListId, fromPort, toPort, description)
+}
+
+// AddAllTrafficIngressRuleFromPrefixList 是一个辅助函数，用于添加允许来自托管前缀列表的所有流量的入站规则
+func AddAllTrafficIngressRuleFromPrefixList(targetSG awsec2.ISecurityGroup, prefixListId string, description string) {
+	GlobalRuleRegistry.AddAllTrafficIngressRuleFromPrefixListSafely(targetSG, prefixListId, description)
+}
+
 // 便捷函数 - 任意 IP 地址
 
 // AddTcpIngressRuleFromAnyIp 是一个辅助函数，用于添加来自任意 IPv4 地址的 TCP 入站规则
-func AddTcpIngressRuleFromAnyIp(targetSG awsec2.SecurityGroup, port int, description string) {
+func AddTcpIngressRuleFromAnyIp(targetSG awsec2.ISecurityGroup, port int, description string) {
 	AddTcpIngressRuleFromCidr(targetSG, "0.0.0.0/0", port, description)
 }
 
 // AddTcpIngressRuleFromAnyIpv6 是一个辅助函数，用于添加来自任意 IPv6 地址的 TCP 入站规则
-func AddTcpIngressRuleFromAnyIpv6(targetSG awsec2.SecurityGroup, port int, description string) {
+func AddTcpIngressRuleFromAnyIpv6(targetSG awsec2.ISecurityGroup, port int, description string) {
 	AddTcpIngressRuleFromCidrIpv6(targetSG, "::/0", port, description)
 }
 
 // AddAllTrafficIngressRuleFromAnyIp 是一个辅助函数，用于添加允许来自任意 IPv4 地址的所有流量的入站规则
-func AddAllTrafficIngressRuleFromAnyIp(targetSG awsec2.SecurityGroup, description string) {
+func AddAllTrafficIngressRuleFromAnyIp(targetSG awsec2.ISecurityGroup, description string) {
 	AddAllTrafficIngressRuleFromCidr(targetSG, "0.0.0.0/0", description)
 }
 
 // AddAllTrafficIngressRuleFromAnyIpv6 是一个辅助函数，用于添加允许来自任意 IPv6 地址的所有流量的入站规则
-func AddAllTrafficIngressRuleFromAnyIpv6(targetSG awsec2.SecurityGroup, description string) {
+func AddAllTrafficIngressRuleFromAnyIpv6(targetSG awsec2.ISecurityGroup, description string) {
 	AddAllTrafficIngressRuleFromCidrIpv6(targetSG, "::/0", description)
 }
 
@@ -0,0 +1,189 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/interfaces"
+	utilsSecurity "github.com/awslabs/InfraForge/core/utils/security"
+)
+
+// IngressSourceResolver 将 "type:id" 格式的 Forge 引用（如 "EC2:bastion"）解析为该实例使用的安全组
+type IngressSourceResolver func(forgeRef string) (awsec2.SecurityGroup, error)
+
+// ApplyIngressRules 应用声明式入站规则到安全组
+func ApplyIngressRules(targetSG awsec2.SecurityGroup, rules []config.IngressRule, tiers *interfaces.SecurityGroups, resolve IngressSourceResolver, dualStack bool) error {
+	for i, rule := range rules {
+		protocol := strings.ToLower(strings.TrimSpace(rule.Protocol))
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		if protocol != "tcp" && protocol != "udp" && protocol != "both" && protocol != "icmp" && protocol != "all" {
+			return fmt.Errorf("ingress[%d]: unsupported protocol %q", i, rule.Protocol)
+		}
+
+		var portRanges []utilsSecurity.PortRange
+		if protocol != "all" && protocol != "icmp" {
+			var err error
+			portRanges, err = utilsSecurity.ParsePortRanges(rule.Ports)
+			if err != nil {
+				return fmt.Errorf("ingress[%d]: %w", i, err)
+			}
+			if len(portRanges) == 0 {
+				return fmt.Errorf("ingress[%d]: ports are required for protocol %q", i, protocol)
+			}
+		}
+
+		source := strings.TrimSpace(rule.Source)
+		description := rule.Description
+		if description == "" {
+			description = fmt.Sprintf("Allow %s %s from %s", protocol, rule.Ports, source)
+		}
+
+		switch {
+		case strings.Contains(source, "/") && strings.Contains(source, ":"):
+			// IPv6 CIDR，仅在双栈模式下可用
+			if !dualStack {
+				return fmt.Errorf("ingress[%d]: IPv6 source %s requires dualStack", i, source)
+			}
+			applyIngressFromCidrIpv6(targetSG, source, protocol, portRanges, description)
+		case strings.Contains(source, "/"):
+			applyIngressFromCidr(targetSG, source, protocol, portRanges, description)
+		case utilsSecurity.IsPrefixListSource(source):
+			if protocol == "icmp" {
+				return fmt.Errorf("ingress[%d]: protocol icmp is not supported for prefix list sources", i)
+			}
+			prefixListId, err := ResolvePrefixList(source)
+			if err != nil {
+				return fmt.Errorf("ingress[%d]: %w", i, err)
+			}
+			applyIngressFromPrefixList(targetSG, prefixListId, protocol, portRanges, description)
+		case source == "public" || source == "private" || source == "isolated":
+			applyIngressFromSecurityGroup(targetSG, tierSecurityGroup(tiers, source), protocol, portRanges, description)
+		case strings.Contains(source, ":"):
+			if resolve == nil {
+				return fmt.Errorf("ingress[%d]: cannot resolve forge reference %q", i, source)
+			}
+			sourceSG, err := resolve(source)
+			if err != nil {
+				return fmt.Errorf("ingress[%d]: %w", i, err)
+			}
+			applyIngressFromSecurityGroup(targetSG, sourceSG, protocol, portRanges, description)
+		default:
+			return fmt.Errorf("ingress[%d]: unrecognized source %q", i, rule.Source)
+		}
+	}
+	return nil
+}
+
+func tierSecurityGroup(tiers *interfaces.SecurityGroups, tier string) awsec2.SecurityGroup {
+	switch tier {
+	case "public":
+		return tiers.Public
+	case "isolated":
+		return tiers.Isolated
+	default:
+		return tiers.Private
+	}
+}
+
+func applyIngressFromSecurityGroup(targetSG, sourceSG awsec2.SecurityGroup, protocol string, portRanges []utilsSecurity.PortRange, description string) {
+	if protocol == "all" {
+		AddAllTrafficIngressRule(targetSG, sourceSG, description)
+		return
+	}
+	if protocol == "icmp" {
+		AddIcmpIngressRule(targetSG, sourceSG, description)
+		return
+	}
+	for _, pr := range portRanges {
+		if protocol == "tcp" || protocol == "both" {
+			if pr.FromPort == pr.ToPort {
+				AddTcpIngressRule(targetSG, sourceSG, pr.FromPort, description)
+			} else {
+				AddTcpRangeIngressRule(targetSG, sourceSG, pr.FromPort, pr.ToPort, description)
+			}
+		}
+		if protocol == "udp" || protocol == "both" {
+			if pr.FromPort == pr.ToPort {
+				AddUdpIngressRule(targetSG, sourceSG, pr.FromPort, description)
+			} else {
+				AddUdpRangeIngressRule(targetSG, sourceSG, pr.FromPort, pr.ToPort, description)
+			}
+		}
+	}
+}
+
+func applyIngressFromCidr(targetSG awsec2.SecurityGroup, cidr string, protocol string, portRanges []utilsSecurity.PortRange, description string) {
+	if protocol == "all" {
+		AddAllTrafficIngressRuleFromCidr(targetSG, cidr, description)
+		return
+	}
+	if protocol == "icmp" {
+		AddIcmpIngressRuleFromCidr(targetSG, cidr, description)
+		return
+	}
+	for _, pr := range portRanges {
+		if protocol == "tcp" || protocol == "both" {
+			if pr.FromPort == pr.ToPort {
+				AddTcpIngressRuleFromCidr(targetSG, cidr, pr.FromPort, description)
+			} else {
+				AddTcpRangeIngressRuleFromCidr(targetSG, cidr, pr.FromPort, pr.ToPort, description)
+			}
+		}
+		if protocol == "udp" || protocol == "both" {
+			if pr.FromPort == pr.ToPort {
+				AddUdpIngressRuleFromCidr(targetSG, cidr, pr.FromPort, description)
+			} else {
+				AddUdpRangeIngressRuleFromCidr(targetSG, cidr, pr.FromPort, pr.ToPort, description)
+			}
+		}
+	}
+}
+
+func applyIngressFromCidrIpv6(targetSG awsec2.SecurityGroup, cidr string, protocol string, portRanges []utilsSecurity.PortRange, description string) {
+	if protocol == "all" {
+		AddAllTrafficIngressRuleFromCidrIpv6(targetSG, cidr, description)
+		return
+	}
+	if protocol == "icmp" {
+		AddIcmpIngressRuleFromCidrIpv6(targetSG, cidr, description)
+		return
+	}
+	for _, pr := range portRanges {
+		if protocol == "tcp" || protocol == "both" {
+			if pr.FromPort == pr.ToPort {
+				AddTcpIngressRuleFromCidrIpv6(targetSG, cidr, pr.FromPort, description)
+			} else {
+				AddTcpRangeIngressRuleFromCidrIpv6(targetSG, cidr, pr.FromPort, pr.ToPort, description)
+			}
+		}
+		if protocol == "udp" || protocol == "both" {
+			if pr.FromPort == pr.ToPort {
+				AddUdpIngressRuleFromCidrIpv6(targetSG, cidr, pr.FromPort, description)
+			} else {
+				AddUdpRangeIngressRuleFromCidrIpv6(targetSG, cidr, pr.FromPort, pr.ToPort, description)
+			}
+		}
+	}
+}
+
+func applyIngressFromPrefixList(targetSG awsec2.SecurityGroup, prefixListId string, protocol string, portRanges []utilsSecurity.PortRange, description string) {
+	if protocol == "all" {
+		AddAllTrafficIngressRuleFromPrefixList(targetSG, prefixListId, description)
+		return
+	}
+	for _, pr := range portRanges {
+		if protocol == "tcp" || protocol == "both" {
+			AddTcpRangeIngressRuleFromPrefixList(targetSG, prefixListId, pr.FromPort, pr.ToPort, description)
+		}
+		if protocol == "udp" || protocol == "both" {
+			AddUdpRangeIngressRuleFromPrefixList(targetSG, prefixListId, pr.FromPort, pr.ToPort, description)
+		}
+	}
+}
@@ -10,11 +10,25 @@ import (
 )
 
 // ApplyPortRules 应用端口规则到安全组
-func ApplyPortRules(targetSG awsec2.SecurityGroup, allowedPorts, allowedPortsIpv6 string, dualStack bool) {
+// 端口配置使用严格解析，存在格式错误或前缀列表无法解析时返回错误且不应用任何规则，避免只开放部分端口
+func ApplyPortRules(targetSG awsec2.SecurityGroup, allowedPorts, allowedPortsIpv6 string, dualStack bool) error {
+	rules, ipv6Rules, err := parseAllowedPortRules(allowedPorts, allowedPortsIpv6, dualStack)
+	if err != nil {
+		return err
+	}
+
 	// 处理IPv4规则
-	if allowedPorts != "" {
-		rules := utilsSecurity.ParseAllowedPorts(allowedPorts)
-		for _, rule := range rules {
+	for _, rule := range rules {
+		if rule.PrefixList != "" {
+			applyPortRuleFromPrefixList(targetSG, rule)
+			continue
+		}
+		switch rule.Protocol {
+		case "icmp":
+			AddIcmpIngressRuleFromCidr(targetSG, rule.Cidr, "Allow ICMP")
+		case "all":
+			AddAllTrafficIngressRuleFromCidr(targetSG, rule.Cidr, "Allow all traffic")
+		default:
 			if rule.Port > 0 {
 				if rule.Protocol == "udp" {
 					AddUdpIngressRuleFromCidr(targetSG, rule.Cidr, rule.Port, fmt.Sprintf("Allow port %d UDP", rule.Port))
@@ -36,11 +50,19 @@ func ApplyPortRules(targetSG awsec2.SecurityGroup, allowedPorts, allowedPortsIpv
 			}
 		}
 	}
-	
+
 	// 处理IPv6规则
-	if dualStack && allowedPortsIpv6 != "" {
-		ipv6Rules := utilsSecurity.ParseAllowedPorts(allowedPortsIpv6)
-		for _, rule := range ipv6Rules {
+	for _, rule := range ipv6Rules {
+		if rule.PrefixList != "" {
+			applyPortRuleFromPrefixList(targetSG, rule)
+			continue
+		}
+		switch rule.Protocol {
+		case "icmp":
+			AddIcmpIngressRuleFromCidrIpv6(targetSG, rule.Cidr, "Allow ICMPv6")
+		case "all":
+			AddAllTrafficIngressRuleFromCidrIpv6(targetSG, rule.Cidr, "Allow all traffic IPv6")
+		default:
 			if rule.Port > 0 {
 				if rule.Protocol == "udp" {
 					AddUdpIngressRuleFromCidrIpv6(targetSG, rule.Cidr, rule.Port, fmt.Sprintf("Allow port %d UDP IPv6", rule.Port))
@@ -62,4 +84,65 @@ func ApplyPortRules(targetSG awsec2.SecurityGroup, allowedPorts, allowedPortsIpv
 			}
 		}
 	}
+	return nil
+}
+
+// ValidatePortPrefixLists 校验端口配置中引用的托管前缀列表都已在 VPC prefixLists 中定义
+// 需要在 VPC 创建并注册前缀列表之后调用，Forge 的 Validate 满足这一条件
+func ValidatePortPrefixLists(allowedPorts, allowedPortsIpv6 string) error {
+	_, _, err := parseAllowedPortRules(allowedPorts, allowedPortsIpv6, true)
+	return err
+}
+
+// parseAllowedPortRules 解析 IPv4 和 IPv6 端口配置，未开启双栈时忽略 IPv6 配置
+func parseAllowedPortRules(allowedPorts, allowedPortsIpv6 string, dualStack bool) (rules, ipv6Rules []utilsSecurity.PortRule, err error) {
+	if allowedPorts != "" {
+		if rules, err = parsePortRules(allowedPorts, false); err != nil {
+			return nil, nil, fmt.Errorf("invalid allowedPorts: %w", err)
+		}
+	}
+	if dualStack && allowedPortsIpv6 != "" {
+		if ipv6Rules, err = parsePortRules(allowedPortsIpv6, true); err != nil {
+			return nil, nil, fmt.Errorf("invalid allowedPortsIpv6: %w", err)
+		}
+	}
+	return rules, ipv6Rules, nil
+}
+
+// parsePortRules 严格解析端口配置，并确认其中引用的前缀列表都已定义
+func parsePortRules(allowedPorts string, ipv6 bool) ([]utilsSecurity.PortRule, error) {
+	rules, err := utilsSecurity.ParseAllowedPortsStrict(allowedPorts, ipv6)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.PrefixList == "" {
+			continue
+		}
+		if _, err := ResolvePrefixList(rule.PrefixList); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// applyPortRuleFromPrefixList 应用来源为托管前缀列表的端口规则，前缀列表已由 parsePortRules 确认可解析
+func applyPortRuleFromPrefixList(targetSG awsec2.SecurityGroup, rule utilsSecurity.PortRule) {
+	prefixListId, _ := ResolvePrefixList(rule.PrefixList)
+
+	if rule.Protocol == "all" {
+		AddAllTrafficIngressRuleFromPrefixList(targetSG, prefixListId, fmt.Sprintf("Allow all traffic from %s", rule.PrefixList))
+		return
+	}
+
+	fromPort, toPort := rule.FromPort, rule.ToPort
+	if rule.Port > 0 {
+		fromPort, toPort = rule.Port, rule.Port
+	}
+	if rule.Protocol == "tcp" || rule.Protocol == "both" {
+		AddTcpRangeIngressRuleFromPrefixList(targetSG, prefixListId, fromPort, toPort, fmt.Sprintf("Allow ports %d-%d TCP from %s", fromPort, toPort, rule.PrefixList))
+	}
+	if rule.Protocol == "udp" || rule.Protocol == "both" {
+		AddUdpRangeIngressRuleFromPrefixList(targetSG, prefixListId, fromPort, toPort, fmt.Sprintf("Allow ports %d-%d UDP from %s", fromPort, toPort, rule.PrefixList))
+	}
 }
@@ -0,0 +1,40 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RegisterPrefixList 注册 VPC 配置中定义的托管前缀列表，供 "pl:name" 形式的来源引用
+func (r *SecurityGroupRuleRegistry) RegisterPrefixList(name, prefixListId string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.prefixLists == nil {
+		r.prefixLists = make(map[string]string)
+	}
+	r.prefixLists[name] = prefixListId
+}
+
+// ResolvePrefixList 将前缀列表引用解析为前缀列表 ID
+// "pl-xxxx" 直接返回，"pl:name" 或 "name" 按 VPC prefixLists 中注册的名称查找
+func (r *SecurityGroupRuleRegistry) ResolvePrefixList(ref string) (string, error) {
+	if strings.HasPrefix(ref, "pl-") {
+		return ref, nil
+	}
+	name := strings.TrimPrefix(ref, "pl:")
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	if id, ok := r.prefixLists[name]; ok {
+		return id, nil
+	}
+	return "", fmt.Errorf("prefix list '%s' is not defined in the vpc prefixLists section", name)
+}
+
+// ResolvePrefixList 使用全局注册表解析前缀列表引用
+func ResolvePrefixList(ref string) (string, error) {
+	return GlobalRuleRegistry.ResolvePrefixList(ref)
+}
@@ -12,8 +12,36 @@ import (
 
 // SecurityGroupRuleRegistry 用于跟踪已添加的安全组规则
 type SecurityGroupRuleRegistry struct {
-	rules map[string]bool
-	mutex sync.RWMutex
+	rules        map[string]bool
+	records      []RuleRecord
+	currentForge string
+	prefixLists  map[string]string // 前缀列表名称 -> 前缀列表 ID
+	sgRules      []sgIngressRule
+	members      map[string][]awsec2.SecurityGroup // 层级安全组路径 -> 成员独立安全组
+	mutex        sync.RWMutex
+}
+
+// sgIngressRule 来源为安全组的入站规则，用于为来源层级的成员安全组补充相同规则
+type sgIngressRule struct {
+	targetSG    awsec2.ISecurityGroup
+	sourceSG    awsec2.SecurityGroup
+	protocol    string
+	fromPort    int
+	toPort      int
+	description string
+}
+
+// RuleRecord 已添加安全组规则的结构化记录，用于审计
+type RuleRecord struct {
+	SecurityGroup string `json:"securityGroup"` // 目标安全组的构造路径
+	Direction     string `json:"direction"`     // ingress 或 egress
+	SourceType    string `json:"sourceType"`    // securityGroup, cidr, cidrIpv6, prefixList
+	Source        string `json:"source"`        // 来源安全组路径、CIDR 或前缀列表 ID
+	Protocol      string `json:"protocol"`      // tcp, udp, icmp, all
+	FromPort      int    `json:"fromPort"`      // protocol 为 icmp/all 时为 0
+	ToPort        int    `json:"toPort"`
+	Description   string `json:"description"`
+	Forge         string `json:"forge,omitempty"` // 添加规则的 Forge 实例，格式为 "TYPE:id"
 }
 
 // 全局单例
@@ -21,46 +49,141 @@ var GlobalRuleRegistry = &SecurityGroupRuleRegistry{
 	rules: make(map[string]bool),
 }
 
+// securityGroupName 返回安全组的构造路径，合成阶段安全组 ID 仍是 Token，路径更易读
+func securityGroupName(sg awsec2.ISecurityGroup) string {
+	return *sg.Node().Path()
+}
+
+// register 注册规则 ID 并保存结构化记录
+func (r *SecurityGroupRuleRegistry) register(ruleID string, record RuleRecord) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	record.Forge = r.currentForge
+	r.rules[ruleID] = true
+	r.records = append(r.records, record)
+}
+
+// SetCurrentForge 设置当前正在处理的 Forge 实例，之后添加的规则都会记录该来源
+func (r *SecurityGroupRuleRegistry) SetCurrentForge(forge string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.currentForge = forge
+}
+
+// Records 返回已添加规则的结构化记录副本
+func (r *SecurityGroupRuleRegistry) Records() []RuleRecord {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	records := make([]RuleRecord, len(r.records))
+	copy(records, r.records)
+	return records
+}
+
+// AddSecurityGroupMember 将独立安全组登记为层级安全组的成员
+// 已经和之后授予层级安全组的入站访问（如存储、数据库端口）都会同样授予成员，成员自身的入站规则保持独立
+func (r *SecurityGroupRuleRegistry) AddSecurityGroupMember(tierSG, memberSG awsec2.SecurityGroup) {
+	tier := securityGroupName(tierSG)
+
+	r.mutex.Lock()
+	if r.members == nil {
+		r.members = make(map[string][]awsec2.SecurityGroup)
+	}
+	r.members[tier] = append(r.members[tier], memberSG)
+	var granted []sgIngressRule
+	for _, rule := range r.sgRules {
+		if securityGroupName(rule.sourceSG) == tier {
+			granted = append(granted, rule)
+		}
+	}
+	r.mutex.Unlock()
+
+	for _, rule := range granted {
+		rule.sourceSG = memberSG
+		r.addSecurityGroupIngress(rule)
+	}
+}
+
+// grantToMembers 记录来源为安全组的入站规则，并为来源层级的成员添加相同规则
+func (r *SecurityGroupRuleRegistry) grantToMembers(rule sgIngressRule) {
+	r.mutex.Lock()
+	r.sgRules = append(r.sgRules, rule)
+	members := append([]awsec2.SecurityGroup(nil), r.members[securityGroupName(rule.sourceSG)]...)
+	r.mutex.Unlock()
+
+	for _, member := range members {
+		rule.sourceSG = member
+		r.addSecurityGroupIngress(rule)
+	}
+}
+
+// addSecurityGroupIngress 按协议添加来源为安全组的入站规则
+func (r *SecurityGroupRuleRegistry) addSecurityGroupIngress(rule sgIngressRule) {
+	switch rule.protocol {
+	case "tcp":
+		if rule.fromPort == rule.toPort {
+			r.AddTcpIngressRuleSafely(rule.targetSG, rule.sourceSG, rule.fromPort, rule.description)
+		} else {
+			r.AddTcpRangeIngressRuleSafely(rule.targetSG, rule.sourceSG, rule.fromPort, rule.toPort, rule.description)
+		}
+	case "udp":
+		if rule.fromPort == rule.toPort {
+			r.AddUdpIngressRuleSafely(rule.targetSG, rule.sourceSG, rule.fromPort, rule.description)
+		} else {
+			r.AddUdpRangeIngressRuleSafely(rule.targetSG, rule.sourceSG, rule.fromPort, rule.toPort, rule.description)
+		}
+	case "all":
+		r.AddAllTrafficIngressRuleSafely(rule.targetSG, rule.sourceSG, rule.description)
+	case "icmp":
+		r.AddIcmpIngressRuleSafely(rule.targetSG, rule.sourceSG, rule.description)
+	}
+}
+
 // 生成安全组到安全组规则的唯一标识符
-func (r *SecurityGroupRuleRegistry) generateSGRuleID(targetSG, sourceSG awsec2.SecurityGroup, protocol string, port int) string {
+func (r *SecurityGroupRuleRegistry) generateSGRuleID(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, protocol string, port int) string {
 	targetID := *targetSG.SecurityGroupId()
 	sourceID := *sourceSG.SecurityGroupId()
 	return fmt.Sprintf("%s-%s-%s-%d", targetID, sourceID, protocol, port)
 }
 
 // 生成安全组到安全组规则的唯一标识符（端口范围）
-func (r *SecurityGroupRuleRegistry) generateSGRangeRuleID(targetSG, sourceSG awsec2.SecurityGroup, protocol string, fromPort, toPort int) string {
+func (r *SecurityGroupRuleRegistry) generateSGRangeRuleID(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, protocol string, fromPort, toPort int) string {
 	targetID := *targetSG.SecurityGroupId()
 	sourceID := *sourceSG.SecurityGroupId()
 	return fmt.Sprintf("%s-%s-%s-%d-%d", targetID, sourceID, protocol, fromPort, toPort)
 }
 
 // 生成 IPv4 CIDR 规则的唯一标识符
-func (r *SecurityGroupRuleRegistry) generateCidrRuleID(targetSG awsec2.SecurityGroup, cidr string, protocol string, port int) string {
+func (r *SecurityGroupRuleRegistry) generateCidrRuleID(targetSG awsec2.ISecurityGroup, cidr string, protocol string, port int) string {
 	targetID := *targetSG.SecurityGroupId()
 	return fmt.Sprintf("%s-cidr:%s-%s-%d", targetID, cidr, protocol, port)
 }
 
 // 生成 IPv4 CIDR 规则的唯一标识符（端口范围）
-func (r *SecurityGroupRuleRegistry) generateCidrRangeRuleID(targetSG awsec2.SecurityGroup, cidr string, protocol string, fromPort, toPort int) string {
+func (r *SecurityGroupRuleRegistry) generateCidrRangeRuleID(targetSG awsec2.ISecurityGroup, cidr string, protocol string, fromPort, toPort int) string {
 	targetID := *targetSG.SecurityGroupId()
 	return fmt.Sprintf("%s-cidr:%s-%s-%d-%d", targetID, cidr, protocol, fromPort, toPort)
 }
 
 // 生成 IPv6 CIDR 规则的唯一标识符
-func (r *SecurityGroupRuleRegistry) generateCidrIpv6RuleID(targetSG awsec2.SecurityGroup, cidr string, protocol string, port int) string {
+func (r *SecurityGroupRuleRegistry) generateCidrIpv6RuleID(targetSG awsec2.ISecurityGroup, cidr string, protocol string, port int) string {
 	targetID := *targetSG.SecurityGroupId()
 	return fmt.Sprintf("%s-cidrv6:%s-%s-%d", targetID, cidr, protocol, port)
 }
 
 // 生成 IPv6 CIDR 规则的唯一标识符（端口范围）
-func (r *SecurityGroupRuleRegistry) generateCidrIpv6RangeRuleID(targetSG awsec2.SecurityGroup, cidr string, protocol string, fromPort, toPort int) string {
+func (r *SecurityGroupRuleRegistry) generateCidrIpv6RangeRuleID(targetSG awsec2.ISecurityGroup, cidr string, protocol string, fromPort, toPort int) string {
 	targetID := *targetSG.SecurityGroupId()
 	return fmt.Sprintf("%s-cidrv6:%s-%s-%d-%d", targetID, cidr, protocol, fromPort, toPort)
 }
 
+// 生成托管前缀列表规则的唯一标识符
+func (r *SecurityGroupRuleRegistry) generatePrefixListRuleID(targetSG awsec2.ISecurityGroup, prefixListId string, protocol string, fromPort, toPort int) string {
+	targetID := *targetSG.SecurityGroupId()
+	return fmt.Sprintf("%s-pl:%s-%s-%d-%d", targetID, prefixListId, protocol, fromPort, toPort)
+}
+
 // AddTcpIngressRuleSafely 安全地添加 TCP 入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddTcpIngressRuleSafely(targetSG, sourceSG awsec2.SecurityGroup, port int, description string) {
+func (r *SecurityGroupRuleRegistry) AddTcpIngressRuleSafely(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, port int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateSGRuleID(targetSG, sourceSG, "tcp", port)
 	
@@ -74,14 +197,23 @@ func (r *SecurityGroupRuleRegistry) AddTcpIngressRuleSafely(targetSG, sourceSG a
 		targetSG.AddIngressRule(sourceSG, awsec2.Port_Tcp(jsii.Number(port)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "securityGroup",
+			Source:        securityGroupName(sourceSG),
+			Protocol:      "tcp",
+			FromPort:      port,
+			ToPort:        port,
+			Description:   description,
+		})
+
+		r.grantToMembers(sgIngressRule{targetSG, sourceSG, "tcp", port, port, description})
 	}
 }
 
 // AddTcpRangeIngressRuleSafely 安全地添加 TCP 端口范围入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddTcpRangeIngressRuleSafely(targetSG, sourceSG awsec2.SecurityGroup, fromPort, toPort int, description string) {
+func (r *SecurityGroupRuleRegistry) AddTcpRangeIngressRuleSafely(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, fromPort, toPort int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateSGRangeRuleID(targetSG, sourceSG, "tcp", fromPort, toPort)
 	
@@ -95,14 +227,23 @@ func (r *SecurityGroupRuleRegistry) AddTcpRangeIngressRuleSafely(targetSG, sourc
 		targetSG.AddIngressRule(sourceSG, awsec2.Port_TcpRange(jsii.Number(fromPort), jsii.Number(toPort)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "securityGroup",
+			Source:        securityGroupName(sourceSG),
+			Protocol:      "tcp",
+			FromPort:      fromPort,
+			ToPort:        toPort,
+			Description:   description,
+		})
+
+		r.grantToMembers(sgIngressRule{targetSG, sourceSG, "tcp", fromPort, toPort, description})
 	}
 }
 
 // AddUdpIngressRuleSafely 安全地添加 UDP 入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddUdpIngressRuleSafely(targetSG, sourceSG awsec2.SecurityGroup, port int, description string) {
+func (r *SecurityGroupRuleRegistry) AddUdpIngressRuleSafely(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, port int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateSGRuleID(targetSG, sourceSG, "udp", port)
 	
@@ -116,14 +257,23 @@ func (r *SecurityGroupRuleRegistry) AddUdpIngressRuleSafely(targetSG, sourceSG a
 		targetSG.AddIngressRule(sourceSG, awsec2.Port_Udp(jsii.Number(port)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "securityGroup",
+			Source:        securityGroupName(sourceSG),
+			Protocol:      "udp",
+			FromPort:      port,
+			ToPort:        port,
+			Description:   description,
+		})
+
+		r.grantToMembers(sgIngressRule{targetSG, sourceSG, "udp", port, port, description})
 	}
 }
 
 // AddUdpRangeIngressRuleSafely 安全地添加 UDP 端口范围入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddUdpRangeIngressRuleSafely(targetSG, sourceSG awsec2.SecurityGroup, fromPort, toPort int, description string) {
+func (r *SecurityGroupRuleRegistry) AddUdpRangeIngressRuleSafely(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, fromPort, toPort int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateSGRangeRuleID(targetSG, sourceSG, "udp", fromPort, toPort)
 	
@@ -137,14 +287,23 @@ func (r *SecurityGroupRuleRegistry) AddUdpRangeIngressRuleSafely(targetSG, sourc
 		targetSG.AddIngressRule(sourceSG, awsec2.Port_UdpRange(jsii.Number(fromPort), jsii.Number(toPort)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "securityGroup",
+			Source:        securityGroupName(sourceSG),
+			Protocol:      "udp",
+			FromPort:      fromPort,
+			ToPort:        toPort,
+			Description:   description,
+		})
+
+		r.grantToMembers(sgIngressRule{targetSG, sourceSG, "udp", fromPort, toPort, description})
 	}
 }
 
 // AddAllTrafficIngressRuleSafely 安全地添加允许所有流量的入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddAllTrafficIngressRuleSafely(targetSG, sourceSG awsec2.SecurityGroup, description string) {
+func (r *SecurityGroupRuleRegistry) AddAllTrafficIngressRuleSafely(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, description string) {
 	// 生成规则 ID
 	ruleID := r.generateSGRuleID(targetSG, sourceSG, "all", 0)
 	
@@ -158,14 +317,23 @@ func (r *SecurityGroupRuleRegistry) AddAllTrafficIngressRuleSafely(targetSG, sou
 		targetSG.AddIngressRule(sourceSG, awsec2.Port_AllTraffic(), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "securityGroup",
+			Source:        securityGroupName(sourceSG),
+			Protocol:      "all",
+			FromPort:      0,
+			ToPort:        0,
+			Description:   description,
+		})
+
+		r.grantToMembers(sgIngressRule{targetSG, sourceSG, "all", 0, 0, description})
 	}
 }
 
 // AddTcpIngressRuleFromCidrSafely 安全地添加来自 IPv4 CIDR 的 TCP 入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddTcpIngressRuleFromCidrSafely(targetSG awsec2.SecurityGroup, cidr string, port int, description string) {
+func (r *SecurityGroupRuleRegistry) AddTcpIngressRuleFromCidrSafely(targetSG awsec2.ISecurityGroup, cidr string, port int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateCidrRuleID(targetSG, cidr, "tcp", port)
 	
@@ -179,14 +347,21 @@ func (r *SecurityGroupRuleRegistry) AddTcpIngressRuleFromCidrSafely(targetSG aws
 		targetSG.AddIngressRule(awsec2.Peer_Ipv4(jsii.String(cidr)), awsec2.Port_Tcp(jsii.Number(port)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidr",
+			Source:        cidr,
+			Protocol:      "tcp",
+			FromPort:      port,
+			ToPort:        port,
+			Description:   description,
+		})
 	}
 }
 
 // AddTcpRangeIngressRuleFromCidrSafely 安全地添加来自 IPv4 CIDR 的 TCP 端口范围入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddTcpRangeIngressRuleFromCidrSafely(targetSG awsec2.SecurityGroup, cidr string, fromPort, toPort int, description string) {
+func (r *SecurityGroupRuleRegistry) AddTcpRangeIngressRuleFromCidrSafely(targetSG awsec2.ISecurityGroup, cidr string, fromPort, toPort int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateCidrRangeRuleID(targetSG, cidr, "tcp", fromPort, toPort)
 	
@@ -200,14 +375,21 @@ func (r *SecurityGroupRuleRegistry) AddTcpRangeIngressRuleFromCidrSafely(targetS
 		targetSG.AddIngressRule(awsec2.Peer_Ipv4(jsii.String(cidr)), awsec2.Port_TcpRange(jsii.Number(fromPort), jsii.Number(toPort)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidr",
+			Source:        cidr,
+			Protocol:      "tcp",
+			FromPort:      fromPort,
+			ToPort:        toPort,
+			Description:   description,
+		})
 	}
 }
 
 // AddUdpIngressRuleFromCidrSafely 安全地添加来自 IPv4 CIDR 的 UDP 入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddUdpIngressRuleFromCidrSafely(targetSG awsec2.SecurityGroup, cidr string, port int, description string) {
+func (r *SecurityGroupRuleRegistry) AddUdpIngressRuleFromCidrSafely(targetSG awsec2.ISecurityGroup, cidr string, port int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateCidrRuleID(targetSG, cidr, "udp", port)
 	
@@ -221,14 +403,21 @@ func (r *SecurityGroupRuleRegistry) AddUdpIngressRuleFromCidrSafely(targetSG aws
 		targetSG.AddIngressRule(awsec2.Peer_Ipv4(jsii.String(cidr)), awsec2.Port_Udp(jsii.Number(port)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidr",
+			Source:        cidr,
+			Protocol:      "udp",
+			FromPort:      port,
+			ToPort:        port,
+			Description:   description,
+		})
 	}
 }
 
 // AddUdpRangeIngressRuleFromCidrSafely 安全地添加来自 IPv4 CIDR 的 UDP 端口范围入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddUdpRangeIngressRuleFromCidrSafely(targetSG awsec2.SecurityGroup, cidr string, fromPort, toPort int, description string) {
+func (r *SecurityGroupRuleRegistry) AddUdpRangeIngressRuleFromCidrSafely(targetSG awsec2.ISecurityGroup, cidr string, fromPort, toPort int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateCidrRangeRuleID(targetSG, cidr, "udp", fromPort, toPort)
 	
@@ -242,14 +431,21 @@ func (r *SecurityGroupRuleRegistry) AddUdpRangeIngressRuleFromCidrSafely(targetS
 		targetSG.AddIngressRule(awsec2.Peer_Ipv4(jsii.String(cidr)), awsec2.Port_UdpRange(jsii.Number(fromPort), jsii.Number(toPort)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidr",
+			Source:        cidr,
+			Protocol:      "udp",
+			FromPort:      fromPort,
+			ToPort:        toPort,
+			Description:   description,
+		})
 	}
 }
 
 // AddAllTrafficIngressRuleFromCidrSafely 安全地添加来自 IPv4 CIDR 的所有流量入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddAllTrafficIngressRuleFromCidrSafely(targetSG awsec2.SecurityGroup, cidr string, description string) {
+func (r *SecurityGroupRuleRegistry) AddAllTrafficIngressRuleFromCidrSafely(targetSG awsec2.ISecurityGroup, cidr string, description string) {
 	// 生成规则 ID
 	ruleID := r.generateCidrRuleID(targetSG, cidr, "all", 0)
 	
@@ -263,14 +459,21 @@ func (r *SecurityGroupRuleRegistry) AddAllTrafficIngressRuleFromCidrSafely(targe
 		targetSG.AddIngressRule(awsec2.Peer_Ipv4(jsii.String(cidr)), awsec2.Port_AllTraffic(), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidr",
+			Source:        cidr,
+			Protocol:      "all",
+			FromPort:      0,
+			ToPort:        0,
+			Description:   description,
+		})
 	}
 }
 
 // AddTcpIngressRuleFromCidrIpv6Safely 安全地添加来自 IPv6 CIDR 的 TCP 入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddTcpIngressRuleFromCidrIpv6Safely(targetSG awsec2.SecurityGroup, cidr string, port int, description string) {
+func (r *SecurityGroupRuleRegistry) AddTcpIngressRuleFromCidrIpv6Safely(targetSG awsec2.ISecurityGroup, cidr string, port int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateCidrIpv6RuleID(targetSG, cidr, "tcp", port)
 	
@@ -284,14 +487,21 @@ func (r *SecurityGroupRuleRegistry) AddTcpIngressRuleFromCidrIpv6Safely(targetSG
 		targetSG.AddIngressRule(awsec2.Peer_Ipv6(jsii.String(cidr)), awsec2.Port_Tcp(jsii.Number(port)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidrIpv6",
+			Source:        cidr,
+			Protocol:      "tcp",
+			FromPort:      port,
+			ToPort:        port,
+			Description:   description,
+		})
 	}
 }
 
 // AddTcpRangeIngressRuleFromCidrIpv6Safely 安全地添加来自 IPv6 CIDR 的 TCP 端口范围入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddTcpRangeIngressRuleFromCidrIpv6Safely(targetSG awsec2.SecurityGroup, cidr string, fromPort, toPort int, description string) {
+func (r *SecurityGroupRuleRegistry) AddTcpRangeIngressRuleFromCidrIpv6Safely(targetSG awsec2.ISecurityGroup, cidr string, fromPort, toPort int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateCidrIpv6RangeRuleID(targetSG, cidr, "tcp", fromPort, toPort)
 	
@@ -305,14 +515,21 @@ func (r *SecurityGroupRuleRegistry) AddTcpRangeIngressRuleFromCidrIpv6Safely(tar
 		targetSG.AddIngressRule(awsec2.Peer_Ipv6(jsii.String(cidr)), awsec2.Port_TcpRange(jsii.Number(fromPort), jsii.Number(toPort)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidrIpv6",
+			Source:        cidr,
+			Protocol:      "tcp",
+			FromPort:      fromPort,
+			ToPort:        toPort,
+			Description:   description,
+		})
 	}
 }
 
 // AddUdpIngressRuleFromCidrIpv6Safely 安全地添加来自 IPv6 CIDR 的 UDP 入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddUdpIngressRuleFromCidrIpv6Safely(targetSG awsec2.SecurityGroup, cidr string, port int, description string) {
+func (r *SecurityGroupRuleRegistry) AddUdpIngressRuleFromCidrIpv6Safely(targetSG awsec2.ISecurityGroup, cidr string, port int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateCidrIpv6RuleID(targetSG, cidr, "udp", port)
 	
@@ -326,14 +543,21 @@ func (r *SecurityGroupRuleRegistry) AddUdpIngressRuleFromCidrIpv6Safely(targetSG
 		targetSG.AddIngressRule(awsec2.Peer_Ipv6(jsii.String(cidr)), awsec2.Port_Udp(jsii.Number(port)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidrIpv6",
+			Source:        cidr,
+			Protocol:      "udp",
+			FromPort:      port,
+			ToPort:        port,
+			Description:   description,
+		})
 	}
 }
 
 // AddUdpRangeIngressRuleFromCidrIpv6Safely 安全地添加来自 IPv6 CIDR 的 UDP 端口范围入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddUdpRangeIngressRuleFromCidrIpv6Safely(targetSG awsec2.SecurityGroup, cidr string, fromPort, toPort int, description string) {
+func (r *SecurityGroupRuleRegistry) AddUdpRangeIngressRuleFromCidrIpv6Safely(targetSG awsec2.ISecurityGroup, cidr string, fromPort, toPort int, description string) {
 	// 生成规则 ID
 	ruleID := r.generateCidrIpv6RangeRuleID(targetSG, cidr, "udp", fromPort, toPort)
 	
@@ -347,14 +571,21 @@ func (r *SecurityGroupRuleRegistry) AddUdpRangeIngressRuleFromCidrIpv6Safely(tar
 		targetSG.AddIngressRule(awsec2.Peer_Ipv6(jsii.String(cidr)), awsec2.Port_UdpRange(jsii.Number(fromPort), jsii.Number(toPort)), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidrIpv6",
+			Source:        cidr,
+			Protocol:      "udp",
+			FromPort:      fromPort,
+			ToPort:        toPort,
+			Description:   description,
+		})
 	}
 }
 
 // AddAllTrafficIngressRuleFromCidrIpv6Safely 安全地添加来自 IPv6 CIDR 的所有流量入站规则，避免重复
-func (r *SecurityGroupRuleRegistry) AddAllTrafficIngressRuleFromCidrIpv6Safely(targetSG awsec2.SecurityGroup, cidr string, description string) {
+func (r *SecurityGroupRuleRegistry) AddAllTrafficIngressRuleFromCidrIpv6Safely(targetSG awsec2.ISecurityGroup, cidr string, description string) {
 	// 生成规则 ID
 	ruleID := r.generateCidrIpv6RuleID(targetSG, cidr, "all", 0)
 	
@@ -368,9 +599,186 @@ func (r *SecurityGroupRuleRegistry) AddAllTrafficIngressRuleFromCidrIpv6Safely(t
 		targetSG.AddIngressRule(awsec2.Peer_Ipv6(jsii.String(cidr)), awsec2.Port_AllTraffic(), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidrIpv6",
+			Source:        cidr,
+			Protocol:      "all",
+			FromPort:      0,
+			ToPort:        0,
+			Description:   description,
+		})
+	}
+}
+
+// AddTcpRangeIngressRuleFromPrefixListSafely 安全地添加来自托管前缀列表的 TCP 端口范围入站规则，避免重复
+func (r *SecurityGroupRuleRegistry) AddTcpRangeIngressRuleFromPrefixListSafely(targetSG awsec2.ISecurityGroup, prefixListId string, fromPort, toPort int, description string) {
+	// 生成规则 ID
+	ruleID := r.generatePrefixListRuleID(targetSG, prefixListId, "tcp", fromPort, toPort)
+	
+	// 检查规则是否已存在
+	r.mutex.RLock()
+	exists := r.rules[ruleID]
+	r.mutex.RUnlock()
+	
+	if !exists {
+		// 添加规则
+		targetSG.AddIngressRule(awsec2.Peer_PrefixList(jsii.String(prefixListId)), awsec2.Port_TcpRange(jsii.Number(fromPort), jsii.Number(toPort)), jsii.String(description), nil)
+		
+		// 注册规则
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "prefixList",
+			Source:        prefixListId,
+			Protocol:      "tcp",
+			FromPort:      fromPort,
+			ToPort:        toPort,
+			Description:   description,
+		})
+	}
+}
+
+// AddUdpRangeIngressRuleFromPrefixListSafely 安全地添加来自托管前缀列表的 UDP 端口范围入站规则，避免重复
+func (r *SecurityGroupRuleRegistry) AddUdpRangeIngressRuleFromPrefixListSafely(targetSG awsec2.ISecurityGroup, prefixListId string, fromPort, toPort int, description string) {
+	// 生成规则 ID
+	ruleID := r.generatePrefixListRuleID(targetSG, prefixListId, "udp", fromPort, toPort)
+	
+	// 检查规则是否已存在
+	r.mutex.RLock()
+	exists := r.rules[ruleID]
+	r.mutex.RUnlock()
+	
+	if !exists {
+		// 添加规则
+		targetSG.AddIngressRule(awsec2.Peer_PrefixList(jsii.String(prefixListId)), awsec2.Port_UdpRange(jsii.Number(fromPort), jsii.Number(toPort)), jsii.String(description), nil)
+		
+		// 注册规则
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "prefixList",
+			Source:        prefixListId,
+			Protocol:      "udp",
+			FromPort:      fromPort,
+			ToPort:        toPort,
+			Description:   description,
+		})
+	}
+}
+
+// AddAllTrafficIngressRuleFromPrefixListSafely 安全地添加来自托管前缀列表的所有流量入站规则，避免重复
+func (r *SecurityGroupRuleRegistry) AddAllTrafficIngressRuleFromPrefixListSafely(targetSG awsec2.ISecurityGroup, prefixListId string, description string) {
+	// 生成规则 ID
+	ruleID := r.generatePrefixListRuleID(targetSG, prefixListId, "all", 0, 0)
+	
+	// 检查规则是否已存在
+	r.mutex.RLock()
+	exists := r.rules[ruleID]
+	r.mutex.RUnlock()
+	
+	if !exists {
+		// 添加规则
+		targetSG.AddIngressRule(awsec2.Peer_PrefixList(jsii.String(prefixListId)), awsec2.Port_AllTraffic(), jsii.String(description), nil)
+		
+		// 注册规则
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "prefixList",
+			Source:        prefixListId,
+			Protocol:      "all",
+			FromPort:      0,
+			ToPort:        0,
+			Description:   description,
+		})
+	}
+}
+
+// AddIcmpIngressRuleSafely 安全地添加允许所有 ICMP 的入站规则，避免重复
+func (r *SecurityGroupRuleRegistry) AddIcmpIngressRuleSafely(targetSG awsec2.ISecurityGroup, sourceSG awsec2.SecurityGroup, description string) {
+	// 生成规则 ID
+	ruleID := r.generateSGRuleID(targetSG, sourceSG, "icmp", 0)
+	
+	// 检查规则是否已存在
+	r.mutex.RLock()
+	exists := r.rules[ruleID]
+	r.mutex.RUnlock()
+	
+	if !exists {
+		// 添加规则
+		targetSG.AddIngressRule(sourceSG, awsec2.Port_AllIcmp(), jsii.String(description), nil)
+		
+		// 注册规则
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "securityGroup",
+			Source:        securityGroupName(sourceSG),
+			Protocol:      "icmp",
+			FromPort:      0,
+			ToPort:        0,
+			Description:   description,
+		})
+
+		r.grantToMembers(sgIngressRule{targetSG, sourceSG, "icmp", 0, 0, description})
+	}
+}
+
+// AddIcmpIngressRuleFromCidrSafely 安全地添加来自 IPv4 CIDR 的 ICMP 入站规则，避免重复
+func (r *SecurityGroupRuleRegistry) AddIcmpIngressRuleFromCidrSafely(targetSG awsec2.ISecurityGroup, cidr string, description string) {
+	// 生成规则 ID
+	ruleID := r.generateCidrRuleID(targetSG, cidr, "icmp", 0)
+	
+	// 检查规则是否已存在
+	r.mutex.RLock()
+	exists := r.rules[ruleID]
+	r.mutex.RUnlock()
+	
+	if !exists {
+		// 添加规则
+		targetSG.AddIngressRule(awsec2.Peer_Ipv4(jsii.String(cidr)), awsec2.Port_AllIcmp(), jsii.String(description), nil)
+		
+		// 注册规则
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidr",
+			Source:        cidr,
+			Protocol:      "icmp",
+			FromPort:      0,
+			ToPort:        0,
+			Description:   description,
+		})
+	}
+}
+
+// AddIcmpIngressRuleFromCidrIpv6Safely 安全地添加来自 IPv6 CIDR 的 ICMPv6 入站规则，避免重复
+func (r *SecurityGroupRuleRegistry) AddIcmpIngressRuleFromCidrIpv6Safely(targetSG awsec2.ISecurityGroup, cidr string, description string) {
+	// 生成规则 ID
+	ruleID := r.generateCidrIpv6RuleID(targetSG, cidr, "icmpv6", 0)
+	
+	// 检查规则是否已存在
+	r.mutex.RLock()
+	exists := r.rules[ruleID]
+	r.mutex.RUnlock()
+	
+	if !exists {
+		// 添加规则
+		targetSG.AddIngressRule(awsec2.Peer_Ipv6(jsii.String(cidr)), awsec2.Port_AllIcmpV6(), jsii.String(description), nil)
+		
+		// 注册规则
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(targetSG),
+			Direction:     "ingress",
+			SourceType:    "cidrIpv6",
+			Source:        cidr,
+			Protocol:      "icmp",
+			FromPort:      0,
+			ToPort:        0,
+			Description:   description,
+		})
 	}
 }
 
@@ -391,9 +799,16 @@ func (r *SecurityGroupRuleRegistry) AddAllTrafficEgressRuleSafely(sourceGroup, t
 		sourceGroup.AddEgressRule(targetGroup, awsec2.Port_AllTraffic(), jsii.String(description), nil)
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(sourceGroup),
+			Direction:     "egress",
+			SourceType:    "securityGroup",
+			Source:        securityGroupName(targetGroup),
+			Protocol:      "all",
+			FromPort:      0,
+			ToPort:        0,
+			Description:   description,
+		})
 	}
 }
 
@@ -420,14 +835,27 @@ func (r *SecurityGroupRuleRegistry) AddEfaEgressRuleSafely(sourceGroup awsec2.Se
 		})
 		
 		// 注册规则
-		r.mutex.Lock()
-		r.rules[ruleID] = true
-		r.mutex.Unlock()
+		r.register(ruleID, RuleRecord{
+			SecurityGroup: securityGroupName(sourceGroup),
+			Direction:     "egress",
+			SourceType:    "securityGroup",
+			Source:        securityGroupName(sourceGroup),
+			Protocol:      "all",
+			FromPort:      0,
+			ToPort:        0,
+			Description:   description,
+		})
 	}
 }
 
+// Reset 清空注册表，每个堆栈开始时调用，保证不同堆栈（以及测试）之间互不影响
 func (r *SecurityGroupRuleRegistry) Reset() {
-        r.mutex.Lock()
-        defer r.mutex.Unlock()
-        r.rules = make(map[string]bool)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.rules = make(map[string]bool)
+	r.records = nil
+	r.currentForge = ""
+	r.prefixLists = nil
+	r.sgRules = nil
+	r.members = nil
 }
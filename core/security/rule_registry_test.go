@@ -6,69 +6,76 @@ package security
 import (
 	"testing"
 
+	"github.com/aws/aws-cdk-go/awscdk/v2"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
 	"github.com/aws/jsii-runtime-go"
 )
 
-// 模拟安全组
-type mockSecurityGroup struct {
-	id            string
-	ingressRules  []string
-	securityGroup awsec2.SecurityGroup
-}
+// 创建测试用的 VPC 和安全组
+func newTestSecurityGroups(names ...string) []awsec2.SecurityGroup {
+	app := awscdk.NewApp(nil)
+	stack := awscdk.NewStack(app, jsii.String("RegistryTestStack"), nil)
+	vpc := awsec2.NewVpc(stack, jsii.String("Vpc"), &awsec2.VpcProps{MaxAzs: jsii.Number(1)})
 
-func (m *mockSecurityGroup) SecurityGroupId() *string {
-	return jsii.String(m.id)
+	var groups []awsec2.SecurityGroup
+	for _, name := range names {
+		groups = append(groups, awsec2.NewSecurityGroup(stack, jsii.String(name), &awsec2.SecurityGroupProps{Vpc: vpc}))
+	}
+	return groups
 }
 
-func (m *mockSecurityGroup) AddIngressRule(peer awsec2.IPeer, connection awsec2.Port, description *string, remoteRule *bool) awsec2.CfnIngress {
-	// 记录规则
-	m.ingressRules = append(m.ingressRules, *description)
-	return nil
+// 统计目标安全组上已注册的规则数量
+func countRules(targetSG awsec2.SecurityGroup) int {
+	count := 0
+	for _, record := range GlobalRuleRegistry.Records() {
+		if record.SecurityGroup == securityGroupName(targetSG) {
+			count++
+		}
+	}
+	return count
 }
 
 func TestSecurityGroupToSecurityGroupRules(t *testing.T) {
 	// 重置全局注册表
 	GlobalRuleRegistry.Reset()
 
-	// 创建模拟安全组
-	targetSG := &mockSecurityGroup{id: "sg-target"}
-	sourceSG := &mockSecurityGroup{id: "sg-source"}
+	groups := newTestSecurityGroups("Target", "Source")
+	targetSG, sourceSG := groups[0], groups[1]
 
 	// 测试 TCP 规则
 	GlobalRuleRegistry.AddTcpIngressRuleSafely(targetSG, sourceSG, 22, "SSH")
-	if len(targetSG.ingressRules) != 1 {
-		t.Errorf("Expected 1 rule, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 1 {
+		t.Errorf("Expected 1 rule, got %d", countRules(targetSG))
 	}
 
 	// 再次添加相同的规则
 	GlobalRuleRegistry.AddTcpIngressRuleSafely(targetSG, sourceSG, 22, "SSH")
-	if len(targetSG.ingressRules) != 1 {
-		t.Errorf("Expected still 1 rule, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 1 {
+		t.Errorf("Expected still 1 rule, got %d", countRules(targetSG))
 	}
 
 	// 添加不同端口的规则
 	GlobalRuleRegistry.AddTcpIngressRuleSafely(targetSG, sourceSG, 80, "HTTP")
-	if len(targetSG.ingressRules) != 2 {
-		t.Errorf("Expected 2 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 2 {
+		t.Errorf("Expected 2 rules, got %d", countRules(targetSG))
 	}
 
 	// 测试 UDP 规则
 	GlobalRuleRegistry.AddUdpIngressRuleSafely(targetSG, sourceSG, 53, "DNS")
-	if len(targetSG.ingressRules) != 3 {
-		t.Errorf("Expected 3 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 3 {
+		t.Errorf("Expected 3 rules, got %d", countRules(targetSG))
 	}
 
 	// 测试端口范围规则
 	GlobalRuleRegistry.AddTcpRangeIngressRuleSafely(targetSG, sourceSG, 1024, 2048, "TCP Range")
-	if len(targetSG.ingressRules) != 4 {
-		t.Errorf("Expected 4 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 4 {
+		t.Errorf("Expected 4 rules, got %d", countRules(targetSG))
 	}
 
 	// 测试 AllTraffic 规则
 	GlobalRuleRegistry.AddAllTrafficIngressRuleSafely(targetSG, sourceSG, "All Traffic")
-	if len(targetSG.ingressRules) != 5 {
-		t.Errorf("Expected 5 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 5 {
+		t.Errorf("Expected 5 rules, got %d", countRules(targetSG))
 	}
 }
 
@@ -76,49 +83,48 @@ func TestCidrRules(t *testing.T) {
 	// 重置全局注册表
 	GlobalRuleRegistry.Reset()
 
-	// 创建模拟安全组
-	targetSG := &mockSecurityGroup{id: "sg-target"}
+	targetSG := newTestSecurityGroups("Target")[0]
 
 	// 测试 IPv4 CIDR 规则
 	GlobalRuleRegistry.AddTcpIngressRuleFromCidrSafely(targetSG, "192.168.1.0/24", 22, "IPv4 SSH")
-	if len(targetSG.ingressRules) != 1 {
-		t.Errorf("Expected 1 rule, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 1 {
+		t.Errorf("Expected 1 rule, got %d", countRules(targetSG))
 	}
 
 	// 再次添加相同的规则
 	GlobalRuleRegistry.AddTcpIngressRuleFromCidrSafely(targetSG, "192.168.1.0/24", 22, "IPv4 SSH")
-	if len(targetSG.ingressRules) != 1 {
-		t.Errorf("Expected still 1 rule, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 1 {
+		t.Errorf("Expected still 1 rule, got %d", countRules(targetSG))
 	}
 
 	// 测试 IPv6 CIDR 规则
 	GlobalRuleRegistry.AddTcpIngressRuleFromCidrIpv6Safely(targetSG, "2001:db8::/32", 22, "IPv6 SSH")
-	if len(targetSG.ingressRules) != 2 {
-		t.Errorf("Expected 2 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 2 {
+		t.Errorf("Expected 2 rules, got %d", countRules(targetSG))
 	}
 
 	// 再次添加相同的 IPv6 规则
 	GlobalRuleRegistry.AddTcpIngressRuleFromCidrIpv6Safely(targetSG, "2001:db8::/32", 22, "IPv6 SSH")
-	if len(targetSG.ingressRules) != 2 {
-		t.Errorf("Expected still 2 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 2 {
+		t.Errorf("Expected still 2 rules, got %d", countRules(targetSG))
 	}
 
 	// 添加不同的 IPv6 规则
 	GlobalRuleRegistry.AddTcpIngressRuleFromCidrIpv6Safely(targetSG, "2001:db8::/32", 80, "IPv6 HTTP")
-	if len(targetSG.ingressRules) != 3 {
-		t.Errorf("Expected 3 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 3 {
+		t.Errorf("Expected 3 rules, got %d", countRules(targetSG))
 	}
 
 	// 测试 AllTraffic IPv4 规则
 	GlobalRuleRegistry.AddAllTrafficIngressRuleFromCidrSafely(targetSG, "0.0.0.0/0", "All Traffic IPv4")
-	if len(targetSG.ingressRules) != 4 {
-		t.Errorf("Expected 4 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 4 {
+		t.Errorf("Expected 4 rules, got %d", countRules(targetSG))
 	}
 
 	// 测试 AllTraffic IPv6 规则
 	GlobalRuleRegistry.AddAllTrafficIngressRuleFromCidrIpv6Safely(targetSG, "::/0", "All Traffic IPv6")
-	if len(targetSG.ingressRules) != 5 {
-		t.Errorf("Expected 5 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 5 {
+		t.Errorf("Expected 5 rules, got %d", countRules(targetSG))
 	}
 }
 
@@ -126,55 +132,135 @@ func TestHelperFunctions(t *testing.T) {
 	// 重置全局注册表
 	GlobalRuleRegistry.Reset()
 
-	// 创建模拟安全组
-	targetSG := &mockSecurityGroup{id: "sg-target"}
-	sourceSG := &mockSecurityGroup{id: "sg-source"}
+	groups := newTestSecurityGroups("Target", "Source")
+	targetSG, sourceSG := groups[0], groups[1]
 
 	// 测试安全组到安全组的辅助函数
 	AddTcpIngressRule(targetSG, sourceSG, 22, "SG to SG SSH")
-	if len(targetSG.ingressRules) != 1 {
-		t.Errorf("Expected 1 rule, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 1 {
+		t.Errorf("Expected 1 rule, got %d", countRules(targetSG))
 	}
 
 	// 测试 IPv4 CIDR 辅助函数
 	AddTcpIngressRuleFromCidr(targetSG, "192.168.1.0/24", 80, "IPv4 HTTP")
-	if len(targetSG.ingressRules) != 2 {
-		t.Errorf("Expected 2 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 2 {
+		t.Errorf("Expected 2 rules, got %d", countRules(targetSG))
 	}
 
 	// 测试 IPv6 CIDR 辅助函数
 	AddTcpIngressRuleFromCidrIpv6(targetSG, "2001:db8::/32", 443, "IPv6 HTTPS")
-	if len(targetSG.ingressRules) != 3 {
-		t.Errorf("Expected 3 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 3 {
+		t.Errorf("Expected 3 rules, got %d", countRules(targetSG))
 	}
 
 	// 测试任意 IP 辅助函数
 	AddTcpIngressRuleFromAnyIp(targetSG, 8080, "Any IPv4 to 8080")
-	if len(targetSG.ingressRules) != 4 {
-		t.Errorf("Expected 4 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 4 {
+		t.Errorf("Expected 4 rules, got %d", countRules(targetSG))
 	}
 
 	// 测试任意 IPv6 辅助函数
 	AddTcpIngressRuleFromAnyIpv6(targetSG, 8080, "Any IPv6 to 8080")
-	if len(targetSG.ingressRules) != 5 {
-		t.Errorf("Expected 5 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 5 {
+		t.Errorf("Expected 5 rules, got %d", countRules(targetSG))
 	}
 
 	// 测试 AllTraffic 辅助函数
 	AddAllTrafficIngressRule(targetSG, sourceSG, "All traffic from SG")
-	if len(targetSG.ingressRules) != 6 {
-		t.Errorf("Expected 6 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 6 {
+		t.Errorf("Expected 6 rules, got %d", countRules(targetSG))
 	}
 
 	// 测试 AllTraffic IPv4 辅助函数
 	AddAllTrafficIngressRuleFromAnyIp(targetSG, "All traffic from any IPv4")
-	if len(targetSG.ingressRules) != 7 {
-		t.Errorf("Expected 7 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 7 {
+		t.Errorf("Expected 7 rules, got %d", countRules(targetSG))
 	}
 
 	// 测试 AllTraffic IPv6 辅助函数
 	AddAllTrafficIngressRuleFromAnyIpv6(targetSG, "All traffic from any IPv6")
-	if len(targetSG.ingressRules) != 8 {
-		t.Errorf("Expected 8 rules, got %d", len(targetSG.ingressRules))
+	if countRules(targetSG) != 8 {
+		t.Errorf("Expected 8 rules, got %d", countRules(targetSG))
+	}
+}
+
+func TestRuleRecords(t *testing.T) {
+	GlobalRuleRegistry.Reset()
+
+	groups := newTestSecurityGroups("Target", "Source")
+	targetSG, sourceSG := groups[0], groups[1]
+
+	GlobalRuleRegistry.SetCurrentForge("EC2:web")
+	AddTcpRangeIngressRule(targetSG, sourceSG, 8000, 8999, "App ports")
+	GlobalRuleRegistry.SetCurrentForge("")
+	AddUdpIngressRuleFromCidr(targetSG, "10.0.0.0/8", 53, "DNS")
+
+	records := GlobalRuleRegistry.Records()
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	expected := RuleRecord{
+		SecurityGroup: "RegistryTestStack/Target",
+		Direction:     "ingress",
+		SourceType:    "securityGroup",
+		Source:        "RegistryTestStack/Source",
+		Protocol:      "tcp",
+		FromPort:      8000,
+		ToPort:        8999,
+		Description:   "App ports",
+		Forge:         "EC2:web",
+	}
+	if records[0] != expected {
+		t.Errorf("Expected %+v, got %+v", expected, records[0])
+	}
+	if records[1].SourceType != "cidr" || records[1].Source != "10.0.0.0/8" || records[1].Protocol != "udp" || records[1].Forge != "" {
+		t.Errorf("Unexpected CIDR record %+v", records[1])
+	}
+
+	// 返回的是副本
+	records[0].Description = "changed"
+	if GlobalRuleRegistry.Records()[0].Description != "App ports" {
+		t.Errorf("Records should return a copy")
+	}
+
+	GlobalRuleRegistry.Reset()
+	if len(GlobalRuleRegistry.Records()) != 0 {
+		t.Errorf("Expected no records after Reset")
+	}
+}
+
+func TestSecurityGroupMembers(t *testing.T) {
+	GlobalRuleRegistry.Reset()
+
+	groups := newTestSecurityGroups("Private", "Storage", "Database", "Dedicated")
+	privateSG, storageSG, databaseSG, dedicatedSG := groups[0], groups[1], groups[2], groups[3]
+
+	// 登记成员之前授予层级的访问
+	AddTcpIngressRule(storageSG, privateSG, 2049, "Allow EFS access from private subnet")
+	GlobalRuleRegistry.AddSecurityGroupMember(privateSG, dedicatedSG)
+	// 登记成员之后授予层级的访问
+	AddTcpIngressRule(databaseSG, privateSG, 5432, "Allow POSTGRES access from private subnet")
+	// 来源不是层级安全组的规则不复制
+	AddTcpIngressRuleFromCidr(databaseSG, "10.0.0.0/8", 5432, "Allow POSTGRES access from VPC")
+
+	hasRule := func(target awsec2.SecurityGroup, port int) bool {
+		for _, record := range GlobalRuleRegistry.Records() {
+			if record.SecurityGroup == securityGroupName(target) && record.Source == securityGroupName(dedicatedSG) && record.FromPort == port {
+				return true
+			}
+		}
+		return false
+	}
+	if !hasRule(storageSG, 2049) {
+		t.Errorf("Expected dedicated security group to reach storage granted before it was registered")
+	}
+	if !hasRule(databaseSG, 5432) {
+		t.Errorf("Expected dedicated security group to reach database granted after it was registered")
+	}
+	if countRules(storageSG) != 2 || countRules(databaseSG) != 3 {
+		t.Errorf("Expected 2 storage and 3 database rules, got %d and %d", countRules(storageSG), countRules(databaseSG))
+	}
+	if countRules(dedicatedSG) != 0 {
+		t.Errorf("Expected no inbound rules on the dedicated security group, got %d", countRules(dedicatedSG))
 	}
 }
@@ -26,6 +26,7 @@ type ForgeAMIConfig struct {
 	UserDataScriptPath string
 	MagicToken        string
 	S3Location        string
+	MountScript       string
 }
 
 func GetAMIInfo(partition, osType, osVersion, instanceArch string) (string, string) {
@@ -369,6 +370,7 @@ func (f *ForgeAMIConfig) GetImage(constructs.Construct) *awsec2.MachineImageConf
 		UserDataScriptPath: f.UserDataScriptPath,
 		MagicToken:         f.MagicToken,
 		S3Location:         f.S3Location,
+		MountScript:        f.MountScript,
 	}
 
 	userData, err := userDataGenerator.GenerateUserData()
@@ -0,0 +1,210 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/awslabs/InfraForge/core/dependency"
+)
+
+// MountConfig 实例级挂载配置，source 引用 dependsOn 中的存储依赖，其余字段覆盖默认值
+type MountConfig struct {
+	Source      string `json:"source"`                // 存储依赖，如 "EFS:shared"、"LUSTRE:fsx"
+	MountPoint  string `json:"mountPoint,omitempty"`  // 默认使用依赖的 mountPoint
+	Options     string `json:"options,omitempty"`     // 完全替换默认挂载选项
+	Tls         *bool  `json:"tls,omitempty"`         // EFS：通过 amazon-efs-utils 启用 TLS，默认 true
+	Iam         *bool  `json:"iam,omitempty"`         // EFS：使用实例角色进行 IAM 授权，默认在 EFS 禁止匿名访问时启用
+	AccessPoint string `json:"accessPoint,omitempty"` // EFS：访问点名称，需在 EFS accessPoints 中定义
+	Nconnect    int    `json:"nconnect,omitempty"`    // EFS/NFS：每个挂载的 TCP 连接数（1-16）
+	Automount   *bool  `json:"automount,omitempty"`   // 使用 systemd automount 在首次访问时挂载，默认 false
+}
+
+// MountSpec 由存储依赖属性推导出的挂载规格
+type MountSpec struct {
+	Source     string
+	FsType     string // efs、lustre、nfs4、mount-s3
+	Device     string
+	MountPoint string
+	Options    string
+	Automount  bool
+}
+
+// ValidateMounts 校验挂载配置，source 必须出现在 dependsOn 中
+func ValidateMounts(mounts []MountConfig, dependsOn string) error {
+	deps := make(map[string]bool)
+	for _, dep := range strings.Split(dependsOn, ",") {
+		deps[strings.ToUpper(strings.TrimSpace(dep))] = true
+	}
+
+	seen := make(map[string]bool)
+	for i, mount := range mounts {
+		if mount.Source == "" {
+			return fmt.Errorf("mounts[%d]: source is required", i)
+		}
+		if !deps[strings.ToUpper(strings.TrimSpace(mount.Source))] {
+			return fmt.Errorf("mounts[%d]: source %s must also be listed in dependsOn", i, mount.Source)
+		}
+		if mount.MountPoint != "" && !path.IsAbs(mount.MountPoint) {
+			return fmt.Errorf("mounts[%d]: mountPoint %s must be an absolute path", i, mount.MountPoint)
+		}
+		if mount.Nconnect < 0 || mount.Nconnect > 16 {
+			return fmt.Errorf("mounts[%d]: nconnect must be between 1 and 16", i)
+		}
+		if seen[mount.MountPoint] && mount.MountPoint != "" {
+			return fmt.Errorf("mounts[%d]: mountPoint %s is used more than once", i, mount.MountPoint)
+		}
+		seen[mount.MountPoint] = true
+	}
+	return nil
+}
+
+// BuildMountSpecs 通过依赖的 FileSystemProvider 推导挂载规格
+func BuildMountSpecs(mounts []MountConfig) ([]MountSpec, error) {
+	var specs []MountSpec
+	for _, mount := range mounts {
+		provider, err := dependency.Resolve[dependency.FileSystemProvider](mount.Source)
+		if err != nil {
+			return nil, fmt.Errorf("mount %s: %w", mount.Source, err)
+		}
+		fileSystem := provider.FileSystem()
+
+		spec := MountSpec{
+			Source:     mount.Source,
+			MountPoint: fileSystem.MountPoint,
+			Automount:  mount.Automount != nil && *mount.Automount,
+		}
+		if mount.MountPoint != "" {
+			spec.MountPoint = mount.MountPoint
+		}
+
+		var options []string
+		switch fileSystem.Type {
+		case "EFS":
+			spec.FsType = "efs"
+			spec.Device = fileSystem.FileSystemId + ":/"
+			options = []string{"_netdev", "noresvport"}
+			if mount.Tls == nil || *mount.Tls {
+				options = append(options, "tls")
+			}
+			if (mount.Iam == nil && fileSystem.IamRequired) || (mount.Iam != nil && *mount.Iam) {
+				options = append(options, "iam")
+			}
+			if mount.AccessPoint != "" {
+				accessPointId, ok := fileSystem.AccessPoints[mount.AccessPoint]
+				if !ok {
+					return nil, fmt.Errorf("mount %s: access point %s not found", mount.Source, mount.AccessPoint)
+				}
+				options = append(options, "accesspoint="+accessPointId)
+			}
+		case "LUSTRE":
+			spec.FsType = "lustre"
+			spec.Device = fmt.Sprintf("%s@tcp:/%s", fileSystem.DnsName, fileSystem.MountName)
+			options = []string{"_netdev", "flock", "noatime"}
+		case "OPENZFS", "ONTAP":
+			spec.FsType = "nfs4"
+			spec.Device = fileSystem.DnsName + ":" + fileSystem.ExportPath
+			if fileSystem.MountOptions != "" {
+				options = strings.Split(fileSystem.MountOptions, ",")
+			}
+			options = append(options, "_netdev", "hard", "timeo=600")
+		case "S3":
+			spec.FsType = "mount-s3"
+			spec.Device = "s3://" + fileSystem.FileSystemId + "/"
+			options = []string{"_netdev", "nosuid", "nodev", "nofail", "rw", "allow-other"}
+		default:
+			return nil, fmt.Errorf("mount %s: unsupported file system type %s", mount.Source, fileSystem.Type)
+		}
+
+		if mount.Nconnect > 0 && (spec.FsType == "efs" || spec.FsType == "nfs4") {
+			options = append(options, fmt.Sprintf("nconnect=%d", mount.Nconnect))
+		}
+		spec.Options = strings.Join(options, ",")
+		if mount.Options != "" {
+			spec.Options = mount.Options
+		}
+
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// RenderMountScript 生成安装客户端、写入 /etc/fstab 或 systemd automount 单元并挂载的 bash 脚本
+func RenderMountScript(specs []MountSpec) string {
+	if len(specs) == 0 {
+		return ""
+	}
+
+	packages := make(map[string]bool)
+	for _, spec := range specs {
+		packages[spec.FsType] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("# InfraForge storage mounts\n")
+	b.WriteString(mountScriptHeader)
+	for _, fsType := range []string{"efs", "lustre", "nfs4", "mount-s3"} {
+		if packages[fsType] {
+			fmt.Fprintf(&b, "infraforge_install_client %s\n", fsType)
+		}
+	}
+	for _, spec := range specs {
+		if spec.Automount {
+			fmt.Fprintf(&b, "infraforge_automount '%s' '%s' '%s' '%s'\n", spec.Device, spec.MountPoint, spec.FsType, spec.Options)
+		} else {
+			fmt.Fprintf(&b, "infraforge_fstab_mount '%s' '%s' '%s' '%s'\n", spec.Device, spec.MountPoint, spec.FsType, spec.Options)
+		}
+	}
+	b.WriteString("systemctl daemon-reload\n")
+	b.WriteString("# InfraForge storage mounts end\n")
+	return b.String()
+}
+
+// mountScriptHeader 按操作系统系列安装客户端，并提供 fstab 和 automount 辅助函数
+const mountScriptHeader = `. /etc/os-release
+infraforge_install_client() {
+    case "$1:$ID" in
+        efs:amzn|efs:rhel|efs:centos|efs:rocky|efs:fedora) yum install -y amazon-efs-utils ;;
+        efs:ubuntu|efs:debian)
+            apt-get update -y && apt-get install -y git binutils rustc cargo pkg-config libssl-dev
+            git clone https://github.com/aws/efs-utils /tmp/efs-utils && (cd /tmp/efs-utils && ./build-deb.sh && apt-get install -y ./build/amazon-efs-utils*deb) ;;
+        lustre:amzn)
+            if [ "$VERSION_ID" = "2" ]; then amazon-linux-extras install -y lustre; else dnf install -y lustre-client; fi ;;
+        lustre:rhel|lustre:centos|lustre:rocky)
+            curl -s https://fsx-lustre-client-repo-public-keys.s3.amazonaws.com/fsx-rpm-public-key.asc -o /tmp/fsx-rpm-public-key.asc
+            rpm --import /tmp/fsx-rpm-public-key.asc
+            curl -s "https://fsx-lustre-client-repo.s3.amazonaws.com/el/${VERSION_ID%%.*}/fsx-lustre-client.repo" -o /etc/yum.repos.d/aws-fsx.repo
+            yum install -y kmod-lustre-client lustre-client ;;
+        lustre:ubuntu)
+            curl -s https://fsx-lustre-client-repo-public-keys.s3.amazonaws.com/fsx-ubuntu-public-key.asc | gpg --dearmor > /usr/share/keyrings/fsx-ubuntu-public-key.gpg
+            echo "deb [signed-by=/usr/share/keyrings/fsx-ubuntu-public-key.gpg] https://fsx-lustre-client-repo.s3.amazonaws.com/ubuntu $VERSION_CODENAME main" > /etc/apt/sources.list.d/fsxlustreclientrepo.list
+            apt-get update -y && apt-get install -y "lustre-client-modules-$(uname -r)" ;;
+        nfs4:ubuntu|nfs4:debian) apt-get update -y && apt-get install -y nfs-common ;;
+        nfs4:*) yum install -y nfs-utils ;;
+        mount-s3:ubuntu|mount-s3:debian)
+            curl -s "https://s3.amazonaws.com/mountpoint-s3-release/latest/$(uname -m)/mount-s3.deb" -o /tmp/mount-s3.deb
+            apt-get install -y /tmp/mount-s3.deb ;;
+        mount-s3:*)
+            curl -s "https://s3.amazonaws.com/mountpoint-s3-release/latest/$(uname -m)/mount-s3.rpm" -o /tmp/mount-s3.rpm
+            yum install -y /tmp/mount-s3.rpm ;;
+        *) echo "InfraForge: no $1 client package for $ID" ;;
+    esac
+}
+infraforge_fstab_mount() {
+    mkdir -p "$2"
+    grep -q " $2 " /etc/fstab || echo "$1 $2 $3 $4 0 0" >> /etc/fstab
+    mount "$2" || echo "InfraForge: failed to mount $2"
+}
+infraforge_automount() {
+    mkdir -p "$2"
+    local unit
+    unit=$(systemd-escape -p --suffix=mount "$2")
+    printf '[Unit]\nDescription=InfraForge mount %s\nAfter=network-online.target\nWants=network-online.target\n\n[Mount]\nWhat=%s\nWhere=%s\nType=%s\nOptions=%s\n' "$2" "$1" "$2" "$3" "$4" > "/etc/systemd/system/$unit"
+    printf '[Unit]\nDescription=InfraForge automount %s\n\n[Automount]\nWhere=%s\nTimeoutIdleSec=600\n\n[Install]\nWantedBy=multi-user.target\n' "$2" "$2" > "/etc/systemd/system/${unit%.mount}.automount"
+    systemctl daemon-reload
+    systemctl enable --now "${unit%.mount}.automount" || echo "InfraForge: failed to enable automount for $2"
+}
+`
@@ -0,0 +1,179 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package aws
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/jsii-runtime-go"
+	"github.com/awslabs/InfraForge/core/dependency"
+)
+
+type testFileSystemForge struct {
+	info dependency.FileSystemInfo
+}
+
+func (f *testFileSystemForge) FileSystem() dependency.FileSystemInfo {
+	return f.info
+}
+
+func storeTestFileSystems() {
+	dependency.GlobalManager.Store("EFS:mount-efs", &testFileSystemForge{
+		info: dependency.FileSystemInfo{Type: "EFS", FileSystemId: "fs-123", MountPoint: "/shared", AccessPoints: map[string]string{"data": "fsap-456"}},
+	})
+	dependency.GlobalManager.Store("EFS:mount-efs-iam", &testFileSystemForge{
+		info: dependency.FileSystemInfo{Type: "EFS", FileSystemId: "fs-iam", MountPoint: "/secure", IamRequired: true},
+	})
+	dependency.GlobalManager.Store("LUSTRE:mount-lustre", &testFileSystemForge{
+		info: dependency.FileSystemInfo{Type: "LUSTRE", DnsName: "fs-789.fsx.us-east-1.amazonaws.com", MountName: "abcd", MountPoint: "/fsx"},
+	})
+	dependency.GlobalManager.Store("OPENZFS:mount-zfs", &testFileSystemForge{
+		info: dependency.FileSystemInfo{Type: "OPENZFS", DnsName: "zfs.example.com", ExportPath: "/fsx/vol1", MountPoint: "/zfs"},
+	})
+	dependency.GlobalManager.Store("ONTAP:mount-ontap", &testFileSystemForge{
+		info: dependency.FileSystemInfo{Type: "ONTAP", DnsName: "svm.example.com", ExportPath: "/vol1", MountPoint: "/ontap", MountOptions: "nfsvers=4.1"},
+	})
+	dependency.GlobalManager.Store("S3:mount-bucket", &testFileSystemForge{
+		info: dependency.FileSystemInfo{Type: "S3", FileSystemId: "datasets", MountPoint: "/datasets"},
+	})
+	dependency.GlobalManager.Store("EKS:mount-eks", &struct{}{})
+}
+
+func TestValidateMounts(t *testing.T) {
+	tests := []struct {
+		name      string
+		mounts    []MountConfig
+		dependsOn string
+		wantErr   bool
+	}{
+		{"no mounts", nil, "", false},
+		{"source in dependsOn", []MountConfig{{Source: "EFS:shared"}}, "VPC:main, efs:shared", false},
+		{"two mounts", []MountConfig{{Source: "EFS:shared", MountPoint: "/a"}, {Source: "LUSTRE:fsx", MountPoint: "/b", Nconnect: 16}}, "EFS:shared,LUSTRE:fsx", false},
+		{"missing source", []MountConfig{{MountPoint: "/a"}}, "EFS:shared", true},
+		{"source not in dependsOn", []MountConfig{{Source: "EFS:other"}}, "EFS:shared", true},
+		{"relative mount point", []MountConfig{{Source: "EFS:shared", MountPoint: "shared"}}, "EFS:shared", true},
+		{"nconnect too large", []MountConfig{{Source: "EFS:shared", Nconnect: 17}}, "EFS:shared", true},
+		{"negative nconnect", []MountConfig{{Source: "EFS:shared", Nconnect: -1}}, "EFS:shared", true},
+		{"duplicate mount point", []MountConfig{{Source: "EFS:shared", MountPoint: "/a"}, {Source: "LUSTRE:fsx", MountPoint: "/a"}}, "EFS:shared,LUSTRE:fsx", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMounts(tt.mounts, tt.dependsOn)
+			if tt.wantErr && err == nil {
+				t.Errorf("Expected validation error for %s", tt.name)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected %s to be valid, got %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestBuildMountSpecs(t *testing.T) {
+	storeTestFileSystems()
+
+	tests := []struct {
+		name  string
+		mount MountConfig
+		want  MountSpec
+	}{
+		{"efs defaults", MountConfig{Source: "EFS:mount-efs"},
+			MountSpec{FsType: "efs", Device: "fs-123:/", MountPoint: "/shared", Options: "_netdev,noresvport,tls"}},
+		{"efs access point", MountConfig{Source: "efs:mount-efs", MountPoint: "/data", Tls: jsii.Bool(false), Iam: jsii.Bool(true), AccessPoint: "data", Nconnect: 4},
+			MountSpec{FsType: "efs", Device: "fs-123:/", MountPoint: "/data", Options: "_netdev,noresvport,iam,accesspoint=fsap-456,nconnect=4"}},
+		{"efs without anonymous access", MountConfig{Source: "EFS:mount-efs-iam"},
+			MountSpec{FsType: "efs", Device: "fs-iam:/", MountPoint: "/secure", Options: "_netdev,noresvport,tls,iam"}},
+		{"efs iam disabled", MountConfig{Source: "EFS:mount-efs-iam", Iam: jsii.Bool(false)},
+			MountSpec{FsType: "efs", Device: "fs-iam:/", MountPoint: "/secure", Options: "_netdev,noresvport,tls"}},
+		{"lustre", MountConfig{Source: "LUSTRE:mount-lustre", Nconnect: 4},
+			MountSpec{FsType: "lustre", Device: "fs-789.fsx.us-east-1.amazonaws.com@tcp:/abcd", MountPoint: "/fsx", Options: "_netdev,flock,noatime"}},
+		{"openzfs without mount options", MountConfig{Source: "OPENZFS:mount-zfs"},
+			MountSpec{FsType: "nfs4", Device: "zfs.example.com:/fsx/vol1", MountPoint: "/zfs", Options: "_netdev,hard,timeo=600"}},
+		{"ontap with mount options", MountConfig{Source: "ONTAP:mount-ontap", Nconnect: 8},
+			MountSpec{FsType: "nfs4", Device: "svm.example.com:/vol1", MountPoint: "/ontap", Options: "nfsvers=4.1,_netdev,hard,timeo=600,nconnect=8"}},
+		{"s3 with automount", MountConfig{Source: "S3:mount-bucket", Automount: jsii.Bool(true)},
+			MountSpec{FsType: "mount-s3", Device: "s3://datasets/", MountPoint: "/datasets", Options: "_netdev,nosuid,nodev,nofail,rw,allow-other", Automount: true}},
+		{"options override", MountConfig{Source: "EFS:mount-efs", Options: "ro"},
+			MountSpec{FsType: "efs", Device: "fs-123:/", MountPoint: "/shared", Options: "ro"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs, err := BuildMountSpecs([]MountConfig{tt.mount})
+			if err != nil {
+				t.Fatalf("Expected %s to build, got %v", tt.name, err)
+			}
+			tt.want.Source = tt.mount.Source
+			if len(specs) != 1 || specs[0] != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, specs)
+			}
+		})
+	}
+}
+
+func TestBuildMountSpecsErrors(t *testing.T) {
+	storeTestFileSystems()
+
+	tests := []struct {
+		name  string
+		mount MountConfig
+	}{
+		{"unknown dependency", MountConfig{Source: "EFS:mount-missing"}},
+		{"not a file system", MountConfig{Source: "EKS:mount-eks"}},
+		{"unknown access point", MountConfig{Source: "EFS:mount-efs", AccessPoint: "logs"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := BuildMountSpecs([]MountConfig{tt.mount}); err == nil {
+				t.Errorf("Expected an error for %s", tt.name)
+			}
+		})
+	}
+}
+
+func TestRenderMountScript(t *testing.T) {
+	if script := RenderMountScript(nil); script != "" {
+		t.Errorf("Expected no script without mounts, got %q", script)
+	}
+
+	tests := []struct {
+		name     string
+		specs    []MountSpec
+		contains []string
+		excludes []string
+	}{
+		{"fstab mount", []MountSpec{{FsType: "efs", Device: "fs-123:/", MountPoint: "/shared", Options: "_netdev,tls"}},
+			[]string{"infraforge_install_client efs\n", "infraforge_fstab_mount 'fs-123:/' '/shared' 'efs' '_netdev,tls'\n", "# InfraForge storage mounts end\n"},
+			[]string{"infraforge_install_client lustre\n", "infraforge_automount '"}},
+		{"automount", []MountSpec{{FsType: "mount-s3", Device: "s3://datasets/", MountPoint: "/datasets", Options: "rw", Automount: true}},
+			[]string{"infraforge_install_client mount-s3\n", "infraforge_automount 's3://datasets/' '/datasets' 'mount-s3' 'rw'\n"},
+			[]string{"infraforge_fstab_mount '"}},
+		{"client installed once", []MountSpec{
+			{FsType: "nfs4", Device: "a:/vol1", MountPoint: "/a", Options: "hard"},
+			{FsType: "nfs4", Device: "b:/vol2", MountPoint: "/b", Options: "hard"},
+		}, []string{"infraforge_fstab_mount 'a:/vol1' '/a'", "infraforge_fstab_mount 'b:/vol2' '/b'"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := RenderMountScript(tt.specs)
+			for _, want := range tt.contains {
+				if !strings.Contains(script, want) {
+					t.Errorf("Expected script to contain %q", want)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(script, unwanted) {
+					t.Errorf("Expected script not to contain %q", unwanted)
+				}
+			}
+			if count := strings.Count(script, "infraforge_install_client nfs4\n"); len(tt.specs) > 1 && count != 1 {
+				t.Errorf("Expected the nfs4 client to be installed once, got %d", count)
+			}
+		})
+	}
+}
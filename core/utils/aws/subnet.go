@@ -51,3 +51,22 @@ func SelectSubnetIdByAzIndex(azIndex int, vpc awsec2.IVpc, subnetType awsec2.Sub
 	subnet := SelectSubnetByAzIndex(azIndex, vpc, subnetType)
 	return *subnet.SubnetId()
 }
+
+// vpcAzCount VPC 的可用区数量，由 ForgeManager 创建 VPC 后设置，0 表示未知
+var vpcAzCount int
+
+// SetVpcAvailabilityZones 记录 VPC 的可用区数量，供 Validate 校验 azIndex
+func SetVpcAvailabilityZones(vpc awsec2.IVpc) {
+	vpcAzCount = len(*vpc.AvailabilityZones())
+}
+
+// ValidateAzIndex 校验 azIndex 不超过 VPC 的可用区数量，0 表示不指定可用区
+func ValidateAzIndex(azIndex int) error {
+	if azIndex < 0 {
+		return fmt.Errorf("azIndex starts at 1, got %d", azIndex)
+	}
+	if vpcAzCount > 0 && azIndex > vpcAzCount {
+		return fmt.Errorf("azIndex %d exceeds the %d availability zones of the VPC", azIndex, vpcAzCount)
+	}
+	return nil
+}
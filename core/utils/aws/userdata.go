@@ -19,6 +19,7 @@ type UserDataGenerator struct {
 	UserDataScriptPath string
 	MagicToken         string
 	S3Location         string
+	MountScript        string // Linux 存储挂载脚本，在 userdata 模块之前执行
 }
 
 func (g *UserDataGenerator) GenerateUserData() (awsec2.UserData, error) {
@@ -52,6 +53,9 @@ func (g *UserDataGenerator) GenerateUserData() (awsec2.UserData, error) {
 			Shebang: jsii.String("#!/bin/bash"),
 		}
 		userData = awsec2.UserData_ForLinux(linuxUserDataOptions)
+		if g.MountScript != "" {
+			userData.AddCommands(jsii.String(g.MountScript))
+		}
 		userData.AddCommands(jsii.String(replacedScript))
 	case awsec2.OperatingSystemType_WINDOWS:
 		windowsUserDataOptions := &awsec2.WindowsUserDataOptions{
@@ -2,11 +2,14 @@ package eks
 
 import (
 	"encoding/json"
+	"fmt"
 	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awsiam"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awslambda"
 	"github.com/aws/aws-cdk-go/awscdk/v2/customresources"
+	"github.com/awslabs/InfraForge/core/partition"
 	"github.com/awslabs/InfraForge/forges/aws/eks/utils"
 	"github.com/aws/jsii-runtime-go"
 )
@@ -18,6 +21,7 @@ type KubectlExecutorProps struct {
 	OnCreateCmds   []string // 创建时执行的命令
 	OnUpdateCmds   []string // 更新时执行的命令  
 	OnDeleteCmds   []string // 删除时执行的命令
+	ApiAuthMode    bool     // 集群只使用访问条目（authenticationMode API）时，aws-auth 映射不生效，改为创建访问条目
 }
 
 // CreateKubectlExecutor 创建可复用的kubectl执行器
@@ -45,6 +49,19 @@ func CreateKubectlExecutor(stack awscdk.Stack, id string, props *KubectlExecutor
 		},
 	})
 
+	// 集群公有端点关闭或受限时，CDK 会把 kubectl Handler 放到 VPC 私有子网，执行器同样在 VPC 内运行
+	var vpc awsec2.IVpc
+	var vpcSubnets *awsec2.SubnetSelection
+	var securityGroups *[]awsec2.ISecurityGroup
+	if kubectlSubnets := props.Cluster.KubectlPrivateSubnets(); kubectlSubnets != nil && len(*kubectlSubnets) > 0 {
+		vpc = props.Cluster.Vpc()
+		vpcSubnets = &awsec2.SubnetSelection{Subnets: kubectlSubnets}
+		if kubectlSecurityGroup := props.Cluster.KubectlSecurityGroup(); kubectlSecurityGroup != nil {
+			securityGroups = &[]awsec2.ISecurityGroup{kubectlSecurityGroup}
+		}
+		lambdaRole.AddManagedPolicy(awsiam.ManagedPolicy_FromAwsManagedPolicyName(jsii.String("service-role/AWSLambdaVPCAccessExecutionRole")))
+	}
+
 	// 创建kubectl执行Lambda
 	kubectlLambda := awslambda.NewFunction(stack, jsii.String(id+"Lambda"), &awslambda.FunctionProps{
 		Runtime: awslambda.Runtime_PYTHON_3_13(),
@@ -139,10 +156,25 @@ def handler(event, context):
 			utils.GetKubectlLayer(stack, id+"KubectlLayer", props.EksVersion),
 		},
 		Timeout: awscdk.Duration_Minutes(jsii.Number(10)),
+		Vpc: vpc,
+		VpcSubnets: vpcSubnets,
+		SecurityGroups: securityGroups,
 	})
 
-	// 将 Lambda 角色添加到 EKS 集群访问控制
-	if cluster, ok := props.Cluster.(awseks.Cluster); ok {
+	// 将 Lambda 角色添加到 EKS 集群访问控制，API 模式下使用访问条目
+	var accessEntry awseks.CfnAccessEntry
+	if props.ApiAuthMode {
+		accessEntry = awseks.NewCfnAccessEntry(stack, jsii.String(id+"AccessEntry"), &awseks.CfnAccessEntryProps{
+			ClusterName:  props.Cluster.ClusterName(),
+			PrincipalArn: lambdaRole.RoleArn(),
+			AccessPolicies: &[]*awseks.CfnAccessEntry_AccessPolicyProperty{
+				{
+					PolicyArn:   jsii.String(fmt.Sprintf("arn:%s:eks::aws:cluster-access-policy/AmazonEKSClusterAdminPolicy", partition.DefaultPartition)),
+					AccessScope: &awseks.CfnAccessEntry_AccessScopeProperty{Type: jsii.String("cluster")},
+				},
+			},
+		})
+	} else if cluster, ok := props.Cluster.(awseks.Cluster); ok {
 		cluster.AwsAuth().AddRoleMapping(lambdaRole, &awseks.AwsAuthMapping{
 			Groups: jsii.Strings("system:masters"),
 		})
@@ -221,5 +253,10 @@ def handler(event, context):
 		}
 	}
 
-	return customresources.NewAwsCustomResource(stack, jsii.String(id), customResourceProps)
+	executor := customresources.NewAwsCustomResource(stack, jsii.String(id), customResourceProps)
+	// 访问条目在执行器之后删除，保证 OnDelete 命令仍有集群权限
+	if accessEntry != nil {
+		executor.Node().AddDependency(accessEntry)
+	}
+	return executor
 }
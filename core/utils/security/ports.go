@@ -4,19 +4,25 @@
 package security
 
 import (
+	"errors"
+	"fmt"
+	"net"
 	"strconv"
 	"strings"
 )
 
 type PortRule struct {
-	Port      int    `json:"port,omitempty"`
-	FromPort  int    `json:"fromPort,omitempty"`
-	ToPort    int    `json:"toPort,omitempty"`
-	Cidr      string `json:"cidr"`
-	Protocol  string `json:"protocol,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	FromPort int    `json:"fromPort,omitempty"`
+	ToPort   int    `json:"toPort,omitempty"`
+	Cidr     string `json:"cidr"`
+	Protocol string `json:"protocol,omitempty"`
+	// PrefixList 来源为托管前缀列表时的引用，"pl-xxxx" 或 VPC 配置中 prefixLists 的名称，此时 Cidr 为空
+	PrefixList string `json:"prefixList,omitempty"`
 }
 
-// ParseAllowedPorts 解析端口配置字符串
+// ParseAllowedPorts 解析端口配置字符串，格式错误的条目会被忽略
+// 需要报告错误时使用 ParseAllowedPortsStrict
 // 格式: "22@10.0.0.0/8;80,443@0.0.0.0/0;8000-8999@10.69.0.0/16"
 // IPv6格式: "22@2406:da18::/32;80,443@::/0"
 func ParseAllowedPorts(allowedPorts string) []PortRule {
@@ -76,3 +82,225 @@ func ParseAllowedPorts(allowedPorts string) []PortRule {
 	}
 	return rules
 }
+
+// PortRange 表示一个端口范围，单个端口时 FromPort 与 ToPort 相同
+type PortRange struct {
+	FromPort int `json:"fromPort"`
+	ToPort   int `json:"toPort"`
+}
+
+// ParsePortRanges 解析端口列表字符串
+// 格式: "22"、"80,443"、"8000-8999"、"22,8000-8999"
+func ParsePortRanges(ports string) ([]PortRange, error) {
+	var ranges []PortRange
+	for _, portStr := range strings.Split(ports, ",") {
+		portStr = strings.TrimSpace(portStr)
+		if portStr == "" {
+			continue
+		}
+
+		fromStr, toStr := portStr, portStr
+		if strings.Contains(portStr, "-") {
+			rangeParts := strings.Split(portStr, "-")
+			if len(rangeParts) != 2 {
+				return nil, fmt.Errorf("invalid port range %q", portStr)
+			}
+			fromStr, toStr = strings.TrimSpace(rangeParts[0]), strings.TrimSpace(rangeParts[1])
+		}
+
+		fromPort, err := strconv.Atoi(fromStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", fromStr)
+		}
+		toPort, err := strconv.Atoi(toStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", toStr)
+		}
+		if fromPort < 0 || toPort > 65535 || fromPort > toPort {
+			return nil, fmt.Errorf("invalid port range %q", portStr)
+		}
+
+		ranges = append(ranges, PortRange{FromPort: fromPort, ToPort: toPort})
+	}
+	return ranges, nil
+}
+
+// AllowedPortsError 表示 allowedPorts 字符串中某个位置的解析错误
+type AllowedPortsError struct {
+	Offset int    // 出错条目在原始字符串中的字符偏移（从 0 开始）
+	Entry  string // 出错的条目
+	Reason string
+}
+
+func (e *AllowedPortsError) Error() string {
+	return fmt.Sprintf("allowedPorts offset %d, entry %q: %s", e.Offset, e.Entry, e.Reason)
+}
+
+// ParseAllowedPortsStrict 严格解析端口配置字符串，返回所有带位置信息的错误
+// 与 ParseAllowedPorts 的格式相同，另外支持:
+//   - 协议 icmp 和 all: "icmp@10.0.0.0/8"、"all@10.0.0.0/16"
+//   - ipv6 为 true 时要求 CIDR 为 IPv6，否则要求 IPv4
+//   - 托管前缀列表来源: "22@pl:corp-egress"（VPC prefixLists 中的名称）或 "22@pl-0123456789abcdef0"
+func ParseAllowedPortsStrict(allowedPorts string, ipv6 bool) ([]PortRule, error) {
+	var rules []PortRule
+	var errs []error
+
+	offset := 0
+	for _, rawEntry := range strings.Split(allowedPorts, ";") {
+		entryOffset := offset + len(rawEntry) - len(strings.TrimLeft(rawEntry, " \t"))
+		offset += len(rawEntry) + 1
+
+		entry := strings.TrimSpace(rawEntry)
+		if entry == "" {
+			continue
+		}
+		fail := func(format string, args ...interface{}) {
+			errs = append(errs, &AllowedPortsError{Offset: entryOffset, Entry: entry, Reason: fmt.Sprintf(format, args...)})
+		}
+
+		parts := strings.Split(entry, "@")
+		if len(parts) != 2 {
+			fail("expected exactly one '@' separating ports and CIDR")
+			continue
+		}
+
+		cidr := strings.TrimSpace(parts[1])
+		prefixList := ""
+		if IsPrefixListSource(cidr) {
+			prefixList = strings.TrimPrefix(cidr, "pl:")
+			cidr = ""
+			if prefixList == "" {
+				fail("empty prefix list name")
+				continue
+			}
+		} else if err := validateCidr(cidr, ipv6); err != nil {
+			fail("%v", err)
+			continue
+		}
+
+		for _, portStr := range strings.Split(parts[0], ",") {
+			portStr = strings.TrimSpace(portStr)
+			if portStr == "" {
+				fail("empty port")
+				continue
+			}
+
+			protocol := "tcp"
+			if idx := strings.Index(portStr, "/"); idx >= 0 {
+				protocol = strings.ToLower(strings.TrimSpace(portStr[idx+1:]))
+				portStr = strings.TrimSpace(portStr[:idx])
+			} else if lowered := strings.ToLower(portStr); lowered == "icmp" || lowered == "all" {
+				protocol = lowered
+				portStr = ""
+			}
+
+			switch protocol {
+			case "tcp", "udp", "both":
+			case "icmp", "all":
+				if portStr != "" && portStr != protocol {
+					fail("protocol %s does not take ports, got %q", protocol, portStr)
+					continue
+				}
+				if protocol == "icmp" && prefixList != "" {
+					fail("protocol icmp is not supported for prefix list sources")
+					continue
+				}
+				rules = append(rules, PortRule{Cidr: cidr, Protocol: protocol, PrefixList: prefixList})
+				continue
+			default:
+				fail("unknown protocol %q (expected tcp, udp, both, icmp or all)", protocol)
+				continue
+			}
+
+			ranges, err := ParsePortRanges(portStr)
+			if err != nil || len(ranges) != 1 {
+				fail("invalid port %q", portStr)
+				continue
+			}
+			pr := ranges[0]
+			if pr.FromPort == 0 {
+				fail("port 0 is not allowed")
+				continue
+			}
+			if strings.Contains(portStr, "-") {
+				rules = append(rules, PortRule{FromPort: pr.FromPort, ToPort: pr.ToPort, Cidr: cidr, Protocol: protocol, PrefixList: prefixList})
+			} else {
+				rules = append(rules, PortRule{Port: pr.FromPort, Cidr: cidr, Protocol: protocol, PrefixList: prefixList})
+			}
+		}
+	}
+
+	return rules, errors.Join(errs...)
+}
+
+// IsPrefixListSource 判断来源是否为托管前缀列表引用（"pl:name" 或 "pl-xxxx"）
+func IsPrefixListSource(source string) bool {
+	return strings.HasPrefix(source, "pl:") || strings.HasPrefix(source, "pl-")
+}
+
+// validateCidr 校验 CIDR 格式以及 IPv4/IPv6 放置是否正确
+func validateCidr(cidr string, ipv6 bool) error {
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR %q", cidr)
+	}
+	isIpv4 := ip.To4() != nil
+	if ipv6 && isIpv4 {
+		return fmt.Errorf("IPv4 CIDR %q found in allowedPortsIpv6", cidr)
+	}
+	if !ipv6 && !isIpv4 {
+		return fmt.Errorf("IPv6 CIDR %q found in allowedPorts, use allowedPortsIpv6 instead", cidr)
+	}
+	return nil
+}
+
+// AdminPorts 对全网开放时需要告警的管理端口
+var AdminPorts = []int{22, 3389, 5432, 3306, 8443}
+
+// LintPortRules 检查对 0.0.0.0/0 或 ::/0 开放管理端口的规则，suppress 中的端口不告警
+func LintPortRules(rules []PortRule, suppress []int) []string {
+	var warnings []string
+	for _, rule := range rules {
+		if rule.Cidr != "0.0.0.0/0" && rule.Cidr != "::/0" {
+			continue
+		}
+		if rule.Protocol == "icmp" {
+			continue
+		}
+		for _, port := range AdminPorts {
+			if containsPort(suppress, port) || !ruleCoversPort(rule, port) {
+				continue
+			}
+			warnings = append(warnings, fmt.Sprintf("admin port %d is open to %s", port, rule.Cidr))
+		}
+	}
+	return warnings
+}
+
+func ruleCoversPort(rule PortRule, port int) bool {
+	if rule.Protocol == "all" {
+		return true
+	}
+	if rule.Port > 0 {
+		return rule.Port == port
+	}
+	return rule.FromPort <= port && port <= rule.ToPort
+}
+
+func containsPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateAllowedPorts 严格校验 IPv4/IPv6 端口配置，返回 lint 告警和解析错误
+func ValidateAllowedPorts(allowedPorts, allowedPortsIpv6 string, suppress []int) ([]string, error) {
+	rules, err := ParseAllowedPortsStrict(allowedPorts, false)
+	ipv6Rules, ipv6Err := ParseAllowedPortsStrict(allowedPortsIpv6, true)
+
+	warnings := LintPortRules(append(rules, ipv6Rules...), suppress)
+	return warnings, errors.Join(err, ipv6Err)
+}
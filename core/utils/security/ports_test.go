@@ -0,0 +1,109 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package security
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseAllowedPortsStrict(t *testing.T) {
+	rules, err := ParseAllowedPortsStrict("22@10.0.0.0/8;80,443/udp@0.0.0.0/0;8000-8999/both@10.69.0.0/16;icmp@10.0.0.0/8;all@192.168.0.0/16", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []PortRule{
+		{Port: 22, Cidr: "10.0.0.0/8", Protocol: "tcp"},
+		{Port: 80, Cidr: "0.0.0.0/0", Protocol: "tcp"},
+		{Port: 443, Cidr: "0.0.0.0/0", Protocol: "udp"},
+		{FromPort: 8000, ToPort: 8999, Cidr: "10.69.0.0/16", Protocol: "both"},
+		{Cidr: "10.0.0.0/8", Protocol: "icmp"},
+		{Cidr: "192.168.0.0/16", Protocol: "all"},
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rules)
+	}
+}
+
+func TestParseAllowedPortsStrictPrefixList(t *testing.T) {
+	rules, err := ParseAllowedPortsStrict("22@pl:corp-egress;443,8000-8999/udp@pl-0123456789abcdef0;all@pl:corp-egress", false)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []PortRule{
+		{Port: 22, Protocol: "tcp", PrefixList: "corp-egress"},
+		{Port: 443, Protocol: "tcp", PrefixList: "pl-0123456789abcdef0"},
+		{FromPort: 8000, ToPort: 8999, Protocol: "udp", PrefixList: "pl-0123456789abcdef0"},
+		{Protocol: "all", PrefixList: "corp-egress"},
+	}
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, rules)
+	}
+
+	// 前缀列表同样可用于 IPv6 配置
+	if _, err := ParseAllowedPortsStrict("22@pl:corp-egress-v6", true); err != nil {
+		t.Errorf("Unexpected error for IPv6 prefix list: %v", err)
+	}
+}
+
+func TestParseAllowedPortsStrictErrors(t *testing.T) {
+	testCases := []struct {
+		input  string
+		ipv6   bool
+		offset int
+		reason string
+	}{
+		{"22@0.0.0.0/0;8o@10.0.0.0/8", false, 13, "invalid port"},
+		{"22", false, 0, "'@'"},
+		{"9000-8000@10.0.0.0/8", false, 0, "invalid port"},
+		{"22@10.0.0.300/8", false, 0, "invalid CIDR"},
+		{"22/sctp@10.0.0.0/8", false, 0, "unknown protocol"},
+		{"22@::/0", false, 0, "use allowedPortsIpv6"},
+		{"22@0.0.0.0/0", true, 0, "in allowedPortsIpv6"},
+		{"22@10.0.0.0/8; 80/icmp@10.0.0.0/8", false, 15, "does not take ports"},
+		{"22@pl:", false, 0, "empty prefix list name"},
+		{"icmp@pl:corp-egress", false, 0, "not supported for prefix list"},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseAllowedPortsStrict(tc.input, tc.ipv6)
+		if err == nil {
+			t.Errorf("%q: expected error, got nil", tc.input)
+			continue
+		}
+		var portsErr *AllowedPortsError
+		if !errors.As(err, &portsErr) {
+			t.Errorf("%q: expected AllowedPortsError, got %T", tc.input, err)
+			continue
+		}
+		if portsErr.Offset != tc.offset {
+			t.Errorf("%q: expected offset %d, got %d", tc.input, tc.offset, portsErr.Offset)
+		}
+		if !strings.Contains(portsErr.Reason, tc.reason) {
+			t.Errorf("%q: expected reason containing %q, got %q", tc.input, tc.reason, portsErr.Reason)
+		}
+	}
+}
+
+func TestLintPortRules(t *testing.T) {
+	rules := []PortRule{
+		{Port: 22, Cidr: "0.0.0.0/0", Protocol: "tcp"},
+		{FromPort: 3000, ToPort: 4000, Cidr: "::/0", Protocol: "tcp"},
+		{Port: 5432, Cidr: "10.0.0.0/8", Protocol: "tcp"},
+	}
+
+	warnings := LintPortRules(rules, nil)
+	if len(warnings) != 3 {
+		t.Errorf("Expected 3 warnings (22, 3306, 3389), got %v", warnings)
+	}
+
+	warnings = LintPortRules(rules, []int{22, 3306, 3389})
+	if len(warnings) != 0 {
+		t.Errorf("Expected suppressed ports to produce no warnings, got %v", warnings)
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EncodeConstructId 将任意字符串编码为只含字母数字的 construct ID，
+// 非字母数字字符和 'Z' 编码为 "Z" 加两位十六进制，不同输入不会得到相同结果（如 "a-b" 与 "ab"）
+func EncodeConstructId(s string) string {
+	var builder strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != 'Z' && ((c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')) {
+			builder.WriteByte(c)
+		} else {
+			fmt.Fprintf(&builder, "Z%02X", c)
+		}
+	}
+	return builder.String()
+}
@@ -4,6 +4,7 @@
 package batch
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -179,6 +180,10 @@ func (b *BatchForge) createLaunchTemplateWithUserData(batchInstance *BatchInstan
 	if err != nil {
 		fmt.Printf("Error getting dependency info: %v\n", err)
 	}
+	s3Location, err := dependency.ResolveS3Location(batchInstance.S3Location, batchInstance.DependsOn)
+	if err != nil {
+		fmt.Printf("Error resolving S3 location: %v\n", err)
+	}
 
 	// 使用 MIME multipart 格式的 UserData（专为 Batch Launch Template 设计）
 	userDataGenerator := &aws.UserDataGenerator{
@@ -187,7 +192,7 @@ func (b *BatchForge) createLaunchTemplateWithUserData(batchInstance *BatchInstan
 		UserDataToken:      batchInstance.UserDataToken,
 		UserDataScriptPath: batchInstance.UserDataScriptPath,
 		MagicToken:         magicToken,
-		S3Location:         batchInstance.S3Location,
+		S3Location:         s3Location,
 	}
 
 	userData, err := userDataGenerator.GenerateMimeMultipartUserData()
@@ -276,35 +281,31 @@ func (b *BatchForge) createStorageVolumes(batchInstance *BatchInstanceConfig) []
 		return volumes
 	}
 
-	// 解析每个依赖
+	// 解析每个依赖，只有实现 FileSystemProvider 的依赖（EFS、Lustre、OpenZFS、ONTAP、S3）需要映射卷
 	dependencies := strings.Split(batchInstance.DependsOn, ",")
 	for _, dep := range dependencies {
 		dep = strings.TrimSpace(dep)
-		
-		// 直接获取挂载点
-		mountPoint, err := dependency.GetMountPoint(dep)
-		if err != nil {
-			fmt.Printf("Error getting mount point for %s: %v\n", dep, err)
-			continue
-		}
 
-		// 解析依赖格式: "EFS:efs" -> resourceType="EFS", resourceId="efs"
-		parts := strings.Split(dep, ":")
-		if len(parts) != 2 {
+		provider, err := dependency.Resolve[dependency.FileSystemProvider](dep)
+		if errors.Is(err, dependency.ErrCapabilityNotProvided) {
 			continue
 		}
-		resourceType := strings.ToLower(parts[0])
-		resourceId := parts[1]
-
-		// 根据资源类型创建相应的卷 - 使用 Host 卷映射已挂载的目录
-		if resourceType == "efs" || resourceType == "fsx" || resourceType == "lustre" {
-			hostVolume := awsbatch.EcsVolume_Host(&awsbatch.HostVolumeOptions{
-				Name:      jsii.String(fmt.Sprintf("%s-volume", resourceId)),
-				HostPath: jsii.String(mountPoint),
-				ContainerPath: jsii.String(mountPoint),
-			})
-			volumes = append(volumes, hostVolume)
+		if err != nil {
+			fmt.Printf("Error resolving file system for %s: %v\n", dep, err)
+			continue
 		}
+		fileSystem := provider.FileSystem()
+
+		// 解析依赖格式: "EFS:efs" -> resourceId="efs"，外部依赖 "EFS:ext/shared" -> "ext-shared"
+		resourceId := strings.ReplaceAll(dep[strings.Index(dep, ":")+1:], "/", "-")
+
+		// 使用 Host 卷映射已挂载的目录，S3 依赖由 nas 模块通过 Mountpoint for Amazon S3 挂载到主机的 mountPoint
+		hostVolume := awsbatch.EcsVolume_Host(&awsbatch.HostVolumeOptions{
+			Name:          jsii.String(fmt.Sprintf("%s-volume", resourceId)),
+			HostPath:      jsii.String(fileSystem.MountPoint),
+			ContainerPath: jsii.String(fileSystem.MountPoint),
+		})
+		volumes = append(volumes, hostVolume)
 	}
 
 	return volumes
@@ -398,6 +399,9 @@ func (b *BatchForge) MergeConfigs(defaults, instance config.InstanceConfig) conf
 	if batchInstance.S3Location != "" {
 		merged.S3Location = batchInstance.S3Location
 	}
+	if batchInstance.DependsOn != "" {
+		merged.DependsOn = batchInstance.DependsOn
+	}
 	if batchInstance.UseOptimalInstanceTypes != nil {
 		merged.UseOptimalInstanceTypes = batchInstance.UseOptimalInstanceTypes
 	}
@@ -5,37 +5,179 @@ package ds
 
 import (
         "fmt"
+        "strings"
 
         "github.com/awslabs/InfraForge/core/config"
+        "github.com/awslabs/InfraForge/core/dependency"
         "github.com/awslabs/InfraForge/core/interfaces"
+        "github.com/awslabs/InfraForge/core/security"
         "github.com/awslabs/InfraForge/core/utils/types"
-        "github.com/awslabs/InfraForge/core/utils/security"
+        utilsSecurity "github.com/awslabs/InfraForge/core/utils/security"
         "github.com/aws/aws-cdk-go/awscdk/v2"
         "github.com/aws/aws-cdk-go/awscdk/v2/awsdirectoryservice"
         "github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+        "github.com/aws/aws-cdk-go/awscdk/v2/awsiam"
+        "github.com/aws/aws-cdk-go/awscdk/v2/awslambda"
         "github.com/aws/aws-cdk-go/awscdk/v2/awssecretsmanager"
+        "github.com/aws/aws-cdk-go/awscdk/v2/customresources"
         "github.com/aws/jsii-runtime-go"
 )
 
+// 目录服务模式
+const (
+        ModeMicrosoftAD = "microsoftAD" // 新建 AWS Managed Microsoft AD
+        ModeAdConnector = "adConnector" // 通过 AD Connector 连接已有（本地）目录
+)
+
+// AD 客户端访问域控制器所需端口
+var (
+        adTcpPorts = []int{53, 88, 389, 445, 464, 636, 3268, 3269}
+        adUdpPorts = []int{53, 88, 389, 464}
+)
+
+// adConnectorHandler AD Connector 自定义资源的 Lambda 处理函数
+// on_event 在部署时读取服务账号密码并连接目录，is_complete 等待目录状态变为 Active 或删除完成
+const adConnectorHandler = `
+import boto3
+
+ds = boto3.client('ds')
+
+def on_event(event, context):
+    props = event['ResourceProperties']
+    if event['RequestType'] == 'Delete':
+        directory_id = event['PhysicalResourceId']
+        if directory_id.startswith('d-'):
+            try:
+                ds.delete_directory(DirectoryId=directory_id)
+            except ds.exceptions.EntityDoesNotExistException:
+                pass
+        return {'PhysicalResourceId': directory_id}
+
+    # Create 和 Update 都连接新的目录，Update 返回新的 ID 后 CloudFormation 会删除旧的连接器
+    secret = boto3.client('secretsmanager').get_secret_value(SecretId=props['ServiceAccountSecretArn'])
+    params = {
+        'Name': props['Name'],
+        'Password': secret['SecretString'],
+        'Size': props.get('Size') or 'Small',
+        'ConnectSettings': {
+            'VpcId': props['VpcId'],
+            'SubnetIds': props['SubnetIds'],
+            'CustomerDnsIps': props['CustomerDnsIps'],
+            'CustomerUserName': props['CustomerUserName'],
+        },
+    }
+    if props.get('ShortName'):
+        params['ShortName'] = props['ShortName']
+    response = ds.connect_directory(**params)
+    return {'PhysicalResourceId': response['DirectoryId']}
+
+def is_complete(event, context):
+    directory_id = event['PhysicalResourceId']
+    if event['RequestType'] == 'Delete':
+        if not directory_id.startswith('d-'):
+            return {'IsComplete': True}
+        try:
+            directories = ds.describe_directories(DirectoryIds=[directory_id])['DirectoryDescriptions']
+        except ds.exceptions.EntityDoesNotExistException:
+            return {'IsComplete': True}
+        return {'IsComplete': not directories or directories[0]['Stage'] == 'Deleted'}
+
+    directory = ds.describe_directories(DirectoryIds=[directory_id])['DirectoryDescriptions'][0]
+    if directory['Stage'] == 'Failed':
+        raise Exception('AD Connector %s failed: %s' % (directory_id, directory.get('StageReason', '')))
+    if directory['Stage'] != 'Active':
+        return {'IsComplete': False}
+    return {
+        'IsComplete': True,
+        'Data': {
+            'DirectoryId': directory_id,
+            'SecurityGroupId': directory['ConnectSettings']['SecurityGroupId'],
+        },
+    }
+`
+
 // DsInstanceConfig 配置结构体
 type DsInstanceConfig struct {
         config.BaseInstanceConfig
+        Mode       string `json:"mode"`
         DomainName string `json:"domainName"`
         ShortName  string `json:"shortName"`
         Edition    string `json:"edition"`
         EnableSso  *bool  `json:"enableSso,omitempty"`
         UnixHome   string `json:"unixHome"`
+        // 允许访问 AD 端口的子网层，逗号分隔，如 "private,public"，默认只开放给 private
+        ClientTiers string `json:"clientTiers"`
+
+        // adConnector 模式参数
+        DnsIps                  string `json:"dnsIps"`                  // 已有目录的 DNS 服务器 IP，逗号分隔
+        ServiceAccountUser      string `json:"serviceAccountUser"`      // 连接目录使用的服务账号
+        ServiceAccountSecretArn string `json:"serviceAccountSecretArn"` // 存放服务账号明文密码的 Secret ARN
+        ConnectorSize           string `json:"connectorSize"`           // Small 或 Large
+
+        // AWS Backup 不支持 Directory Service，配置 backupPlan 时校验报错而不是静默忽略
+        BackupPlan string `json:"backupPlan,omitempty"`
+}
+
+// Validate 拒绝 backupPlan，校验客户端子网层、目录服务模式及 adConnector 必填参数
+func (c *DsInstanceConfig) Validate() error {
+        if c.BackupPlan != "" {
+                return fmt.Errorf("ds %s: backupPlan is not supported, AWS Backup does not protect Directory Service, Managed Microsoft AD takes daily snapshots automatically", c.GetID())
+        }
+        for _, tier := range clientTiers(c.ClientTiers) {
+                if tier != "public" && tier != "private" && tier != "isolated" {
+                        return fmt.Errorf("ds %s: unknown client tier '%s', expected public, private or isolated", c.GetID(), tier)
+                }
+        }
+        switch c.Mode {
+        case "", ModeMicrosoftAD:
+                return nil
+        case ModeAdConnector:
+                var missing []string
+                if c.DomainName == "" {
+                        missing = append(missing, "domainName")
+                }
+                if c.DnsIps == "" {
+                        missing = append(missing, "dnsIps")
+                }
+                if c.ServiceAccountUser == "" {
+                        missing = append(missing, "serviceAccountUser")
+                }
+                if c.ServiceAccountSecretArn == "" {
+                        missing = append(missing, "serviceAccountSecretArn")
+                }
+                if len(missing) > 0 {
+                        return fmt.Errorf("ds %s: adConnector mode requires %s", c.GetID(), strings.Join(missing, ", "))
+                }
+                if c.ConnectorSize != "" && c.ConnectorSize != "Small" && c.ConnectorSize != "Large" {
+                        return fmt.Errorf("ds %s: connectorSize must be Small or Large, got '%s'", c.GetID(), c.ConnectorSize)
+                }
+                return nil
+        default:
+                return fmt.Errorf("ds %s: unsupported mode '%s', expected %s or %s", c.GetID(), c.Mode, ModeMicrosoftAD, ModeAdConnector)
+        }
 }
 
 // DsForge 结构体
 type DsForge struct {
-        directory  awsdirectoryservice.CfnMicrosoftAD
-        secret     awssecretsmanager.Secret
-        properties map[string]interface{}
+        directory   awsdirectoryservice.CfnMicrosoftAD
+        connector   awscdk.CustomResource
+        directoryId *string
+        // 目录服务创建的安全组，域控制器（或连接器）的网卡使用该安全组
+        securityGroupId *string
+        directorySG     awsec2.ISecurityGroup
+        shortName   *string
+        secret      awssecretsmanager.ISecret
+        properties  map[string]interface{}
+        info        dependency.DirectoryInfo
 }
 
-// GetSecret 返回创建的密码
-func (d *DsForge) GetSecret() awssecretsmanager.Secret {
+// Directory 实现 dependency.DirectoryProvider
+func (d *DsForge) Directory() dependency.DirectoryInfo {
+        return d.info
+}
+
+// GetSecret 返回目录密码 Secret，adConnector 模式下为服务账号密码
+func (d *DsForge) GetSecret() awssecretsmanager.ISecret {
         return d.secret
 }
 
@@ -49,14 +191,14 @@ func (d *DsForge) GetSecretName() *string {
         return d.secret.SecretName()
 }
 
-// GetDirectory 返回创建的 Directory Service 对象
+// GetDirectory 返回创建的 Directory Service 对象，adConnector 模式下为 nil
 func (d *DsForge) GetDirectory() awsdirectoryservice.CfnMicrosoftAD {
 	return d.directory
 }
 
 // GetDirectoryId 返回Directory Service的ID
 func (d *DsForge) GetDirectoryId() *string {
-        return d.directory.Ref()
+        return d.directoryId
 }
 
 // Create 实现创建接口
@@ -78,12 +220,18 @@ func (d *DsForge) Create(ctx *interfaces.ForgeContext) interface{} {
                 panic("Need at least 2 private subnets for Directory Service")
         }
 
+        if dsInstance.Mode == ModeAdConnector {
+                d.createAdConnector(ctx, dsInstance, subnetSlice)
+                d.importDirectorySecurityGroup(ctx, dsInstance)
+                return d
+        }
+
         // 创建密码Secret
         secretName := fmt.Sprintf("%s-%s-DirectoryPassword", *ctx.Stack.StackName(), dsInstance.GetID())
 
 	// 获取或创建密码和Secret对象
 	dsPassId := fmt.Sprintf("%s-DSPassword", dsInstance.GetID())
-	password, secret := security.GetOrCreateSecretPassword(
+	password, secret := utilsSecurity.GetOrCreateSecretPassword(
 		ctx.Stack, 
 		dsPassId, 
 		secretName,
@@ -119,6 +267,28 @@ func (d *DsForge) Create(ctx *interfaces.ForgeContext) interface{} {
         directory.AddMetadata(&unixHomeKey, dsInstance.UnixHome)
 
         d.directory = directory
+        d.directoryId = directory.Ref()
+        d.shortName = directory.ShortName()
+
+        // CfnMicrosoftAD 不返回域控制器的安全组，通过 DescribeDirectories 查询
+        describe := &customresources.AwsSdkCall{
+                Service: jsii.String("DirectoryService"),
+                Action:  jsii.String("DescribeDirectories"),
+                Parameters: map[string]interface{}{
+                        "DirectoryIds": []*string{directory.Ref()},
+                },
+                PhysicalResourceId: customresources.PhysicalResourceId_Of(directory.Ref()),
+                OutputPaths:        jsii.Strings("DirectoryDescriptions.0.VpcSettings.SecurityGroupId"),
+        }
+        lookup := customresources.NewAwsCustomResource(ctx.Stack, jsii.String(dsInstance.GetID()+"SecurityGroupLookup"), &customresources.AwsCustomResourceProps{
+                OnCreate: describe,
+                OnUpdate: describe,
+                Policy: customresources.AwsCustomResourcePolicy_FromSdkCalls(&customresources.SdkCallsPolicyOptions{
+                        Resources: customresources.AwsCustomResourcePolicy_ANY_RESOURCE(),
+                }),
+        })
+        d.securityGroupId = lookup.GetResponseField(jsii.String("DirectoryDescriptions.0.VpcSettings.SecurityGroupId"))
+        d.importDirectorySecurityGroup(ctx, dsInstance)
         
         // 保存 DS 属性
         if d.properties == nil {
@@ -141,10 +311,148 @@ func (d *DsForge) Create(ctx *interfaces.ForgeContext) interface{} {
         // Unix Home (重用之前定义的 unixHomeKey)
         d.properties["unixHome"] = directory.GetMetadata(&unixHomeKey)
 
+        d.info = dependency.DirectoryInfo{
+                DirectoryId: *directory.Ref(),
+                DomainName:  dsInstance.DomainName,
+                ShortName:   dsInstance.ShortName,
+                Edition:     dsInstance.Edition,
+                DnsIps: []string{
+                        *awscdk.Fn_Select(jsii.Number(0), directory.AttrDnsIpAddresses()),
+                        *awscdk.Fn_Select(jsii.Number(1), directory.AttrDnsIpAddresses()),
+                },
+                SecretArn: *secret.SecretArn(),
+                UnixHome:  dsInstance.UnixHome,
+        }
+
         //return directory
         return d
 }
 
+// createAdConnector 通过 AD Connector 连接已有目录
+// CloudFormation 没有 AD Connector 资源类型，这里使用 Lambda 自定义资源调用 ConnectDirectory
+// 服务账号密码由 Lambda 在部署时从 Secrets Manager 读取，不会写入模板
+// 生成的属性与 Managed Microsoft AD 保持一致，依赖方（如 ParallelCluster）无需区分模式
+func (d *DsForge) createAdConnector(ctx *interfaces.ForgeContext, dsInstance *DsInstanceConfig, subnetSlice []awsec2.ISubnet) {
+        dnsIps := []string{}
+        for _, ip := range strings.Split(dsInstance.DnsIps, ",") {
+                if ip = strings.TrimSpace(ip); ip != "" {
+                        dnsIps = append(dnsIps, ip)
+                }
+        }
+
+        onEvent := awslambda.NewFunction(ctx.Stack, jsii.String(dsInstance.GetID()+"ConnectorOnEvent"), &awslambda.FunctionProps{
+                Runtime: awslambda.Runtime_PYTHON_3_13(),
+                Handler: jsii.String("index.on_event"),
+                Code:    awslambda.Code_FromInline(jsii.String(adConnectorHandler)),
+                Timeout: awscdk.Duration_Minutes(jsii.Number(5)),
+        })
+        onEvent.AddToRolePolicy(awsiam.NewPolicyStatement(&awsiam.PolicyStatementProps{
+                Effect:    awsiam.Effect_ALLOW,
+                Actions:   jsii.Strings("secretsmanager:GetSecretValue"),
+                Resources: jsii.Strings(dsInstance.ServiceAccountSecretArn),
+        }))
+        onEvent.AddToRolePolicy(awsiam.NewPolicyStatement(&awsiam.PolicyStatementProps{
+                Effect: awsiam.Effect_ALLOW,
+                Actions: jsii.Strings(
+                        "ds:ConnectDirectory",
+                        "ds:DeleteDirectory",
+                        "ds:DescribeDirectories",
+                        "ec2:AuthorizeSecurityGroupEgress",
+                        "ec2:AuthorizeSecurityGroupIngress",
+                        "ec2:CreateNetworkInterface",
+                        "ec2:CreateSecurityGroup",
+                        "ec2:CreateTags",
+                        "ec2:DeleteNetworkInterface",
+                        "ec2:DeleteSecurityGroup",
+                        "ec2:DescribeNetworkInterfaces",
+                        "ec2:DescribeSecurityGroups",
+                        "ec2:DescribeSubnets",
+                        "ec2:DescribeVpcs",
+                        "ec2:RevokeSecurityGroupEgress",
+                        "ec2:RevokeSecurityGroupIngress",
+                ),
+                Resources: jsii.Strings("*"),
+        }))
+
+        isComplete := awslambda.NewFunction(ctx.Stack, jsii.String(dsInstance.GetID()+"ConnectorIsComplete"), &awslambda.FunctionProps{
+                Runtime: awslambda.Runtime_PYTHON_3_13(),
+                Handler: jsii.String("index.is_complete"),
+                Code:    awslambda.Code_FromInline(jsii.String(adConnectorHandler)),
+                Timeout: awscdk.Duration_Minutes(jsii.Number(1)),
+        })
+        isComplete.AddToRolePolicy(awsiam.NewPolicyStatement(&awsiam.PolicyStatementProps{
+                Effect:    awsiam.Effect_ALLOW,
+                Actions:   jsii.Strings("ds:DescribeDirectories"),
+                Resources: jsii.Strings("*"),
+        }))
+
+        provider := customresources.NewProvider(ctx.Stack, jsii.String(dsInstance.GetID()+"ConnectorProvider"), &customresources.ProviderProps{
+                OnEventHandler:    onEvent,
+                IsCompleteHandler: isComplete,
+                QueryInterval:     awscdk.Duration_Seconds(jsii.Number(30)),
+                TotalTimeout:      awscdk.Duration_Minutes(jsii.Number(45)),
+        })
+
+        // 任一属性变化都会连接新的目录，CloudFormation 随后删除旧的连接器
+        connector := awscdk.NewCustomResource(ctx.Stack, jsii.String(dsInstance.GetID()), &awscdk.CustomResourceProps{
+                ServiceToken: provider.ServiceToken(),
+                ResourceType: jsii.String("Custom::AdConnector"),
+                Properties: &map[string]interface{}{
+                        "Name":                    dsInstance.DomainName,
+                        "ShortName":               dsInstance.ShortName,
+                        "Size":                    dsInstance.ConnectorSize,
+                        "VpcId":                   ctx.VPC.VpcId(),
+                        "SubnetIds":               []*string{subnetSlice[0].SubnetId(), subnetSlice[1].SubnetId()},
+                        "CustomerDnsIps":          dnsIps,
+                        "CustomerUserName":        dsInstance.ServiceAccountUser,
+                        "ServiceAccountSecretArn": dsInstance.ServiceAccountSecretArn,
+                },
+        })
+
+        d.connector = connector
+        d.directoryId = connector.Ref()
+        d.securityGroupId = connector.GetAttString(jsii.String("SecurityGroupId"))
+        d.shortName = jsii.String(dsInstance.ShortName)
+        d.secret = awssecretsmanager.Secret_FromSecretCompleteArn(ctx.Stack, jsii.String(dsInstance.GetID()+"ServiceAccountSecret"), jsii.String(dsInstance.ServiceAccountSecretArn))
+
+        // 保存 DS 属性，与 Managed Microsoft AD 的属性保持相同结构
+        if d.properties == nil {
+                d.properties = make(map[string]interface{})
+        }
+        d.properties["attrId"] = d.directoryId
+        d.properties["domainName"] = dsInstance.DomainName
+        d.properties["shortName"] = dsInstance.ShortName
+        d.properties["edition"] = dsInstance.Edition
+        d.properties["name"] = dsInstance.DomainName
+        d.properties["secretARN"] = dsInstance.ServiceAccountSecretArn
+        d.properties["unixHome"] = dsInstance.UnixHome
+
+        // AD Connector 将请求转发到已有目录的 DNS 服务器
+        attrDnsIpAddresses := []interface{}{}
+        for _, ip := range dnsIps {
+                attrDnsIpAddresses = append(attrDnsIpAddresses, ip)
+        }
+        d.properties["attrDnsIpAddresses"] = attrDnsIpAddresses
+
+        d.info = dependency.DirectoryInfo{
+                DirectoryId: *d.directoryId,
+                DomainName:  dsInstance.DomainName,
+                ShortName:   dsInstance.ShortName,
+                Edition:     dsInstance.Edition,
+                DnsIps:      dnsIps,
+                SecretArn:   dsInstance.ServiceAccountSecretArn,
+                UnixHome:    dsInstance.UnixHome,
+        }
+}
+
+// importDirectorySecurityGroup 导入目录服务创建的安全组，AD 端口规则添加到该安全组上
+func (d *DsForge) importDirectorySecurityGroup(ctx *interfaces.ForgeContext, dsInstance *DsInstanceConfig) {
+        d.directorySG = awsec2.SecurityGroup_FromSecurityGroupId(ctx.Stack, jsii.String(dsInstance.GetID()+"DirectorySG"), d.securityGroupId, &awsec2.SecurityGroupImportOptions{
+                Mutable:          jsii.Bool(true),
+                AllowAllOutbound: jsii.Bool(true),
+        })
+}
+
 // CreateOutputs 实现输出接口
 func (d *DsForge) CreateOutputs(ctx *interfaces.ForgeContext) {
         dsInstance, ok := (*ctx.Instance).(*DsInstanceConfig)
@@ -153,15 +461,20 @@ func (d *DsForge) CreateOutputs(ctx *interfaces.ForgeContext) {
         }
 
         awscdk.NewCfnOutput(ctx.Stack, jsii.String("DirectoryService"+dsInstance.GetID()), &awscdk.CfnOutputProps{
-                Value:       d.directory.ShortName(),
+                Value:       d.shortName,
                 Description: jsii.String("Directory Service DNS Name"),
         })
 
         awscdk.NewCfnOutput(ctx.Stack, jsii.String("DirectoryServiceId"+dsInstance.GetID()), &awscdk.CfnOutputProps{
-                Value:       d.directory.Ref(),
+                Value:       d.directoryId,
                 Description: jsii.String("Directory Service ID"),
         })
 
+        if dsInstance.Mode == ModeAdConnector {
+                // 服务账号密码由用户管理，不重复输出
+                return
+        }
+
         // 输出密码的 ARN，以便后续查找
         awscdk.NewCfnOutput(ctx.Stack, jsii.String(fmt.Sprintf("%sPasswordARN", dsInstance.GetID())), &awscdk.CfnOutputProps{
                 Value:       d.secret.SecretArn(),
@@ -177,68 +490,51 @@ func (d *DsForge) CreateOutputs(ctx *interfaces.ForgeContext) {
 
 // ConfigureRules 实现安全组规则配置接口
 func (d *DsForge) ConfigureRules(ctx *interfaces.ForgeContext) {
-        // AWS Directory Service 自动创建和管理自己的安全组
-        // 不需要手动配置安全组规则
-        
-        /*
-        // Directory Service 所需端口
-        ports := []int{53, 88, 389, 445, 464, 636, 3268, 3269, 5985, 9389}
-        for _, port := range ports {
-                security.AddTcpIngressRule(
-                        ctx.SecurityGroups.Default,
-                        privateSG,
-			port,
-                        fmt.Sprintf("Allow AD TCP port %d from private subnet", port),
-                )
-                security.AddTcpIngressRule(
-                        defaultSG,
-                        publicSG,
-			port,
-                        fmt.Sprintf("Allow AD TCP port %d from public subnet", port),
-                )
+        dsInstance, ok := (*ctx.Instance).(*DsInstanceConfig)
+        if !ok {
+                return
         }
 
-        // UDP ports
-        udpPorts := []int{53, 88, 123, 389, 464}
-        for _, udpPort := range udpPorts {
-                security.AddUdpIngressRule(
-                        defaultSG,
-                        privateSG,
-			udpPort,
-                        fmt.Sprintf("Allow AD UDP port %d from private subnet", udpPort),
-                )
-                security.AddUdpIngressRule(
-                        defaultSG,
-                        publicSG,
-			udpPort,
-                        fmt.Sprintf("Allow AD UDP port %d from public subnet", udpPort),
-		)
-        }
-        */
-
-	/*
-        ports := []float64{53, 88, 389, 445, 464, 636, 3268, 3269, 5985, 9389}
-
-        for _, port := range ports {
-                defaultSG.AddIngressRule(
-                        privateSG,
-                        awsec2.Port_Tcp(jsii.Number(port)),
-                        jsii.String(fmt.Sprintf("Allow AD port %f from private subnet", port)),
-                        nil,
-                )
+        // 为使用目录的子网层在目录的安全组上开放 AD 端口，子网层名称已在 Validate 中校验
+        for _, tier := range clientTiers(dsInstance.ClientTiers) {
+                var sourceSG awsec2.SecurityGroup
+                switch tier {
+                case "public":
+                        sourceSG = ctx.SecurityGroups.Public
+                case "private":
+                        sourceSG = ctx.SecurityGroups.Private
+                case "isolated":
+                        sourceSG = ctx.SecurityGroups.Isolated
+                }
+
+                for _, port := range adTcpPorts {
+                        security.AddTcpIngressRule(
+                                d.directorySG,
+                                sourceSG,
+                                port,
+                                fmt.Sprintf("Allow AD TCP port %d from %s subnet", port, tier),
+                        )
+                }
+                for _, port := range adUdpPorts {
+                        security.AddUdpIngressRule(
+                                d.directorySG,
+                                sourceSG,
+                                port,
+                                fmt.Sprintf("Allow AD UDP port %d from %s subnet", port, tier),
+                        )
+                }
         }
+}
 
-        // UDP ports
-        udpPorts := []float64{53, 88, 123, 389, 464}
-        for _, port := range udpPorts {
-                defaultSG.AddIngressRule(
-                        privateSG,
-                        awsec2.Port_Udp(jsii.Number(port)),
-                        jsii.String(fmt.Sprintf("Allow AD UDP port %f from private subnet", port)),
-                        nil,
-                )
+// clientTiers 解析逗号分隔的子网层列表
+func clientTiers(value string) []string {
+        var tiers []string
+        for _, tier := range strings.Split(value, ",") {
+                if tier = strings.TrimSpace(tier); tier != "" {
+                        tiers = append(tiers, tier)
+                }
         }
-	*/
+        return tiers
 }
 
 // MergeConfigs 实现配置合并接口
@@ -262,6 +558,24 @@ func (d *DsForge) MergeConfigs(defaults config.InstanceConfig, instance config.I
                 }
 
                 // 合并特定配置
+                if dsInstance.Mode != "" {
+                        merged.Mode = dsInstance.Mode
+                }
+                if dsInstance.ClientTiers != "" {
+                        merged.ClientTiers = dsInstance.ClientTiers
+                }
+                if dsInstance.DnsIps != "" {
+                        merged.DnsIps = dsInstance.DnsIps
+                }
+                if dsInstance.ServiceAccountUser != "" {
+                        merged.ServiceAccountUser = dsInstance.ServiceAccountUser
+                }
+                if dsInstance.ServiceAccountSecretArn != "" {
+                        merged.ServiceAccountSecretArn = dsInstance.ServiceAccountSecretArn
+                }
+                if dsInstance.ConnectorSize != "" {
+                        merged.ConnectorSize = dsInstance.ConnectorSize
+                }
                 if dsInstance.EnableSso != nil {
                         merged.EnableSso = dsInstance.EnableSso
                 }
@@ -277,13 +591,28 @@ func (d *DsForge) MergeConfigs(defaults config.InstanceConfig, instance config.I
                 if dsInstance.UnixHome != "" {
                         merged.UnixHome = dsInstance.UnixHome
                 }
+                if dsInstance.BackupPlan != "" {
+                        merged.BackupPlan = dsInstance.BackupPlan
+                }
         }
 
         // 设置默认值
+        if merged.Mode == "" {
+                merged.Mode = ModeMicrosoftAD
+        }
+
         if merged.Edition == "" {
                 merged.Edition = "Standard"
         }
 
+        if merged.ClientTiers == "" {
+                merged.ClientTiers = "private"
+        }
+
+        if merged.Mode == ModeAdConnector && merged.ConnectorSize == "" {
+                merged.ConnectorSize = "Small"
+        }
+
         if merged.UnixHome == "" {
                 merged.UnixHome = "/home"
         }
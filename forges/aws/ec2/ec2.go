@@ -10,6 +10,7 @@ import (
 	"github.com/awslabs/InfraForge/core/config"
 	"github.com/awslabs/InfraForge/core/interfaces"
 	"github.com/awslabs/InfraForge/core/security"
+	utilsSecurity "github.com/awslabs/InfraForge/core/utils/security"
 	"github.com/awslabs/InfraForge/core/utils/types"
 	"github.com/awslabs/InfraForge/core/utils/aws"
 	"github.com/awslabs/InfraForge/core/partition"
@@ -56,6 +57,7 @@ type Ec2InstanceConfig struct {
 	CapacityBlockId          string `json:"capacityBlockId,omitempty"`   // Capacity Block ID（独立配置）
 	AllowedPorts             string `json:"allowedPorts,omitempty"`      // 允许的端口配置
 	AllowedPortsIpv6         string `json:"allowedPortsIpv6,omitempty"`  // IPv6端口配置
+	SuppressPortWarnings     []int  `json:"suppressPortWarnings,omitempty"` // 不对这些端口的全网开放发出告警
 	InstanceType             string `json:"instanceType"`
 	OsArch                   string `json:"osArch"`
 	OsImage                  string `json:"osImage,omitempty"`
@@ -68,6 +70,7 @@ type Ec2InstanceConfig struct {
 	UserDataScriptPath       string `json:"userDataScriptPath,omitempty"`
 	StoreInstanceInfo        *bool  `json:"storeInstanceInfo,omitempty"`
 	BandwidthWeighting       string `json:"bandwidthWeighting,omitempty"`       // 带宽权重: "default", "vpc-1", "ebs-1"
+	Mounts                   []aws.MountConfig `json:"mounts,omitempty"`        // 由 InfraForge 写入 fstab/automount 挂载的存储依赖
 }
 
 /*
@@ -281,6 +284,21 @@ func createEc2Instance(stack awscdk.Stack, ec2Instance *Ec2InstanceConfig, vpc a
 	if err != nil {
 		fmt.Printf("Error getting dependency info: %v\n", err)
 	}
+	s3Location, err := dependency.ResolveS3Location(ec2Instance.S3Location, ec2Instance.DependsOn)
+	if err != nil {
+		fmt.Printf("Error resolving S3 location: %v\n", err)
+	}
+
+	// 根据存储依赖属性生成挂载脚本，在 userdata 模块之前执行
+	mountScript := ""
+	if len(ec2Instance.Mounts) > 0 {
+		// 挂载规格已在 Validate 中校验
+		mountSpecs, err := aws.BuildMountSpecs(ec2Instance.Mounts)
+		if err != nil {
+			panic(fmt.Sprintf("EC2 instance %s: %v", ec2Instance.GetID(), err))
+		}
+		mountScript = aws.RenderMountScript(mountSpecs)
+	}
 
 	// 使用统一的子网选择函数
 	selectedSubnet := aws.SelectSubnetByAzIndex(ec2Instance.AzIndex, vpc, subnetType)
@@ -297,7 +315,8 @@ func createEc2Instance(stack awscdk.Stack, ec2Instance *Ec2InstanceConfig, vpc a
 			UserDataToken:     ec2Instance.UserDataToken,
 			UserDataScriptPath: ec2Instance.UserDataScriptPath,
 			MagicToken:        magicToken,
-			S3Location:        ec2Instance.S3Location,
+			S3Location:        s3Location,
+			MountScript:       mountScript,
 		},
 		KeyPair:        iKeyPair,
 		SecurityGroup:  defaultSG,
@@ -522,8 +541,10 @@ func (e *Ec2Forge) ConfigureRules(ctx *interfaces.ForgeContext) {
 	if ctx.Instance != nil {
 		ec2Instance, ok := (*ctx.Instance).(*Ec2InstanceConfig)
 		if ok && (ec2Instance.AllowedPorts != "" || ec2Instance.AllowedPortsIpv6 != "") {
-			// 使用通用端口规则处理函数
-			security.ApplyPortRules(ctx.SecurityGroups.Public, ec2Instance.AllowedPorts, ec2Instance.AllowedPortsIpv6, ctx.DualStack)
+			// 使用通用端口规则处理函数，端口配置已在 Validate 中校验
+			if err := security.ApplyPortRules(ctx.SecurityGroups.Public, ec2Instance.AllowedPorts, ec2Instance.AllowedPortsIpv6, ctx.DualStack); err != nil {
+				panic(fmt.Sprintf("EC2 instance %s: %v", ec2Instance.GetID(), err))
+			}
 			
 			// 如果启用了EFA，配置EFA规则
 			if types.GetBoolValue(ec2Instance.EnableEfa, false) {
@@ -702,9 +723,42 @@ func (e *Ec2Forge) MergeConfigs(defaults config.InstanceConfig, instance config.
 	if ec2Instance.AllowedPortsIpv6 != "" {
 		merged.AllowedPortsIpv6 = ec2Instance.AllowedPortsIpv6
 	}
+	if len(ec2Instance.SuppressPortWarnings) > 0 {
+		merged.SuppressPortWarnings = ec2Instance.SuppressPortWarnings
+	}
+	if len(ec2Instance.Mounts) > 0 {
+		merged.Mounts = ec2Instance.Mounts
+	}
 
 	return merged
 }
+
+// Validate 严格校验端口配置及其引用的前缀列表，并对全网开放的管理端口发出告警；校验存储挂载配置
+func (c *Ec2InstanceConfig) Validate() error {
+	warnings, err := utilsSecurity.ValidateAllowedPorts(c.AllowedPorts, c.AllowedPortsIpv6, c.SuppressPortWarnings)
+	for _, warning := range warnings {
+		fmt.Printf("Warning: EC2 instance '%s': %s\n", c.GetID(), warning)
+	}
+	if err != nil {
+		return err
+	}
+	if err := security.ValidatePortPrefixLists(c.AllowedPorts, c.AllowedPortsIpv6); err != nil {
+		return err
+	}
+
+	if len(c.Mounts) > 0 && strings.EqualFold(c.OsType, "windows") {
+		return fmt.Errorf("mounts are only supported on Linux instances")
+	}
+	if err := aws.ValidateMounts(c.Mounts, c.DependsOn); err != nil {
+		return err
+	}
+	// 依赖已在 Validate 之前创建，提前解析挂载规格，避免实例在缺少挂载的情况下启动
+	if _, err := aws.BuildMountSpecs(c.Mounts); err != nil {
+		return fmt.Errorf("EC2 instance '%s': %w", c.GetID(), err)
+	}
+	return nil
+}
+
 func (e *Ec2Forge) GetProperties() map[string]interface{} {
 	return e.properties
 }
@@ -292,6 +292,15 @@ func (e *EcsForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	return e
 }
 
+// Cluster 实现 dependency.ClusterProvider
+func (e *EcsForge) Cluster() dependency.ClusterInfo {
+	return dependency.ClusterInfo{
+		Type:        "ECS",
+		ClusterName: *e.ecs.ClusterName(),
+		ClusterArn:  *e.ecs.ClusterArn(),
+	}
+}
+
 func (e *EcsForge) CreateOutputs(ctx *interfaces.ForgeContext) {
 	ecsInstance, ok := (*ctx.Instance).(*EcsInstanceConfig)
 	if !ok {
@@ -451,10 +460,11 @@ func createTaskDefinitions(stack awscdk.Stack, config TaskDefinitionConfig) {
 		ExecutionRole() awsiam.IRole
 	}
 
-	mountPointPath, err := dependency.GetMountPoint(config.EcsInstance.DependsOn)
-	if err != nil {
+	mountPointPath := "/data"
+	if fileSystem, err := dependency.ResolveFirst[dependency.FileSystemProvider](config.EcsInstance.DependsOn); err != nil {
 		fmt.Printf("Error mount point: %v\n", err)
-		mountPointPath = "/data"
+	} else {
+		mountPointPath = fileSystem.FileSystem().MountPoint
 	}
 
 	//mountPointPath = mountPointPath
@@ -0,0 +1,398 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/awslabs/InfraForge/core/partition"
+	"github.com/awslabs/InfraForge/core/utils/types"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsiam"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awslambda"
+	"github.com/aws/aws-cdk-go/awscdk/v2/customresources"
+	"github.com/aws/constructs-go/constructs/v10"
+	"github.com/aws/jsii-runtime-go"
+)
+
+// EksAccessConfig 集群访问配置
+type EksAccessConfig struct {
+	AuthenticationMode string                 `json:"authenticationMode,omitempty"` // API_AND_CONFIG_MAP（默认）或 API，切换到 API 后不能再回退
+	Entries            []EksAccessEntryConfig `json:"entries,omitempty"`
+	Groups             []EksRbacGroupConfig   `json:"groups,omitempty"` // 自定义 Kubernetes 组的命名空间权限
+}
+
+// EksAccessEntryConfig 访问条目，principalArn 和 permissionSet 二选一
+type EksAccessEntryConfig struct {
+	PrincipalArn     string                  `json:"principalArn,omitempty"`  // IAM 用户、角色或 IAM Identity Center 权限集 ARN
+	PermissionSet    string                  `json:"permissionSet,omitempty"` // IAM Identity Center 权限集名称
+	Username         string                  `json:"username,omitempty"`
+	KubernetesGroups []string                `json:"kubernetesGroups,omitempty"`
+	Policies         []EksAccessPolicyConfig `json:"policies,omitempty"`
+}
+
+// EksAccessPolicyConfig 访问策略，namespaces 为空时作用于整个集群
+type EksAccessPolicyConfig struct {
+	Policy     string   `json:"policy"` // ClusterAdmin、Admin、Edit、View 或完整策略名称
+	Namespaces []string `json:"namespaces,omitempty"`
+}
+
+// EksRbacGroupConfig 为 Kubernetes 组在命名空间中创建 RoleBinding，clusterRole 和 rules 二选一
+type EksRbacGroupConfig struct {
+	Name        string        `json:"name"`
+	Namespaces  []string      `json:"namespaces"`            // 命名空间需要已存在
+	ClusterRole string        `json:"clusterRole,omitempty"` // 绑定已有 ClusterRole，如 edit、view
+	Rules       []EksRbacRule `json:"rules,omitempty"`       // 创建同名 Role
+}
+
+// EksRbacRule Role 规则
+type EksRbacRule struct {
+	ApiGroups     []string `json:"apiGroups"`
+	Resources     []string `json:"resources"`
+	Verbs         []string `json:"verbs"`
+	ResourceNames []string `json:"resourceNames,omitempty"`
+}
+
+// 访问策略简称
+var accessPolicyNames = map[string]string{
+	"clusteradmin": "AmazonEKSClusterAdminPolicy",
+	"admin":        "AmazonEKSAdminPolicy",
+	"edit":         "AmazonEKSEditPolicy",
+	"view":         "AmazonEKSViewPolicy",
+}
+
+var (
+	accessPrincipalPattern  = regexp.MustCompile(`^arn:aws[a-z-]*:iam::[0-9]{12}:(role|user)/.+$`)
+	permissionSetArnPattern = regexp.MustCompile(`^arn:aws[a-z-]*:sso:::permissionSet/ssoins-[0-9a-zA-Z]+/ps-[0-9a-zA-Z]+$`)
+	permissionSetPattern    = regexp.MustCompile(`^[\w+=,.@-]{1,32}$`)
+	namespacePattern        = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+	accessIdPattern         = regexp.MustCompile(`[^A-Za-z0-9]`)
+)
+
+// IsApiMode 是否只使用访问条目，不再维护 aws-auth ConfigMap
+func (c EksAccessConfig) IsApiMode() bool {
+	return strings.EqualFold(c.AuthenticationMode, "API")
+}
+
+// Validate 校验认证模式、访问条目和 RBAC 组
+func (c EksAccessConfig) Validate() error {
+	switch strings.ToUpper(c.AuthenticationMode) {
+	case "", "API", "API_AND_CONFIG_MAP":
+	case "CONFIG_MAP":
+		return fmt.Errorf("access.authenticationMode CONFIG_MAP is not supported, clusters are created with API_AND_CONFIG_MAP and EKS cannot switch back")
+	default:
+		return fmt.Errorf("unknown access.authenticationMode '%s', expected API_AND_CONFIG_MAP or API", c.AuthenticationMode)
+	}
+
+	principals := make(map[string]bool)
+	for i, entry := range c.Entries {
+		field := fmt.Sprintf("access.entries[%d]", i)
+		switch {
+		case entry.PrincipalArn != "" && entry.PermissionSet != "":
+			return fmt.Errorf("%s: principalArn and permissionSet are mutually exclusive", field)
+		case entry.PrincipalArn != "":
+			if !accessPrincipalPattern.MatchString(entry.PrincipalArn) && !permissionSetArnPattern.MatchString(entry.PrincipalArn) {
+				return fmt.Errorf("%s: principalArn '%s' must be an IAM user, IAM role or IAM Identity Center permission set ARN", field, entry.PrincipalArn)
+			}
+		case entry.PermissionSet != "":
+			if !permissionSetPattern.MatchString(entry.PermissionSet) {
+				return fmt.Errorf("%s: invalid permissionSet name '%s'", field, entry.PermissionSet)
+			}
+		default:
+			return fmt.Errorf("%s: principalArn or permissionSet is required", field)
+		}
+		if principals[entry.principalKey()] {
+			return fmt.Errorf("%s: duplicate principal %s", field, entry.principalKey())
+		}
+		principals[entry.principalKey()] = true
+
+		if len(entry.Policies) == 0 && len(entry.KubernetesGroups) == 0 {
+			return fmt.Errorf("%s: at least one of policies or kubernetesGroups is required", field)
+		}
+		for _, group := range entry.KubernetesGroups {
+			if group == "" || strings.HasPrefix(group, "system:") {
+				return fmt.Errorf("%s: invalid kubernetesGroups entry '%s', groups starting with system: are reserved", field, group)
+			}
+		}
+		policies := make(map[string]bool)
+		for j, policy := range entry.Policies {
+			policyName, err := accessPolicyName(policy.Policy)
+			if err != nil {
+				return fmt.Errorf("%s.policies[%d]: %w", field, j, err)
+			}
+			if policies[policyName] {
+				return fmt.Errorf("%s.policies[%d]: duplicate policy %s, list all namespaces in one entry", field, j, policyName)
+			}
+			policies[policyName] = true
+			if err := validateNamespaces(policy.Namespaces); err != nil {
+				return fmt.Errorf("%s.policies[%d]: %w", field, j, err)
+			}
+		}
+	}
+
+	groups := make(map[string]string)
+	for i, group := range c.Groups {
+		field := fmt.Sprintf("access.groups[%d]", i)
+		if group.Name == "" || strings.HasPrefix(group.Name, "system:") {
+			return fmt.Errorf("%s: name is required and must not start with system:", field)
+		}
+		// Role 和 RoleBinding 名称由组名转换而来，转换后相同的组会互相覆盖
+		if other, ok := groups[rbacResourceName(group.Name)]; ok {
+			return fmt.Errorf("%s: group %s conflicts with group %s, both create %s", field, group.Name, other, rbacResourceName(group.Name))
+		}
+		groups[rbacResourceName(group.Name)] = group.Name
+		if len(group.Namespaces) == 0 {
+			return fmt.Errorf("%s: namespaces is required", field)
+		}
+		if err := validateNamespaces(group.Namespaces); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if (group.ClusterRole == "") == (len(group.Rules) == 0) {
+			return fmt.Errorf("%s: exactly one of clusterRole or rules is required", field)
+		}
+		for j, rule := range group.Rules {
+			if len(rule.Resources) == 0 || len(rule.Verbs) == 0 {
+				return fmt.Errorf("%s.rules[%d]: resources and verbs are required", field, j)
+			}
+		}
+	}
+	return nil
+}
+
+// principalKey 访问条目的主体，用于检查重复主体和生成 Construct ID
+func (e EksAccessEntryConfig) principalKey() string {
+	if e.PermissionSet != "" {
+		return "permissionSet/" + e.PermissionSet
+	}
+	return e.PrincipalArn
+}
+
+// isPermissionSet 主体是否为 IAM Identity Center 权限集，部署时需要解析为账号中的角色
+func (e EksAccessEntryConfig) isPermissionSet() bool {
+	return e.PermissionSet != "" || permissionSetArnPattern.MatchString(e.PrincipalArn)
+}
+
+// rbacResourceName 自定义 Kubernetes 组的 Role 和 RoleBinding 名称
+func rbacResourceName(group string) string {
+	return "infraforge-" + strings.ToLower(accessIdPattern.ReplaceAllString(group, "-"))
+}
+
+// accessPolicyName 将简称转换为 EKS 访问策略名称
+func accessPolicyName(policy string) (string, error) {
+	if name, ok := accessPolicyNames[strings.ToLower(policy)]; ok {
+		return name, nil
+	}
+	if strings.HasPrefix(policy, "AmazonEKS") && strings.HasSuffix(policy, "Policy") {
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown policy '%s', expected ClusterAdmin, Admin, Edit, View or an AmazonEKS*Policy name", policy)
+}
+
+func validateNamespaces(namespaces []string) error {
+	for _, namespace := range namespaces {
+		if !namespacePattern.MatchString(namespace) {
+			return fmt.Errorf("invalid namespace '%s'", namespace)
+		}
+	}
+	return nil
+}
+
+// createAccessEntries 创建访问条目，API 模式下 adminUsers 也通过访问条目授权
+// 所有条目位于返回的 Construct 下，系统组件依赖它即可等待所有权限生效
+func createAccessEntries(stack awscdk.Stack, cluster awseks.Cluster, eksInstance *EksInstanceConfig) constructs.Construct {
+	scope := constructs.NewConstruct(stack, jsii.String(fmt.Sprintf("%s-access", eksInstance.GetID())))
+	entries := append([]EksAccessEntryConfig{}, eksInstance.Access.Entries...)
+
+	if eksInstance.Access.IsApiMode() && eksInstance.AdminUsers != "" {
+		// 账号可能是 Token，按用户名跳过已在 access.entries 中配置的用户
+		configured := make(map[string]bool)
+		for _, entry := range entries {
+			if strings.Contains(entry.PrincipalArn, ":user/") {
+				configured[entry.PrincipalArn[strings.LastIndex(entry.PrincipalArn, "/")+1:]] = true
+			}
+		}
+		for _, username := range strings.Split(eksInstance.AdminUsers, ",") {
+			username = strings.TrimSpace(username)
+			if username != "" && !configured[username] {
+				entries = append(entries, EksAccessEntryConfig{
+					PrincipalArn: fmt.Sprintf("arn:%s:iam::%s:user/%s", partition.DefaultPartition, *stack.Account(), username),
+					Username:     username,
+					Policies:     []EksAccessPolicyConfig{{Policy: "ClusterAdmin"}},
+				})
+			}
+		}
+	}
+
+	var ssoRoleProvider customresources.Provider
+	for i, entry := range entries {
+		// 按完整主体命名，调整条目顺序不会替换资源；adminUsers 的 ARN 包含账号 Token，按用户名命名
+		principal := entry.principalKey()
+		if i >= len(eksInstance.Access.Entries) {
+			principal = "adminUser/" + entry.Username
+		}
+		id := "AccessEntry" + types.EncodeConstructId(principal)
+		principalArn := jsii.String(entry.PrincipalArn)
+		if entry.isPermissionSet() {
+			if ssoRoleProvider == nil {
+				ssoRoleProvider = newSsoRoleProvider(scope)
+			}
+			principalArn = resolveSsoRole(scope, ssoRoleProvider, id, entry)
+		}
+
+		var policies []*awseks.CfnAccessEntry_AccessPolicyProperty
+		for _, policy := range entry.Policies {
+			policyName, _ := accessPolicyName(policy.Policy)
+			scopeProps := &awseks.CfnAccessEntry_AccessScopeProperty{
+				Type: jsii.String("cluster"),
+			}
+			if len(policy.Namespaces) > 0 {
+				scopeProps.Type = jsii.String("namespace")
+				scopeProps.Namespaces = jsii.Strings(policy.Namespaces...)
+			}
+			policies = append(policies, &awseks.CfnAccessEntry_AccessPolicyProperty{
+				PolicyArn:   jsii.String(fmt.Sprintf("arn:%s:eks::aws:cluster-access-policy/%s", partition.DefaultPartition, policyName)),
+				AccessScope: scopeProps,
+			})
+		}
+
+		props := &awseks.CfnAccessEntryProps{
+			ClusterName:    cluster.ClusterName(),
+			PrincipalArn:   principalArn,
+			AccessPolicies: &policies,
+		}
+		if entry.Username != "" {
+			props.Username = jsii.String(entry.Username)
+		}
+		if len(entry.KubernetesGroups) > 0 {
+			props.KubernetesGroups = jsii.Strings(entry.KubernetesGroups...)
+		}
+		awseks.NewCfnAccessEntry(scope, jsii.String(id), props)
+	}
+	return scope
+}
+
+// ssoRoleHandler 将 IAM Identity Center 权限集解析为账号中 AWSReservedSSO_<权限集名称>_<后缀> 角色的 Lambda 处理函数
+// 权限集 ARN 先通过 DescribePermissionSet 查询名称；后缀不含下划线，避免 Admin 匹配到 Admin_ReadOnly 权限集
+const ssoRoleHandler = `
+import boto3
+
+def on_event(event, context):
+    if event['RequestType'] == 'Delete':
+        return {'PhysicalResourceId': event['PhysicalResourceId']}
+
+    props = event['ResourceProperties']
+    name = props.get('PermissionSetName')
+    if not name:
+        arn = props['PermissionSetArn']
+        instance_arn = 'arn:%s:sso:::instance/%s' % (arn.split(':')[1], arn.split('/')[1])
+        name = boto3.client('sso-admin').describe_permission_set(InstanceArn=instance_arn, PermissionSetArn=arn)['PermissionSet']['Name']
+
+    prefix = 'AWSReservedSSO_%s_' % name
+    for page in boto3.client('iam').get_paginator('list_roles').paginate(PathPrefix='/aws-reserved/sso.amazonaws.com/'):
+        for role in page['Roles']:
+            if role['RoleName'].startswith(prefix) and '_' not in role['RoleName'][len(prefix):]:
+                return {'PhysicalResourceId': role['Arn'], 'Data': {'RoleArn': role['Arn']}}
+    raise Exception('no IAM Identity Center role found for permission set %s, make sure it is provisioned to this account' % name)
+`
+
+// newSsoRoleProvider 创建部署时解析权限集角色的自定义资源 Provider
+func newSsoRoleProvider(scope constructs.Construct) customresources.Provider {
+	onEvent := awslambda.NewFunction(scope, jsii.String("SsoRoleOnEvent"), &awslambda.FunctionProps{
+		Runtime: awslambda.Runtime_PYTHON_3_13(),
+		Handler: jsii.String("index.on_event"),
+		Code:    awslambda.Code_FromInline(jsii.String(ssoRoleHandler)),
+		Timeout: awscdk.Duration_Minutes(jsii.Number(1)),
+	})
+	onEvent.AddToRolePolicy(awsiam.NewPolicyStatement(&awsiam.PolicyStatementProps{
+		Actions:   jsii.Strings("iam:ListRoles", "sso:DescribePermissionSet"),
+		Resources: jsii.Strings("*"),
+	}))
+	return customresources.NewProvider(scope, jsii.String("SsoRoleProvider"), &customresources.ProviderProps{
+		OnEventHandler: onEvent,
+	})
+}
+
+// resolveSsoRole 返回权限集在账号中的角色 ARN，找不到角色时部署失败
+func resolveSsoRole(scope constructs.Construct, provider customresources.Provider, id string, entry EksAccessEntryConfig) *string {
+	properties := map[string]interface{}{"PermissionSetArn": entry.PrincipalArn}
+	if entry.PermissionSet != "" {
+		properties = map[string]interface{}{"PermissionSetName": entry.PermissionSet}
+	}
+	role := awscdk.NewCustomResource(scope, jsii.String(id+"SsoRole"), &awscdk.CustomResourceProps{
+		ServiceToken: provider.ServiceToken(),
+		ResourceType: jsii.String("Custom::SsoRole"),
+		Properties:   &properties,
+	})
+	return role.GetAttString(jsii.String("RoleArn"))
+}
+
+// createRbacGroups 为自定义 Kubernetes 组创建命名空间级 Role 和 RoleBinding
+func createRbacGroups(cluster awseks.Cluster, groups []EksRbacGroupConfig) []awseks.KubernetesManifest {
+	var manifests []awseks.KubernetesManifest
+	for _, group := range groups {
+		resourceName := rbacResourceName(group.Name)
+		roleRef := map[string]interface{}{
+			"apiGroup": "rbac.authorization.k8s.io",
+			"kind":     "ClusterRole",
+			"name":     group.ClusterRole,
+		}
+		if group.ClusterRole == "" {
+			roleRef["kind"] = "Role"
+			roleRef["name"] = resourceName
+		}
+
+		var objects []*map[string]interface{}
+		for _, namespace := range group.Namespaces {
+			if group.ClusterRole == "" {
+				var rules []map[string]interface{}
+				for _, rule := range group.Rules {
+					apiGroups := rule.ApiGroups
+					if len(apiGroups) == 0 {
+						apiGroups = []string{""}
+					}
+					ruleObj := map[string]interface{}{
+						"apiGroups": apiGroups,
+						"resources": rule.Resources,
+						"verbs":     rule.Verbs,
+					}
+					if len(rule.ResourceNames) > 0 {
+						ruleObj["resourceNames"] = rule.ResourceNames
+					}
+					rules = append(rules, ruleObj)
+				}
+				objects = append(objects, &map[string]interface{}{
+					"apiVersion": "rbac.authorization.k8s.io/v1",
+					"kind":       "Role",
+					"metadata": map[string]interface{}{
+						"name":      resourceName,
+						"namespace": namespace,
+					},
+					"rules": rules,
+				})
+			}
+			objects = append(objects, &map[string]interface{}{
+				"apiVersion": "rbac.authorization.k8s.io/v1",
+				"kind":       "RoleBinding",
+				"metadata": map[string]interface{}{
+					"name":      resourceName,
+					"namespace": namespace,
+				},
+				"roleRef": roleRef,
+				"subjects": []map[string]interface{}{
+					{
+						"apiGroup": "rbac.authorization.k8s.io",
+						"kind":     "Group",
+						"name":     group.Name,
+					},
+				},
+			})
+		}
+		manifests = append(manifests, cluster.AddManifest(jsii.String("RbacGroup"+types.EncodeConstructId(group.Name)), objects...))
+	}
+	return manifests
+}
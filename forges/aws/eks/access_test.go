@@ -0,0 +1,121 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2/assertions"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/jsii-runtime-go"
+)
+
+func TestEksAccessConfigValidate(t *testing.T) {
+	valid := EksAccessConfig{
+		AuthenticationMode: "api",
+		Entries: []EksAccessEntryConfig{
+			{PrincipalArn: "arn:aws:iam::123456789012:role/DataScience", KubernetesGroups: []string{"ml-readers"}, Policies: []EksAccessPolicyConfig{{Policy: "Edit", Namespaces: []string{"ml"}}}},
+			{PermissionSet: "ReadOnly", Policies: []EksAccessPolicyConfig{{Policy: "AmazonEKSAdminViewPolicy"}}},
+			{PrincipalArn: "arn:aws:sso:::permissionSet/ssoins-1234567890abcdef/ps-1234567890abcdef", Policies: []EksAccessPolicyConfig{{Policy: "View"}}},
+		},
+		Groups: []EksRbacGroupConfig{{Name: "ml-readers", Namespaces: []string{"ml"}, Rules: []EksRbacRule{{Resources: []string{"pods"}, Verbs: []string{"get"}}}}},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Expected valid config, got %v", err)
+	}
+	if !valid.IsApiMode() {
+		t.Error("Expected authenticationMode to be case-insensitive")
+	}
+
+	role := "arn:aws:iam::123456789012:role/Dev"
+	tests := []struct {
+		name   string
+		config EksAccessConfig
+		want   string
+	}{
+		{"config map", EksAccessConfig{AuthenticationMode: "CONFIG_MAP"}, "cannot switch back"},
+		{"missing principal", EksAccessConfig{Entries: []EksAccessEntryConfig{{Policies: []EksAccessPolicyConfig{{Policy: "View"}}}}}, "principalArn or permissionSet is required"},
+		{"not a principal arn", EksAccessConfig{Entries: []EksAccessEntryConfig{{PrincipalArn: "arn:aws:sso:::instance/ssoins-1", KubernetesGroups: []string{"a"}}}}, "must be an IAM user, IAM role or IAM Identity Center permission set ARN"},
+		{"duplicate principal", EksAccessConfig{Entries: []EksAccessEntryConfig{{PrincipalArn: role, KubernetesGroups: []string{"a"}}, {PrincipalArn: role, KubernetesGroups: []string{"b"}}}}, "duplicate principal"},
+		{"no permissions", EksAccessConfig{Entries: []EksAccessEntryConfig{{PrincipalArn: role}}}, "policies or kubernetesGroups"},
+		{"system group", EksAccessConfig{Entries: []EksAccessEntryConfig{{PrincipalArn: role, KubernetesGroups: []string{"system:masters"}}}}, "reserved"},
+		{"unknown policy", EksAccessConfig{Entries: []EksAccessEntryConfig{{PrincipalArn: role, Policies: []EksAccessPolicyConfig{{Policy: "Owner"}}}}}, "unknown policy"},
+		{"namespace", EksAccessConfig{Entries: []EksAccessEntryConfig{{PrincipalArn: role, Policies: []EksAccessPolicyConfig{{Policy: "View", Namespaces: []string{"ML"}}}}}}, "invalid namespace"},
+		{"group name conflict", EksAccessConfig{Groups: []EksRbacGroupConfig{{Name: "ml.readers", Namespaces: []string{"ml"}, ClusterRole: "view"}, {Name: "ml-readers", Namespaces: []string{"ml"}, ClusterRole: "view"}}}, "both create infraforge-ml-readers"},
+		{"group role", EksAccessConfig{Groups: []EksRbacGroupConfig{{Name: "dev", Namespaces: []string{"dev"}, ClusterRole: "edit", Rules: []EksRbacRule{{Resources: []string{"pods"}, Verbs: []string{"get"}}}}}}, "exactly one of clusterRole or rules"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCreateAccessEntries(t *testing.T) {
+	eksInstance := &EksInstanceConfig{
+		AdminUsers: "dev, ops",
+		Access: EksAccessConfig{
+			AuthenticationMode: "API",
+			Entries: []EksAccessEntryConfig{
+				// 最后一段相同或去掉标点后相同的主体使用不同的 Construct ID
+				{PrincipalArn: "arn:aws:iam::123456789012:role/team/dev", Policies: []EksAccessPolicyConfig{{Policy: "Edit", Namespaces: []string{"dev"}}}},
+				{PrincipalArn: "arn:aws:iam::123456789012:user/dev", Policies: []EksAccessPolicyConfig{{Policy: "View"}}},
+				{PrincipalArn: "arn:aws:iam::123456789012:role/a.b", KubernetesGroups: []string{"ml.readers"}},
+				{PrincipalArn: "arn:aws:iam::123456789012:role/ab", KubernetesGroups: []string{"ml-readers"}},
+				{PermissionSet: "PlatformAdmins", Policies: []EksAccessPolicyConfig{{Policy: "ClusterAdmin"}}},
+				{PrincipalArn: "arn:aws:sso:::permissionSet/ssoins-1234567890abcdef/ps-1234567890abcdef", Policies: []EksAccessPolicyConfig{{Policy: "View"}}},
+			},
+			Groups: []EksRbacGroupConfig{
+				{Name: "ml.readers", Namespaces: []string{"ml"}, ClusterRole: "view"},
+				{Name: "mlreaders", Namespaces: []string{"ml"}, Rules: []EksRbacRule{{Resources: []string{"pods"}, Verbs: []string{"get"}}}},
+			},
+		},
+	}
+	if err := eksInstance.Access.Validate(); err != nil {
+		t.Fatalf("Expected valid access config, got %v", err)
+	}
+	ctx, cluster := newTestCluster(t, eksInstance, awsec2.SubnetType_PRIVATE_WITH_EGRESS)
+	createAccessEntries(ctx.Stack, cluster, eksInstance)
+	createRbacGroups(cluster, eksInstance.Access.Groups)
+
+	template := assertions.Template_FromStack(ctx.Stack, nil)
+	// 6 个配置的条目，加上 adminUsers 中未配置的 ops；dev 已通过 ARN 配置
+	template.ResourceCountIs(jsii.String("AWS::EKS::AccessEntry"), jsii.Number(7))
+	template.HasResourceProperties(jsii.String("AWS::EKS::AccessEntry"), map[string]interface{}{
+		"PrincipalArn": "arn:aws:iam::123456789012:role/team/dev",
+		"AccessPolicies": []interface{}{map[string]interface{}{
+			"PolicyArn":   assertions.Match_StringLikeRegexp(jsii.String("cluster-access-policy/AmazonEKSEditPolicy$")),
+			"AccessScope": map[string]interface{}{"Type": "namespace", "Namespaces": []interface{}{"dev"}},
+		}},
+	})
+	template.HasResourceProperties(jsii.String("AWS::EKS::AccessEntry"), map[string]interface{}{
+		"Username": "ops",
+	})
+
+	// 权限集名称和 ARN 在部署时解析为角色
+	template.ResourceCountIs(jsii.String("Custom::SsoRole"), jsii.Number(2))
+	template.HasResourceProperties(jsii.String("Custom::SsoRole"), map[string]interface{}{
+		"PermissionSetName": "PlatformAdmins",
+	})
+	template.HasResourceProperties(jsii.String("AWS::EKS::AccessEntry"), map[string]interface{}{
+		"PrincipalArn": map[string]interface{}{"Fn::GetAtt": assertions.Match_ArrayWith(&[]interface{}{"RoleArn"})},
+	})
+
+	manifests := template.FindResources(jsii.String("Custom::AWSCDK-EKS-KubernetesResource"), nil)
+	rbacGroups := 0
+	for _, manifest := range *manifests {
+		content, _ := json.Marshal(manifest)
+		if strings.Contains(string(content), "infraforge-ml") {
+			rbacGroups++
+		}
+	}
+	if rbacGroups != 2 {
+		t.Errorf("Expected 2 RBAC group manifests, got %d", rbacGroups)
+	}
+}
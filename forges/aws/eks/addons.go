@@ -0,0 +1,251 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsiam"
+	"github.com/aws/aws-cdk-go/awscdk/v2/customresources"
+	"github.com/aws/constructs-go/constructs/v10"
+	"github.com/aws/jsii-runtime-go"
+)
+
+// 核心组件安装方式
+const (
+	AddonModeHelm    = "helm"    // Helm Chart 或原始清单（默认）
+	AddonModeManaged = "managed" // EKS 托管插件，由 AWS 负责升级
+)
+
+// EksAddonConfig 单个组件的托管插件配置，key 为 eksAddonSpecs 中的组件名称
+type EksAddonConfig struct {
+	Mode                string                 `json:"mode,omitempty"`                // managed 或 helm，默认使用 addonMode
+	Version             string                 `json:"version,omitempty"`             // 插件版本，如 v1.45.0-eksbuild.1，latest 表示最新兼容版本，留空使用 EKS 默认版本
+	ConfigurationValues map[string]interface{} `json:"configurationValues,omitempty"` // 与 aws eks describe-addon-configuration 的 schema 一致
+	ResolveConflicts    string                 `json:"resolveConflicts,omitempty"`    // OVERWRITE（默认）、PRESERVE、NONE
+	PreserveOnDelete    *bool                  `json:"preserveOnDelete,omitempty"`    // 删除插件时保留集群中的资源
+}
+
+// eksAddonSpec 组件对应的 EKS 托管插件
+type eksAddonSpec struct {
+	addonName          string
+	serviceAccountName string // 需要 AWS 权限的 ServiceAccount（kube-system），为空表示使用节点角色
+	managedPolicyName  string
+}
+
+var eksAddonSpecs = map[string]eksAddonSpec{
+	"vpcCni":                {addonName: "vpc-cni"},
+	"coreDns":               {addonName: "coredns"},
+	"podIdentityAgent":      {addonName: "eks-pod-identity-agent"},
+	"metricsServer":         {addonName: "metrics-server"},
+	"ebsCsiDriver":          {addonName: "aws-ebs-csi-driver", serviceAccountName: "ebs-csi-controller-sa", managedPolicyName: "service-role/AmazonEBSCSIDriverPolicy"},
+	"efsCsiDriver":          {addonName: "aws-efs-csi-driver", serviceAccountName: "efs-csi-controller-sa", managedPolicyName: "service-role/AmazonEFSCSIDriverPolicy"},
+	"fsxCsiDriver":          {addonName: "aws-fsx-csi-driver", serviceAccountName: "fsx-csi-controller-sa"},
+	"mountpointS3CsiDriver": {addonName: "aws-mountpoint-s3-csi-driver", serviceAccountName: "s3-csi-driver-sa"},
+}
+
+var (
+	addonVersionPattern  = regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+-eksbuild\.[0-9]+$`)
+	addonConflictOptions = []string{"OVERWRITE", "PRESERVE", "NONE"}
+)
+
+// AddonModeFor 返回组件的安装方式，组件配置优先于全局 addonMode
+func (c *EksInstanceConfig) AddonModeFor(component string) string {
+	if addon, ok := c.Addons[component]; ok && addon.Mode != "" {
+		return strings.ToLower(addon.Mode)
+	}
+	if c.AddonMode != "" {
+		return strings.ToLower(c.AddonMode)
+	}
+	return AddonModeHelm
+}
+
+// validateAddons 校验安装方式、组件名称和冲突处理方式
+func (c *EksInstanceConfig) validateAddons() error {
+	validMode := func(mode string) bool {
+		mode = strings.ToLower(mode)
+		return mode == "" || mode == AddonModeHelm || mode == AddonModeManaged
+	}
+	if !validMode(c.AddonMode) {
+		return fmt.Errorf("unknown addonMode '%s', expected managed or helm", c.AddonMode)
+	}
+	for component, addon := range c.Addons {
+		if _, ok := eksAddonSpecs[component]; !ok {
+			return fmt.Errorf("unknown addons component '%s', expected one of %s", component, strings.Join(addonComponents(), ", "))
+		}
+		if !validMode(addon.Mode) {
+			return fmt.Errorf("addons.%s: unknown mode '%s', expected managed or helm", component, addon.Mode)
+		}
+		if addon.Version != "" && addon.Version != "latest" && !addonVersionPattern.MatchString(addon.Version) {
+			return fmt.Errorf("addons.%s: version '%s' must be latest or an add-on version such as v1.45.0-eksbuild.1", component, addon.Version)
+		}
+		if addon.ResolveConflicts != "" && !containsString(addonConflictOptions, strings.ToUpper(addon.ResolveConflicts)) {
+			return fmt.Errorf("addons.%s: unknown resolveConflicts '%s', expected OVERWRITE, PRESERVE or NONE", component, addon.ResolveConflicts)
+		}
+	}
+	return nil
+}
+
+func addonComponents() []string {
+	var components []string
+	for component := range eksAddonSpecs {
+		components = append(components, component)
+	}
+	sort.Strings(components)
+	return components
+}
+
+// eksAddons 以 EKS 托管插件方式安装核心组件
+type eksAddons struct {
+	stack       awscdk.Stack
+	cluster     awseks.Cluster
+	eksInstance *EksInstanceConfig
+	nodeGroup   constructs.Construct // 插件中的 Deployment 需要节点才能变为 ACTIVE
+	podIdentity constructs.Construct // Pod Identity Agent，存在时使用 Pod Identity 关联代替 IRSA
+}
+
+func newEksAddons(stack awscdk.Stack, cluster awseks.Cluster, eksInstance *EksInstanceConfig, nodeGroup constructs.Construct) *eksAddons {
+	return &eksAddons{
+		stack:       stack,
+		cluster:     cluster,
+		eksInstance: eksInstance,
+		nodeGroup:   nodeGroup,
+	}
+}
+
+// isManaged 组件是否以托管插件方式安装
+func (a *eksAddons) isManaged(component string) bool {
+	return a.eksInstance.AddonModeFor(component) == AddonModeManaged
+}
+
+// deploy 创建托管插件
+// componentVersion 为组件原有的版本字段（如 ebsCsiDriverVersion），Helm Chart 版本无法用于托管插件，此时使用 EKS 默认版本；
+// customPolicy 为需要附加到 ServiceAccount 角色的自定义策略；defaultValues 为默认配置值，用户配置按顶层 key 覆盖
+func (a *eksAddons) deploy(component string, componentVersion string, customPolicy awsiam.Policy, defaultValues map[string]interface{}) awseks.CfnAddon {
+	spec := eksAddonSpecs[component]
+	addonConfig := a.eksInstance.Addons[component]
+	id := strings.ToUpper(component[:1]) + component[1:] + "Addon"
+
+	resolveConflicts := strings.ToUpper(addonConfig.ResolveConflicts)
+	if resolveConflicts == "" {
+		// 覆盖集群中已有的自管理版本
+		resolveConflicts = "OVERWRITE"
+	}
+	props := &awseks.CfnAddonProps{
+		AddonName:        jsii.String(spec.addonName),
+		ClusterName:      a.cluster.ClusterName(),
+		AddonVersion:     a.resolveVersion(id, spec.addonName, component, componentVersion),
+		ResolveConflicts: jsii.String(resolveConflicts),
+	}
+	if addonConfig.PreserveOnDelete != nil {
+		props.PreserveOnDelete = addonConfig.PreserveOnDelete
+	}
+
+	values := make(map[string]interface{})
+	for k, v := range defaultValues {
+		values[k] = v
+	}
+	for k, v := range addonConfig.ConfigurationValues {
+		values[k] = v
+	}
+	if len(values) > 0 {
+		configurationValues, err := json.Marshal(values)
+		if err != nil {
+			panic(fmt.Errorf("invalid configurationValues for add-on %s: %w", spec.addonName, err))
+		}
+		props.ConfigurationValues = jsii.String(string(configurationValues))
+	}
+
+	// ServiceAccount 角色：有 Pod Identity Agent 时使用 Pod Identity 关联，否则使用 IRSA
+	if spec.serviceAccountName != "" {
+		var role awsiam.Role
+		if a.podIdentity != nil {
+			role = newPodIdentityRole(a.stack, id+"Role")
+			props.PodIdentityAssociations = &[]*awseks.CfnAddon_PodIdentityAssociationProperty{
+				{
+					RoleArn:        role.RoleArn(),
+					ServiceAccount: jsii.String(spec.serviceAccountName),
+				},
+			}
+		} else {
+			role = awsiam.NewRole(a.stack, jsii.String(id+"Role"), &awsiam.RoleProps{
+				AssumedBy: a.irsaPrincipal(id, spec.serviceAccountName),
+			})
+			props.ServiceAccountRoleArn = role.RoleArn()
+		}
+		if spec.managedPolicyName != "" {
+			role.AddManagedPolicy(awsiam.ManagedPolicy_FromAwsManagedPolicyName(jsii.String(spec.managedPolicyName)))
+		}
+		if customPolicy != nil {
+			role.AttachInlinePolicy(customPolicy)
+		}
+	}
+
+	addon := awseks.NewCfnAddon(a.stack, jsii.String(id), props)
+	if a.nodeGroup != nil {
+		addon.Node().AddDependency(a.nodeGroup)
+	}
+	if a.podIdentity != nil && spec.serviceAccountName != "" {
+		addon.Node().AddDependency(a.podIdentity)
+	}
+	return addon
+}
+
+// resolveVersion 解析插件版本：addons.<component>.version 优先，latest 通过 DescribeAddonVersions 查询当前 Kubernetes 版本的最新插件版本
+func (a *eksAddons) resolveVersion(id string, addonName string, component string, componentVersion string) *string {
+	version := a.eksInstance.Addons[component].Version
+	if version == "" {
+		version = componentVersion
+	}
+
+	switch {
+	case version == "":
+		return nil
+	case version == "latest":
+		lookup := customresources.NewAwsCustomResource(a.stack, jsii.String(id+"Version"), &customresources.AwsCustomResourceProps{
+			OnUpdate: &customresources.AwsSdkCall{
+				Service: jsii.String("EKS"),
+				Action:  jsii.String("describeAddonVersions"),
+				Parameters: map[string]interface{}{
+					"addonName":         addonName,
+					"kubernetesVersion": a.eksInstance.addonKubernetesVersion(),
+				},
+				// Kubernetes 版本变化时重新查询
+				PhysicalResourceId: customresources.PhysicalResourceId_Of(jsii.String(fmt.Sprintf("%s-%s", addonName, a.eksInstance.addonKubernetesVersion()))),
+				OutputPaths:        jsii.Strings("addons.0.addonVersions.0.addonVersion"),
+			},
+			Policy: customresources.AwsCustomResourcePolicy_FromSdkCalls(&customresources.SdkCallsPolicyOptions{
+				Resources: customresources.AwsCustomResourcePolicy_ANY_RESOURCE(),
+			}),
+		})
+		return lookup.GetResponseField(jsii.String("addons.0.addonVersions.0.addonVersion"))
+	case addonVersionPattern.MatchString(version):
+		return jsii.String(version)
+	default:
+		fmt.Printf("Warning: %s version '%s' is not an EKS add-on version, using the default %s add-on version for Kubernetes %s\n",
+			component, version, addonName, a.eksInstance.EksVersion)
+		return nil
+	}
+}
+
+// irsaPrincipal 信任集群 OIDC 提供商中 kube-system 命名空间的 ServiceAccount
+func (a *eksAddons) irsaPrincipal(id string, serviceAccountName string) awsiam.IPrincipal {
+	issuer := *a.cluster.ClusterOpenIdConnectIssuer()
+	// 条件 key 中包含 Token，需要 CfnJson 在部署时解析
+	conditions := awscdk.NewCfnJson(a.stack, jsii.String(id+"Conditions"), &awscdk.CfnJsonProps{
+		Value: map[string]interface{}{
+			issuer + ":sub": "system:serviceaccount:kube-system:" + serviceAccountName,
+			issuer + ":aud": "sts.amazonaws.com",
+		},
+	})
+	return awsiam.NewOpenIdConnectPrincipal(a.cluster.OpenIdConnectProvider(), &map[string]interface{}{
+		"StringEquals": conditions,
+	})
+}
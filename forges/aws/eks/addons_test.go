@@ -0,0 +1,125 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2/assertions"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/jsii-runtime-go"
+)
+
+func TestAddonModeFor(t *testing.T) {
+	c := &EksInstanceConfig{
+		AddonMode: "Managed",
+		Addons:    map[string]EksAddonConfig{"metricsServer": {Mode: "helm"}},
+	}
+	if c.AddonModeFor("ebsCsiDriver") != AddonModeManaged {
+		t.Errorf("Expected addonMode to apply to components without overrides")
+	}
+	if c.AddonModeFor("metricsServer") != AddonModeHelm {
+		t.Errorf("Expected addons.metricsServer.mode to override addonMode")
+	}
+	if (&EksInstanceConfig{}).AddonModeFor("vpcCni") != AddonModeHelm {
+		t.Errorf("Expected helm by default")
+	}
+}
+
+func TestValidateAddons(t *testing.T) {
+	tests := []struct {
+		name   string
+		config EksInstanceConfig
+		want   string
+	}{
+		{"valid", EksInstanceConfig{AddonMode: "managed", Addons: map[string]EksAddonConfig{"ebsCsiDriver": {Version: "v1.45.0-eksbuild.1", ResolveConflicts: "preserve"}, "coreDns": {Version: "latest"}}}, ""},
+		{"addonMode", EksInstanceConfig{AddonMode: "eks"}, "unknown addonMode"},
+		{"component", EksInstanceConfig{Addons: map[string]EksAddonConfig{"kubeProxy": {}}}, "unknown addons component"},
+		{"version", EksInstanceConfig{Addons: map[string]EksAddonConfig{"vpcCni": {Version: "1.20.4"}}}, "add-on version"},
+		{"resolveConflicts", EksInstanceConfig{Addons: map[string]EksAddonConfig{"vpcCni": {ResolveConflicts: "REPLACE"}}}, "unknown resolveConflicts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validateAddons()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestDeployManagedAddons(t *testing.T) {
+	eksInstance := &EksInstanceConfig{
+		AddonMode: AddonModeManaged,
+		Addons: map[string]EksAddonConfig{
+			"ebsCsiDriver": {ConfigurationValues: map[string]interface{}{"controller": map[string]interface{}{"replicaCount": 1}}},
+			"coreDns":      {Version: "v1.11.3-eksbuild.1", ResolveConflicts: "preserve"},
+		},
+	}
+	ctx, cluster := newTestCluster(t, eksInstance, awsec2.SubnetType_PRIVATE_WITH_EGRESS)
+	addons := newEksAddons(ctx.Stack, cluster, eksInstance, nil)
+	addons.deploy("coreDns", "", nil, nil)
+	addons.deploy("ebsCsiDriver", "v1.45.0-eksbuild.1", nil, map[string]interface{}{"sidecars": map[string]interface{}{}})
+
+	template := assertions.Template_FromStack(ctx.Stack, nil)
+	template.ResourceCountIs(jsii.String("AWS::EKS::Addon"), jsii.Number(2))
+	template.HasResourceProperties(jsii.String("AWS::EKS::Addon"), map[string]interface{}{
+		"AddonName":             "coredns",
+		"AddonVersion":          "v1.11.3-eksbuild.1",
+		"ResolveConflicts":      "PRESERVE",
+		"ServiceAccountRoleArn": assertions.Match_Absent(),
+	})
+	// 用户配置按顶层 key 覆盖默认值，ServiceAccount 角色通过 IRSA 信任 kube-system 中的 ServiceAccount
+	template.HasResourceProperties(jsii.String("AWS::EKS::Addon"), map[string]interface{}{
+		"AddonName":             "aws-ebs-csi-driver",
+		"AddonVersion":          "v1.45.0-eksbuild.1",
+		"ResolveConflicts":      "OVERWRITE",
+		"ConfigurationValues":   `{"controller":{"replicaCount":1},"sidecars":{}}`,
+		"ServiceAccountRoleArn": map[string]interface{}{"Fn::GetAtt": []interface{}{assertions.Match_StringLikeRegexp(jsii.String("^EbsCsiDriverAddonRole")), "Arn"}},
+	})
+	template.HasResourceProperties(jsii.String("AWS::IAM::Role"), map[string]interface{}{
+		"AssumeRolePolicyDocument": assertions.Match_ObjectLike(&map[string]interface{}{
+			"Statement": []interface{}{assertions.Match_ObjectLike(&map[string]interface{}{"Action": "sts:AssumeRoleWithWebIdentity"})},
+		}),
+		"ManagedPolicyArns": []interface{}{assertions.Match_ObjectLike(&map[string]interface{}{
+			"Fn::Join": []interface{}{"", assertions.Match_ArrayWith(&[]interface{}{":iam::aws:policy/service-role/AmazonEBSCSIDriverPolicy"})},
+		})},
+	})
+
+	// 有 Pod Identity Agent 时改用 Pod Identity 关联
+	podIdentityInstance := &EksInstanceConfig{AddonMode: AddonModeManaged}
+	ctx, cluster = newTestCluster(t, podIdentityInstance, awsec2.SubnetType_PRIVATE_WITH_EGRESS)
+	addons = newEksAddons(ctx.Stack, cluster, podIdentityInstance, nil)
+	addons.podIdentity = addons.deploy("podIdentityAgent", "", nil, nil)
+	addons.deploy("efsCsiDriver", "", nil, nil)
+
+	template = assertions.Template_FromStack(ctx.Stack, nil)
+	template.HasResourceProperties(jsii.String("AWS::EKS::Addon"), map[string]interface{}{
+		"AddonName": "aws-efs-csi-driver",
+		"PodIdentityAssociations": []interface{}{map[string]interface{}{
+			"ServiceAccount": "efs-csi-controller-sa",
+			"RoleArn":        map[string]interface{}{"Fn::GetAtt": []interface{}{assertions.Match_StringLikeRegexp(jsii.String("^EfsCsiDriverAddonRole")), "Arn"}},
+		}},
+		"ServiceAccountRoleArn": assertions.Match_Absent(),
+	})
+	template.HasResource(jsii.String("AWS::EKS::Addon"), map[string]interface{}{
+		"Properties": map[string]interface{}{"AddonName": "aws-efs-csi-driver"},
+		"DependsOn":  assertions.Match_ArrayWith(&[]interface{}{assertions.Match_StringLikeRegexp(jsii.String("^PodIdentityAgentAddon"))}),
+	})
+	template.HasResourceProperties(jsii.String("AWS::IAM::Role"), map[string]interface{}{
+		"AssumeRolePolicyDocument": assertions.Match_ObjectLike(&map[string]interface{}{
+			"Statement": []interface{}{assertions.Match_ObjectLike(&map[string]interface{}{
+				"Action":    []interface{}{"sts:AssumeRole", "sts:TagSession"},
+				"Principal": map[string]interface{}{"Service": "pods.eks.amazonaws.com"},
+			})},
+		}),
+	})
+}
@@ -0,0 +1,174 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsiam"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awslambda"
+	"github.com/aws/aws-cdk-go/awscdk/v2/customresources"
+	"github.com/aws/constructs-go/constructs/v10"
+	"github.com/aws/jsii-runtime-go"
+)
+
+// autoModeHandler 开启 Auto Mode 的 Lambda 处理函数
+// on_event 在配置与集群不一致时调用 UpdateClusterConfig，is_complete 等待集群更新完成
+// 删除时不关闭 Auto Mode，集群删除时一并清理
+const autoModeHandler = `
+import boto3
+
+eks = boto3.client('eks')
+
+def on_event(event, context):
+    props = event['ResourceProperties']
+    cluster_name = props['ClusterName']
+    if event['RequestType'] == 'Delete':
+        return {'PhysicalResourceId': cluster_name}
+
+    compute = eks.describe_cluster(name=cluster_name)['cluster'].get('computeConfig') or {}
+    if compute.get('enabled') and sorted(compute.get('nodePools', [])) == sorted(props['NodePools']) and compute.get('nodeRoleArn') == props['NodeRoleArn']:
+        return {'PhysicalResourceId': cluster_name}
+
+    update = eks.update_cluster_config(
+        name=cluster_name,
+        computeConfig={'enabled': True, 'nodePools': props['NodePools'], 'nodeRoleArn': props['NodeRoleArn']},
+        storageConfig={'blockStorage': {'enabled': True}},
+        kubernetesNetworkConfig={'elasticLoadBalancing': {'enabled': True}},
+    )['update']
+    return {'PhysicalResourceId': cluster_name, 'Data': {'UpdateId': update['id']}}
+
+def is_complete(event, context):
+    update_id = event.get('Data', {}).get('UpdateId')
+    if event['RequestType'] == 'Delete' or not update_id:
+        return {'IsComplete': True}
+
+    update = eks.describe_update(name=event['ResourceProperties']['ClusterName'], updateId=update_id)['update']
+    if update['status'] in ('Failed', 'Cancelled'):
+        raise Exception('EKS Auto Mode update %s failed: %s' % (update_id, update.get('errors', [])))
+    return {'IsComplete': update['status'] == 'Successful'}
+`
+
+// EksAutoModeConfig EKS Auto Mode 配置，启用后由 EKS 管理计算、块存储和负载均衡，不再部署 Karpenter
+type EksAutoModeConfig struct {
+	Enabled   *bool    `json:"enabled,omitempty"`
+	NodePools []string `json:"nodePools,omitempty"` // 内置节点池：general-purpose、system，默认两者都启用
+}
+
+// Auto Mode 内置节点池
+const (
+	AutoModeNodePoolGeneralPurpose = "general-purpose"
+	AutoModeNodePoolSystem         = "system"
+)
+
+// minAutoModeMinor Auto Mode 要求的最低 Kubernetes 次版本
+const minAutoModeMinor = 29
+
+// IsEnabled 是否启用 Auto Mode
+func (a EksAutoModeConfig) IsEnabled() bool {
+	return a.Enabled != nil && *a.Enabled
+}
+
+// GetNodePools 返回启用的内置节点池
+func (a EksAutoModeConfig) GetNodePools() []string {
+	if len(a.NodePools) == 0 {
+		return []string{AutoModeNodePoolGeneralPurpose, AutoModeNodePoolSystem}
+	}
+	return a.NodePools
+}
+
+// validateAutoMode 校验内置节点池名称和 Kubernetes 版本，Auto Mode 不部署 Karpenter，不能同时配置 karpenterNodePools
+func (c *EksInstanceConfig) validateAutoMode() error {
+	if !c.AutoMode.IsEnabled() {
+		return nil
+	}
+	if !c.KarpenterNodePools.IsEmpty() {
+		return fmt.Errorf("autoMode: karpenterNodePools is not supported, EKS Auto Mode manages nodes with its built-in node pools")
+	}
+	for _, nodePool := range c.AutoMode.NodePools {
+		if nodePool != AutoModeNodePoolGeneralPurpose && nodePool != AutoModeNodePoolSystem {
+			return fmt.Errorf("autoMode: unknown node pool '%s', expected %s or %s", nodePool, AutoModeNodePoolGeneralPurpose, AutoModeNodePoolSystem)
+		}
+	}
+	if minor, ok := kubernetesMinor(c.EksVersion); ok && minor < minAutoModeMinor {
+		return fmt.Errorf("autoMode: requires Kubernetes 1.%d or later, got %s", minAutoModeMinor, c.EksVersion)
+	}
+	return nil
+}
+
+// configureAutoModeClusterRole 为 CDK 创建的集群角色附加 Auto Mode 所需的 sts:TagSession 以及计算、存储、负载均衡和网络策略
+// 沿用已有的集群角色，更换角色会触发集群替换，而集群名称固定时替换会失败
+func configureAutoModeClusterRole(cluster awseks.Cluster) {
+	role := cluster.Role().(awsiam.Role)
+	for _, policyName := range []string{"AmazonEKSComputePolicy", "AmazonEKSBlockStoragePolicy", "AmazonEKSLoadBalancingPolicy", "AmazonEKSNetworkingPolicy"} {
+		role.AddManagedPolicy(awsiam.ManagedPolicy_FromAwsManagedPolicyName(jsii.String(policyName)))
+	}
+	role.AssumeRolePolicy().AddStatements(awsiam.NewPolicyStatement(&awsiam.PolicyStatementProps{
+		Actions:    jsii.Strings("sts:TagSession"),
+		Principals: &[]awsiam.IPrincipal{awsiam.NewServicePrincipal(jsii.String("eks.amazonaws.com"), nil)},
+	}))
+}
+
+// configureAutoMode 为集群开启 Auto Mode 的计算、块存储和负载均衡
+// awseks.Cluster 通过自定义资源创建集群，属性传给 Handler 时会变成字符串，而 Handler 只转换少数布尔字段，
+// 这里在集群创建后调用 UpdateClusterConfig 开启 Auto Mode，返回的资源在集群更新完成后才就绪
+func configureAutoMode(scope constructs.Construct, cluster awseks.Cluster, eksInstance *EksInstanceConfig) awscdk.CustomResource {
+	configureAutoModeClusterRole(cluster)
+
+	// EKS 为节点角色自动创建 EC2 类型的访问条目
+	nodeRole := awsiam.NewRole(scope, jsii.String(fmt.Sprintf("%s-auto-mode-node-role", eksInstance.GetID())), &awsiam.RoleProps{
+		AssumedBy:   awsiam.NewServicePrincipal(jsii.String("ec2.amazonaws.com"), nil),
+		Description: jsii.String("EKS Auto Mode node role"),
+		ManagedPolicies: &[]awsiam.IManagedPolicy{
+			awsiam.ManagedPolicy_FromAwsManagedPolicyName(jsii.String("AmazonEKSWorkerNodeMinimalPolicy")),
+			awsiam.ManagedPolicy_FromAwsManagedPolicyName(jsii.String("AmazonEC2ContainerRegistryPullOnly")),
+		},
+	})
+
+	onEvent := awslambda.NewFunction(scope, jsii.String(fmt.Sprintf("%s-auto-mode-on-event", eksInstance.GetID())), &awslambda.FunctionProps{
+		Runtime: awslambda.Runtime_PYTHON_3_13(),
+		Handler: jsii.String("index.on_event"),
+		Code:    awslambda.Code_FromInline(jsii.String(autoModeHandler)),
+		Timeout: awscdk.Duration_Minutes(jsii.Number(1)),
+	})
+	onEvent.AddToRolePolicy(awsiam.NewPolicyStatement(&awsiam.PolicyStatementProps{
+		Actions:   jsii.Strings("eks:DescribeCluster", "eks:UpdateClusterConfig"),
+		Resources: jsii.Strings(*cluster.ClusterArn()),
+	}))
+	// 开启 Auto Mode 时需要传递节点角色
+	onEvent.AddToRolePolicy(awsiam.NewPolicyStatement(&awsiam.PolicyStatementProps{
+		Actions:   jsii.Strings("iam:PassRole"),
+		Resources: jsii.Strings(*nodeRole.RoleArn()),
+	}))
+
+	isComplete := awslambda.NewFunction(scope, jsii.String(fmt.Sprintf("%s-auto-mode-is-complete", eksInstance.GetID())), &awslambda.FunctionProps{
+		Runtime: awslambda.Runtime_PYTHON_3_13(),
+		Handler: jsii.String("index.is_complete"),
+		Code:    awslambda.Code_FromInline(jsii.String(autoModeHandler)),
+		Timeout: awscdk.Duration_Minutes(jsii.Number(1)),
+	})
+	isComplete.AddToRolePolicy(awsiam.NewPolicyStatement(&awsiam.PolicyStatementProps{
+		Actions:   jsii.Strings("eks:DescribeUpdate"),
+		Resources: jsii.Strings(*cluster.ClusterArn()),
+	}))
+
+	provider := customresources.NewProvider(scope, jsii.String(fmt.Sprintf("%s-auto-mode-provider", eksInstance.GetID())), &customresources.ProviderProps{
+		OnEventHandler:    onEvent,
+		IsCompleteHandler: isComplete,
+		QueryInterval:     awscdk.Duration_Seconds(jsii.Number(30)),
+		TotalTimeout:      awscdk.Duration_Minutes(jsii.Number(30)),
+	})
+
+	return awscdk.NewCustomResource(scope, jsii.String(fmt.Sprintf("%s-auto-mode", eksInstance.GetID())), &awscdk.CustomResourceProps{
+		ServiceToken: provider.ServiceToken(),
+		ResourceType: jsii.String("Custom::EksAutoMode"),
+		Properties: &map[string]interface{}{
+			"ClusterName": cluster.ClusterName(),
+			"NodePools":   eksInstance.AutoMode.GetNodePools(),
+			"NodeRoleArn": nodeRole.RoleArn(),
+		},
+	})
+}
@@ -0,0 +1,255 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/awslabs/InfraForge/core/interfaces"
+	"github.com/awslabs/InfraForge/core/utils/types"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awskms"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awslogs"
+	"github.com/aws/constructs-go/constructs/v10"
+	"github.com/aws/jsii-runtime-go"
+)
+
+// 集群 API 端点访问方式
+const (
+	EndpointAccessPublic           = "public"
+	EndpointAccessPrivate          = "private"
+	EndpointAccessPublicAndPrivate = "publicAndPrivate"
+)
+
+// EksControlPlaneLoggingConfig 控制平面日志，写入 /aws/eks/<集群名称>/cluster 日志组
+type EksControlPlaneLoggingConfig struct {
+	Types         []string `json:"types,omitempty"`         // api、audit、authenticator、controllerManager、scheduler
+	RetentionDays int      `json:"retentionDays,omitempty"` // 日志保留天数，0 表示永久保留
+}
+
+// EksSecretsEncryptionConfig 使用 KMS 密钥对 Kubernetes Secret 做信封加密，启用后不能关闭
+type EksSecretsEncryptionConfig struct {
+	Enabled   *bool  `json:"enabled,omitempty"`
+	KmsKeyArn string `json:"kmsKeyArn,omitempty"` // 留空时创建开启自动轮换的密钥
+}
+
+var controlPlaneLogTypes = map[string]awseks.ClusterLoggingTypes{
+	"api":               awseks.ClusterLoggingTypes_API,
+	"audit":             awseks.ClusterLoggingTypes_AUDIT,
+	"authenticator":     awseks.ClusterLoggingTypes_AUTHENTICATOR,
+	"controllerManager": awseks.ClusterLoggingTypes_CONTROLLER_MANAGER,
+	"scheduler":         awseks.ClusterLoggingTypes_SCHEDULER,
+}
+
+// logRetentionDays CloudWatch Logs 支持的保留天数
+var logRetentionDays = map[int]awslogs.RetentionDays{
+	1:    awslogs.RetentionDays_ONE_DAY,
+	3:    awslogs.RetentionDays_THREE_DAYS,
+	5:    awslogs.RetentionDays_FIVE_DAYS,
+	7:    awslogs.RetentionDays_ONE_WEEK,
+	14:   awslogs.RetentionDays_TWO_WEEKS,
+	30:   awslogs.RetentionDays_ONE_MONTH,
+	60:   awslogs.RetentionDays_TWO_MONTHS,
+	90:   awslogs.RetentionDays_THREE_MONTHS,
+	120:  awslogs.RetentionDays_FOUR_MONTHS,
+	150:  awslogs.RetentionDays_FIVE_MONTHS,
+	180:  awslogs.RetentionDays_SIX_MONTHS,
+	365:  awslogs.RetentionDays_ONE_YEAR,
+	400:  awslogs.RetentionDays_THIRTEEN_MONTHS,
+	545:  awslogs.RetentionDays_EIGHTEEN_MONTHS,
+	731:  awslogs.RetentionDays_TWO_YEARS,
+	1096: awslogs.RetentionDays_THREE_YEARS,
+	1827: awslogs.RetentionDays_FIVE_YEARS,
+	2192: awslogs.RetentionDays_SIX_YEARS,
+	2557: awslogs.RetentionDays_SEVEN_YEARS,
+	2922: awslogs.RetentionDays_EIGHT_YEARS,
+	3288: awslogs.RetentionDays_NINE_YEARS,
+	3653: awslogs.RetentionDays_TEN_YEARS,
+}
+
+// privateEndpointServices 私有端点模式下 kubectl Handler 在 VPC 内访问的 AWS 服务：
+// update-kubeconfig 调用 EKS API，Handler 通过 STS 扮演集群创建角色
+var privateEndpointServices = []struct {
+	name    string
+	service func() awsec2.InterfaceVpcEndpointAwsService
+}{
+	{"eks", awsec2.InterfaceVpcEndpointAwsService_EKS},
+	{"sts", awsec2.InterfaceVpcEndpointAwsService_STS},
+}
+
+// validateControlPlane 校验端点访问方式、公有访问 CIDR、日志类型和保留天数
+func (c *EksInstanceConfig) validateControlPlane() error {
+	switch c.EndpointAccess {
+	case "", EndpointAccessPublic, EndpointAccessPrivate, EndpointAccessPublicAndPrivate:
+	default:
+		return fmt.Errorf("unknown endpointAccess '%s', expected public, private or publicAndPrivate", c.EndpointAccess)
+	}
+	if len(c.PublicAccessCidrs) > 0 {
+		switch c.EndpointAccess {
+		case EndpointAccessPrivate:
+			return fmt.Errorf("publicAccessCidrs cannot be used with endpointAccess private")
+		case EndpointAccessPublic:
+			// 只开放公有端点时，节点和 kubectl Handler 也要通过受限的公有端点访问，CDK 要求同时开启私有端点
+			return fmt.Errorf("publicAccessCidrs requires endpointAccess publicAndPrivate, nodes could not reach a restricted public-only endpoint")
+		}
+		for _, cidr := range c.PublicAccessCidrs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return fmt.Errorf("publicAccessCidrs: '%s' is not a valid CIDR block", cidr)
+			}
+		}
+	}
+	for _, logType := range c.ControlPlaneLogging.Types {
+		if _, ok := controlPlaneLogTypes[logType]; !ok {
+			return fmt.Errorf("controlPlaneLogging: unknown type '%s', expected one of %s", logType, strings.Join(sortedLogTypes(), ", "))
+		}
+	}
+	if days := c.ControlPlaneLogging.RetentionDays; days != 0 {
+		if len(c.ControlPlaneLogging.Types) == 0 {
+			return fmt.Errorf("controlPlaneLogging: retentionDays requires at least one log type")
+		}
+		if _, ok := logRetentionDays[days]; !ok {
+			return fmt.Errorf("controlPlaneLogging: retentionDays %d is not supported by CloudWatch Logs, use e.g. 7, 14, 30, 90, 365", days)
+		}
+	}
+	if c.SecretsEncryption.KmsKeyArn != "" && !types.GetBoolValue(c.SecretsEncryption.Enabled, false) {
+		return fmt.Errorf("secretsEncryption: kmsKeyArn requires enabled")
+	}
+	return nil
+}
+
+func sortedLogTypes() []string {
+	var names []string
+	for name := range controlPlaneLogTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsPrivateEndpoint 集群 API 是否只有私有端点
+func (c *EksInstanceConfig) IsPrivateEndpoint() bool {
+	return c.EndpointAccess == EndpointAccessPrivate
+}
+
+// clusterEndpointAccess 返回端点访问方式，未配置时使用 CDK 默认的公有和私有端点
+// 公有端点受限或关闭时，CDK 会将 kubectl Handler 放到 VPC 私有子网中
+func (c *EksInstanceConfig) clusterEndpointAccess() awseks.EndpointAccess {
+	switch c.EndpointAccess {
+	case EndpointAccessPublic:
+		return awseks.EndpointAccess_PUBLIC()
+	case EndpointAccessPrivate:
+		return awseks.EndpointAccess_PRIVATE()
+	}
+	if len(c.PublicAccessCidrs) > 0 {
+		return awseks.EndpointAccess_PUBLIC_AND_PRIVATE().OnlyFrom(*jsii.Strings(c.PublicAccessCidrs...)...)
+	}
+	if c.EndpointAccess == EndpointAccessPublicAndPrivate {
+		return awseks.EndpointAccess_PUBLIC_AND_PRIVATE()
+	}
+	return nil
+}
+
+// clusterLogging 返回需要开启的控制平面日志类型
+func (c *EksInstanceConfig) clusterLogging() *[]awseks.ClusterLoggingTypes {
+	if len(c.ControlPlaneLogging.Types) == 0 {
+		return nil
+	}
+	var logTypes []awseks.ClusterLoggingTypes
+	for _, logType := range c.ControlPlaneLogging.Types {
+		logTypes = append(logTypes, controlPlaneLogTypes[logType])
+	}
+	return &logTypes
+}
+
+// secretsEncryptionKey 返回用于 Secret 信封加密的 KMS 密钥，未启用时为 nil
+func secretsEncryptionKey(scope constructs.Construct, eksInstance *EksInstanceConfig) awskms.IKey {
+	if !types.GetBoolValue(eksInstance.SecretsEncryption.Enabled, false) {
+		return nil
+	}
+	if eksInstance.SecretsEncryption.KmsKeyArn != "" {
+		return awskms.Key_FromKeyArn(scope, jsii.String(eksInstance.GetID()+"SecretsKmsKey"), jsii.String(eksInstance.SecretsEncryption.KmsKeyArn))
+	}
+	return awskms.NewKey(scope, jsii.String(eksInstance.GetID()+"SecretsKmsKey"), &awskms.KeyProps{
+		Description:       jsii.String(fmt.Sprintf("EKS %s secrets encryption", eksInstance.GetID())),
+		EnableKeyRotation: jsii.Bool(true),
+		RemovalPolicy:     awscdk.RemovalPolicy_RETAIN,
+	})
+}
+
+// configureControlPlaneLogRetention 设置控制平面日志组的保留天数，EKS 尚未创建日志组时由 LogRetention 创建
+func configureControlPlaneLogRetention(scope constructs.Construct, cluster awseks.Cluster, eksInstance *EksInstanceConfig) {
+	days := eksInstance.ControlPlaneLogging.RetentionDays
+	if days == 0 {
+		return
+	}
+	logRetention := awslogs.NewLogRetention(scope, jsii.String(eksInstance.GetID()+"ControlPlaneLogRetention"), &awslogs.LogRetentionProps{
+		LogGroupName: jsii.String(fmt.Sprintf("/aws/eks/%s/cluster", *cluster.ClusterName())),
+		Retention:    logRetentionDays[days],
+	})
+	logRetention.Node().AddDependency(cluster)
+}
+
+// privateSubnetType 私有端点、VPC 内 Handler 使用的子网类型，ctx.SubnetType 为公有子网时使用带出口的私有子网
+func privateSubnetType(ctx *interfaces.ForgeContext) awsec2.SubnetType {
+	if ctx.SubnetType == awsec2.SubnetType_PUBLIC {
+		return awsec2.SubnetType_PRIVATE_WITH_EGRESS
+	}
+	return ctx.SubnetType
+}
+
+// kubectlSubnetSelection 返回传给 awseks.Cluster 的 VpcSubnets
+// awseks.Cluster 只从 VpcSubnets 中选择 kubectl Handler 的私有子网（KubectlPrivateSubnets），
+// 公有端点关闭或受限而集群只选择了公有子网时，补充私有子网供 kubectl Handler 使用
+func kubectlSubnetSelection(ctx *interfaces.ForgeContext, eksInstance *EksInstanceConfig, clusterSubnets []*awsec2.SubnetSelection) []*awsec2.SubnetSelection {
+	if !(eksInstance.IsPrivateEndpoint() || len(eksInstance.PublicAccessCidrs) > 0) || ctx.SubnetType != awsec2.SubnetType_PUBLIC {
+		return clusterSubnets
+	}
+	selection := append([]*awsec2.SubnetSelection{}, clusterSubnets...)
+	return append(selection, &awsec2.SubnetSelection{SubnetType: privateSubnetType(ctx)})
+}
+
+// keepClusterSubnets 让集群继续使用原有子网，补充的私有子网只用于 kubectl Handler
+// 已有集群更换子网会触发替换，而集群名称固定时替换会失败
+func keepClusterSubnets(ctx *interfaces.ForgeContext, cluster awseks.Cluster, clusterSubnets []*awsec2.SubnetSelection) {
+	var subnetIds []*string
+	seen := make(map[string]bool)
+	for _, selection := range clusterSubnets {
+		for _, subnetId := range *ctx.VPC.SelectSubnets(selection).SubnetIds {
+			if !seen[*subnetId] {
+				seen[*subnetId] = true
+				subnetIds = append(subnetIds, subnetId)
+			}
+		}
+	}
+	// Cluster -> ClusterResource -> CustomResource -> CfnResource
+	clusterResource := cluster.Node().FindChild(jsii.String("Resource")).Node().DefaultChild()
+	resource := clusterResource.Node().DefaultChild().(awscdk.CfnResource)
+	resource.AddPropertyOverride(jsii.String("Config.resourcesVpcConfig.subnetIds"), subnetIds)
+}
+
+// createPrivateEndpoints 为私有端点集群创建 kubectl Handler 需要的 VPC 接口端点，集群内的资源在端点就绪后创建
+// VPC 中已有这些端点时设置 createVpcEndpoints 为 false，避免私有 DNS 冲突
+func createPrivateEndpoints(ctx *interfaces.ForgeContext, cluster awseks.Cluster, eksInstance *EksInstanceConfig) {
+	if !eksInstance.IsPrivateEndpoint() || !types.GetBoolValue(eksInstance.CreateVpcEndpoints, true) {
+		return
+	}
+	for _, endpointService := range privateEndpointServices {
+		endpoint := awsec2.NewInterfaceVpcEndpoint(ctx.Stack, jsii.String(fmt.Sprintf("%s-%s-endpoint", eksInstance.GetID(), endpointService.name)), &awsec2.InterfaceVpcEndpointProps{
+			Vpc:               ctx.VPC,
+			Service:           endpointService.service(),
+			PrivateDnsEnabled: jsii.Bool(true),
+			Subnets: &awsec2.SubnetSelection{
+				SubnetType: privateSubnetType(ctx),
+				OnePerAz:   jsii.Bool(true),
+			},
+		})
+		cluster.Node().AddDependency(endpoint)
+	}
+}
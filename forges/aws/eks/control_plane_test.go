@@ -0,0 +1,126 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/assertions"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
+	"github.com/aws/jsii-runtime-go"
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/interfaces"
+	"github.com/awslabs/InfraForge/forges/aws/eks/utils"
+)
+
+// newTestCluster 在包含公有和私有子网的 VPC 中按 Create 的方式创建集群，集群使用 subnetType 子网
+func newTestCluster(t *testing.T, eksInstance *EksInstanceConfig, subnetType awsec2.SubnetType) (*interfaces.ForgeContext, awseks.Cluster) {
+	t.Helper()
+	stack := awscdk.NewStack(awscdk.NewApp(nil), jsii.String("EksTest"), nil)
+	vpc := awsec2.NewVpc(stack, jsii.String("Vpc"), &awsec2.VpcProps{MaxAzs: jsii.Number(2)})
+	ctx := &interfaces.ForgeContext{Stack: stack, VPC: vpc, SubnetType: subnetType}
+	if eksInstance.ID == "" {
+		eksInstance.BaseInstanceConfig = config.BaseInstanceConfig{ID: "eks"}
+	}
+	if eksInstance.EksVersion == "" {
+		eksInstance.EksVersion = "1.31"
+	}
+
+	clusterSubnets := []*awsec2.SubnetSelection{{SubnetType: subnetType}}
+	vpcSubnets := kubectlSubnetSelection(ctx, eksInstance, clusterSubnets)
+	cluster := awseks.NewCluster(stack, jsii.String(eksInstance.GetID()), &awseks.ClusterProps{
+		ClusterName:     jsii.String(eksInstance.GetID()),
+		Version:         awseks.KubernetesVersion_Of(jsii.String(eksInstance.EksVersion)),
+		KubectlLayer:    utils.GetKubectlLayer(stack, "kubectl", eksInstance.EksVersion),
+		DefaultCapacity: jsii.Number(0),
+		Vpc:             vpc,
+		VpcSubnets:      &vpcSubnets,
+		EndpointAccess:  eksInstance.clusterEndpointAccess(),
+	})
+	if len(vpcSubnets) != len(clusterSubnets) {
+		keepClusterSubnets(ctx, cluster, clusterSubnets)
+	}
+	return ctx, cluster
+}
+
+func TestValidateControlPlane(t *testing.T) {
+	tests := []struct {
+		name   string
+		config EksInstanceConfig
+		want   string
+	}{
+		{"valid", EksInstanceConfig{EndpointAccess: "publicAndPrivate", PublicAccessCidrs: []string{"203.0.113.0/24"},
+			ControlPlaneLogging: EksControlPlaneLoggingConfig{Types: []string{"api", "audit", "controllerManager"}, RetentionDays: 90},
+			SecretsEncryption:   EksSecretsEncryptionConfig{Enabled: jsii.Bool(true), KmsKeyArn: "arn:aws:kms:us-east-1:111122223333:key/abc"}}, ""},
+		{"endpoint access", EksInstanceConfig{EndpointAccess: "internal"}, "unknown endpointAccess"},
+		{"cidrs with private", EksInstanceConfig{EndpointAccess: "private", PublicAccessCidrs: []string{"203.0.113.0/24"}}, "cannot be used with endpointAccess private"},
+		{"cidrs with public", EksInstanceConfig{EndpointAccess: "public", PublicAccessCidrs: []string{"203.0.113.0/24"}}, "requires endpointAccess publicAndPrivate"},
+		{"cidr", EksInstanceConfig{PublicAccessCidrs: []string{"203.0.113.1"}}, "not a valid CIDR"},
+		{"log type", EksInstanceConfig{ControlPlaneLogging: EksControlPlaneLoggingConfig{Types: []string{"controller-manager"}}}, "unknown type 'controller-manager'"},
+		{"retention without types", EksInstanceConfig{ControlPlaneLogging: EksControlPlaneLoggingConfig{RetentionDays: 30}}, "requires at least one log type"},
+		{"retention days", EksInstanceConfig{ControlPlaneLogging: EksControlPlaneLoggingConfig{Types: []string{"api"}, RetentionDays: 45}}, "retentionDays 45 is not supported"},
+		{"kms key without enabled", EksInstanceConfig{SecretsEncryption: EksSecretsEncryptionConfig{KmsKeyArn: "arn"}}, "kmsKeyArn requires enabled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validateControlPlane()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestPrivateEndpointSubnets(t *testing.T) {
+	tests := []struct {
+		name              string
+		config            EksInstanceConfig
+		subnetType        awsec2.SubnetType
+		wantEndpoints     int
+		wantKubectlSubnet bool
+	}{
+		{"public endpoint", EksInstanceConfig{}, awsec2.SubnetType_PUBLIC, 0, false},
+		{"private endpoint on public subnets", EksInstanceConfig{EndpointAccess: "private"}, awsec2.SubnetType_PUBLIC, 2, true},
+		{"restricted endpoint on public subnets", EksInstanceConfig{PublicAccessCidrs: []string{"203.0.113.0/24"}}, awsec2.SubnetType_PUBLIC, 0, true},
+		{"existing endpoints", EksInstanceConfig{EndpointAccess: "private", CreateVpcEndpoints: jsii.Bool(false)}, awsec2.SubnetType_PRIVATE_WITH_EGRESS, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cluster := newTestCluster(t, &tt.config, tt.subnetType)
+			createPrivateEndpoints(ctx, cluster, &tt.config)
+
+			kubectlSubnets := cluster.KubectlPrivateSubnets()
+			if hasKubectlSubnets := kubectlSubnets != nil && len(*kubectlSubnets) > 0; hasKubectlSubnets != tt.wantKubectlSubnet {
+				t.Errorf("Expected kubectl private subnets %v, got %v", tt.wantKubectlSubnet, hasKubectlSubnets)
+			}
+
+			template := assertions.Template_FromStack(ctx.Stack, nil)
+			template.ResourceCountIs(jsii.String("AWS::EC2::VPCEndpoint"), jsii.Number(tt.wantEndpoints))
+
+			// 集群只使用 subnetType 子网，补充的私有子网只给 kubectl Handler 使用
+			clusters := template.FindResources(jsii.String("Custom::AWSCDK-EKS-Cluster"), nil)
+			for _, resource := range *clusters {
+				vpcConfig := (*resource)["Properties"].(map[string]interface{})["Config"].(map[string]interface{})["resourcesVpcConfig"]
+				content, _ := json.Marshal(vpcConfig)
+				wantTier, otherTier := "PublicSubnet", "PrivateSubnet"
+				if tt.subnetType != awsec2.SubnetType_PUBLIC {
+					wantTier, otherTier = otherTier, wantTier
+				}
+				if !strings.Contains(string(content), wantTier) || strings.Contains(string(content), otherTier) {
+					t.Errorf("Expected cluster subnets in %s only, got %s", wantTier, content)
+				}
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awsiam"
+	"github.com/aws/constructs-go/constructs/v10"
 
 	"github.com/aws/jsii-runtime-go"
 )
@@ -28,7 +29,7 @@ type EksInstanceConfig struct {
 	config.BaseInstanceConfig
 	EksVersion 		 string `json:"eksVersion"`
 	KarpenterVersion	 string `json:"karpenterVersion"`
-	KarpenterNodePools	 string `json:"karpenterNodePools"`
+	KarpenterNodePools	 KarpenterNodePoolList `json:"karpenterNodePools"` // "cpu,gpu,neuron" 或节点池数组
 	KarpenterOsType		 string `json:"karpenterOsType"`
 	InstanceTypes		 string `json:"instanceTypes"`
 	// 移除 SharedKeyName，统一使用 KeyName
@@ -45,6 +46,37 @@ type EksInstanceConfig struct {
 	AdminUsers               string `json:"adminUsers,omitempty"` // 新增字段，逗号分隔的管理员用户列表
 	ControlPlaneAzIndices    string `json:"controlPlaneAzIndices,omitempty"` // 新增字段，用于指定控制平面可用区索引，格式为"1,2,3"
 	PodIdentityAgentVersion  string `json:"podIdentityAgentVersion,omitempty"` // Pod Identity Agent 版本
+
+	// 用于支持工作负载 ServiceAccount
+	ServiceAccounts          []EksServiceAccountConfig `json:"serviceAccounts,omitempty"` // 通过 Pod Identity 或 IRSA 获取 AWS 权限的 ServiceAccount
+
+	// 用于支持访问条目和命名空间 RBAC
+	Access                   EksAccessConfig `json:"access,omitempty"` // 认证模式、访问条目和自定义 Kubernetes 组
+
+	// 用于支持多个托管节点组
+	NodeGroups               []EksNodeGroupConfig `json:"nodeGroups,omitempty"` // 默认节点组之外的托管节点组，使用自定义启动模板
+
+	// 用于支持 Fargate 和 EKS Auto Mode
+	FargateProfiles          []EksFargateProfileConfig `json:"fargateProfiles,omitempty"` // 按命名空间和标签将 Pod 调度到 Fargate
+	AutoMode                 EksAutoModeConfig `json:"autoMode,omitempty"` // 启用后由 EKS 管理节点，不部署 Karpenter 和默认节点组
+
+	// 用于支持 API 端点访问控制、控制平面日志和 Secret 加密
+	EndpointAccess           string `json:"endpointAccess,omitempty"` // public、private、publicAndPrivate，默认公有和私有端点
+	PublicAccessCidrs        []string `json:"publicAccessCidrs,omitempty"` // 允许访问公有端点的 CIDR，需要 publicAndPrivate
+	CreateVpcEndpoints       *bool  `json:"createVpcEndpoints,omitempty"` // private 模式下是否为 kubectl Handler 创建 EKS 和 STS 接口端点，默认 true
+	ControlPlaneLogging      EksControlPlaneLoggingConfig `json:"controlPlaneLogging,omitempty"` // 控制平面日志类型和保留天数
+	SecretsEncryption        EksSecretsEncryptionConfig `json:"secretsEncryption,omitempty"` // KMS 信封加密 Kubernetes Secret
+
+	// 用于支持分阶段升级
+	Upgrade                  EksUpgradeConfig `json:"upgrade,omitempty"` // 当前版本和升级阶段，用于预检和分阶段升级
+
+	// 用于支持通用 Helm Release 和 Kubernetes 清单
+	HelmReleases             []EksHelmReleaseConfig `json:"helmReleases,omitempty"` // 在 Karpenter 和 CSI 驱动之后安装的 Helm Release
+	Manifests                []EksManifestConfig `json:"manifests,omitempty"` // 本地文件或 URL 中的 YAML 清单，synth 时读取
+
+	// 用于支持 EKS 托管插件
+	AddonMode                string `json:"addonMode,omitempty"` // 核心组件安装方式：helm（默认）或 managed
+	Addons                   map[string]EksAddonConfig `json:"addons,omitempty"` // 按组件覆盖安装方式、插件版本和配置值
 	
 	// 用于支持 Training Operator
 	DeployTrainingOperator   *bool  `json:"deployTrainingOperator,omitempty"` // 是否部署 Training Operator
@@ -152,8 +184,49 @@ type EksInstanceConfig struct {
 	KarpenterNeuronTaints              string `json:"karpenterNeuronTaints,omitempty"`
 }
 
+// Validate 校验 Karpenter 节点池、控制平面、托管节点组、Fargate、Auto Mode、托管插件、访问配置、ServiceAccount、Helm Release、升级预检和 S3 依赖
+func (c *EksInstanceConfig) Validate() error {
+	if err := c.KarpenterNodePools.Validate(); err != nil {
+		return fmt.Errorf("eks %s: %w", c.GetID(), err)
+	}
+	if err := c.validateControlPlane(); err != nil {
+		return fmt.Errorf("eks %s: %w", c.GetID(), err)
+	}
+	if err := c.validateNodeGroups(); err != nil {
+		return fmt.Errorf("eks %s: %w", c.GetID(), err)
+	}
+	if err := c.validateFargateProfiles(); err != nil {
+		return fmt.Errorf("eks %s: %w", c.GetID(), err)
+	}
+	if err := c.validateAutoMode(); err != nil {
+		return fmt.Errorf("eks %s: %w", c.GetID(), err)
+	}
+	if err := c.validateAddons(); err != nil {
+		return fmt.Errorf("eks %s: %w", c.GetID(), err)
+	}
+	if err := c.Access.Validate(); err != nil {
+		return fmt.Errorf("eks %s: %w", c.GetID(), err)
+	}
+	if err := c.validateServiceAccounts(); err != nil {
+		return fmt.Errorf("eks %s: %w", c.GetID(), err)
+	}
+	if err := c.validateHelmReleases(); err != nil {
+		return fmt.Errorf("eks %s: %w", c.GetID(), err)
+	}
+	if err := c.validateUpgrade(); err != nil {
+		return fmt.Errorf("eks %s: %w", c.GetID(), err)
+	}
+	if c.S3BucketName == "" {
+		if _, err := dependency.ResolveS3BucketName(c.DependsOn); err != nil {
+			return fmt.Errorf("eks %s: failed to resolve the S3 bucket in dependsOn: %w", c.GetID(), err)
+		}
+	}
+	return nil
+}
+
 type EksForge struct {
 	eks        awseks.Cluster
+	eksVersion string
 	properties map[string]interface{}
 }
 func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
@@ -163,6 +236,18 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		return nil
 	}
 
+	// 升级预检的 ERROR 已在 Validate 中拦截，这里只输出 WARNING
+	upgradePlan := eksInstance.BuildUpgradePlan()
+	for _, finding := range upgradePlan.Findings {
+		fmt.Printf("Warning: eks %s upgrade preflight (%s): %s\n", eksInstance.GetID(), finding.Component, finding.Message)
+	}
+
+	// valuesFile 和清单通常已在 Validate 中读取，未读取时在这里读取，失败时不创建集群
+	if err := eksInstance.loadHelmContent(); err != nil {
+		fmt.Printf("Error: eks %s: %v\n", eksInstance.GetID(), err)
+		return nil
+	}
+
 	// 获取所有可用区
 	availabilityZones := ctx.VPC.AvailabilityZones()
 
@@ -211,6 +296,9 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		}
 	}
 
+	// 公有端点关闭或受限时 kubectl Handler 运行在私有子网中，集群只选择了公有子网时为其补充私有子网
+	vpcSubnets := kubectlSubnetSelection(ctx, eksInstance, subnetSelection)
+
 	// 为安全组添加 Karpenter 发现标签
 	// Karpenter 可以通过发现标签找到和使用这个安全组
 	awscdk.Tags_Of(ctx.SecurityGroups.Default).Add(
@@ -249,6 +337,12 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		},
 	})
 
+	// API 模式只使用访问条目，不再维护 aws-auth ConfigMap
+	authenticationMode := awseks.AuthenticationMode_API_AND_CONFIG_MAP
+	if eksInstance.Access.IsApiMode() {
+		authenticationMode = awseks.AuthenticationMode_API
+	}
+
 	cluster := awseks.NewCluster(ctx.Stack, jsii.String(eksInstance.GetID()), &awseks.ClusterProps{
 		ClusterName: jsii.String(eksInstance.GetID()),
 		Version: awseks.KubernetesVersion_Of(&eksInstance.EksVersion),
@@ -258,16 +352,28 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		OutputConfigCommand: jsii.Bool(true),
 		OutputMastersRoleArn: jsii.Bool(true),
 		Vpc: ctx.VPC,
-		VpcSubnets: &subnetSelection, 
+		VpcSubnets: &vpcSubnets,
 		MastersRole: mastersRole,
 		SecurityGroup: ctx.SecurityGroups.Default,
-		AuthenticationMode: awseks.AuthenticationMode_API_AND_CONFIG_MAP,
+		AuthenticationMode: authenticationMode,
+		EndpointAccess: eksInstance.clusterEndpointAccess(),
+		ClusterLogging: eksInstance.clusterLogging(),
+		SecretsEncryptionKey: secretsEncryptionKey(ctx.Stack, eksInstance),
 		Tags: &map[string]*string{
 			"karpenter.sh/discovery": jsii.String(eksInstance.GetID()),
 		},
 	})
 
 
+	// 补充的私有子网只用于 kubectl Handler，集群子网保持不变
+	if len(vpcSubnets) != len(subnetSelection) {
+		keepClusterSubnets(ctx, cluster, subnetSelection)
+	}
+
+	// 私有端点所需的 VPC 接口端点和控制平面日志保留天数
+	createPrivateEndpoints(ctx, cluster, eksInstance)
+	configureControlPlaneLogRetention(ctx.Stack, cluster, eksInstance)
+
 	// 简化：直接使用 KeyName，如果为空则使用默认值
 	// 使用 CreateOrGetKeyPair 函数获取或创建密钥对
 	keyPair := aws.CreateOrGetKeyPair(ctx.Stack, eksInstance.KeyName, eksInstance.OsType)
@@ -288,107 +394,177 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		}
 	}
 
-	// 创建启动模板
-	// 使用 template 才能指定 default Node Pool 的 Security Group
-	// 普通环境 CoreDNS 运行在 default Node Pool
-	// 如果 Karpenter nodepool SG 和 default Node Pool SG 不在同一个 SG，需要特殊权限设置，否则会导致 karpenter 管理的节点无法访问 CoreDNS. 
-	launchTemplate := awsec2.NewLaunchTemplate(ctx.Stack, jsii.String(fmt.Sprintf("%s.EksDefaultNodePool", eksInstance.GetID())), &awsec2.LaunchTemplateProps{
-		LaunchTemplateName: jsii.String(fmt.Sprintf("%s-default-node-template", eksInstance.GetID())),
-		SecurityGroup: ctx.SecurityGroups.Default,
-		// 在启动模板中使用密钥对
-		KeyPair: keyPair, // 直接传递密钥对对象
-		BlockDevices: &[]*awsec2.BlockDevice{
-			{
-				DeviceName: jsii.String("/dev/xvda"),
-				// 正确使用 BlockDeviceVolume.ebs 静态方法
-				Volume: awsec2.BlockDeviceVolume_Ebs(jsii.Number(eksInstance.DiskSize), &awsec2.EbsDeviceOptions{
-					VolumeType: awsec2.EbsDeviceVolumeType_GP3,
-					// 可选: 其他 EBS 参数
-					// Iops: jsii.Number(3000),
-					// Throughput: jsii.Number(125),
-				}),
+	// Auto Mode 由 system 节点池运行 CoreDNS 等系统组件，不创建默认节点组
+	// 托管插件需要节点就绪，Auto Mode 下等待集群开启 Auto Mode
+	var autoMode awscdk.CustomResource
+	var nodeCapacity constructs.Construct
+	if eksInstance.AutoMode.IsEnabled() {
+		autoMode = configureAutoMode(ctx.Stack, cluster, eksInstance)
+		nodeCapacity = autoMode
+	} else {
+		// 创建启动模板
+		// 使用 template 才能指定 default Node Pool 的 Security Group
+		// 普通环境 CoreDNS 运行在 default Node Pool
+		// 如果 Karpenter nodepool SG 和 default Node Pool SG 不在同一个 SG，需要特殊权限设置，否则会导致 karpenter 管理的节点无法访问 CoreDNS. 
+		launchTemplate := awsec2.NewLaunchTemplate(ctx.Stack, jsii.String(fmt.Sprintf("%s.EksDefaultNodePool", eksInstance.GetID())), &awsec2.LaunchTemplateProps{
+			LaunchTemplateName: jsii.String(fmt.Sprintf("%s-default-node-template", eksInstance.GetID())),
+			SecurityGroup: ctx.SecurityGroups.Default,
+			// 在启动模板中使用密钥对
+			KeyPair: keyPair, // 直接传递密钥对对象
+			BlockDevices: &[]*awsec2.BlockDevice{
+				{
+					DeviceName: jsii.String("/dev/xvda"),
+					// 正确使用 BlockDeviceVolume.ebs 静态方法
+					Volume: awsec2.BlockDeviceVolume_Ebs(jsii.Number(eksInstance.DiskSize), &awsec2.EbsDeviceOptions{
+						VolumeType: awsec2.EbsDeviceVolumeType_GP3,
+						// 可选: 其他 EBS 参数
+						// Iops: jsii.Number(3000),
+						// Throughput: jsii.Number(125),
+					}),
+				},
 			},
-		},
-	})
+		})
 
-	cluster.AddNodegroupCapacity(jsii.String(fmt.Sprintf("%s-node-group-v%s", eksInstance.GetID(), strings.ReplaceAll(eksInstance.EksVersion, ".", ""))), &awseks.NodegroupOptions{
-		InstanceTypes: &instanceTypes,
-		MinSize: jsii.Number(eksInstance.MinSize),
-		MaxSize: jsii.Number(eksInstance.MaxSize),
-		//DiskSize: jsii.Number(eksInstance.DiskSize),
-		AmiType: utils.SelectEksAmiType(eksInstance.OsType, eksInstance.OsArch, eksInstance.GpuType, eksInstance.WindowsType),
-		// 使用启动模板
-		LaunchTemplateSpec: &awseks.LaunchTemplateSpec{
-			Id: launchTemplate.LaunchTemplateId(),
-			Version: launchTemplate.LatestVersionNumber(),
-		},
-	})
+		// 节点组名称包含 Kubernetes 版本，分阶段升级时新旧节点组同时存在（蓝绿），旧节点组固定在 fromVersion
+		var nodeGroup awseks.Nodegroup
+		for _, nodeGroupVersion := range eksInstance.nodeGroupVersions() {
+			nodeGroup = cluster.AddNodegroupCapacity(jsii.String(fmt.Sprintf("%s-node-group-v%s", eksInstance.GetID(), strings.ReplaceAll(nodeGroupVersion, ".", ""))), &awseks.NodegroupOptions{
+				InstanceTypes: &instanceTypes,
+				MinSize: jsii.Number(eksInstance.MinSize),
+				MaxSize: jsii.Number(eksInstance.MaxSize),
+				//DiskSize: jsii.Number(eksInstance.DiskSize),
+				AmiType: utils.SelectEksAmiType(eksInstance.OsType, eksInstance.OsArch, eksInstance.GpuType, eksInstance.WindowsType),
+				// 使用启动模板
+				LaunchTemplateSpec: &awseks.LaunchTemplateSpec{
+					Id: launchTemplate.LaunchTemplateId(),
+					Version: launchTemplate.LatestVersionNumber(),
+				},
+			})
+			if eksInstance.IsUpgrading() {
+				nodeGroup.Node().DefaultChild().(awseks.CfnNodegroup).SetVersion(jsii.String(nodeGroupVersion))
+			}
+		}
+		nodeCapacity = nodeGroup
+	}
 
-	// 添加 Admin 角色到 aws-auth ConfigMap，支持所有 Admin/* 联合身份用户, 支持 Isengard 用户
-	adminRole := awsiam.Role_FromRoleArn(ctx.Stack, jsii.String("AdminRole"), 
-	jsii.String(fmt.Sprintf("arn:%s:iam::%s:role/Admin", partition.DefaultPartition, *ctx.Stack.Account())), nil)
-	cluster.AwsAuth().AddRoleMapping(adminRole, &awseks.AwsAuthMapping{
-		Groups: &[]*string{
-			jsii.String("system:masters"),
-		},
-		Username: jsii.String("{{SessionName}}"), // 使用 SessionName 作为用户名，映射到联合身份用户的实际名称
-	})
+	// 创建额外的托管节点组，依赖信息和可用区已在 Validate 中检查
+	nodeGroups, err := createNodeGroups(ctx, cluster, eksInstance, keyPair)
+	if err != nil {
+		fmt.Printf("Error: eks %s: %v\n", eksInstance.GetID(), err)
+		return nil
+	}
 
-	// 解析 AdminUsers 字段并添加用户到 aws-auth ConfigMap, 用于支持 aws console 用户访问
-	if eksInstance.AdminUsers != "" {
-		// 将逗号分隔的字符串拆分为字符串数组
-		userList := strings.Split(eksInstance.AdminUsers, ",")
+	// 创建 Fargate Profile
+	fargateProfiles := createFargateProfiles(ctx, cluster, eksInstance)
 
-		for _, username := range userList {
-			// 去除可能存在的空格
-			username = strings.TrimSpace(username)
-			if username != "" {
-				// 为每个用户创建一个唯一的逻辑 ID
-				userLogicalId := fmt.Sprintf("%s-%s-User", eksInstance.GetID(), username)
-				user := awsiam.User_FromUserName(ctx.Stack, jsii.String(userLogicalId), jsii.String(username))
-				cluster.AwsAuth().AddUserMapping(user, &awseks.AwsAuthMapping{
-					Groups: &[]*string{
-						jsii.String("system:masters"),
-					},
-					Username: jsii.String(username),
-				})
+	// 创建访问条目和自定义 Kubernetes 组的 RBAC
+	accessEntries := createAccessEntries(ctx.Stack, cluster, eksInstance)
+
+	// 开启 Auto Mode 期间集群处于 UPDATING 状态，节点组、Fargate Profile 和访问条目在更新完成后创建
+	if autoMode != nil {
+		for _, group := range nodeGroups {
+			group.Node().AddDependency(autoMode)
+		}
+		for _, profile := range fargateProfiles {
+			profile.Node().AddDependency(autoMode)
+		}
+		accessEntries.Node().AddDependency(autoMode)
+	}
+	createRbacGroups(cluster, eksInstance.Access.Groups)
+
+	// 系统组件在集群访问配置之后部署：API 模式为访问条目，否则为 aws-auth ConfigMap
+	var clusterAccess constructs.Construct
+	if eksInstance.Access.IsApiMode() {
+		fmt.Printf("Note: eks %s uses authenticationMode API, the Admin role is not mapped automatically, add it to access.entries if needed\n", eksInstance.GetID())
+		clusterAccess = accessEntries
+	} else {
+		// 添加 Admin 角色到 aws-auth ConfigMap，支持所有 Admin/* 联合身份用户, 支持 Isengard 用户
+		adminRole := awsiam.Role_FromRoleArn(ctx.Stack, jsii.String("AdminRole"), 
+		jsii.String(fmt.Sprintf("arn:%s:iam::%s:role/Admin", partition.DefaultPartition, *ctx.Stack.Account())), nil)
+		cluster.AwsAuth().AddRoleMapping(adminRole, &awseks.AwsAuthMapping{
+			Groups: &[]*string{
+				jsii.String("system:masters"),
+			},
+			Username: jsii.String("{{SessionName}}"), // 使用 SessionName 作为用户名，映射到联合身份用户的实际名称
+		})
+
+		// 解析 AdminUsers 字段并添加用户到 aws-auth ConfigMap, 用于支持 aws console 用户访问
+		if eksInstance.AdminUsers != "" {
+			// 将逗号分隔的字符串拆分为字符串数组
+			userList := strings.Split(eksInstance.AdminUsers, ",")
+
+			for _, username := range userList {
+				// 去除可能存在的空格
+				username = strings.TrimSpace(username)
+				if username != "" {
+					// 为每个用户创建一个唯一的逻辑 ID
+					userLogicalId := fmt.Sprintf("%s-%s-User", eksInstance.GetID(), username)
+					user := awsiam.User_FromUserName(ctx.Stack, jsii.String(userLogicalId), jsii.String(username))
+					cluster.AwsAuth().AddUserMapping(user, &awseks.AwsAuthMapping{
+						Groups: &[]*string{
+							jsii.String("system:masters"),
+						},
+						Username: jsii.String(username),
+					})
+				}
 			}
 		}
-	}
 
-	// 给 aws-auth ConfigMap 添加 mastersRole 依赖，确保删除顺序正确
-	awsAuthConfigMap := cluster.AwsAuth()
-	if awsAuthConfigMap != nil {
+		// 给 aws-auth ConfigMap 添加 mastersRole 依赖，确保删除顺序正确
+		awsAuthConfigMap := cluster.AwsAuth()
 		awsAuthConfigMap.Node().AddDependency(mastersRole)
+		awsAuthConfigMap.Node().AddDependency(accessEntries)
+		clusterAccess = awsAuthConfigMap
 	}
 
+	// 托管插件需要节点组就绪后才能变为 ACTIVE
+	addons := newEksAddons(ctx.Stack, cluster, eksInstance, nodeCapacity)
+
 	// 1. 首先部署 Pod Identity Agent（如果指定了版本）- 底层身份认证组件
 	if eksInstance.PodIdentityAgentVersion != "" {
-		podIdentityAgent := deployPodIdentityAgent(ctx.Stack, cluster, eksInstance.PodIdentityAgentVersion)
-		if podIdentityAgent != nil {
-			// 依赖 aws-auth ConfigMap 而不是直接依赖 mastersRole
-			podIdentityAgent.Node().AddDependency(awsAuthConfigMap)
+		if addons.isManaged("podIdentityAgent") {
+			addons.podIdentity = addons.deploy("podIdentityAgent", eksInstance.PodIdentityAgentVersion, nil, nil)
+		} else {
+			podIdentityAgent := deployPodIdentityAgent(ctx.Stack, cluster, eksInstance.PodIdentityAgentVersion)
+			if podIdentityAgent != nil {
+				// 依赖 aws-auth ConfigMap 而不是直接依赖 mastersRole
+				podIdentityAgent.Node().AddDependency(clusterAccess)
+				addons.podIdentity = podIdentityAgent
+			}
 		}
 	}
 
-	// 创建 Karpenter IAM 资源
-	karpenterIam := createKarpenterIamResources(ctx.Stack, "KarpenterIam", cluster)
+	// 创建工作负载 ServiceAccount，Pod Identity 关联依赖上面部署的 Agent
+	for _, serviceAccount := range createServiceAccounts(ctx.Stack, cluster, eksInstance, addons.podIdentity) {
+		serviceAccount.Node().AddDependency(clusterAccess)
+	}
 
 	// 创建统一的 eks-admin ServiceAccount 和 ClusterRoleBinding（供各组件使用）
 	eksAdminSA, eksAdminCRB := createEksAdminResources(ctx.Stack, "EksAdmin", cluster, "eks-admin")
 
-	// 检查是否需要 Multi-NIC 支持
-	nodePoolProps := &KarpenterNodePoolProps{
-		GpuInstanceTypes:    eksInstance.KarpenterGpuInstanceTypes,
-		CpuInstanceTypes:    eksInstance.KarpenterCpuInstanceTypes,
-		NeuronInstanceTypes: eksInstance.KarpenterNeuronInstanceTypes,
+	// 解析 Karpenter 节点池，旧格式由扁平字段转换；Auto Mode 由 EKS 管理节点，不使用 Karpenter 节点池
+	var karpenterNodePools []KarpenterNodePoolConfig
+	if !eksInstance.AutoMode.IsEnabled() {
+		karpenterNodePools = eksInstance.KarpenterNodePools.Resolve(eksInstance)
 	}
-	
-	needsMultiNic := needsMultiNicSupport(nodePoolProps)
+
+	// 检查是否需要 Multi-NIC 支持
+	needsMultiNic := needsMultiNicSupport(karpenterNodePools)
 	
 	// 升级 VPC CNI 并配置 Multi-NIC（如果需要）
-	var vpcCniManifest awseks.KubernetesManifest
-	if needsMultiNic {
+	var vpcCniManifest constructs.Construct
+	if addons.isManaged("vpcCni") {
+		// 托管插件通过配置值开启 Multi-NIC，代替升级 Job；Multi-NIC 需要较新的 VPC CNI，未指定版本时使用最新版
+		var vpcCniVersion string
+		var vpcCniValues map[string]interface{}
+		if needsMultiNic {
+			vpcCniVersion = "latest"
+			vpcCniValues = map[string]interface{}{
+				"env": map[string]interface{}{"ENABLE_MULTI_NIC": "true"},
+			}
+		}
+		vpcCniManifest = addons.deploy("vpcCni", vpcCniVersion, nil, vpcCniValues)
+	} else if needsMultiNic {
 		vpcCniManifest = upgradeVpcCniAndConfigureMultiNic(ctx.Stack, "VpcCniUpgrade", &VpcCniUpgradeProps{
 			Cluster:        cluster,
 			ClusterName:    *cluster.ClusterName(),
@@ -397,88 +573,59 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		}, eksAdminSA, eksAdminCRB)
 	}
 
-	// EFA Launch Templates 已移除（Karpenter v1 不支持自定义 Launch Template）
-
-	// 部署 Karpenter Helm Chart
-	karpenterChart := deployKarpenterHelm(ctx.Stack, "KarpenterHelm", &KarpenterHelmProps{
-		ClusterName:      *cluster.ClusterName(),
-		Cluster:          cluster,
-		ControllerRoleArn: *karpenterIam.ControllerRole.RoleArn(),
-		KarpenterVersion: eksInstance.KarpenterVersion,
-	})
-	
-	if karpenterChart != nil {
-		karpenterChart.Node().AddDependency(awsAuthConfigMap)
+	if addons.isManaged("coreDns") {
+		addons.deploy("coreDns", "", nil, nil)
 	}
 
-	// 创建 Karpenter NodePool 和 EC2NodeClass
-	nodePools := createKarpenterNodePool(ctx.Stack, "KarpenterNodePool", &KarpenterNodePoolProps{
-		ClusterName: *cluster.ClusterName(),
-		Cluster:     cluster,
-		IamResources: karpenterIam,
-		KubernetesVersion: eksInstance.EksVersion,
-		KarpenterOsType: eksInstance.KarpenterOsType,
-		NodePoolTypes: eksInstance.KarpenterNodePools, // 使用这个参数决定启用哪些节点池
-		
-		// CPU 配置
-		CpuInstanceTypes:       eksInstance.KarpenterCpuInstanceTypes,
-		CpuInstanceFamilies:    eksInstance.KarpenterCpuInstanceFamilies,
-		CpuInstanceCategories:  eksInstance.KarpenterCpuInstanceCategories,
-		CpuInstanceGenerations: eksInstance.KarpenterCpuInstanceGenerations,
-		CpuCapacityTypes:       eksInstance.KarpenterCpuCapacityTypes,
-		CpuArchitectures:       eksInstance.KarpenterCpuArchitectures,
-		CpuDiskSize:            eksInstance.KarpenterCpuDiskSize,
-		CpuDiskType:            eksInstance.KarpenterCpuDiskType,
-		CpuDiskIops:            eksInstance.KarpenterCpuDiskIops,
-		CpuDiskThroughput:      eksInstance.KarpenterCpuDiskThroughput,
-		CpuUseInstanceStore:    eksInstance.KarpenterCpuUseInstanceStore,
-		CpuLabels:              eksInstance.KarpenterCpuLabels,
-		CpuTaints:              eksInstance.KarpenterCpuTaints,
-		
-		// GPU 配置
-		GpuInstanceTypes:       eksInstance.KarpenterGpuInstanceTypes,
-		GpuInstanceFamilies:    eksInstance.KarpenterGpuInstanceFamilies,
-		GpuInstanceCategories:  eksInstance.KarpenterGpuInstanceCategories,
-		GpuInstanceGenerations: eksInstance.KarpenterGpuInstanceGenerations,
-		GpuCapacityTypes:       eksInstance.KarpenterGpuCapacityTypes,
-		GpuArchitectures:       eksInstance.KarpenterGpuArchitectures,
-		GpuDiskSize:            eksInstance.KarpenterGpuDiskSize,
-		GpuDiskType:            eksInstance.KarpenterGpuDiskType,
-		GpuDiskIops:            eksInstance.KarpenterGpuDiskIops,
-		GpuDiskThroughput:      eksInstance.KarpenterGpuDiskThroughput,
-		GpuUseInstanceStore:    eksInstance.KarpenterGpuUseInstanceStore,
-		GpuLabels:              eksInstance.KarpenterGpuLabels,
-		GpuTaints:              eksInstance.KarpenterGpuTaints,
+	// EFA Launch Templates 已移除（Karpenter v1 不支持自定义 Launch Template）
+
+	// 平台组件，通用 Helm Release 和清单在其之后安装
+	platformComponents := []constructs.Construct{clusterAccess}
+
+	// Auto Mode 自带节点自动扩缩，不部署 Karpenter
+	var karpenterChart awseks.HelmChart
+	if !eksInstance.AutoMode.IsEnabled() {
+		// 创建 Karpenter IAM 资源
+		karpenterIam := createKarpenterIamResources(ctx.Stack, "KarpenterIam", cluster)
+
+		// 部署 Karpenter Helm Chart
+		karpenterChart = deployKarpenterHelm(ctx.Stack, "KarpenterHelm", &KarpenterHelmProps{
+			ClusterName:      *cluster.ClusterName(),
+			Cluster:          cluster,
+			ControllerRoleArn: *karpenterIam.ControllerRole.RoleArn(),
+			KarpenterVersion: eksInstance.KarpenterVersion,
+		})
 		
-		// Neuron 配置
-		NeuronInstanceTypes:       eksInstance.KarpenterNeuronInstanceTypes,
-		NeuronInstanceFamilies:    eksInstance.KarpenterNeuronInstanceFamilies,
-		NeuronInstanceCategories:  eksInstance.KarpenterNeuronInstanceCategories,
-		NeuronInstanceGenerations: eksInstance.KarpenterNeuronInstanceGenerations,
-		NeuronCapacityTypes:       eksInstance.KarpenterNeuronCapacityTypes,
-		NeuronArchitectures:       eksInstance.KarpenterNeuronArchitectures,
-		NeuronDiskSize:            eksInstance.KarpenterNeuronDiskSize,
-		NeuronDiskType:            eksInstance.KarpenterNeuronDiskType,
-		NeuronDiskIops:            eksInstance.KarpenterNeuronDiskIops,
-		NeuronDiskThroughput:      eksInstance.KarpenterNeuronDiskThroughput,
-		NeuronUseInstanceStore:    eksInstance.KarpenterNeuronUseInstanceStore,
-		NeuronLabels:              eksInstance.KarpenterNeuronLabels,
-		NeuronTaints:              eksInstance.KarpenterNeuronTaints,
-	})
+		if karpenterChart != nil {
+			karpenterChart.Node().AddDependency(clusterAccess)
+			platformComponents = append(platformComponents, karpenterChart)
+		}
 
-	// 确保 Karpenter 在集群和角色创建后部署
-	if karpenterChart != nil {
-		karpenterChart.Node().AddDependency(cluster)
-		karpenterChart.Node().AddDependency(karpenterIam.ControllerRole)
-	}
+		// 创建 Karpenter NodePool 和 EC2NodeClass
+		nodePools := createKarpenterNodePool(ctx.Stack, "KarpenterNodePool", &KarpenterNodePoolProps{
+			ClusterName: *cluster.ClusterName(),
+			Cluster:     cluster,
+			IamResources: karpenterIam,
+			KubernetesVersion: eksInstance.karpenterKubernetesVersion(),
+			KarpenterOsType: eksInstance.KarpenterOsType,
+			NodePools: karpenterNodePools,
+		})
 
-	// 确保所有 NodePool 在 Karpenter 和 VPC CNI 升级后创建
-	for _, nodePool := range nodePools {
+		// 确保 Karpenter 在集群和角色创建后部署
 		if karpenterChart != nil {
-			nodePool.Node().AddDependency(karpenterChart)
+			karpenterChart.Node().AddDependency(cluster)
+			karpenterChart.Node().AddDependency(karpenterIam.ControllerRole)
 		}
-		if vpcCniManifest != nil {
-			nodePool.Node().AddDependency(vpcCniManifest)
+
+		// 确保所有 NodePool 在 Karpenter 和 VPC CNI 升级后创建
+		for _, nodePool := range nodePools {
+			platformComponents = append(platformComponents, nodePool)
+			if karpenterChart != nil {
+				nodePool.Node().AddDependency(karpenterChart)
+			}
+			if vpcCniManifest != nil {
+				nodePool.Node().AddDependency(vpcCniManifest)
+			}
 		}
 	}
 
@@ -486,15 +633,15 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	efaChart := deployEfaDevicePlugin(ctx.Stack, cluster, eksInstance.EfaPluginVersion)
 	if efaChart != nil {
 		efaChart.Node().AddDependency(cluster)
-		efaChart.Node().AddDependency(awsAuthConfigMap)
+		efaChart.Node().AddDependency(clusterAccess)
 	}
 
-	// 如果启用了 GPU 节点池，部署 NVIDIA Device Plugin
-	if strings.Contains(eksInstance.KarpenterNodePools, "gpu") || strings.Contains(eksInstance.KarpenterNodePools, "nvidia") {
+	// 如果启用了 GPU 节点池，部署 NVIDIA Device Plugin（Auto Mode 节点自带）
+	if !eksInstance.AutoMode.IsEnabled() && eksInstance.KarpenterNodePools.HasGpuPool() {
 		nvidiaPlugin := deployNvidiaDevicePlugin(ctx.Stack, cluster, eksInstance.NvidiaPluginVersion)
 		if nvidiaPlugin != nil {
 			nvidiaPlugin.Node().AddDependency(cluster)
-			nvidiaPlugin.Node().AddDependency(awsAuthConfigMap)
+			nvidiaPlugin.Node().AddDependency(clusterAccess)
 		}
 	}
 
@@ -513,7 +660,7 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	if eksInstance.AwsLoadBalancerControllerVersion != "" {
 		awsLbControllerChart = deployAwsLoadBalancerController(ctx.Stack, cluster, eksInstance.AwsLoadBalancerControllerVersion)
 		if awsLbControllerChart != nil {
-			awsLbControllerChart.Node().AddDependency(awsAuthConfigMap)
+			awsLbControllerChart.Node().AddDependency(clusterAccess)
 		}
 	}
 
@@ -530,12 +677,12 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 			},
 		})
 		if certManagerNamespace != nil {
-			certManagerNamespace.Node().AddDependency(awsAuthConfigMap)
+			certManagerNamespace.Node().AddDependency(clusterAccess)
 		}
 		
 		certManagerChart = deployCertManager(ctx.Stack, cluster, eksInstance.CertManagerVersion)
 		if certManagerChart != nil {
-			certManagerChart.Node().AddDependency(awsAuthConfigMap)
+			certManagerChart.Node().AddDependency(clusterAccess)
 			// 确保chart在我们创建的namespace之后部署
 			if certManagerNamespace != nil {
 				certManagerChart.Node().AddDependency(certManagerNamespace)
@@ -549,27 +696,33 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 
 	// 部署 EBS CSI Driver（如果指定了版本）
 	if eksInstance.EbsCsiDriverVersion != "" {
-		ebsCsiChart := deployEbsCsiDriver(ctx.Stack, cluster, eksInstance.EbsCsiDriverVersion)
+		ebsCsiChart := deployEbsCsiDriver(ctx.Stack, cluster, addons, eksInstance.EbsCsiDriverVersion)
 		if ebsCsiChart != nil {
-			ebsCsiChart.Node().AddDependency(awsAuthConfigMap)
+			ebsCsiChart.Node().AddDependency(clusterAccess)
+			platformComponents = append(platformComponents, ebsCsiChart)
 		}
 	}
 
 	// 部署 Mountpoint S3 CSI Driver（如果指定了版本）
 	if eksInstance.MountpointS3CsiDriverVersion != "" {
-		s3CsiChart := deployMountpointS3CsiDriverWithStorage(ctx.Stack, cluster, eksInstance)
+		s3CsiChart := deployMountpointS3CsiDriverWithStorage(ctx.Stack, cluster, addons, eksInstance)
 		if s3CsiChart != nil {
-			s3CsiChart.Node().AddDependency(awsAuthConfigMap)
+			s3CsiChart.Node().AddDependency(clusterAccess)
+			platformComponents = append(platformComponents, s3CsiChart)
 		}
 	}
 
 	// 4. 部署 Metrics Server（如果指定了版本）
 	// 依赖 Cert Manager，保持简单的线性依赖链
-	var metricsServerChart awseks.HelmChart
+	var metricsServerChart constructs.Construct
 	if eksInstance.MetricsServerVersion != "" {
-		metricsServerChart = deployMetricsServer(ctx.Stack, cluster, eksInstance.MetricsServerVersion, eksInstance.CertManagerVersion != "")
+		if addons.isManaged("metricsServer") {
+			metricsServerChart = addons.deploy("metricsServer", eksInstance.MetricsServerVersion, nil, nil)
+		} else {
+			metricsServerChart = deployMetricsServer(ctx.Stack, cluster, eksInstance.MetricsServerVersion, eksInstance.CertManagerVersion != "")
+		}
 		if metricsServerChart != nil {
-			metricsServerChart.Node().AddDependency(awsAuthConfigMap)
+			metricsServerChart.Node().AddDependency(clusterAccess)
 		}
 		// 如果安装了 cert-manager，确保 metrics-server 在 cert-manager 之后部署
 		// 保持简单的依赖链：ALB Controller → Cert Manager → Metrics Server
@@ -594,33 +747,25 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 			// 部署新版 Training Operator，传入版本参数
 			modernTrainingOp := deployModernTrainingOperator(ctx.Stack, cluster, trainingOperatorVersion, eksAdminSA, eksAdminCRB)
 			if modernTrainingOp != nil {
-				modernTrainingOp.Node().AddDependency(awsAuthConfigMap)
+				modernTrainingOp.Node().AddDependency(clusterAccess)
 			}
 		} else {
 			// 部署 Legacy Training Operator
 			legacyTrainingOp := deployLegacyTrainingOperator(ctx.Stack, cluster, trainingOperatorVersion, eksAdminSA, eksAdminCRB)
 			if legacyTrainingOp != nil {
-				legacyTrainingOp.Node().AddDependency(awsAuthConfigMap)
+				legacyTrainingOp.Node().AddDependency(clusterAccess)
 			}
 		}
 	}
 
 	// 处理存储依赖关系（Lustre、EFS 等）
-	if eksInstance.DependsOn != "" {
-		// 获取依赖信息
-		magicToken, err := dependency.GetDependencyInfo(eksInstance.DependsOn)
-		if err != nil {
-			fmt.Printf("Error getting dependency info: %v\n", err)
-		} else {
-			// 如果需要部署 CSI 驱动
-			if types.GetBoolValue(eksInstance.DeployCsiDriver, false) {
-				csiCharts := deployDependentStorageCsiDrivers(ctx.Stack, cluster, magicToken, eksInstance)
-				// 为所有 CSI Charts 添加 mastersRole 依赖
-				for _, chart := range csiCharts {
-					if chart != nil {
-						chart.Node().AddDependency(awsAuthConfigMap)
-					}
-				}
+	if eksInstance.DependsOn != "" && types.GetBoolValue(eksInstance.DeployCsiDriver, false) {
+		csiCharts := deployDependentStorageCsiDrivers(ctx.Stack, cluster, addons, eksInstance)
+		// 为所有 CSI Charts 添加 mastersRole 依赖
+		for _, chart := range csiCharts {
+			if chart != nil {
+				chart.Node().AddDependency(clusterAccess)
+				platformComponents = append(platformComponents, chart)
 			}
 		}
 	}
@@ -629,7 +774,7 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	if eksInstance.MlflowVersion != "" {
 		mlflowChart := deployMlflow(ctx.Stack, cluster, eksInstance.MlflowVersion)
 		if mlflowChart != nil {
-			mlflowChart.Node().AddDependency(awsAuthConfigMap)
+			mlflowChart.Node().AddDependency(clusterAccess)
 		}
 	}
 
@@ -645,15 +790,15 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		if eksInstance.CertManagerVersion != "" && certManagerChart != nil && hyperPodJob != nil {
 			hyperPodJob.Node().AddDependency(certManagerChart)
 		}
-		// 添加 awsAuthConfigMap 依赖确保权限可用
+		// 添加 clusterAccess 依赖确保权限可用
 		if hyperPodJob != nil {
-			hyperPodJob.Node().AddDependency(awsAuthConfigMap)
+			hyperPodJob.Node().AddDependency(clusterAccess)
 		}
 		
 		// 部署 AWS Neuron Device Plugin (HyperPod 必需)
 		neuronPlugin := deployNeuronDevicePlugin(ctx.Stack, cluster, eksInstance.NeuronDevicePluginVersion)
 		if neuronPlugin != nil {
-			neuronPlugin.Node().AddDependency(awsAuthConfigMap)
+			neuronPlugin.Node().AddDependency(clusterAccess)
 		}
 	}
 
@@ -661,7 +806,7 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	if eksInstance.PrometheusStackVersion != "" {
 		prometheusChart := deployPrometheusStack(ctx.Stack, cluster, eksInstance)
 		if prometheusChart != nil {
-			prometheusChart.Node().AddDependency(awsAuthConfigMap)
+			prometheusChart.Node().AddDependency(clusterAccess)
 			// 如果有HyperPod组件，确保在其之后部署
 			if hyperPodJob != nil {
 				prometheusChart.Node().AddDependency(hyperPodJob)
@@ -681,7 +826,7 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	if eksInstance.RayOperatorVersion != "" {
 		rayOperatorChart := deployRayOperator(ctx.Stack, cluster, eksInstance.RayOperatorVersion)
 		if rayOperatorChart != nil {
-			rayOperatorChart.Node().AddDependency(awsAuthConfigMap)
+			rayOperatorChart.Node().AddDependency(clusterAccess)
 			// 如果有HyperPod组件，确保在其之后部署
 			if hyperPodJob != nil {
 				rayOperatorChart.Node().AddDependency(hyperPodJob)
@@ -694,7 +839,7 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	if eksInstance.IstioVersion != "" {
 		istioChart = deployIstio(ctx.Stack, cluster, eksInstance.IstioVersion)
 		if istioChart != nil {
-			istioChart.Node().AddDependency(awsAuthConfigMap)
+			istioChart.Node().AddDependency(clusterAccess)
 			// 如果有HyperPod组件，确保在其之后部署
 			if hyperPodJob != nil {
 				istioChart.Node().AddDependency(hyperPodJob)
@@ -706,7 +851,7 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	if eksInstance.KServeVersion != "" {
 		kserveChart := deployKServe(ctx.Stack, cluster, eksInstance.KServeVersion, eksInstance.KServeIngressClass, eksInstance.S3BucketName)
 		if kserveChart != nil {
-			kserveChart.Node().AddDependency(awsAuthConfigMap)
+			kserveChart.Node().AddDependency(clusterAccess)
 			// KServe 需要在 Cert Manager 之后部署
 			if certManagerChart != nil {
 				kserveChart.Node().AddDependency(certManagerChart)
@@ -722,7 +867,12 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		}
 	}
 
+	// 部署通用 Helm Release 和清单（在 Karpenter 和 CSI 驱动之后）
+	helmReleases := deployHelmReleases(cluster, eksInstance, platformComponents)
+	deployManifests(cluster, eksInstance, platformComponents, helmReleases)
+
 	e.eks = cluster
+	e.eksVersion = eksInstance.EksVersion
 	
 	// 保存 EKS 属性
 	if e.properties == nil {
@@ -733,6 +883,12 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	e.properties["clusterEndpoint"] = cluster.ClusterEndpoint()
 	e.properties["eksVersion"] = eksInstance.EksVersion
 	
+	// 记录升级计划，供 infraforge upgrade-plan 扫描模板中的已弃用 API
+	if clusterRef, ok := ctx.Stack.Resolve(cluster.ClusterName()).(map[string]interface{}); ok {
+		upgradePlan.clusterLogicalId, _ = clusterRef["Ref"].(string)
+	}
+	registerUpgradePlan(upgradePlan)
+	
 	// 保存 HyperPod 组件用于依赖
 	if hyperPodJob != nil {
 		e.properties["hyperPodJob"] = hyperPodJob
@@ -746,6 +902,17 @@ func (e *EksForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	return e
 }
 
+// Cluster 实现 dependency.ClusterProvider
+func (e *EksForge) Cluster() dependency.ClusterInfo {
+	return dependency.ClusterInfo{
+		Type:        "EKS",
+		ClusterName: *e.eks.ClusterName(),
+		ClusterArn:  *e.eks.ClusterArn(),
+		Endpoint:    *e.eks.ClusterEndpoint(),
+		Version:     e.eksVersion,
+	}
+}
+
 // 其余方法保持不变...
 func (e *EksForge) CreateOutputs(ctx *interfaces.ForgeContext) {
 	// 添加集群端点到输出
@@ -810,7 +977,7 @@ func (e *EksForge) MergeConfigs(defaults config.InstanceConfig, instance config.
 	if eksInstance.KarpenterVersion != "" {
 		merged.KarpenterVersion = eksInstance.KarpenterVersion
 	}
-	if eksInstance.KarpenterNodePools != "" {
+	if !eksInstance.KarpenterNodePools.IsEmpty() {
 		merged.KarpenterNodePools = eksInstance.KarpenterNodePools
 	}
 	if eksInstance.KarpenterOsType != "" {
@@ -852,6 +1019,77 @@ func (e *EksForge) MergeConfigs(defaults config.InstanceConfig, instance config.
 	if eksInstance.ControlPlaneAzIndices != "" {
 		merged.ControlPlaneAzIndices = eksInstance.ControlPlaneAzIndices
 	}
+	if eksInstance.Access.AuthenticationMode != "" {
+		merged.Access.AuthenticationMode = eksInstance.Access.AuthenticationMode
+	}
+	if len(eksInstance.Access.Entries) > 0 {
+		merged.Access.Entries = eksInstance.Access.Entries
+	}
+	if len(eksInstance.Access.Groups) > 0 {
+		merged.Access.Groups = eksInstance.Access.Groups
+	}
+	if eksInstance.PodIdentityAgentVersion != "" {
+		merged.PodIdentityAgentVersion = eksInstance.PodIdentityAgentVersion
+	}
+	if len(eksInstance.ServiceAccounts) > 0 {
+		merged.ServiceAccounts = eksInstance.ServiceAccounts
+	}
+
+	// 合并控制平面端点、日志和加密字段
+	if eksInstance.EndpointAccess != "" {
+		merged.EndpointAccess = eksInstance.EndpointAccess
+	}
+	if len(eksInstance.PublicAccessCidrs) > 0 {
+		merged.PublicAccessCidrs = eksInstance.PublicAccessCidrs
+	}
+	if eksInstance.CreateVpcEndpoints != nil {
+		merged.CreateVpcEndpoints = eksInstance.CreateVpcEndpoints
+	}
+	if len(eksInstance.ControlPlaneLogging.Types) > 0 {
+		merged.ControlPlaneLogging = eksInstance.ControlPlaneLogging
+	}
+	if eksInstance.SecretsEncryption.Enabled != nil {
+		merged.SecretsEncryption = eksInstance.SecretsEncryption
+	}
+
+	// 合并托管节点组、Fargate Profile 和 Auto Mode 字段
+	if len(eksInstance.NodeGroups) > 0 {
+		merged.NodeGroups = eksInstance.NodeGroups
+	}
+	if len(eksInstance.FargateProfiles) > 0 {
+		merged.FargateProfiles = eksInstance.FargateProfiles
+	}
+	if eksInstance.AutoMode.Enabled != nil {
+		merged.AutoMode = eksInstance.AutoMode
+	}
+	// Auto Mode 不使用 Karpenter，不继承默认配置中的 karpenterNodePools，只校验实例自己的配置
+	if merged.AutoMode.IsEnabled() && eksInstance.KarpenterNodePools.IsEmpty() {
+		merged.KarpenterNodePools = KarpenterNodePoolList{}
+	}
+
+	// 合并升级字段
+	if eksInstance.Upgrade.FromVersion != "" {
+		merged.Upgrade.FromVersion = eksInstance.Upgrade.FromVersion
+	}
+	if eksInstance.Upgrade.Stage != "" {
+		merged.Upgrade.Stage = eksInstance.Upgrade.Stage
+	}
+
+	// 合并通用 Helm Release 和清单字段
+	if len(eksInstance.HelmReleases) > 0 {
+		merged.HelmReleases = eksInstance.HelmReleases
+	}
+	if len(eksInstance.Manifests) > 0 {
+		merged.Manifests = eksInstance.Manifests
+	}
+
+	// 合并托管插件字段
+	if eksInstance.AddonMode != "" {
+		merged.AddonMode = eksInstance.AddonMode
+	}
+	if len(eksInstance.Addons) > 0 {
+		merged.Addons = eksInstance.Addons
+	}
 
 	// 合并 Storage 相关字段
 	if eksInstance.DependsOn != "" {
@@ -898,6 +1136,10 @@ func (e *EksForge) MergeConfigs(defaults config.InstanceConfig, instance config.
 	if eksInstance.S3BucketName != "" {
 		merged.S3BucketName = eksInstance.S3BucketName
 	}
+	// 未指定 s3BucketName 时使用 dependsOn 中 S3 依赖的存储桶，解析失败由 Validate 报告
+	if merged.S3BucketName == "" {
+		merged.S3BucketName, _ = dependency.ResolveS3BucketName(merged.DependsOn)
+	}
 
 	
 	// 合并 Training Operator 相关字段
@@ -0,0 +1,112 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"fmt"
+
+	"github.com/awslabs/InfraForge/core/interfaces"
+	"github.com/awslabs/InfraForge/core/utils/aws"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
+	"github.com/aws/jsii-runtime-go"
+)
+
+// EksFargateProfileConfig Fargate Profile，匹配任一 selector 的 Pod 运行在 Fargate 上
+type EksFargateProfileConfig struct {
+	Name      string                     `json:"name"`
+	Selectors []EksFargateSelectorConfig `json:"selectors"`           // 最多 5 个
+	AzIndices []int                      `json:"azIndices,omitempty"` // 子网所在可用区索引（从1开始），默认使用所有私有子网
+}
+
+// EksFargateSelectorConfig 按命名空间和标签选择 Pod，labels 需要全部匹配
+type EksFargateSelectorConfig struct {
+	Namespace string            `json:"namespace"`
+	Labels    map[string]string `json:"labels,omitempty"` // 最多 5 个
+}
+
+// Fargate Profile 的数量限制
+const (
+	maxFargateSelectors = 5
+	maxFargateLabels    = 5
+)
+
+// validateFargateProfiles 校验 Profile 名称、selector 和可用区索引
+func (c *EksInstanceConfig) validateFargateProfiles() error {
+	seen := make(map[string]bool)
+	for i, profile := range c.FargateProfiles {
+		field := fmt.Sprintf("fargateProfiles[%d]", i)
+		if !nodeGroupNamePattern.MatchString(profile.Name) {
+			return fmt.Errorf("%s: name '%s' must be lowercase alphanumeric or '-' and at most 40 characters", field, profile.Name)
+		}
+		if seen[profile.Name] {
+			return fmt.Errorf("%s: duplicate fargate profile name '%s'", field, profile.Name)
+		}
+		seen[profile.Name] = true
+		if len(profile.Selectors) == 0 || len(profile.Selectors) > maxFargateSelectors {
+			return fmt.Errorf("%s: selectors must have between 1 and %d entries", field, maxFargateSelectors)
+		}
+		for j, selector := range profile.Selectors {
+			if selector.Namespace == "" {
+				return fmt.Errorf("%s.selectors[%d]: namespace is required", field, j)
+			}
+			if err := validateNamespaces([]string{selector.Namespace}); err != nil {
+				return fmt.Errorf("%s.selectors[%d]: %w", field, j, err)
+			}
+			if len(selector.Labels) > maxFargateLabels {
+				return fmt.Errorf("%s.selectors[%d]: at most %d labels are allowed", field, j, maxFargateLabels)
+			}
+		}
+		for _, azIndex := range profile.AzIndices {
+			if azIndex < 1 {
+				return fmt.Errorf("%s: azIndices start at 1, got %d", field, azIndex)
+			}
+			if err := aws.ValidateAzIndex(azIndex); err != nil {
+				return fmt.Errorf("%s: %w", field, err)
+			}
+		}
+	}
+	return nil
+}
+
+// createFargateProfiles 创建 Fargate Profile，Pod 执行角色由 CDK 创建并加入 aws-auth（API 模式下由 EKS 自动创建访问条目）
+// Fargate 只支持私有子网，ctx.SubnetType 为公有子网时改用带出口的私有子网
+func createFargateProfiles(ctx *interfaces.ForgeContext, cluster awseks.Cluster, eksInstance *EksInstanceConfig) []awseks.FargateProfile {
+	subnetType := ctx.SubnetType
+	if subnetType == awsec2.SubnetType_PUBLIC {
+		subnetType = awsec2.SubnetType_PRIVATE_WITH_EGRESS
+	}
+
+	var profiles []awseks.FargateProfile
+	for _, config := range eksInstance.FargateProfiles {
+		var selectors []*awseks.Selector
+		for _, selector := range config.Selectors {
+			s := &awseks.Selector{Namespace: jsii.String(selector.Namespace)}
+			if len(selector.Labels) > 0 {
+				s.Labels = nodeGroupLabels(selector.Labels)
+			}
+			selectors = append(selectors, s)
+		}
+
+		subnetSelection := &awsec2.SubnetSelection{SubnetType: subnetType}
+		if len(config.AzIndices) > 0 {
+			var subnets []awsec2.ISubnet
+			for _, azIndex := range config.AzIndices {
+				subnets = append(subnets, aws.SelectSubnetByAzIndex(azIndex, ctx.VPC, subnetType))
+			}
+			subnetSelection = &awsec2.SubnetSelection{Subnets: &subnets}
+		}
+
+		// CDK 会让 Profile 逐个创建，并让所有 Kubernetes 资源在 Profile 创建后部署，Karpenter 等 Pod 可以直接调度到 Fargate
+		profile := cluster.AddFargateProfile(jsii.String(config.Name), &awseks.FargateProfileOptions{
+			FargateProfileName: jsii.String(config.Name),
+			Selectors:          &selectors,
+			SubnetSelection:    subnetSelection,
+			Vpc:                ctx.VPC,
+		})
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
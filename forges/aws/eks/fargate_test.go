@@ -0,0 +1,186 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/assertions"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
+	"github.com/aws/jsii-runtime-go"
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/forges/aws/eks/utils"
+)
+
+func TestValidateFargateProfilesAndAutoMode(t *testing.T) {
+	karpenter := EksFargateSelectorConfig{Namespace: "kube-system", Labels: map[string]string{"app.kubernetes.io/name": "karpenter"}}
+	tests := []struct {
+		name   string
+		config EksInstanceConfig
+		want   string
+	}{
+		{"valid", EksInstanceConfig{EksVersion: "1.31", AutoMode: EksAutoModeConfig{Enabled: jsii.Bool(true), NodePools: []string{"system"}},
+			FargateProfiles: []EksFargateProfileConfig{{Name: "karpenter", Selectors: []EksFargateSelectorConfig{karpenter}, AzIndices: []int{1, 2}}}}, ""},
+		{"name", EksInstanceConfig{FargateProfiles: []EksFargateProfileConfig{{Name: "Karpenter", Selectors: []EksFargateSelectorConfig{karpenter}}}}, "lowercase"},
+		{"duplicate", EksInstanceConfig{FargateProfiles: []EksFargateProfileConfig{{Name: "a", Selectors: []EksFargateSelectorConfig{karpenter}}, {Name: "a", Selectors: []EksFargateSelectorConfig{karpenter}}}}, "duplicate fargate profile name"},
+		{"selectors", EksInstanceConfig{FargateProfiles: []EksFargateProfileConfig{{Name: "a"}}}, "between 1 and 5"},
+		{"namespace", EksInstanceConfig{FargateProfiles: []EksFargateProfileConfig{{Name: "a", Selectors: []EksFargateSelectorConfig{{}}}}}, "namespace is required"},
+		{"az index", EksInstanceConfig{FargateProfiles: []EksFargateProfileConfig{{Name: "a", Selectors: []EksFargateSelectorConfig{karpenter}, AzIndices: []int{0}}}}, "start at 1"},
+		{"auto mode node pool", EksInstanceConfig{EksVersion: "1.31", AutoMode: EksAutoModeConfig{Enabled: jsii.Bool(true), NodePools: []string{"gpu"}}}, "unknown node pool 'gpu'"},
+		{"auto mode version", EksInstanceConfig{EksVersion: "1.28", AutoMode: EksAutoModeConfig{Enabled: jsii.Bool(true)}}, "requires Kubernetes 1.29"},
+		{"auto mode with karpenter", EksInstanceConfig{EksVersion: "1.31", AutoMode: EksAutoModeConfig{Enabled: jsii.Bool(true)},
+			KarpenterNodePools: KarpenterNodePoolList{Types: "cpu"}}, "karpenterNodePools is not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validateFargateProfiles()
+			if err == nil {
+				err = tt.config.validateAutoMode()
+			}
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAutoModeMergeConfigs(t *testing.T) {
+	forge := &EksForge{}
+	defaults := &EksInstanceConfig{EksVersion: "1.31", KarpenterNodePools: KarpenterNodePoolList{Types: "cpu,gpu"}}
+
+	// 默认配置中的 karpenterNodePools 不会让 Auto Mode 实例校验失败
+	merged := forge.MergeConfigs(defaults, &EksInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "auto"},
+		AutoMode:           EksAutoModeConfig{Enabled: jsii.Bool(true)},
+	}).(*EksInstanceConfig)
+	if !merged.KarpenterNodePools.IsEmpty() {
+		t.Errorf("Expected default karpenterNodePools to be dropped, got %+v", merged.KarpenterNodePools)
+	}
+	if err := merged.validateAutoMode(); err != nil {
+		t.Errorf("Expected Auto Mode to be valid, got %v", err)
+	}
+
+	// 实例自己配置的 karpenterNodePools 保留下来并在校验时报错
+	merged = forge.MergeConfigs(defaults, &EksInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "auto"},
+		AutoMode:           EksAutoModeConfig{Enabled: jsii.Bool(true)},
+		KarpenterNodePools: KarpenterNodePoolList{Types: "gpu"},
+	}).(*EksInstanceConfig)
+	if err := merged.validateAutoMode(); err == nil || !strings.Contains(err.Error(), "karpenterNodePools") {
+		t.Errorf("Expected karpenterNodePools to be rejected, got %v", err)
+	}
+}
+
+func TestConfigureAutoMode(t *testing.T) {
+	stack := awscdk.NewStack(awscdk.NewApp(nil), jsii.String("AutoModeTest"), nil)
+	eksInstance := &EksInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "auto"},
+		EksVersion:         "1.31",
+		AutoMode:           EksAutoModeConfig{Enabled: jsii.Bool(true), NodePools: []string{"system"}},
+	}
+	cluster := awseks.NewCluster(stack, jsii.String("Cluster"), &awseks.ClusterProps{
+		Version:         awseks.KubernetesVersion_Of(jsii.String(eksInstance.EksVersion)),
+		KubectlLayer:    utils.GetKubectlLayer(stack, "kubectl", eksInstance.EksVersion),
+		DefaultCapacity: jsii.Number(0),
+	})
+	configureAutoMode(stack, cluster, eksInstance)
+
+	template := assertions.Template_FromStack(stack, nil)
+	template.ResourceCountIs(jsii.String("Custom::EksAutoMode"), jsii.Number(1))
+	template.HasResourceProperties(jsii.String("Custom::EksAutoMode"), map[string]interface{}{
+		"NodePools": []interface{}{"system"},
+	})
+
+	// Auto Mode 配置不能经过集群 Handler，否则布尔值会以字符串传给 CreateCluster
+	clusters := template.FindResources(jsii.String("Custom::AWSCDK-EKS-Cluster"), nil)
+	for logicalId, resource := range *clusters {
+		content, _ := json.Marshal(resource)
+		for _, key := range []string{"computeConfig", "storageConfig", "elasticLoadBalancing"} {
+			if strings.Contains(string(content), key) {
+				t.Errorf("Expected %s not to carry %s, got %s", logicalId, key, content)
+			}
+		}
+	}
+
+	// 沿用 CDK 创建的集群角色，附加 Auto Mode 策略和 sts:TagSession
+	roles := template.FindResources(jsii.String("AWS::IAM::Role"), nil)
+	clusterRoles := 0
+	for logicalId, role := range *roles {
+		content, _ := json.Marshal(role)
+		if !strings.Contains(string(content), "eks.amazonaws.com") {
+			continue
+		}
+		clusterRoles++
+		for _, want := range []string{"AmazonEKSClusterPolicy", "AmazonEKSComputePolicy", "AmazonEKSNetworkingPolicy", "sts:TagSession"} {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("Expected cluster role %s to contain %s", logicalId, want)
+			}
+		}
+	}
+	if clusterRoles != 1 {
+		t.Errorf("Expected the Auto Mode policies on the existing cluster role, got %d cluster roles", clusterRoles)
+	}
+
+	// Handler 以 JSON 布尔值开启计算、块存储和负载均衡
+	functions := template.FindResources(jsii.String("AWS::Lambda::Function"), nil)
+	handlers := 0
+	for _, function := range *functions {
+		content, _ := json.Marshal(function)
+		if strings.Contains(string(content), "update_cluster_config") {
+			handlers++
+			for _, want := range []string{`'enabled': True`, `'blockStorage': {'enabled': True}`, `'elasticLoadBalancing': {'enabled': True}`} {
+				if !strings.Contains(string(content), want) {
+					t.Errorf("Expected the Auto Mode handler to contain %q", want)
+				}
+			}
+		}
+	}
+	if handlers != 2 {
+		t.Errorf("Expected on_event and is_complete functions with the Auto Mode handler, got %d", handlers)
+	}
+}
+
+func TestCreateFargateProfiles(t *testing.T) {
+	eksInstance := &EksInstanceConfig{FargateProfiles: []EksFargateProfileConfig{
+		{Name: "karpenter", Selectors: []EksFargateSelectorConfig{{Namespace: "kube-system", Labels: map[string]string{"app.kubernetes.io/name": "karpenter"}}}},
+		{Name: "batch", Selectors: []EksFargateSelectorConfig{{Namespace: "batch"}, {Namespace: "jobs"}}},
+	}}
+	ctx, cluster := newTestCluster(t, eksInstance, awsec2.SubnetType_PUBLIC)
+	if profiles := createFargateProfiles(ctx, cluster, eksInstance); len(profiles) != 2 {
+		t.Fatalf("Expected 2 Fargate profiles, got %d", len(profiles))
+	}
+
+	// 公有子网的集群也只把 Profile 放在私有子网
+	template := assertions.Template_FromStack(ctx.Stack, nil)
+	template.ResourceCountIs(jsii.String("Custom::AWSCDK-EKS-FargateProfile"), jsii.Number(2))
+	template.HasResourceProperties(jsii.String("Custom::AWSCDK-EKS-FargateProfile"), map[string]interface{}{
+		"Config": assertions.Match_ObjectLike(&map[string]interface{}{
+			"fargateProfileName": "karpenter",
+			"selectors": []interface{}{map[string]interface{}{
+				"namespace": "kube-system",
+				"labels":    map[string]interface{}{"app.kubernetes.io/name": "karpenter"},
+			}},
+			"subnets": []interface{}{
+				map[string]interface{}{"Ref": assertions.Match_StringLikeRegexp(jsii.String("VpcPrivateSubnet1"))},
+				map[string]interface{}{"Ref": assertions.Match_StringLikeRegexp(jsii.String("VpcPrivateSubnet2"))},
+			},
+		}),
+	})
+	template.HasResourceProperties(jsii.String("Custom::AWSCDK-EKS-FargateProfile"), map[string]interface{}{
+		"Config": assertions.Match_ObjectLike(&map[string]interface{}{
+			"fargateProfileName": "batch",
+			"selectors":          []interface{}{map[string]interface{}{"namespace": "batch"}, map[string]interface{}{"namespace": "jobs"}},
+		}),
+	})
+}
@@ -0,0 +1,328 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
+	"github.com/aws/constructs-go/constructs/v10"
+	"github.com/aws/jsii-runtime-go"
+	"gopkg.in/yaml.v3"
+)
+
+// EksHelmReleaseConfig 通用 Helm Release，用于安装没有内置支持的组件
+type EksHelmReleaseConfig struct {
+	Name            string                 `json:"name"`                 // Release 名称，同时用于 dependsOn 引用
+	Repository      string                 `json:"repository,omitempty"` // Chart 仓库地址，支持 https:// 和 oci://（如 oci://public.ecr.aws/karpenter）
+	Chart           string                 `json:"chart"`
+	Version         string                 `json:"version,omitempty"`         // 留空使用仓库中的最新版本
+	Namespace       string                 `json:"namespace,omitempty"`       // 默认 default
+	CreateNamespace *bool                  `json:"createNamespace,omitempty"` // 默认 true
+	Values          map[string]interface{} `json:"values,omitempty"`          // 覆盖 valuesFile 中的同名配置
+	ValuesFile      string                 `json:"valuesFile,omitempty"`      // 本地 values YAML 文件路径
+	Wait            *bool                  `json:"wait,omitempty"`            // 等待资源就绪，默认 false
+	Timeout         string                 `json:"timeout,omitempty"`         // 如 10m，最长 15m
+	DependsOn       []string               `json:"dependsOn,omitempty"`       // 需要先安装的其他 Release 名称
+
+	mergedValues map[string]interface{} // loadHelmContent 读取 valuesFile 并与 values 合并的结果
+}
+
+// EksManifestConfig 部署时渲染进模板的 Kubernetes 清单
+type EksManifestConfig struct {
+	Name      string   `json:"name"`
+	Source    string   `json:"source"`              // 本地 YAML 文件路径或 https URL，Validate 时读取
+	DependsOn []string `json:"dependsOn,omitempty"` // 需要先安装的 Helm Release 名称
+
+	documents []*map[string]interface{} // loadHelmContent 读取并解析的清单文档
+	loaded    bool
+}
+
+var releaseNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,51}[a-z0-9])?$`)
+
+// maxHelmTimeout Kubectl Handler 的 Lambda 超时限制
+const maxHelmTimeout = 15 * time.Minute
+
+// GetNamespace 返回 Release 所在命名空间
+func (r EksHelmReleaseConfig) GetNamespace() string {
+	if r.Namespace == "" {
+		return "default"
+	}
+	return r.Namespace
+}
+
+// validateHelmReleases 校验 Release 名称、依赖引用和循环依赖，并读取 valuesFile 和清单内容，使读取错误在合成前暴露
+func (c *EksInstanceConfig) validateHelmReleases() error {
+	releases := make(map[string]EksHelmReleaseConfig)
+	for i, release := range c.HelmReleases {
+		field := fmt.Sprintf("helmReleases[%d]", i)
+		if !releaseNamePattern.MatchString(release.Name) {
+			return fmt.Errorf("%s: name '%s' must be lowercase alphanumeric or '-' and at most 53 characters", field, release.Name)
+		}
+		if _, ok := releases[release.Name]; ok {
+			return fmt.Errorf("%s: duplicate release name '%s'", field, release.Name)
+		}
+		if release.Chart == "" {
+			return fmt.Errorf("%s: chart is required", field)
+		}
+		if release.Repository == "" {
+			return fmt.Errorf("%s: repository is required", field)
+		}
+		if err := validateNamespaces([]string{release.GetNamespace()}); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if release.Timeout != "" {
+			timeout, err := time.ParseDuration(release.Timeout)
+			if err != nil || timeout <= 0 || timeout > maxHelmTimeout {
+				return fmt.Errorf("%s: timeout '%s' must be a duration between 1s and 15m", field, release.Timeout)
+			}
+		}
+		releases[release.Name] = release
+	}
+
+	for i, release := range c.HelmReleases {
+		for _, dep := range release.DependsOn {
+			if _, ok := releases[dep]; !ok {
+				return fmt.Errorf("helmReleases[%d]: dependsOn references unknown release '%s'", i, dep)
+			}
+		}
+	}
+
+	// 深度优先检测循环依赖
+	state := make(map[string]int) // 0 未访问，1 访问中，2 已完成
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case 1:
+			return fmt.Errorf("helmReleases: circular dependsOn %s", strings.Join(append(path, name), " -> "))
+		case 2:
+			return nil
+		}
+		state[name] = 1
+		for _, dep := range releases[name].DependsOn {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = 2
+		return nil
+	}
+	for _, release := range c.HelmReleases {
+		if err := visit(release.Name, nil); err != nil {
+			return err
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, manifest := range c.Manifests {
+		field := fmt.Sprintf("manifests[%d]", i)
+		if !releaseNamePattern.MatchString(manifest.Name) {
+			return fmt.Errorf("%s: name '%s' must be lowercase alphanumeric or '-' and at most 53 characters", field, manifest.Name)
+		}
+		if seen[manifest.Name] {
+			return fmt.Errorf("%s: duplicate manifest name '%s'", field, manifest.Name)
+		}
+		seen[manifest.Name] = true
+		if manifest.Source == "" {
+			return fmt.Errorf("%s: source is required", field)
+		}
+		for _, dep := range manifest.DependsOn {
+			if _, ok := releases[dep]; !ok {
+				return fmt.Errorf("%s: dependsOn references unknown release '%s'", field, dep)
+			}
+		}
+		if strings.HasPrefix(manifest.Source, "http://") {
+			return fmt.Errorf("%s: source %s must use https", field, manifest.Source)
+		}
+	}
+	return c.loadHelmContent()
+}
+
+// loadHelmContent 读取 valuesFile 和清单内容，已读取的不再重复访问文件或网络
+func (c *EksInstanceConfig) loadHelmContent() error {
+	for i, release := range c.HelmReleases {
+		if release.mergedValues != nil {
+			continue
+		}
+		values, err := helmReleaseValues(release)
+		if err != nil {
+			return fmt.Errorf("helmReleases[%d]: %w", i, err)
+		}
+		c.HelmReleases[i].mergedValues = values
+	}
+	for i, manifest := range c.Manifests {
+		if manifest.loaded {
+			continue
+		}
+		content, err := readManifestSource(manifest.Source)
+		if err != nil {
+			return fmt.Errorf("manifests[%d]: %w", i, err)
+		}
+		documents, err := decodeManifestDocuments(content)
+		if err != nil {
+			return fmt.Errorf("manifests[%d]: failed to parse %s: %w", i, manifest.Source, err)
+		}
+		c.Manifests[i].documents = documents
+		c.Manifests[i].loaded = true
+	}
+	return nil
+}
+
+// deployHelmReleases 按配置顺序安装通用 Helm Release，返回按名称索引的 Chart
+// platform 为 Karpenter、CSI 驱动等平台组件，所有 Release 在其之后安装，调用前需先执行 loadHelmContent
+func deployHelmReleases(cluster awseks.Cluster, eksInstance *EksInstanceConfig, platform []constructs.Construct) map[string]awseks.HelmChart {
+	charts := make(map[string]awseks.HelmChart)
+	for _, release := range eksInstance.HelmReleases {
+		values := release.mergedValues
+
+		options := &awseks.HelmChartOptions{
+			Chart:           jsii.String(release.Chart),
+			Repository:      jsii.String(release.Repository),
+			Release:         jsii.String(release.Name),
+			Namespace:       jsii.String(release.GetNamespace()),
+			CreateNamespace: jsii.Bool(release.CreateNamespace == nil || *release.CreateNamespace),
+			Wait:            jsii.Bool(release.Wait != nil && *release.Wait),
+		}
+		if release.Version != "" {
+			options.Version = jsii.String(release.Version)
+		}
+		if len(values) > 0 {
+			options.Values = &values
+		}
+		if release.Timeout != "" {
+			timeout, _ := time.ParseDuration(release.Timeout)
+			options.Timeout = awscdk.Duration_Seconds(jsii.Number(timeout.Seconds()))
+		}
+
+		chart := cluster.AddHelmChart(jsii.String("HelmRelease-"+release.Name), options)
+		for _, component := range platform {
+			if component != nil {
+				chart.Node().AddDependency(component)
+			}
+		}
+		charts[release.Name] = chart
+	}
+
+	// 所有 Release 创建后再添加相互依赖，dependsOn 不受配置顺序影响
+	for _, release := range eksInstance.HelmReleases {
+		for _, dep := range release.DependsOn {
+			charts[release.Name].Node().AddDependency(charts[dep])
+		}
+	}
+	return charts
+}
+
+// helmReleaseValues 读取 valuesFile 并与 values 深度合并，values 优先
+func helmReleaseValues(release EksHelmReleaseConfig) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	if release.ValuesFile != "" {
+		content, err := os.ReadFile(release.ValuesFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read values file: %w", err)
+		}
+		if err := yaml.Unmarshal(content, &values); err != nil {
+			return nil, fmt.Errorf("failed to parse values file %s: %w", release.ValuesFile, err)
+		}
+		if values == nil {
+			values = make(map[string]interface{})
+		}
+	}
+	mergeHelmValues(values, release.Values)
+	return values, nil
+}
+
+// mergeHelmValues 将 override 递归合并到 base
+func mergeHelmValues(base map[string]interface{}, override map[string]interface{}) {
+	for k, v := range override {
+		overrideMap, ok := v.(map[string]interface{})
+		if baseMap, isMap := base[k].(map[string]interface{}); ok && isMap {
+			mergeHelmValues(baseMap, overrideMap)
+			continue
+		}
+		base[k] = v
+	}
+}
+
+// deployManifests 部署 loadHelmContent 读取的清单，位于平台组件和所引用的 Release 之后
+func deployManifests(cluster awseks.Cluster, eksInstance *EksInstanceConfig, platform []constructs.Construct, charts map[string]awseks.HelmChart) []awseks.KubernetesManifest {
+	var manifests []awseks.KubernetesManifest
+	for _, config := range eksInstance.Manifests {
+		documents := config.documents
+		if len(documents) == 0 {
+			fmt.Printf("Warning: manifest %s (%s) contains no Kubernetes resources, skipping\n", config.Name, config.Source)
+			continue
+		}
+
+		manifest := cluster.AddManifest(jsii.String("Manifest-"+config.Name), documents...)
+		for _, component := range platform {
+			if component != nil {
+				manifest.Node().AddDependency(component)
+			}
+		}
+		for _, dep := range config.DependsOn {
+			manifest.Node().AddDependency(charts[dep])
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests
+}
+
+// readManifestSource 读取本地文件或下载 https URL 内容
+func readManifestSource(source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "https://") {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", source, err)
+		}
+		return content, nil
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", source, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s: HTTP %d", source, resp.StatusCode)
+	}
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read content from %s: %w", source, err)
+	}
+	return content, nil
+}
+
+// decodeManifestDocuments 解析多文档 YAML，跳过空文档
+func decodeManifestDocuments(content []byte) ([]*map[string]interface{}, error) {
+	var documents []*map[string]interface{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var document map[string]interface{}
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(document) == 0 {
+			continue
+		}
+		if _, ok := document["kind"]; !ok {
+			return nil, fmt.Errorf("document %d has no kind", len(documents)+1)
+		}
+		documents = append(documents, &document)
+	}
+	return documents, nil
+}
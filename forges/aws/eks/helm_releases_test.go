@@ -0,0 +1,136 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2/assertions"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/jsii-runtime-go"
+)
+
+func TestValidateHelmReleases(t *testing.T) {
+	dir := t.TempDir()
+	issuers := filepath.Join(dir, "issuers.yaml")
+	invalid := filepath.Join(dir, "invalid.yaml")
+	values := filepath.Join(dir, "values.yaml")
+	for path, content := range map[string]string{
+		issuers: "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: issuers\n",
+		invalid: "kind: [\n",
+		values:  "replicaCount: 2\n",
+	} {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		config EksInstanceConfig
+		want   string
+	}{
+		{"valid", EksInstanceConfig{
+			HelmReleases: []EksHelmReleaseConfig{{Name: "external-dns", Repository: "https://kubernetes-sigs.github.io/external-dns", Chart: "external-dns", DependsOn: []string{"podinfo"}}, {Name: "podinfo", Repository: "oci://ghcr.io/stefanprodan/charts", Chart: "podinfo", Timeout: "10m", ValuesFile: values}},
+			Manifests:    []EksManifestConfig{{Name: "issuers", Source: issuers, DependsOn: []string{"external-dns"}}},
+		}, ""},
+		{"name", EksInstanceConfig{HelmReleases: []EksHelmReleaseConfig{{Name: "ExternalDNS", Repository: "r", Chart: "c"}}}, "lowercase"},
+		{"duplicate", EksInstanceConfig{HelmReleases: []EksHelmReleaseConfig{{Name: "a", Repository: "r", Chart: "c"}, {Name: "a", Repository: "r", Chart: "c"}}}, "duplicate release name"},
+		{"repository", EksInstanceConfig{HelmReleases: []EksHelmReleaseConfig{{Name: "a", Chart: "c"}}}, "repository is required"},
+		{"timeout", EksInstanceConfig{HelmReleases: []EksHelmReleaseConfig{{Name: "a", Repository: "r", Chart: "c", Timeout: "30m"}}}, "between 1s and 15m"},
+		{"unknown dependency", EksInstanceConfig{HelmReleases: []EksHelmReleaseConfig{{Name: "a", Repository: "r", Chart: "c", DependsOn: []string{"b"}}}}, "unknown release 'b'"},
+		{"cycle", EksInstanceConfig{HelmReleases: []EksHelmReleaseConfig{{Name: "a", Repository: "r", Chart: "c", DependsOn: []string{"b"}}, {Name: "b", Repository: "r", Chart: "c", DependsOn: []string{"a"}}}}, "circular dependsOn a -> b -> a"},
+		{"manifest source", EksInstanceConfig{Manifests: []EksManifestConfig{{Name: "a"}}}, "source is required"},
+		{"manifest dependency", EksInstanceConfig{Manifests: []EksManifestConfig{{Name: "a", Source: issuers, DependsOn: []string{"b"}}}}, "unknown release 'b'"},
+		{"missing values file", EksInstanceConfig{HelmReleases: []EksHelmReleaseConfig{{Name: "a", Repository: "r", Chart: "c", ValuesFile: filepath.Join(dir, "missing.yaml")}}}, "failed to read values file"},
+		{"invalid values file", EksInstanceConfig{HelmReleases: []EksHelmReleaseConfig{{Name: "a", Repository: "r", Chart: "c", ValuesFile: invalid}}}, "failed to parse values file"},
+		{"manifest http", EksInstanceConfig{Manifests: []EksManifestConfig{{Name: "a", Source: "http://example.com/a.yaml"}}}, "must use https"},
+		{"missing manifest", EksInstanceConfig{Manifests: []EksManifestConfig{{Name: "a", Source: filepath.Join(dir, "missing.yaml")}}}, "failed to read"},
+		{"invalid manifest", EksInstanceConfig{Manifests: []EksManifestConfig{{Name: "a", Source: invalid}}}, "failed to parse"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validateHelmReleases()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Expected valid config, got %v", err)
+				}
+				if len(tt.config.Manifests[0].documents) != 1 || tt.config.HelmReleases[1].mergedValues["replicaCount"] != 2 {
+					t.Errorf("Expected manifests and values to be loaded during validation")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestHelmValuesAndManifestDocuments(t *testing.T) {
+	base := map[string]interface{}{"service": map[string]interface{}{"type": "ClusterIP", "port": 80}}
+	mergeHelmValues(base, map[string]interface{}{"service": map[string]interface{}{"type": "LoadBalancer"}})
+	service := base["service"].(map[string]interface{})
+	if service["type"] != "LoadBalancer" || service["port"] != 80 {
+		t.Errorf("Expected nested values to be merged, got %v", service)
+	}
+
+	documents, err := decodeManifestDocuments([]byte("---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: a\n---\n---\napiVersion: v1\nkind: Namespace\nmetadata:\n  name: b\n"))
+	if err != nil || len(documents) != 2 {
+		t.Fatalf("Expected 2 documents, got %d (%v)", len(documents), err)
+	}
+	if _, err := decodeManifestDocuments([]byte("foo: bar\n")); err == nil {
+		t.Error("Expected a document without kind to be rejected")
+	}
+}
+
+func TestDeployHelmReleases(t *testing.T) {
+	dir := t.TempDir()
+	valuesFile := filepath.Join(dir, "values.yaml")
+	if err := os.WriteFile(valuesFile, []byte("replicas: 1\nimage:\n  tag: v1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	manifestFile := filepath.Join(dir, "ingress.yaml")
+	if err := os.WriteFile(manifestFile, []byte("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: apps\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// 未经过 Validate 的配置在部署前通过 loadHelmContent 读取 values 和清单
+	eksInstance := &EksInstanceConfig{
+		HelmReleases: []EksHelmReleaseConfig{
+			{Name: "base", Repository: "https://charts.example.com", Chart: "base"},
+			{Name: "app", Repository: "oci://public.ecr.aws/example", Chart: "app", Version: "1.2.3", Namespace: "apps",
+				ValuesFile: valuesFile, Values: map[string]interface{}{"replicas": 3}, DependsOn: []string{"base"}},
+		},
+		Manifests: []EksManifestConfig{{Name: "apps", Source: manifestFile, DependsOn: []string{"app"}}},
+	}
+	if err := eksInstance.loadHelmContent(); err != nil {
+		t.Fatalf("Expected helm content to load, got %v", err)
+	}
+	ctx, cluster := newTestCluster(t, eksInstance, awsec2.SubnetType_PRIVATE_WITH_EGRESS)
+	charts := deployHelmReleases(cluster, eksInstance, nil)
+	deployManifests(cluster, eksInstance, nil, charts)
+
+	template := assertions.Template_FromStack(ctx.Stack, nil)
+	template.ResourceCountIs(jsii.String("Custom::AWSCDK-EKS-HelmChart"), jsii.Number(2))
+	template.HasResourceProperties(jsii.String("Custom::AWSCDK-EKS-HelmChart"), map[string]interface{}{
+		"Release":   "app",
+		"Chart":     "app",
+		"Version":   "1.2.3",
+		"Namespace": "apps",
+		"Values":    `{"image":{"tag":"v1"},"replicas":3}`,
+	})
+	// app 依赖 base，清单依赖 app
+	template.HasResource(jsii.String("Custom::AWSCDK-EKS-HelmChart"), map[string]interface{}{
+		"Properties": map[string]interface{}{"Release": "app"},
+		"DependsOn":  assertions.Match_ArrayWith(&[]interface{}{assertions.Match_StringLikeRegexp(jsii.String("HelmReleasebase"))}),
+	})
+	template.HasResource(jsii.String("Custom::AWSCDK-EKS-KubernetesResource"), map[string]interface{}{
+		"Properties": map[string]interface{}{"Manifest": assertions.Match_StringLikeRegexp(jsii.String(`"kind":"Namespace","metadata":\{"name":"apps"`))},
+		"DependsOn":  assertions.Match_ArrayWith(&[]interface{}{assertions.Match_StringLikeRegexp(jsii.String("HelmReleaseapp"))}),
+	})
+}
@@ -205,6 +205,7 @@ func deployHyperPodComponents(stack awscdk.Stack, cluster awseks.Cluster, eksIns
 	hyperPodCleanup := eksutils.CreateKubectlExecutor(stack, "HyperPodCleanup", &eksutils.KubectlExecutorProps{
 		Cluster:     cluster,
 		EksVersion:  eksInstance.EksVersion,
+		ApiAuthMode: eksInstance.Access.IsApiMode(),
 		OnDeleteCmds: []string{
 			// 清理 HyperPod 相关 namespace 中的资源
 			"kubectl delete all --all -n hyperpod-inference-system --ignore-not-found=true --timeout=60s || true",
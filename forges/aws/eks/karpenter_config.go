@@ -0,0 +1,450 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// KarpenterNodePoolList karpenterNodePools 配置，兼容旧格式 "cpu,gpu,neuron" 字符串和节点池数组
+type KarpenterNodePoolList struct {
+	Types string                    // 旧格式：启用的内置节点池，配合 karpenterCpu*/Gpu*/Neuron* 扁平字段
+	Pools []KarpenterNodePoolConfig // 新格式：任意命名的节点池
+}
+
+// KarpenterNodePoolConfig 单个 Karpenter NodePool 及其 EC2NodeClass 的配置
+type KarpenterNodePoolConfig struct {
+	Name string `json:"name"`           // NodePool 和 EC2NodeClass 名称
+	Type string `json:"type,omitempty"` // cpu（默认）、gpu、neuron，决定 AMI 变体和 NVIDIA 设备插件
+
+	// 实例选择简写，为空时使用默认值：代数大于 2、spot 和 on-demand、amd64 和 arm64
+	InstanceTypes       []string `json:"instanceTypes,omitempty"`       // ["m5.large", "c5.xlarge"]
+	InstanceFamilies    []string `json:"instanceFamilies,omitempty"`    // ["m5", "c5"]
+	InstanceCategories  []string `json:"instanceCategories,omitempty"`  // ["c", "m", "r"]
+	InstanceGenerations []string `json:"instanceGenerations,omitempty"` // ["6", "7"]
+	CapacityTypes       []string `json:"capacityTypes,omitempty"`       // ["spot", "on-demand", "reserved"]
+	Architectures       []string `json:"architectures,omitempty"`       // ["amd64", "arm64"]
+
+	Requirements []KarpenterRequirement `json:"requirements,omitempty"` // 原生 requirements，与简写 key 相同时覆盖简写
+	Limits       map[string]string      `json:"limits,omitempty"`       // {"cpu": "1000", "memory": "1000Gi", "nvidia.com/gpu": "64"}
+	Weight       int                    `json:"weight,omitempty"`       // 1-100，多个 NodePool 匹配时优先使用权重高的
+	ExpireAfter  string                 `json:"expireAfter,omitempty"`  // 默认 720h，Never 表示不过期
+	Disruption   *KarpenterDisruption   `json:"disruption,omitempty"`
+	Labels       map[string]string      `json:"labels,omitempty"`
+	Taints       []KarpenterTaint       `json:"taints,omitempty"`
+	NodeClass    KarpenterNodeClass     `json:"nodeClass,omitempty"`
+
+	TerminationGracePeriod string `json:"terminationGracePeriod,omitempty"` // 节点排空的最长时间，超时后强制删除 Pod，如 48h
+}
+
+// KarpenterRequirement NodePool 调度约束
+type KarpenterRequirement struct {
+	Key       string   `json:"key" yaml:"key"`
+	Operator  string   `json:"operator" yaml:"operator"` // In、NotIn、Exists、DoesNotExist、Gt、Lt
+	Values    []string `json:"values,omitempty" yaml:"values,omitempty"`
+	MinValues int      `json:"minValues,omitempty" yaml:"minValues,omitempty"`
+}
+
+// KarpenterTaint 节点污点
+type KarpenterTaint struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value,omitempty" yaml:"value,omitempty"`
+	Effect string `json:"effect" yaml:"effect"` // NoSchedule、PreferNoSchedule、NoExecute
+}
+
+// KarpenterDisruption NodePool 中断配置
+type KarpenterDisruption struct {
+	ConsolidationPolicy string            `json:"consolidationPolicy,omitempty"` // WhenEmptyOrUnderutilized（默认）或 WhenEmpty
+	ConsolidateAfter    string            `json:"consolidateAfter,omitempty"`    // 默认 30s，Never 表示不合并
+	Budgets             []KarpenterBudget `json:"budgets,omitempty"`
+}
+
+// KarpenterBudget 中断预算，限制同时被中断的节点数量
+type KarpenterBudget struct {
+	Nodes    string   `json:"nodes" yaml:"nodes"`                           // "10%" 或 "5"，"0" 表示禁止中断
+	Schedule string   `json:"schedule,omitempty" yaml:"schedule,omitempty"` // UTC cron 表达式，需配合 duration
+	Duration string   `json:"duration,omitempty" yaml:"duration,omitempty"` // 如 8h、1h30m
+	Reasons  []string `json:"reasons,omitempty" yaml:"reasons,omitempty"`   // Underutilized、Empty、Drifted，为空时适用所有原因
+}
+
+// KarpenterNodeClass EC2NodeClass 配置
+type KarpenterNodeClass struct {
+	OsType           string            `json:"osType,omitempty"` // linux 或 bottlerocket，默认使用 karpenterOsType
+	DiskSize         int               `json:"diskSize,omitempty"`
+	DiskType         string            `json:"diskType,omitempty"`       // gp3（默认）、gp2、io1、io2
+	DiskIops         int               `json:"diskIops,omitempty"`       // gp3/io1/io2，默认 3000
+	DiskThroughput   int               `json:"diskThroughput,omitempty"` // gp3 吞吐量（MiB/s）
+	UseInstanceStore bool              `json:"useInstanceStore,omitempty"`
+	Tags             map[string]string `json:"tags,omitempty"`
+}
+
+var (
+	karpenterNamePattern  = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	requirementOperators  = []string{"In", "NotIn", "Exists", "DoesNotExist", "Gt", "Lt"}
+	taintEffects          = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+	consolidationPolicies = []string{"WhenEmptyOrUnderutilized", "WhenEmpty"}
+	disruptionReasons     = []string{"Underutilized", "Empty", "Drifted"}
+
+	// Karpenter v1 CRD 中的格式约束
+	karpenterDurationPattern = regexp.MustCompile(`^([0-9]+(s|m|h))+$`)
+	budgetNodesPattern       = regexp.MustCompile(`^((100|[0-9]{1,2})%|[0-9]+)$`)
+	budgetDurationPattern    = regexp.MustCompile(`^(([0-9]+(h|m))|([0-9]+h[0-9]+m))(0s)?$`)
+	cronMacros               = []string{"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly"}
+	quantityPattern          = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?(m|k|M|G|T|P|E|Ki|Mi|Gi|Ti|Pi|Ei)?$`)
+
+	// 常见的节点池资源限制，其他资源名称只给出告警
+	knownLimitResources = []string{"cpu", "memory", "nvidia.com/gpu", "aws.amazon.com/neuron", "aws.amazon.com/neuroncore", "ephemeral-storage", "pods"}
+)
+
+// UnmarshalJSON 支持字符串（旧格式）和节点池数组两种写法
+func (l *KarpenterNodePoolList) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		return json.Unmarshal(data, &l.Types)
+	}
+	if err := json.Unmarshal(data, &l.Pools); err != nil {
+		return fmt.Errorf("karpenterNodePools must be a string such as \"cpu,gpu\" or an array of node pools: %w", err)
+	}
+	return nil
+}
+
+// MarshalJSON 按配置时的写法输出
+func (l KarpenterNodePoolList) MarshalJSON() ([]byte, error) {
+	if len(l.Pools) > 0 {
+		return json.Marshal(l.Pools)
+	}
+	return json.Marshal(l.Types)
+}
+
+// IsEmpty 是否未配置任何节点池
+func (l KarpenterNodePoolList) IsEmpty() bool {
+	return l.Types == "" && len(l.Pools) == 0
+}
+
+// Resolve 返回要创建的节点池，旧格式由 karpenterCpu*/Gpu*/Neuron* 扁平字段转换
+func (l KarpenterNodePoolList) Resolve(eksInstance *EksInstanceConfig) []KarpenterNodePoolConfig {
+	if len(l.Pools) > 0 {
+		return l.Pools
+	}
+
+	var pools []KarpenterNodePoolConfig
+	for _, nodePoolType := range parseCommaSeparatedString(l.Types) {
+		nodePoolType = normalizeNodePoolType(nodePoolType)
+		switch nodePoolType {
+		case NodePoolTypeCpu, NodePoolTypeGpu, NodePoolTypeNeuron:
+			pools = append(pools, getNodePoolConfig(eksInstance, nodePoolType).toNodePool(nodePoolType))
+		default:
+			fmt.Printf("Warning: Unknown node pool type '%s', skipping\n", nodePoolType)
+		}
+	}
+	return pools
+}
+
+// HasGpuPool 是否包含 GPU 节点池，用于决定是否部署 NVIDIA Device Plugin
+func (l KarpenterNodePoolList) HasGpuPool() bool {
+	if len(l.Pools) == 0 {
+		for _, nodePoolType := range parseCommaSeparatedString(l.Types) {
+			if normalizeNodePoolType(nodePoolType) == NodePoolTypeGpu {
+				return true
+			}
+		}
+		return false
+	}
+	for _, pool := range l.Pools {
+		if normalizeNodePoolType(pool.Type) == NodePoolTypeGpu {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate 校验节点池名称、类型、调度约束、污点和中断配置
+func (l KarpenterNodePoolList) Validate() error {
+	seen := make(map[string]bool)
+	for i, pool := range l.Pools {
+		if pool.Name == "" {
+			return fmt.Errorf("karpenterNodePools[%d]: name is required", i)
+		}
+		if !karpenterNamePattern.MatchString(pool.Name) || len(pool.Name) > 63 {
+			return fmt.Errorf("karpenterNodePools %s: name must be lowercase alphanumeric or '-' and at most 63 characters", pool.Name)
+		}
+		// 构造 ID 忽略 karpenter- 前缀，与旧格式生成的 karpenter-cpu 等节点池保持一致
+		constructName := pool.constructName()
+		if seen[constructName] {
+			return fmt.Errorf("karpenterNodePools %s: duplicate name", pool.Name)
+		}
+		seen[constructName] = true
+
+		switch normalizeNodePoolType(pool.Type) {
+		case NodePoolTypeCpu, NodePoolTypeGpu, NodePoolTypeNeuron:
+		default:
+			return fmt.Errorf("karpenterNodePools %s: unknown type '%s', expected cpu, gpu or neuron", pool.Name, pool.Type)
+		}
+
+		for _, requirement := range pool.Requirements {
+			if requirement.Key == "" {
+				return fmt.Errorf("karpenterNodePools %s: requirement key is required", pool.Name)
+			}
+			if !containsString(requirementOperators, requirement.Operator) {
+				return fmt.Errorf("karpenterNodePools %s: requirement %s has unknown operator '%s', expected %s",
+					pool.Name, requirement.Key, requirement.Operator, strings.Join(requirementOperators, ", "))
+			}
+			existence := requirement.Operator == "Exists" || requirement.Operator == "DoesNotExist"
+			if existence && len(requirement.Values) > 0 {
+				return fmt.Errorf("karpenterNodePools %s: requirement %s with operator %s must not have values", pool.Name, requirement.Key, requirement.Operator)
+			}
+			if !existence && len(requirement.Values) == 0 {
+				return fmt.Errorf("karpenterNodePools %s: requirement %s with operator %s requires values", pool.Name, requirement.Key, requirement.Operator)
+			}
+		}
+
+		for _, taint := range pool.Taints {
+			if taint.Key == "" || !containsString(taintEffects, taint.Effect) {
+				return fmt.Errorf("karpenterNodePools %s: taint '%s' needs a key and an effect of %s", pool.Name, taint.Key, strings.Join(taintEffects, ", "))
+			}
+		}
+
+		if err := pool.validateLifecycle(); err != nil {
+			return fmt.Errorf("karpenterNodePools %s: %w", pool.Name, err)
+		}
+
+		switch pool.NodeClass.OsType {
+		case "", OsTypeLinux, OsTypeBottlerocket:
+		default:
+			return fmt.Errorf("karpenterNodePools %s: unknown nodeClass osType '%s', expected linux or bottlerocket", pool.Name, pool.NodeClass.OsType)
+		}
+	}
+	return nil
+}
+
+// validateLifecycle 按 Karpenter v1 语义校验资源限制、权重、过期时间、中断配置和终止宽限期
+func (p KarpenterNodePoolConfig) validateLifecycle() error {
+	for resource, quantity := range p.Limits {
+		if !quantityPattern.MatchString(quantity) {
+			return fmt.Errorf("limits.%s '%s' is not a valid quantity, e.g. 1000, 1000Gi or 500m", resource, quantity)
+		}
+		if !containsString(knownLimitResources, resource) {
+			fmt.Printf("Warning: karpenterNodePools %s: limits.%s is not a well-known resource (%s)\n", p.Name, resource, strings.Join(knownLimitResources, ", "))
+		}
+	}
+
+	// weight 为 0 表示未设置，Karpenter 要求 1-100
+	if p.Weight < 0 || p.Weight > 100 {
+		return fmt.Errorf("weight %d must be between 1 and 100", p.Weight)
+	}
+
+	if p.ExpireAfter != "" && p.ExpireAfter != "Never" && !karpenterDurationPattern.MatchString(p.ExpireAfter) {
+		return fmt.Errorf("expireAfter '%s' must be a duration such as 720h or Never", p.ExpireAfter)
+	}
+	if p.TerminationGracePeriod != "" && !karpenterDurationPattern.MatchString(p.TerminationGracePeriod) {
+		return fmt.Errorf("terminationGracePeriod '%s' must be a duration such as 48h", p.TerminationGracePeriod)
+	}
+
+	if p.Disruption == nil {
+		return nil
+	}
+	disruption := p.Disruption
+	if disruption.ConsolidationPolicy != "" && !containsString(consolidationPolicies, disruption.ConsolidationPolicy) {
+		return fmt.Errorf("unknown consolidationPolicy '%s', expected WhenEmptyOrUnderutilized or WhenEmpty", disruption.ConsolidationPolicy)
+	}
+	if disruption.ConsolidateAfter != "" && disruption.ConsolidateAfter != "Never" && !karpenterDurationPattern.MatchString(disruption.ConsolidateAfter) {
+		return fmt.Errorf("consolidateAfter '%s' must be a duration such as 30s or Never", disruption.ConsolidateAfter)
+	}
+	if len(disruption.Budgets) > 50 {
+		return fmt.Errorf("at most 50 disruption budgets are allowed, got %d", len(disruption.Budgets))
+	}
+	for i, budget := range disruption.Budgets {
+		if !budgetNodesPattern.MatchString(budget.Nodes) {
+			return fmt.Errorf("budgets[%d].nodes '%s' must be a node count such as 5 or a percentage such as 10%%", i, budget.Nodes)
+		}
+		if (budget.Schedule == "") != (budget.Duration == "") {
+			return fmt.Errorf("budgets[%d]: schedule and duration must be set together", i)
+		}
+		if budget.Schedule != "" && !validCronSchedule(budget.Schedule) {
+			return fmt.Errorf("budgets[%d].schedule '%s' must be a five-field cron expression or a macro such as @daily", i, budget.Schedule)
+		}
+		if budget.Duration != "" && !budgetDurationPattern.MatchString(budget.Duration) {
+			return fmt.Errorf("budgets[%d].duration '%s' must use hours and minutes, e.g. 8h or 1h30m", i, budget.Duration)
+		}
+		for _, reason := range budget.Reasons {
+			if !containsString(disruptionReasons, reason) {
+				return fmt.Errorf("budgets[%d]: unknown reason '%s', expected %s", i, reason, strings.Join(disruptionReasons, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// validCronSchedule 检查 cron 表达式为五个字段或预定义宏
+func validCronSchedule(schedule string) bool {
+	if strings.HasPrefix(schedule, "@") {
+		return containsString(cronMacros, schedule)
+	}
+	return len(strings.Fields(schedule)) == 5
+}
+
+// constructName 用于生成 CDK 构造 ID
+func (p KarpenterNodePoolConfig) constructName() string {
+	return strings.TrimPrefix(p.Name, "karpenter-")
+}
+
+// normalizeNodePoolType 处理 standard/nvidia 别名，空值为 cpu
+func normalizeNodePoolType(nodePoolType string) string {
+	switch strings.ToLower(strings.TrimSpace(nodePoolType)) {
+	case "", NodePoolTypeCpu, "standard": // 支持 "standard" 作为 "cpu" 的别名
+		return NodePoolTypeCpu
+	case NodePoolTypeGpu, "nvidia": // 支持 "nvidia" 作为 "gpu" 的别名
+		return NodePoolTypeGpu
+	case NodePoolTypeNeuron:
+		return NodePoolTypeNeuron
+	}
+	return strings.ToLower(strings.TrimSpace(nodePoolType))
+}
+
+// 旧格式节点池配置结构
+type NodePoolConfig struct {
+	InstanceTypes       string
+	InstanceFamilies    string
+	InstanceCategories  string
+	InstanceGenerations string
+	CapacityTypes       string
+	Architectures       string
+	DiskSize            int
+	DiskType            string
+	DiskIops            int
+	DiskThroughput      int
+	UseInstanceStore    bool
+	Labels              string
+	Taints              string
+}
+
+// 根据节点类型获取扁平字段配置
+func getNodePoolConfig(eksInstance *EksInstanceConfig, nodeType string) NodePoolConfig {
+	switch nodeType {
+	case NodePoolTypeCpu:
+		return NodePoolConfig{
+			InstanceTypes:       eksInstance.KarpenterCpuInstanceTypes,
+			InstanceFamilies:    eksInstance.KarpenterCpuInstanceFamilies,
+			InstanceCategories:  eksInstance.KarpenterCpuInstanceCategories,
+			InstanceGenerations: eksInstance.KarpenterCpuInstanceGenerations,
+			CapacityTypes:       eksInstance.KarpenterCpuCapacityTypes,
+			Architectures:       eksInstance.KarpenterCpuArchitectures,
+			DiskSize:            eksInstance.KarpenterCpuDiskSize,
+			DiskType:            eksInstance.KarpenterCpuDiskType,
+			DiskIops:            eksInstance.KarpenterCpuDiskIops,
+			DiskThroughput:      eksInstance.KarpenterCpuDiskThroughput,
+			UseInstanceStore:    eksInstance.KarpenterCpuUseInstanceStore,
+			Labels:              eksInstance.KarpenterCpuLabels,
+			Taints:              eksInstance.KarpenterCpuTaints,
+		}
+	case NodePoolTypeGpu:
+		return NodePoolConfig{
+			InstanceTypes:       eksInstance.KarpenterGpuInstanceTypes,
+			InstanceFamilies:    eksInstance.KarpenterGpuInstanceFamilies,
+			InstanceCategories:  eksInstance.KarpenterGpuInstanceCategories,
+			InstanceGenerations: eksInstance.KarpenterGpuInstanceGenerations,
+			CapacityTypes:       eksInstance.KarpenterGpuCapacityTypes,
+			Architectures:       eksInstance.KarpenterGpuArchitectures,
+			DiskSize:            eksInstance.KarpenterGpuDiskSize,
+			DiskType:            eksInstance.KarpenterGpuDiskType,
+			DiskIops:            eksInstance.KarpenterGpuDiskIops,
+			DiskThroughput:      eksInstance.KarpenterGpuDiskThroughput,
+			UseInstanceStore:    eksInstance.KarpenterGpuUseInstanceStore,
+			Labels:              eksInstance.KarpenterGpuLabels,
+			Taints:              eksInstance.KarpenterGpuTaints,
+		}
+	case NodePoolTypeNeuron:
+		return NodePoolConfig{
+			InstanceTypes:       eksInstance.KarpenterNeuronInstanceTypes,
+			InstanceFamilies:    eksInstance.KarpenterNeuronInstanceFamilies,
+			InstanceCategories:  eksInstance.KarpenterNeuronInstanceCategories,
+			InstanceGenerations: eksInstance.KarpenterNeuronInstanceGenerations,
+			CapacityTypes:       eksInstance.KarpenterNeuronCapacityTypes,
+			Architectures:       eksInstance.KarpenterNeuronArchitectures,
+			DiskSize:            eksInstance.KarpenterNeuronDiskSize,
+			DiskType:            eksInstance.KarpenterNeuronDiskType,
+			DiskIops:            eksInstance.KarpenterNeuronDiskIops,
+			DiskThroughput:      eksInstance.KarpenterNeuronDiskThroughput,
+			UseInstanceStore:    eksInstance.KarpenterNeuronUseInstanceStore,
+			Labels:              eksInstance.KarpenterNeuronLabels,
+			Taints:              eksInstance.KarpenterNeuronTaints,
+		}
+	default:
+		return NodePoolConfig{}
+	}
+}
+
+// toNodePool 将扁平字段转换为节点池配置，名称沿用 karpenter-<type>
+func (c NodePoolConfig) toNodePool(nodeType string) KarpenterNodePoolConfig {
+	pool := KarpenterNodePoolConfig{
+		Name:                fmt.Sprintf("karpenter-%s", nodeType),
+		Type:                nodeType,
+		InstanceTypes:       parseCommaSeparatedString(c.InstanceTypes),
+		InstanceFamilies:    parseCommaSeparatedString(c.InstanceFamilies),
+		InstanceCategories:  parseCommaSeparatedString(c.InstanceCategories),
+		InstanceGenerations: parseCommaSeparatedString(c.InstanceGenerations),
+		CapacityTypes:       parseCommaSeparatedString(c.CapacityTypes),
+		Architectures:       parseCommaSeparatedString(c.Architectures),
+		NodeClass: KarpenterNodeClass{
+			DiskSize:         c.DiskSize,
+			DiskType:         c.DiskType,
+			DiskIops:         c.DiskIops,
+			DiskThroughput:   c.DiskThroughput,
+			UseInstanceStore: c.UseInstanceStore,
+		},
+	}
+
+	// 解析标签 (格式: "key1=value1,key2=value2")
+	for _, pair := range parseCommaSeparatedString(c.Labels) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) == 2 {
+			key := strings.TrimSpace(parts[0])
+			value := strings.TrimSpace(parts[1])
+			if key != "" && value != "" {
+				if pool.Labels == nil {
+					pool.Labels = make(map[string]string)
+				}
+				pool.Labels[key] = value
+			}
+		}
+	}
+
+	// 解析污点 (格式: "key1=value1:Effect1,key2=value2:Effect2")
+	for _, pair := range parseCommaSeparatedString(c.Taints) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		valueParts := strings.SplitN(strings.TrimSpace(parts[1]), ":", 2)
+		if len(valueParts) != 2 {
+			continue
+		}
+		taint := KarpenterTaint{
+			Key:    strings.TrimSpace(parts[0]),
+			Value:  strings.TrimSpace(valueParts[0]),
+			Effect: strings.TrimSpace(valueParts[1]),
+		}
+		if taint.Key != "" && taint.Value != "" && taint.Effect != "" {
+			pool.Taints = append(pool.Taints, taint)
+		}
+	}
+
+	return pool
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
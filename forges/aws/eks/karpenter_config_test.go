@@ -0,0 +1,129 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestKarpenterNodePoolListUnmarshal(t *testing.T) {
+	var legacy EksInstanceConfig
+	if err := json.Unmarshal([]byte(`{"karpenterNodePools": "cpu,nvidia", "karpenterCpuLabels": "workload-type=general", "karpenterGpuTaints": "nvidia.com/gpu=true:NoSchedule", "karpenterGpuCapacityTypes": "on-demand"}`), &legacy); err != nil {
+		t.Fatalf("Failed to parse legacy config: %v", err)
+	}
+	pools := legacy.KarpenterNodePools.Resolve(&legacy)
+	if len(pools) != 2 || pools[0].Name != "karpenter-cpu" || pools[1].Name != "karpenter-gpu" {
+		t.Fatalf("Expected karpenter-cpu and karpenter-gpu, got %+v", pools)
+	}
+	if pools[0].Labels["workload-type"] != "general" {
+		t.Errorf("Expected legacy labels to be translated, got %v", pools[0].Labels)
+	}
+	if len(pools[1].Taints) != 1 || pools[1].Taints[0] != (KarpenterTaint{Key: "nvidia.com/gpu", Value: "true", Effect: "NoSchedule"}) {
+		t.Errorf("Expected legacy taints to be translated, got %v", pools[1].Taints)
+	}
+	if !legacy.KarpenterNodePools.HasGpuPool() {
+		t.Error("Expected the nvidia alias to count as a GPU pool")
+	}
+
+	var structured EksInstanceConfig
+	if err := json.Unmarshal([]byte(`{"karpenterNodePools": [{"name": "spot-inference", "type": "gpu", "capacityTypes": ["spot"], "limits": {"nvidia.com/gpu": "16"}, "weight": 10, "terminationGracePeriod": "48h", "disruption": {"consolidateAfter": "Never", "budgets": [{"nodes": "0", "schedule": "0 9 * * mon-fri", "duration": "8h", "reasons": ["Underutilized"]}, {"nodes": "10%"}]}}]}`), &structured); err != nil {
+		t.Fatalf("Failed to parse node pool array: %v", err)
+	}
+	pools = structured.KarpenterNodePools.Resolve(&structured)
+	if len(pools) != 1 || pools[0].Name != "spot-inference" || pools[0].Weight != 10 {
+		t.Fatalf("Unexpected node pools %+v", pools)
+	}
+	if err := structured.Validate(); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+}
+
+func TestKarpenterNodePoolListValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		pools []KarpenterNodePoolConfig
+		want  string
+	}{
+		{"missing name", []KarpenterNodePoolConfig{{}}, "name is required"},
+		{"invalid name", []KarpenterNodePoolConfig{{Name: "Spot_Pool"}}, "lowercase alphanumeric"},
+		{"duplicate of legacy name", []KarpenterNodePoolConfig{{Name: "cpu"}, {Name: "karpenter-cpu"}}, "duplicate name"},
+		{"unknown type", []KarpenterNodePoolConfig{{Name: "a", Type: "tpu"}}, "unknown type"},
+		{"operator", []KarpenterNodePoolConfig{{Name: "a", Requirements: []KarpenterRequirement{{Key: "k", Operator: "in", Values: []string{"v"}}}}}, "unknown operator"},
+		{"exists with values", []KarpenterNodePoolConfig{{Name: "a", Requirements: []KarpenterRequirement{{Key: "k", Operator: "Exists", Values: []string{"v"}}}}}, "must not have values"},
+		{"taint effect", []KarpenterNodePoolConfig{{Name: "a", Taints: []KarpenterTaint{{Key: "k", Effect: "NoScheduled"}}}}, "taint"},
+		{"limit quantity", []KarpenterNodePoolConfig{{Name: "a", Limits: map[string]string{"memory": "1000 GiB"}}}, "not a valid quantity"},
+		{"weight", []KarpenterNodePoolConfig{{Name: "a", Weight: 101}}, "between 1 and 100"},
+		{"expireAfter", []KarpenterNodePoolConfig{{Name: "a", ExpireAfter: "30d"}}, "expireAfter"},
+		{"terminationGracePeriod", []KarpenterNodePoolConfig{{Name: "a", TerminationGracePeriod: "Never"}}, "terminationGracePeriod"},
+		{"budget nodes", []KarpenterNodePoolConfig{{Name: "a", Disruption: &KarpenterDisruption{Budgets: []KarpenterBudget{{Nodes: "150%"}}}}}, "budgets[0].nodes"},
+		{"budget schedule without duration", []KarpenterNodePoolConfig{{Name: "a", Disruption: &KarpenterDisruption{Budgets: []KarpenterBudget{{Nodes: "0", Schedule: "@daily"}}}}}, "set together"},
+		{"budget duration seconds", []KarpenterNodePoolConfig{{Name: "a", Disruption: &KarpenterDisruption{Budgets: []KarpenterBudget{{Nodes: "0", Schedule: "0 9 * * 1-5", Duration: "30s"}}}}}, "hours and minutes"},
+		{"budget reason", []KarpenterNodePoolConfig{{Name: "a", Disruption: &KarpenterDisruption{Budgets: []KarpenterBudget{{Nodes: "1", Reasons: []string{"Expired"}}}}}}, "unknown reason"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := KarpenterNodePoolList{Pools: tt.pools}.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestRenderNodePoolManifest(t *testing.T) {
+	pool := KarpenterNodePoolConfig{
+		Name:          "arm-batch",
+		Architectures: []string{"arm64"},
+		Requirements:  []KarpenterRequirement{{Key: "karpenter.sh/capacity-type", Operator: "In", Values: []string{"spot"}}},
+		Limits:        map[string]string{"cpu": "1000"},
+		Disruption:    &KarpenterDisruption{ConsolidationPolicy: "WhenEmpty", Budgets: []KarpenterBudget{{Nodes: "10%"}}},
+		NodeClass:     KarpenterNodeClass{DiskSize: 200},
+	}
+	rendered, err := renderNodePoolManifest(pool, "demo", "node-role", "1.33", OsTypeLinux)
+	if err != nil {
+		t.Fatalf("Failed to render: %v", err)
+	}
+
+	var nodePool nodePoolManifest
+	var nodeClass ec2NodeClassManifest
+	decoder := yaml.NewDecoder(strings.NewReader(rendered))
+	if err := decoder.Decode(&nodePool); err != nil {
+		t.Fatalf("Failed to decode NodePool: %v", err)
+	}
+	if err := decoder.Decode(&nodeClass); err != nil {
+		t.Fatalf("Failed to decode EC2NodeClass: %v", err)
+	}
+
+	if nodePool.Spec.Template.Spec.NodeClassRef.Name != "arm-batch" || nodeClass.Metadata.Name != "arm-batch" {
+		t.Errorf("Expected NodePool and EC2NodeClass named arm-batch")
+	}
+	capacityTypes := 0
+	for _, requirement := range nodePool.Spec.Template.Spec.Requirements {
+		if requirement.Key == "karpenter.sh/capacity-type" {
+			capacityTypes++
+			if strings.Join(requirement.Values, ",") != "spot" {
+				t.Errorf("Expected custom requirement to replace the default capacity types, got %v", requirement.Values)
+			}
+		}
+	}
+	if capacityTypes != 1 {
+		t.Errorf("Expected one capacity-type requirement, got %d", capacityTypes)
+	}
+	if nodePool.Spec.Disruption.ConsolidationPolicy != "WhenEmpty" || nodePool.Spec.Disruption.ConsolidateAfter != "30s" || len(nodePool.Spec.Disruption.Budgets) != 1 {
+		t.Errorf("Unexpected disruption %+v", nodePool.Spec.Disruption)
+	}
+	if nodePool.Spec.Template.Spec.ExpireAfter != "720h" || nodePool.Spec.Limits["cpu"] != "1000" {
+		t.Errorf("Unexpected expireAfter or limits in %+v", nodePool.Spec)
+	}
+	if len(nodeClass.Spec.BlockDeviceMappings) != 1 || nodeClass.Spec.BlockDeviceMappings[0].Ebs.VolumeSize != "200Gi" || nodeClass.Spec.BlockDeviceMappings[0].Ebs.Iops != 3000 {
+		t.Errorf("Unexpected block device mappings %+v", nodeClass.Spec.BlockDeviceMappings)
+	}
+	if nodeClass.Spec.Tags["karpenter.sh/discovery"] != "demo" || nodeClass.Spec.Tags["nodepool-type"] != "cpu" {
+		t.Errorf("Unexpected tags %v", nodeClass.Spec.Tags)
+	}
+}
@@ -331,14 +331,22 @@ func createKarpenterIamResources(scope constructs.Construct, id string, cluster
 		},
 	})
 
-	// 添加 Karpenter 节点角色到 aws-auth ConfigMap
-	cluster.AwsAuth().AddRoleMapping(nodeRole, &awseks.AwsAuthMapping{
-		Groups: &[]*string{
-			jsii.String("system:bootstrappers"),
-			jsii.String("system:nodes"),
-		},
-		Username: jsii.String("system:node:{{EC2PrivateDNSName}}"),
-	})
+	// 添加 Karpenter 节点角色到 aws-auth ConfigMap，API 模式下使用 EC2_LINUX 访问条目
+	if cluster.AuthenticationMode() == awseks.AuthenticationMode_API {
+		awseks.NewCfnAccessEntry(scope, jsii.String("KarpenterNodeAccessEntry"), &awseks.CfnAccessEntryProps{
+			ClusterName:  cluster.ClusterName(),
+			PrincipalArn: nodeRole.RoleArn(),
+			Type:         jsii.String("EC2_LINUX"),
+		})
+	} else {
+		cluster.AwsAuth().AddRoleMapping(nodeRole, &awseks.AwsAuthMapping{
+			Groups: &[]*string{
+				jsii.String("system:bootstrappers"),
+				jsii.String("system:nodes"),
+			},
+			Username: jsii.String("system:node:{{EC2PrivateDNSName}}"),
+		})
+	}
 
 	return &KarpenterIamResources{
 		NodeRole:        nodeRole,
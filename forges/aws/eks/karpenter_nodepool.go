@@ -14,76 +14,14 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// KarpenterNodePoolProps - 扁平化配置
+// KarpenterNodePoolProps 节点池创建参数，NodePools 由 karpenterNodePools 解析得到
 type KarpenterNodePoolProps struct {
 	Cluster           awseks.Cluster
 	ClusterName       string
 	IamResources      *KarpenterIamResources
 	KubernetesVersion string
 	KarpenterOsType   string
-	NodePoolTypes     string // "cpu,gpu,neuron" - 决定启用哪些节点池
-	
-	// CPU 配置
-	CpuInstanceTypes       string
-	CpuInstanceFamilies    string
-	CpuInstanceCategories  string
-	CpuInstanceGenerations string
-	CpuCapacityTypes       string
-	CpuArchitectures       string
-	CpuDiskSize            int
-	CpuDiskType            string
-	CpuDiskIops            int
-	CpuDiskThroughput      int
-	CpuUseInstanceStore    bool
-	CpuLabels              string
-	CpuTaints              string
-	
-	// GPU 配置
-	GpuInstanceTypes       string
-	GpuInstanceFamilies    string
-	GpuInstanceCategories  string
-	GpuInstanceGenerations string
-	GpuCapacityTypes       string
-	GpuArchitectures       string
-	GpuDiskSize            int
-	GpuDiskType            string
-	GpuDiskIops            int
-	GpuDiskThroughput      int
-	GpuUseInstanceStore    bool
-	GpuLabels              string
-	GpuTaints              string
-	
-	// Neuron 配置
-	NeuronInstanceTypes       string
-	NeuronInstanceFamilies    string
-	NeuronInstanceCategories  string
-	NeuronInstanceGenerations string
-	NeuronCapacityTypes       string
-	NeuronArchitectures       string
-	NeuronDiskSize            int
-	NeuronDiskType            string
-	NeuronDiskIops            int
-	NeuronDiskThroughput      int
-	NeuronUseInstanceStore    bool
-	NeuronLabels              string
-	NeuronTaints              string
-}
-
-// 节点池配置结构
-type NodePoolConfig struct {
-	InstanceTypes       string
-	InstanceFamilies    string
-	InstanceCategories  string
-	InstanceGenerations string
-	CapacityTypes       string
-	Architectures       string
-	DiskSize            int
-	DiskType            string
-	DiskIops            int
-	DiskThroughput      int
-	UseInstanceStore    bool
-	Labels              string
-	Taints              string
+	NodePools         []KarpenterNodePoolConfig
 }
 
 // 定义节点池类型常量
@@ -95,405 +33,300 @@ const (
 
 // 定义操作系统类型常量
 const (
-	OsTypeLinux       = "linux"
+	OsTypeLinux        = "linux"
 	OsTypeBottlerocket = "bottlerocket"
 )
 
+// Karpenter v1 清单结构，通过 yaml.v3 序列化
+type manifestMetadata struct {
+	Name   string            `yaml:"name,omitempty"`
+	Labels map[string]string `yaml:"labels,omitempty"`
+}
+
+type nodePoolManifest struct {
+	ApiVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   manifestMetadata `yaml:"metadata"`
+	Spec       nodePoolSpec     `yaml:"spec"`
+}
+
+type nodePoolSpec struct {
+	Template   nodeClaimTemplate  `yaml:"template"`
+	Disruption nodePoolDisruption `yaml:"disruption"`
+	Limits     map[string]string  `yaml:"limits,omitempty"`
+	Weight     int                `yaml:"weight,omitempty"`
+}
+
+type nodeClaimTemplate struct {
+	Metadata manifestMetadata `yaml:"metadata"`
+	Spec     nodeClaimSpec    `yaml:"spec"`
+}
+
+type nodeClaimSpec struct {
+	Requirements           []KarpenterRequirement `yaml:"requirements"`
+	Taints                 []KarpenterTaint       `yaml:"taints,omitempty"`
+	NodeClassRef           nodeClassRef           `yaml:"nodeClassRef"`
+	ExpireAfter            string                 `yaml:"expireAfter"`
+	TerminationGracePeriod string                 `yaml:"terminationGracePeriod,omitempty"`
+}
+
+type nodeClassRef struct {
+	Group string `yaml:"group"`
+	Kind  string `yaml:"kind"`
+	Name  string `yaml:"name"`
+}
+
+type nodePoolDisruption struct {
+	ConsolidationPolicy string            `yaml:"consolidationPolicy"`
+	ConsolidateAfter    string            `yaml:"consolidateAfter"`
+	Budgets             []KarpenterBudget `yaml:"budgets,omitempty"`
+}
+
+type ec2NodeClassManifest struct {
+	ApiVersion string           `yaml:"apiVersion"`
+	Kind       string           `yaml:"kind"`
+	Metadata   manifestMetadata `yaml:"metadata"`
+	Spec       ec2NodeClassSpec `yaml:"spec"`
+}
+
+type ec2NodeClassSpec struct {
+	InstanceProfile            string               `yaml:"instanceProfile"`
+	AmiFamily                  string               `yaml:"amiFamily"`
+	AssociatePublicIPAddress   bool                 `yaml:"associatePublicIPAddress"`
+	InstanceStorePolicy        string               `yaml:"instanceStorePolicy,omitempty"`
+	AmiSelectorTerms           []selectorTerm       `yaml:"amiSelectorTerms"`
+	BlockDeviceMappings        []blockDeviceMapping `yaml:"blockDeviceMappings,omitempty"`
+	SubnetSelectorTerms        []selectorTerm       `yaml:"subnetSelectorTerms"`
+	SecurityGroupSelectorTerms []selectorTerm       `yaml:"securityGroupSelectorTerms"`
+	Tags                       map[string]string    `yaml:"tags"`
+}
+
+type selectorTerm struct {
+	SsmParameter string            `yaml:"ssmParameter,omitempty"`
+	Tags         map[string]string `yaml:"tags,omitempty"`
+}
+
+type blockDeviceMapping struct {
+	DeviceName string    `yaml:"deviceName"`
+	Ebs        ebsVolume `yaml:"ebs"`
+}
+
+type ebsVolume struct {
+	VolumeSize          string `yaml:"volumeSize"`
+	VolumeType          string `yaml:"volumeType"`
+	DeleteOnTermination bool   `yaml:"deleteOnTermination"`
+	Iops                int    `yaml:"iops,omitempty"`
+	Throughput          int    `yaml:"throughput,omitempty"`
+}
+
 // 创建 Karpenter 节点池
 func createKarpenterNodePool(scope constructs.Construct, id string, props *KarpenterNodePoolProps) []awseks.KubernetesManifest {
 	var kubeManifests []awseks.KubernetesManifest
-	
+
 	// 使用提供的 Kubernetes 版本，如果未提供则默认为 "1.34"
 	kubeVersion := props.KubernetesVersion
 	if kubeVersion == "" {
 		kubeVersion = "1.34"
 	}
-	
-	// 解析 NodePoolTypes 参数，决定启用哪些节点池
-	enabledNodePools := parseCommaSeparatedString(props.NodePoolTypes)
-	
-	for _, nodePoolType := range enabledNodePools {
-		nodePoolType = strings.ToLower(strings.TrimSpace(nodePoolType))
-		
-		switch nodePoolType {
-		case NodePoolTypeCpu, "standard": // 支持 "standard" 作为 "cpu" 的别名
-			config := getNodePoolConfig(props, NodePoolTypeCpu)
-			kubeManifest := createTypedNodePool(scope, fmt.Sprintf("%s-cpu", id), props, config, kubeVersion, NodePoolTypeCpu)
-			kubeManifests = append(kubeManifests, kubeManifest)
-			
-		case NodePoolTypeGpu, "nvidia": // 支持 "nvidia" 作为 "gpu" 的别名
-			config := getNodePoolConfig(props, NodePoolTypeGpu)
-			kubeManifest := createTypedNodePool(scope, fmt.Sprintf("%s-gpu", id), props, config, kubeVersion, NodePoolTypeGpu)
-			kubeManifests = append(kubeManifests, kubeManifest)
-			
-		case NodePoolTypeNeuron:
-			config := getNodePoolConfig(props, NodePoolTypeNeuron)
-			kubeManifest := createTypedNodePool(scope, fmt.Sprintf("%s-neuron", id), props, config, kubeVersion, NodePoolTypeNeuron)
-			kubeManifests = append(kubeManifests, kubeManifest)
-			
-		default:
-			fmt.Printf("Warning: Unknown node pool type '%s', skipping\n", nodePoolType)
+	roleName := *props.IamResources.NodeRole.RoleName()
+
+	for _, pool := range props.NodePools {
+		nodePoolManifest, err := renderNodePoolManifest(pool, props.ClusterName, roleName, kubeVersion, props.KarpenterOsType)
+		if err != nil {
+			panic(fmt.Errorf("failed to render Karpenter node pool %s: %w", pool.Name, err))
 		}
+		// 构造 ID 沿用 KarpenterNodePool-cpu 等旧格式，避免已部署的节点池被替换
+		kubeManifest := createKubernetesManifest(scope, fmt.Sprintf("%s-%s", id, pool.constructName()), props.Cluster, props.IamResources, nodePoolManifest, pool.Name)
+		kubeManifests = append(kubeManifests, kubeManifest)
 	}
-	
+
 	return kubeManifests
 }
 
-// 根据节点类型获取配置
-func getNodePoolConfig(props *KarpenterNodePoolProps, nodeType string) NodePoolConfig {
-	switch nodeType {
-	case NodePoolTypeCpu:
-		return NodePoolConfig{
-			InstanceTypes:       props.CpuInstanceTypes,
-			InstanceFamilies:    props.CpuInstanceFamilies,
-			InstanceCategories:  props.CpuInstanceCategories,
-			InstanceGenerations: props.CpuInstanceGenerations,
-			CapacityTypes:       props.CpuCapacityTypes,
-			Architectures:       props.CpuArchitectures,
-			DiskSize:            props.CpuDiskSize,
-			DiskType:            props.CpuDiskType,
-			DiskIops:            props.CpuDiskIops,
-			DiskThroughput:      props.CpuDiskThroughput,
-			UseInstanceStore:    props.CpuUseInstanceStore,
-			Labels:              props.CpuLabels,
-			Taints:              props.CpuTaints,
+// renderNodePoolManifest 生成 NodePool 和同名 EC2NodeClass 的 YAML
+func renderNodePoolManifest(pool KarpenterNodePoolConfig, clusterName string, instanceProfile string, kubeVersion string, defaultOsType string) (string, error) {
+	nodeType := normalizeNodePoolType(pool.Type)
+	osType := pool.NodeClass.OsType
+	if osType == "" {
+		osType = defaultOsType
+	}
+
+	// 默认标签，用户标签可覆盖
+	labels := map[string]string{"nodepool-type": nodeType}
+	for k, v := range pool.Labels {
+		labels[k] = v
+	}
+
+	expireAfter := pool.ExpireAfter
+	if expireAfter == "" {
+		expireAfter = "720h"
+	}
+	disruption := nodePoolDisruption{
+		ConsolidationPolicy: "WhenEmptyOrUnderutilized",
+		ConsolidateAfter:    "30s",
+	}
+	if pool.Disruption != nil {
+		if pool.Disruption.ConsolidationPolicy != "" {
+			disruption.ConsolidationPolicy = pool.Disruption.ConsolidationPolicy
 		}
-	case NodePoolTypeGpu:
-		return NodePoolConfig{
-			InstanceTypes:       props.GpuInstanceTypes,
-			InstanceFamilies:    props.GpuInstanceFamilies,
-			InstanceCategories:  props.GpuInstanceCategories,
-			InstanceGenerations: props.GpuInstanceGenerations,
-			CapacityTypes:       props.GpuCapacityTypes,
-			Architectures:       props.GpuArchitectures,
-			DiskSize:            props.GpuDiskSize,
-			DiskType:            props.GpuDiskType,
-			DiskIops:            props.GpuDiskIops,
-			DiskThroughput:      props.GpuDiskThroughput,
-			UseInstanceStore:    props.GpuUseInstanceStore,
-			Labels:              props.GpuLabels,
-			Taints:              props.GpuTaints,
+		if pool.Disruption.ConsolidateAfter != "" {
+			disruption.ConsolidateAfter = pool.Disruption.ConsolidateAfter
 		}
-	case NodePoolTypeNeuron:
-		return NodePoolConfig{
-			InstanceTypes:       props.NeuronInstanceTypes,
-			InstanceFamilies:    props.NeuronInstanceFamilies,
-			InstanceCategories:  props.NeuronInstanceCategories,
-			InstanceGenerations: props.NeuronInstanceGenerations,
-			CapacityTypes:       props.NeuronCapacityTypes,
-			Architectures:       props.NeuronArchitectures,
-			DiskSize:            props.NeuronDiskSize,
-			DiskType:            props.NeuronDiskType,
-			DiskIops:            props.NeuronDiskIops,
-			DiskThroughput:      props.NeuronDiskThroughput,
-			UseInstanceStore:    props.NeuronUseInstanceStore,
-			Labels:              props.NeuronLabels,
-			Taints:              props.NeuronTaints,
+		disruption.Budgets = pool.Disruption.Budgets
+	}
+
+	nodePool := nodePoolManifest{
+		ApiVersion: "karpenter.sh/v1",
+		Kind:       "NodePool",
+		Metadata:   manifestMetadata{Name: pool.Name},
+		Spec: nodePoolSpec{
+			Template: nodeClaimTemplate{
+				Metadata: manifestMetadata{Labels: labels},
+				Spec: nodeClaimSpec{
+					Requirements: buildInstanceRequirements(pool),
+					Taints:       pool.Taints,
+					NodeClassRef: nodeClassRef{
+						Group: "karpenter.k8s.aws",
+						Kind:  "EC2NodeClass",
+						Name:  pool.Name,
+					},
+					ExpireAfter:            expireAfter,
+					TerminationGracePeriod: pool.TerminationGracePeriod,
+				},
+			},
+			Disruption: disruption,
+			Limits:     pool.Limits,
+			Weight:     pool.Weight,
+		},
+	}
+
+	// 用户标签在前，发现标签和节点池标签不可覆盖
+	tags := make(map[string]string)
+	for k, v := range pool.NodeClass.Tags {
+		tags[k] = v
+	}
+	tags["karpenter.sh/discovery"] = clusterName
+	tags["nodepool-type"] = nodeType
+	tags["kubernetes-version"] = kubeVersion
+
+	discovery := []selectorTerm{{Tags: map[string]string{"karpenter.sh/discovery": clusterName}}}
+	nodeClass := ec2NodeClassManifest{
+		ApiVersion: "karpenter.k8s.aws/v1",
+		Kind:       "EC2NodeClass",
+		Metadata:   manifestMetadata{Name: pool.Name},
+		Spec: ec2NodeClassSpec{
+			InstanceProfile:            instanceProfile,
+			AmiFamily:                  getAmiFamily(osType),
+			AssociatePublicIPAddress:   false,
+			AmiSelectorTerms:           buildAmiSelectorTerms(nodeType, kubeVersion, osType),
+			BlockDeviceMappings:        buildBlockDeviceMappings(pool.NodeClass),
+			SubnetSelectorTerms:        discovery,
+			SecurityGroupSelectorTerms: discovery,
+			Tags:                       tags,
+		},
+	}
+	if pool.NodeClass.UseInstanceStore {
+		nodeClass.Spec.InstanceStorePolicy = "RAID0"
+	}
+
+	var documents []string
+	for _, manifest := range []interface{}{nodePool, nodeClass} {
+		document, err := yaml.Marshal(manifest)
+		if err != nil {
+			return "", err
 		}
-	default:
-		return NodePoolConfig{}
+		documents = append(documents, string(document))
 	}
-}
-// 统一的节点池创建函数
-func createTypedNodePool(scope constructs.Construct, id string, props *KarpenterNodePoolProps, config NodePoolConfig, kubeVersion string, nodeType string) awseks.KubernetesManifest {
-	resourceName := fmt.Sprintf("karpenter-%s", nodeType)
-	roleName := *props.IamResources.NodeRole.RoleName()
-	
-	// 构建实例要求
-	instanceRequirements := buildInstanceRequirements(config)
-	
-	// 构建标签和污点
-	nodeLabels := buildNodeLabels(config, nodeType)
-	nodeTaints := buildNodeTaints(config)
-	
-	// 构建 AMI 选择器
-	amiSelectorTerms := buildAmiSelectorTerms(nodeType, kubeVersion, props.KarpenterOsType)
-	
-	// 构建磁盘配置
-	blockDeviceMappings := buildBlockDeviceMappings(config)
-	
-	// 构建 Instance Store 配置
-	instanceStorePolicy := buildInstanceStorePolicy(config)
-	
-	// 设置资源限制 - 移除硬编码限制，允许无限制扩展
-	// cpuLimit := 1000
-	// memoryLimit := "1000Gi"
-	// gpuLimit := ""
-	
-	// 为 GPU 节点池添加 GPU 限制
-	// if nodeType == NodePoolTypeGpu {
-	//	gpuLimit = `
-    // nvidia.com/gpu: 8`
-	// }
-	
-	// Launch Template 配置已移除（Karpenter v1 不支持）
-	launchTemplateConfig := ""
-
-	// 创建 NodePool 和 EC2NodeClass 的 Kubernetes 清单
-	nodePoolManifest := fmt.Sprintf(`
-apiVersion: karpenter.sh/v1
-kind: NodePool
-metadata:
-  name: %s
-spec:
-  template:
-    metadata:
-      labels:%s
-    spec:
-      requirements:%s%s
-      nodeClassRef:
-        group: karpenter.k8s.aws
-        kind: EC2NodeClass
-        name: %s
-      expireAfter: 720h
-  disruption:
-    consolidationPolicy: WhenEmptyOrUnderutilized
-    consolidateAfter: 30s
----
-apiVersion: karpenter.k8s.aws/v1
-kind: EC2NodeClass
-metadata:
-  name: %s
-spec:
-  instanceProfile: %s
-  amiFamily: %s
-  associatePublicIPAddress: false%s%s
-  amiSelectorTerms:%s%s
-  subnetSelectorTerms:
-  - tags:
-      karpenter.sh/discovery: %s
-  securityGroupSelectorTerms:
-  - tags:
-      karpenter.sh/discovery: %s
-  tags:
-    karpenter.sh/discovery: %s
-    nodepool-type: "%s"
-    kubernetes-version: "%s"
-`,
-		resourceName,
-		nodeLabels,
-		instanceRequirements,
-		nodeTaints,
-		resourceName,
-		resourceName,
-		roleName,
-		getAmiFamily(props.KarpenterOsType),
-		launchTemplateConfig,
-		instanceStorePolicy,
-		amiSelectorTerms,
-		blockDeviceMappings,
-		props.ClusterName,
-		props.ClusterName,
-		props.ClusterName,
-		nodeType,
-		kubeVersion)
-	
-	return createKubernetesManifest(scope, id, props.Cluster, props.IamResources, nodePoolManifest, resourceName)
+	return strings.Join(documents, "---\n"), nil
 }
 
-// 构建实例要求
-func buildInstanceRequirements(config NodePoolConfig) string {
-	var requirements []string
-	
+// 构建实例要求，简写字段在前，requirements 中相同 key 的条目覆盖简写
+func buildInstanceRequirements(pool KarpenterNodePoolConfig) []KarpenterRequirement {
+	in := func(key string, values []string) KarpenterRequirement {
+		return KarpenterRequirement{Key: key, Operator: "In", Values: values}
+	}
+
+	var requirements []KarpenterRequirement
+
 	// 具体实例类型要求 (最高优先级)
-	if config.InstanceTypes != "" {
-		instanceTypes := parseCommaSeparatedString(config.InstanceTypes)
-		if len(instanceTypes) > 0 {
-			values := fmt.Sprintf(`["%s"]`, strings.Join(instanceTypes, `", "`))
-			requirements = append(requirements, fmt.Sprintf(`
-      - key: node.kubernetes.io/instance-type
-        operator: In
-        values: %s`, values))
-		}
+	if len(pool.InstanceTypes) > 0 {
+		requirements = append(requirements, in("node.kubernetes.io/instance-type", pool.InstanceTypes))
 	}
-	
+
 	// 实例家族要求
-	if config.InstanceFamilies != "" {
-		instanceFamilies := parseCommaSeparatedString(config.InstanceFamilies)
-		if len(instanceFamilies) > 0 {
-			values := fmt.Sprintf(`["%s"]`, strings.Join(instanceFamilies, `", "`))
-			requirements = append(requirements, fmt.Sprintf(`
-      - key: karpenter.k8s.aws/instance-family
-        operator: In
-        values: %s`, values))
-		}
+	if len(pool.InstanceFamilies) > 0 {
+		requirements = append(requirements, in("karpenter.k8s.aws/instance-family", pool.InstanceFamilies))
 	}
-	
+
 	// 实例类别要求
-	if config.InstanceCategories != "" {
-		instanceCategories := parseCommaSeparatedString(config.InstanceCategories)
-		if len(instanceCategories) > 0 {
-			values := fmt.Sprintf(`["%s"]`, strings.Join(instanceCategories, `", "`))
-			requirements = append(requirements, fmt.Sprintf(`
-      - key: karpenter.k8s.aws/instance-category
-        operator: In
-        values: %s`, values))
-		}
+	if len(pool.InstanceCategories) > 0 {
+		requirements = append(requirements, in("karpenter.k8s.aws/instance-category", pool.InstanceCategories))
 	}
-	
-	// 实例代数要求
-	if config.InstanceGenerations != "" {
-		instanceGenerations := parseCommaSeparatedString(config.InstanceGenerations)
-		if len(instanceGenerations) > 0 {
-			values := fmt.Sprintf(`["%s"]`, strings.Join(instanceGenerations, `", "`))
-			requirements = append(requirements, fmt.Sprintf(`
-      - key: karpenter.k8s.aws/instance-generation
-        operator: In
-        values: %s`, values))
-		}
+
+	// 实例代数要求，默认使用较新的代数
+	if len(pool.InstanceGenerations) > 0 {
+		requirements = append(requirements, in("karpenter.k8s.aws/instance-generation", pool.InstanceGenerations))
 	} else {
-		// 默认使用较新的代数
-		requirements = append(requirements, `
-      - key: karpenter.k8s.aws/instance-generation
-        operator: Gt
-        values: ["2"]`)
+		requirements = append(requirements, KarpenterRequirement{Key: "karpenter.k8s.aws/instance-generation", Operator: "Gt", Values: []string{"2"}})
 	}
-	
-	// 容量类型要求
-	if config.CapacityTypes != "" {
-		capacityTypes := parseCommaSeparatedString(config.CapacityTypes)
-		if len(capacityTypes) > 0 {
-			values := fmt.Sprintf(`["%s"]`, strings.Join(capacityTypes, `", "`))
-			requirements = append(requirements, fmt.Sprintf(`
-      - key: karpenter.sh/capacity-type
-        operator: In
-        values: %s`, values))
-		}
+
+	// 容量类型要求，默认支持 spot 和 on-demand
+	if len(pool.CapacityTypes) > 0 {
+		requirements = append(requirements, in("karpenter.sh/capacity-type", pool.CapacityTypes))
 	} else {
-		// 默认支持 spot 和 on-demand
-		requirements = append(requirements, `
-      - key: karpenter.sh/capacity-type
-        operator: In
-        values: ["spot", "on-demand"]`)
+		requirements = append(requirements, in("karpenter.sh/capacity-type", []string{"spot", "on-demand"}))
 	}
-	
-	// 架构要求
-	if config.Architectures != "" {
-		architectures := parseCommaSeparatedString(config.Architectures)
-		if len(architectures) > 0 {
-			values := fmt.Sprintf(`["%s"]`, strings.Join(architectures, `", "`))
-			requirements = append(requirements, fmt.Sprintf(`
-      - key: kubernetes.io/arch
-        operator: In
-        values: %s`, values))
-		}
+
+	// 架构要求，默认支持 amd64 和 arm64
+	if len(pool.Architectures) > 0 {
+		requirements = append(requirements, in("kubernetes.io/arch", pool.Architectures))
 	} else {
-		// 默认支持 amd64 和 arm64
-		requirements = append(requirements, `
-      - key: kubernetes.io/arch
-        operator: In
-        values: ["amd64", "arm64"]`)
+		requirements = append(requirements, in("kubernetes.io/arch", []string{"amd64", "arm64"}))
 	}
-	
+
 	// 操作系统要求
-	requirements = append(requirements, `
-      - key: kubernetes.io/os
-        operator: In
-        values: ["linux"]`)
-	
-	return strings.Join(requirements, "")
-}
+	requirements = append(requirements, in("kubernetes.io/os", []string{"linux"}))
 
-// 构建节点标签
-func buildNodeLabels(config NodePoolConfig, nodeType string) string {
-	labels := make(map[string]string)
-	
-	// 添加默认标签
-	labels["nodepool-type"] = nodeType
-	
-	// 解析用户自定义标签 (格式: "key1=value1,key2=value2")
-	if config.Labels != "" {
-		labelPairs := parseCommaSeparatedString(config.Labels)
-		for _, pair := range labelPairs {
-			if strings.Contains(pair, "=") {
-				parts := strings.SplitN(pair, "=", 2)
-				if len(parts) == 2 {
-					key := strings.TrimSpace(parts[0])
-					value := strings.TrimSpace(parts[1])
-					if key != "" && value != "" {
-						labels[key] = value
-					}
-				}
+	// 原生 requirements
+	for _, custom := range pool.Requirements {
+		replaced := false
+		for i := range requirements {
+			if requirements[i].Key == custom.Key {
+				requirements[i] = custom
+				replaced = true
+				break
 			}
 		}
-	}
-	
-	var labelStrings []string
-	for k, v := range labels {
-		labelStrings = append(labelStrings, fmt.Sprintf(`
-        %s: "%s"`, k, v))
-	}
-	
-	if len(labelStrings) == 0 {
-		return ""
-	}
-	
-	return strings.Join(labelStrings, "")
-}
-
-// 构建节点污点
-func buildNodeTaints(config NodePoolConfig) string {
-	if config.Taints == "" {
-		return ""
-	}
-	
-	// 解析污点 (格式: "key1=value1:Effect1,key2=value2:Effect2")
-	taintPairs := parseCommaSeparatedString(config.Taints)
-	var taintStrings []string
-	
-	for _, pair := range taintPairs {
-		if strings.Contains(pair, "=") && strings.Contains(pair, ":") {
-			// 分割 key=value:effect
-			parts := strings.SplitN(pair, "=", 2)
-			if len(parts) == 2 {
-				key := strings.TrimSpace(parts[0])
-				valueEffect := strings.TrimSpace(parts[1])
-				
-				valueParts := strings.SplitN(valueEffect, ":", 2)
-				if len(valueParts) == 2 {
-					value := strings.TrimSpace(valueParts[0])
-					effect := strings.TrimSpace(valueParts[1])
-					
-					if key != "" && value != "" && effect != "" {
-						taintStrings = append(taintStrings, fmt.Sprintf(`
-      - key: %s
-        value: "%s"
-        effect: %s`, key, value, effect))
-					}
-				}
-			}
+		if !replaced {
+			requirements = append(requirements, custom)
 		}
 	}
-	
-	if len(taintStrings) == 0 {
-		return ""
-	}
-	
-	return fmt.Sprintf(`
-      taints:%s`, strings.Join(taintStrings, ""))
+
+	return requirements
 }
+
 // 构建 AMI 选择器
-func buildAmiSelectorTerms(nodeType string, kubeVersion string, osType string) string {
+func buildAmiSelectorTerms(nodeType string, kubeVersion string, osType string) []selectorTerm {
 	amiFamily := getAmiFamily(osType)
-	
-	var amiSelectorTerms []string
-	
+
+	var ssmParameters []string
+
 	switch amiFamily {
 	case "Bottlerocket":
 		switch nodeType {
 		case NodePoolTypeGpu:
-			amiSelectorTerms = []string{
+			ssmParameters = []string{
 				fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s-nvidia/x86_64/latest/image_id", kubeVersion),
 				fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s-nvidia/arm64/latest/image_id", kubeVersion),
 			}
 		case NodePoolTypeNeuron:
 			// Bottlerocket 可能不支持 Neuron，使用标准版本
 			fmt.Printf("Warning: Bottlerocket may not support Neuron, using standard AMI\n")
-			amiSelectorTerms = []string{
+			ssmParameters = []string{
 				fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/x86_64/latest/image_id", kubeVersion),
 			}
 		default: // CPU
-			amiSelectorTerms = []string{
+			ssmParameters = []string{
 				fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/x86_64/latest/image_id", kubeVersion),
 				fmt.Sprintf("/aws/service/bottlerocket/aws-k8s-%s/arm64/latest/image_id", kubeVersion),
 			}
@@ -501,85 +334,61 @@ func buildAmiSelectorTerms(nodeType string, kubeVersion string, osType string) s
 	case "AL2023":
 		switch nodeType {
 		case NodePoolTypeGpu:
-			amiSelectorTerms = []string{
+			ssmParameters = []string{
 				fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/nvidia/recommended/image_id", kubeVersion),
 				fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/arm64/nvidia/recommended/image_id", kubeVersion),
 			}
 		case NodePoolTypeNeuron:
-			amiSelectorTerms = []string{
+			ssmParameters = []string{
 				fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/neuron/recommended/image_id", kubeVersion),
 			}
 		default: // CPU
-			amiSelectorTerms = []string{
+			ssmParameters = []string{
 				fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/standard/recommended/image_id", kubeVersion),
 				fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/arm64/standard/recommended/image_id", kubeVersion),
 			}
 		}
-	default:
-		// 默认使用 AL2023 标准版本
-		amiSelectorTerms = []string{
-			fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/x86_64/standard/recommended/image_id", kubeVersion),
-			fmt.Sprintf("/aws/service/eks/optimized-ami/%s/amazon-linux-2023/arm64/standard/recommended/image_id", kubeVersion),
-		}
 	}
-	
-	var amiSelectorTermsYaml string
-	for _, ssmParam := range amiSelectorTerms {
-		amiSelectorTermsYaml += fmt.Sprintf(`
-    - ssmParameter: %s`, ssmParam)
+
+	var terms []selectorTerm
+	for _, ssmParameter := range ssmParameters {
+		terms = append(terms, selectorTerm{SsmParameter: ssmParameter})
 	}
-	
-	return amiSelectorTermsYaml
+	return terms
 }
 
 // 构建块设备映射
-func buildBlockDeviceMappings(config NodePoolConfig) string {
-	if config.DiskSize <= 0 {
-		return ""
+func buildBlockDeviceMappings(nodeClass KarpenterNodeClass) []blockDeviceMapping {
+	if nodeClass.DiskSize <= 0 {
+		return nil
 	}
-	
+
 	// 默认值
 	volumeType := "gp3"
-	if config.DiskType != "" {
-		volumeType = config.DiskType
+	if nodeClass.DiskType != "" {
+		volumeType = nodeClass.DiskType
 	}
-	
-	// 构建基本的 EBS 配置
-	ebsConfig := fmt.Sprintf(`
-        volumeSize: %dGi
-        volumeType: %s
-        deleteOnTermination: true`, config.DiskSize, volumeType)
-	
-	// 添加 IOPS 配置（仅对支持的卷类型）
-	iops := config.DiskIops
-	if iops == 0 && (volumeType == "gp3" || volumeType == "io1" || volumeType == "io2") {
-		// 默认 IOPS 设置为 3000
-		iops = 3000
+
+	ebs := ebsVolume{
+		VolumeSize:          fmt.Sprintf("%dGi", nodeClass.DiskSize),
+		VolumeType:          volumeType,
+		DeleteOnTermination: true,
 	}
-	if iops > 0 && (volumeType == "gp3" || volumeType == "io1" || volumeType == "io2") {
-		ebsConfig += fmt.Sprintf(`
-        iops: %d`, iops)
+
+	// 添加 IOPS 配置（仅对支持的卷类型），默认 3000
+	if volumeType == "gp3" || volumeType == "io1" || volumeType == "io2" {
+		ebs.Iops = nodeClass.DiskIops
+		if ebs.Iops == 0 {
+			ebs.Iops = 3000
+		}
 	}
-	
+
 	// 添加吞吐量配置（仅对 gp3 卷类型）
-	if config.DiskThroughput > 0 && volumeType == "gp3" {
-		ebsConfig += fmt.Sprintf(`
-        throughput: %d`, config.DiskThroughput)
+	if nodeClass.DiskThroughput > 0 && volumeType == "gp3" {
+		ebs.Throughput = nodeClass.DiskThroughput
 	}
-	
-	return fmt.Sprintf(`
-  blockDeviceMappings:
-    - deviceName: /dev/xvda
-      ebs:%s`, ebsConfig)
-}
 
-// 构建 Instance Store 策略配置
-func buildInstanceStorePolicy(config NodePoolConfig) string {
-	if config.UseInstanceStore {
-		return `
-  instanceStorePolicy: RAID0`
-	}
-	return ""
+	return []blockDeviceMapping{{DeviceName: "/dev/xvda", Ebs: ebs}}
 }
 
 // 获取 AMI 家族
@@ -0,0 +1,372 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/awslabs/InfraForge/core/dependency"
+	"github.com/awslabs/InfraForge/core/interfaces"
+	"github.com/awslabs/InfraForge/core/utils/aws"
+	"github.com/awslabs/InfraForge/forges/aws/eks/utils"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
+	"github.com/aws/jsii-runtime-go"
+)
+
+// 托管节点组容量类型
+const (
+	NodeGroupCapacityOnDemand      = "onDemand"
+	NodeGroupCapacitySpot          = "spot"
+	NodeGroupCapacityCapacityBlock = "capacityBlock"
+)
+
+// EksNodeGroupConfig 额外的托管节点组，用于不适合 Karpenter 的工作负载
+type EksNodeGroupConfig struct {
+	Name          string            `json:"name"`
+	InstanceTypes []string          `json:"instanceTypes"`
+	CapacityType  string            `json:"capacityType,omitempty"` // onDemand（默认）、spot、capacityBlock
+	AmiType       string            `json:"amiType,omitempty"`      // 如 AL2023_x86_64_NVIDIA，默认按 osType、osArch、gpuType 选择
+	OsType        string            `json:"osType,omitempty"`       // 默认使用集群的 osType
+	OsArch        string            `json:"osArch,omitempty"`       // 默认使用集群的 osArch
+	GpuType       string            `json:"gpuType,omitempty"`      // 默认使用集群的 gpuType
+	MinSize       int               `json:"minSize,omitempty"`
+	MaxSize       int               `json:"maxSize,omitempty"`
+	DesiredSize   int               `json:"desiredSize,omitempty"` // 默认等于 minSize
+	DiskSize      int               `json:"diskSize,omitempty"`    // 默认使用集群的 diskSize
+	Labels        map[string]string `json:"labels,omitempty"`
+	Taints        []KarpenterTaint  `json:"taints,omitempty"`
+	AzIndex       int               `json:"azIndex,omitempty"` // 可用区索引（从1开始），0 表示使用集群的所有子网
+
+	LaunchTemplate EksLaunchTemplateConfig `json:"launchTemplate,omitempty"`
+
+	MaxUnavailable           int    `json:"maxUnavailable,omitempty"`           // 滚动更新时同时不可用的节点数
+	MaxUnavailablePercentage int    `json:"maxUnavailablePercentage,omitempty"` // 与 maxUnavailable 二选一
+	CapacityReservationId    string `json:"capacityReservationId,omitempty"`    // 容量预留或 Capacity Block ID
+}
+
+// EksLaunchTemplateConfig 节点组启动模板配置
+type EksLaunchTemplateConfig struct {
+	EfaInterfaces      int    `json:"efaInterfaces,omitempty"`      // EFA 网卡数量，第一个为 efa，其余为 efa-only
+	HttpTokens         string `json:"httpTokens,omitempty"`         // IMDS：required（默认）或 optional
+	HttpHopLimit       int    `json:"httpHopLimit,omitempty"`       // IMDS 响应跳数，默认 2 以便 Pod 访问
+	UserDataToken      string `json:"userDataToken,omitempty"`      // 不为空时通过 UserDataGenerator 生成 userdata，如 "nas"
+	UserDataScriptPath string `json:"userDataScriptPath,omitempty"` // 自定义 userdata 模块地址
+}
+
+var (
+	nodeGroupNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,38}[a-z0-9])?$`)
+	taintEffectsCdk      = map[string]awseks.TaintEffect{
+		"NoSchedule":       awseks.TaintEffect_NO_SCHEDULE,
+		"PreferNoSchedule": awseks.TaintEffect_PREFER_NO_SCHEDULE,
+		"NoExecute":        awseks.TaintEffect_NO_EXECUTE,
+	}
+)
+
+// validateNodeGroups 校验节点组名称、容量、污点、可用区和启动模板
+func (c *EksInstanceConfig) validateNodeGroups() error {
+	seen := make(map[string]bool)
+	for i, group := range c.NodeGroups {
+		field := fmt.Sprintf("nodeGroups[%d]", i)
+		if !nodeGroupNamePattern.MatchString(group.Name) {
+			return fmt.Errorf("%s: name '%s' must be lowercase alphanumeric or '-' and at most 40 characters", field, group.Name)
+		}
+		if seen[group.Name] {
+			return fmt.Errorf("%s: duplicate node group name '%s'", field, group.Name)
+		}
+		seen[group.Name] = true
+
+		if len(group.InstanceTypes) == 0 {
+			return fmt.Errorf("%s: instanceTypes is required", field)
+		}
+		switch group.CapacityType {
+		case "", NodeGroupCapacityOnDemand, NodeGroupCapacitySpot:
+		case NodeGroupCapacityCapacityBlock:
+			if group.CapacityReservationId == "" {
+				return fmt.Errorf("%s: capacityType capacityBlock requires capacityReservationId", field)
+			}
+		default:
+			return fmt.Errorf("%s: unknown capacityType '%s', expected onDemand, spot or capacityBlock", field, group.CapacityType)
+		}
+		if group.CapacityType == NodeGroupCapacitySpot && group.CapacityReservationId != "" {
+			return fmt.Errorf("%s: capacityReservationId cannot be used with spot", field)
+		}
+		// maxSize 为 0 时使用计算出的默认值，desiredSize 同样不能超过它
+		minSize, maxSize, desiredSize := group.sizes()
+		if minSize > maxSize || desiredSize > maxSize {
+			return fmt.Errorf("%s: minSize and desiredSize must not exceed maxSize (%d)", field, maxSize)
+		}
+		if group.MaxUnavailable > 0 && group.MaxUnavailablePercentage > 0 {
+			return fmt.Errorf("%s: maxUnavailable and maxUnavailablePercentage are mutually exclusive", field)
+		}
+		if group.MaxUnavailablePercentage > 100 {
+			return fmt.Errorf("%s: maxUnavailablePercentage must be between 1 and 100", field)
+		}
+		for _, taint := range group.Taints {
+			if _, ok := taintEffectsCdk[taint.Effect]; !ok || taint.Key == "" {
+				return fmt.Errorf("%s: taint '%s' needs a key and an effect of %s", field, taint.Key, strings.Join(taintEffects, ", "))
+			}
+		}
+
+		template := group.LaunchTemplate
+		switch template.HttpTokens {
+		case "", "required", "optional":
+		default:
+			return fmt.Errorf("%s: launchTemplate.httpTokens must be required or optional", field)
+		}
+		if template.HttpHopLimit < 0 || template.HttpHopLimit > 64 {
+			return fmt.Errorf("%s: launchTemplate.httpHopLimit must be between 1 and 64", field)
+		}
+		if template.EfaInterfaces < 0 {
+			return fmt.Errorf("%s: launchTemplate.efaInterfaces must not be negative", field)
+		}
+		if err := aws.ValidateAzIndex(group.AzIndex); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if template.EfaInterfaces > 0 && group.AzIndex == 0 {
+			return fmt.Errorf("%s: launchTemplate.efaInterfaces requires azIndex, EFA traffic does not cross availability zones", field)
+		}
+		if template.UserDataToken != "" && !strings.HasPrefix(strings.ToUpper(group.amiType(c)), "AL2") {
+			return fmt.Errorf("%s: launchTemplate.userDataToken requires an Amazon Linux AMI type, Bottlerocket and Windows do not run shell userdata", field)
+		}
+		if template.UserDataToken != "" && c.DependsOn != "" {
+			if _, err := dependency.GetDependencyInfo(c.DependsOn); err != nil {
+				return fmt.Errorf("%s: launchTemplate.userDataToken needs dependsOn information: %w", field, err)
+			}
+		}
+	}
+	return nil
+}
+
+// sizes 返回节点组的最小、最大和期望节点数，maxSize 默认为 max(minSize, 1)，desiredSize 默认为 minSize
+func (g EksNodeGroupConfig) sizes() (minSize, maxSize, desiredSize int) {
+	minSize, maxSize, desiredSize = g.MinSize, g.MaxSize, g.DesiredSize
+	if maxSize == 0 {
+		maxSize = max(minSize, 1)
+	}
+	if desiredSize == 0 {
+		desiredSize = minSize
+	}
+	return minSize, maxSize, desiredSize
+}
+
+// amiType 返回节点组的 AMI 类型，未指定时按操作系统、架构和 GPU 类型选择
+// EKS API 写法（如 AL2023_x86_64_NVIDIA）转换为大写，与 CDK 的 NodegroupAmiType 取值一致
+func (g EksNodeGroupConfig) amiType(eksInstance *EksInstanceConfig) string {
+	if g.AmiType != "" {
+		return strings.ToUpper(g.AmiType)
+	}
+	osType, osArch, gpuType := g.OsType, g.OsArch, g.GpuType
+	if osType == "" {
+		osType = eksInstance.OsType
+	}
+	if osArch == "" {
+		osArch = eksInstance.OsArch
+	}
+	if gpuType == "" {
+		gpuType = eksInstance.GpuType
+	}
+	return string(utils.SelectEksAmiType(osType, osArch, gpuType, eksInstance.WindowsType))
+}
+
+// createNodeGroups 为每个节点组创建启动模板和托管节点组，分阶段升级时与默认节点组一样按版本蓝绿替换
+func createNodeGroups(ctx *interfaces.ForgeContext, cluster awseks.Cluster, eksInstance *EksInstanceConfig, keyPair awsec2.IKeyPair) ([]awseks.Nodegroup, error) {
+	var nodeGroups []awseks.Nodegroup
+	for _, group := range eksInstance.NodeGroups {
+		id := fmt.Sprintf("%s-%s", eksInstance.GetID(), group.Name)
+		launchTemplate, err := createNodeGroupLaunchTemplate(ctx, eksInstance, group, id, keyPair)
+		if err != nil {
+			return nil, err
+		}
+
+		minSize, maxSize, desiredSize := group.sizes()
+
+		options := &awseks.NodegroupOptions{
+			InstanceTypes: nodeGroupInstanceTypes(group.InstanceTypes),
+			MinSize:       jsii.Number(minSize),
+			MaxSize:       jsii.Number(maxSize),
+			DesiredSize:   jsii.Number(desiredSize),
+			AmiType:       awseks.NodegroupAmiType(group.amiType(eksInstance)),
+			Labels:        nodeGroupLabels(group.Labels),
+			LaunchTemplateSpec: &awseks.LaunchTemplateSpec{
+				Id:      launchTemplate.Ref(),
+				Version: launchTemplate.AttrLatestVersionNumber(),
+			},
+		}
+		switch group.CapacityType {
+		case NodeGroupCapacitySpot:
+			options.CapacityType = awseks.CapacityType_SPOT
+		case NodeGroupCapacityCapacityBlock:
+			options.CapacityType = awseks.CapacityType_CAPACITY_BLOCK
+		default:
+			options.CapacityType = awseks.CapacityType_ON_DEMAND
+		}
+		if len(group.Taints) > 0 {
+			var taints []*awseks.TaintSpec
+			for _, taint := range group.Taints {
+				taints = append(taints, &awseks.TaintSpec{
+					Key:    jsii.String(taint.Key),
+					Value:  jsii.String(taint.Value),
+					Effect: taintEffectsCdk[taint.Effect],
+				})
+			}
+			options.Taints = &taints
+		}
+		if group.AzIndex > 0 {
+			subnet := aws.SelectSubnetByAzIndex(group.AzIndex, ctx.VPC, ctx.SubnetType)
+			options.Subnets = &awsec2.SubnetSelection{Subnets: &[]awsec2.ISubnet{subnet}}
+		}
+		if group.MaxUnavailable > 0 {
+			options.MaxUnavailable = jsii.Number(group.MaxUnavailable)
+		}
+		if group.MaxUnavailablePercentage > 0 {
+			options.MaxUnavailablePercentage = jsii.Number(group.MaxUnavailablePercentage)
+		}
+
+		for _, nodeGroupVersion := range eksInstance.nodeGroupVersions() {
+			nodeGroup := cluster.AddNodegroupCapacity(jsii.String(fmt.Sprintf("%s-v%s", id, strings.ReplaceAll(nodeGroupVersion, ".", ""))), options)
+			if eksInstance.IsUpgrading() {
+				nodeGroup.Node().DefaultChild().(awseks.CfnNodegroup).SetVersion(jsii.String(nodeGroupVersion))
+			}
+			nodeGroups = append(nodeGroups, nodeGroup)
+		}
+	}
+	return nodeGroups, nil
+}
+
+// createNodeGroupLaunchTemplate 创建节点组启动模板：磁盘、IMDS、EFA 网卡、容量预留和 userdata
+func createNodeGroupLaunchTemplate(ctx *interfaces.ForgeContext, eksInstance *EksInstanceConfig, group EksNodeGroupConfig, id string, keyPair awsec2.IKeyPair) (awsec2.CfnLaunchTemplate, error) {
+	template := group.LaunchTemplate
+	diskSize := group.DiskSize
+	if diskSize == 0 {
+		diskSize = eksInstance.DiskSize
+	}
+
+	httpTokens := template.HttpTokens
+	if httpTokens == "" {
+		httpTokens = "required"
+	}
+	hopLimit := template.HttpHopLimit
+	if hopLimit == 0 {
+		hopLimit = 2
+	}
+
+	data := &awsec2.CfnLaunchTemplate_LaunchTemplateDataProperty{
+		KeyName: keyPair.KeyPairName(),
+		MetadataOptions: &awsec2.CfnLaunchTemplate_MetadataOptionsProperty{
+			HttpEndpoint:            jsii.String("enabled"),
+			HttpTokens:              jsii.String(httpTokens),
+			HttpPutResponseHopLimit: jsii.Number(hopLimit),
+		},
+		TagSpecifications: []interface{}{
+			&awsec2.CfnLaunchTemplate_TagSpecificationProperty{
+				ResourceType: jsii.String("instance"),
+				Tags: &[]*awscdk.CfnTag{
+					{Key: jsii.String("Name"), Value: jsii.String(id)},
+				},
+			},
+		},
+	}
+	if diskSize > 0 {
+		data.BlockDeviceMappings = []interface{}{
+			&awsec2.CfnLaunchTemplate_BlockDeviceMappingProperty{
+				DeviceName: jsii.String("/dev/xvda"),
+				Ebs: &awsec2.CfnLaunchTemplate_EbsProperty{
+					VolumeSize: jsii.Number(diskSize),
+					VolumeType: jsii.String("gp3"),
+					Encrypted:  jsii.Bool(true),
+				},
+			},
+		}
+	}
+
+	// 使用网卡时安全组必须设置在网卡上
+	securityGroupIds := []*string{ctx.SecurityGroups.Default.SecurityGroupId()}
+	if template.EfaInterfaces > 0 {
+		var networkInterfaces []interface{}
+		for i := 0; i < template.EfaInterfaces; i++ {
+			interfaceType := "efa-only"
+			deviceIndex := 1
+			if i == 0 {
+				interfaceType = "efa"
+				deviceIndex = 0
+			}
+			networkInterfaces = append(networkInterfaces, &awsec2.CfnLaunchTemplate_NetworkInterfaceProperty{
+				DeviceIndex:         jsii.Number(deviceIndex),
+				NetworkCardIndex:    jsii.Number(i),
+				InterfaceType:       jsii.String(interfaceType),
+				Groups:              &securityGroupIds,
+				DeleteOnTermination: jsii.Bool(true),
+			})
+		}
+		data.NetworkInterfaces = networkInterfaces
+	} else {
+		data.SecurityGroupIds = &securityGroupIds
+	}
+
+	if group.CapacityType == NodeGroupCapacityCapacityBlock {
+		data.InstanceMarketOptions = &awsec2.CfnLaunchTemplate_InstanceMarketOptionsProperty{
+			MarketType: jsii.String("capacity-block"),
+		}
+	}
+	if group.CapacityReservationId != "" {
+		data.CapacityReservationSpecification = &awsec2.CfnLaunchTemplate_CapacityReservationSpecificationProperty{
+			CapacityReservationTarget: &awsec2.CfnLaunchTemplate_CapacityReservationTargetProperty{
+				CapacityReservationId: jsii.String(group.CapacityReservationId),
+			},
+		}
+	}
+
+	// 托管节点组要求 MIME multipart 格式的 userdata，EKS 会追加节点引导部分
+	if template.UserDataToken != "" {
+		var magicToken string
+		if eksInstance.DependsOn != "" {
+			token, err := dependency.GetDependencyInfo(eksInstance.DependsOn)
+			if err != nil {
+				return nil, fmt.Errorf("eks node group %s: %w", group.Name, err)
+			}
+			magicToken = token
+		}
+		userDataGenerator := &aws.UserDataGenerator{
+			OsType:             awsec2.OperatingSystemType_LINUX,
+			ScriptPath:         "./userdata.sh",
+			UserDataToken:      template.UserDataToken,
+			UserDataScriptPath: template.UserDataScriptPath,
+			MagicToken:         magicToken,
+		}
+		userData, err := userDataGenerator.GenerateMimeMultipartUserData()
+		if err != nil {
+			return nil, fmt.Errorf("eks node group %s: %w", group.Name, err)
+		}
+		data.UserData = awscdk.Fn_Base64(userData.Render())
+	}
+
+	return awsec2.NewCfnLaunchTemplate(ctx.Stack, jsii.String(id+"-LaunchTemplate"), &awsec2.CfnLaunchTemplateProps{
+		LaunchTemplateName: jsii.String(id + "-node-template"),
+		LaunchTemplateData: data,
+	}), nil
+}
+
+func nodeGroupInstanceTypes(names []string) *[]awsec2.InstanceType {
+	var instanceTypes []awsec2.InstanceType
+	for _, name := range names {
+		instanceTypes = append(instanceTypes, awsec2.NewInstanceType(jsii.String(strings.TrimSpace(name))))
+	}
+	return &instanceTypes
+}
+
+func nodeGroupLabels(labels map[string]string) *map[string]*string {
+	if len(labels) == 0 {
+		return nil
+	}
+	result := make(map[string]*string, len(labels))
+	for k, v := range labels {
+		result[k] = jsii.String(v)
+	}
+	return &result
+}
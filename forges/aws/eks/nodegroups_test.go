@@ -0,0 +1,148 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/assertions"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/jsii-runtime-go"
+	"github.com/awslabs/InfraForge/core/interfaces"
+	"github.com/awslabs/InfraForge/core/utils/aws"
+)
+
+func TestValidateNodeGroups(t *testing.T) {
+	tests := []struct {
+		name  string
+		group EksNodeGroupConfig
+		want  string
+	}{
+		{"valid", EksNodeGroupConfig{Name: "gpu", InstanceTypes: []string{"g5.48xlarge"}, CapacityType: "capacityBlock", CapacityReservationId: "cr-123", MaxSize: 2, AzIndex: 1, LaunchTemplate: EksLaunchTemplateConfig{EfaInterfaces: 32}}, ""},
+		{"name", EksNodeGroupConfig{Name: "GPU", InstanceTypes: []string{"g5.48xlarge"}}, "lowercase"},
+		{"instance types", EksNodeGroupConfig{Name: "gpu"}, "instanceTypes is required"},
+		{"capacity block", EksNodeGroupConfig{Name: "gpu", InstanceTypes: []string{"g5.48xlarge"}, CapacityType: "capacityBlock"}, "requires capacityReservationId"},
+		{"spot reservation", EksNodeGroupConfig{Name: "gpu", InstanceTypes: []string{"g5.48xlarge"}, CapacityType: "spot", CapacityReservationId: "cr-123"}, "cannot be used with spot"},
+		{"sizes", EksNodeGroupConfig{Name: "gpu", InstanceTypes: []string{"g5.48xlarge"}, MinSize: 3, MaxSize: 2}, "must not exceed maxSize"},
+		{"default max size", EksNodeGroupConfig{Name: "gpu", InstanceTypes: []string{"g5.48xlarge"}, MinSize: 2, DesiredSize: 2}, ""},
+		{"desired over default max size", EksNodeGroupConfig{Name: "gpu", InstanceTypes: []string{"g5.48xlarge"}, DesiredSize: 3}, "must not exceed maxSize (1)"},
+		{"max unavailable", EksNodeGroupConfig{Name: "gpu", InstanceTypes: []string{"g5.48xlarge"}, MaxUnavailable: 1, MaxUnavailablePercentage: 10}, "mutually exclusive"},
+		{"efa", EksNodeGroupConfig{Name: "gpu", InstanceTypes: []string{"g5.48xlarge"}, LaunchTemplate: EksLaunchTemplateConfig{EfaInterfaces: 1}}, "requires azIndex"},
+		{"userdata", EksNodeGroupConfig{Name: "gpu", InstanceTypes: []string{"g5.48xlarge"}, AmiType: "BOTTLEROCKET_x86_64_NVIDIA", LaunchTemplate: EksLaunchTemplateConfig{UserDataToken: "nas"}}, "Amazon Linux AMI type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := EksInstanceConfig{NodeGroups: []EksNodeGroupConfig{tt.group}}
+			err := config.validateNodeGroups()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateNodeGroupsUserDataDependency(t *testing.T) {
+	config := EksInstanceConfig{
+		DependsOn: "EFS:nodegroup-missing",
+		NodeGroups: []EksNodeGroupConfig{{
+			Name:           "gpu",
+			InstanceTypes:  []string{"g5.48xlarge"},
+			AmiType:        "AL2023_x86_64_NVIDIA",
+			LaunchTemplate: EksLaunchTemplateConfig{UserDataToken: "nas"},
+		}},
+	}
+	if err := config.validateNodeGroups(); err == nil || !strings.Contains(err.Error(), "needs dependsOn information") {
+		t.Errorf("Expected a missing dependency to fail validation, got %v", err)
+	}
+}
+
+func TestValidateNodeGroupsAzIndex(t *testing.T) {
+	stack := awscdk.NewStack(awscdk.NewApp(nil), jsii.String("AzTest"), nil)
+	aws.SetVpcAvailabilityZones(awsec2.NewVpc(stack, jsii.String("Vpc"), &awsec2.VpcProps{MaxAzs: jsii.Number(2)}))
+
+	// azIndex 超出 VPC 可用区数量时在 Validate 中报错，而不是在 Create 中回退到其他可用区
+	config := EksInstanceConfig{NodeGroups: []EksNodeGroupConfig{{Name: "gpu", InstanceTypes: []string{"g5.48xlarge"}, AzIndex: 3}}}
+	if err := config.validateNodeGroups(); err == nil || !strings.Contains(err.Error(), "exceeds the 2 availability zones") {
+		t.Errorf("Expected azIndex 3 to be rejected, got %v", err)
+	}
+	config.NodeGroups[0].AzIndex = 2
+	if err := config.validateNodeGroups(); err != nil {
+		t.Errorf("Expected azIndex 2 to be valid, got %v", err)
+	}
+
+	fargate := EksInstanceConfig{FargateProfiles: []EksFargateProfileConfig{{Name: "apps", Selectors: []EksFargateSelectorConfig{{Namespace: "apps"}}, AzIndices: []int{1, 3}}}}
+	if err := fargate.validateFargateProfiles(); err == nil || !strings.Contains(err.Error(), "exceeds the 2 availability zones") {
+		t.Errorf("Expected fargate azIndices 3 to be rejected, got %v", err)
+	}
+}
+
+func TestCreateNodeGroups(t *testing.T) {
+	eksInstance := &EksInstanceConfig{
+		NodeGroups: []EksNodeGroupConfig{
+			{Name: "gpu", InstanceTypes: []string{"g5.48xlarge"}, AmiType: "AL2023_x86_64_NVIDIA", CapacityType: "capacityBlock", CapacityReservationId: "cr-123",
+				MinSize: 2, MaxSize: 2, DiskSize: 200, Labels: map[string]string{"workload": "training"},
+				Taints:         []KarpenterTaint{{Key: "nvidia.com/gpu", Effect: "NoSchedule"}},
+				LaunchTemplate: EksLaunchTemplateConfig{EfaInterfaces: 2}},
+			{Name: "spot", InstanceTypes: []string{"m7i.large", "m6i.large"}, AmiType: "AL2023_x86_64_STANDARD", CapacityType: "spot",
+				LaunchTemplate: EksLaunchTemplateConfig{HttpTokens: "optional", HttpHopLimit: 1}},
+		},
+	}
+	ctx, cluster := newTestCluster(t, eksInstance, awsec2.SubnetType_PRIVATE_WITH_EGRESS)
+	ctx.SecurityGroups = &interfaces.SecurityGroups{Default: awsec2.NewSecurityGroup(ctx.Stack, jsii.String("Sg"), &awsec2.SecurityGroupProps{Vpc: ctx.VPC})}
+	keyPair := awsec2.KeyPair_FromKeyPairName(ctx.Stack, jsii.String("Key"), jsii.String("eks-key"))
+	if _, err := createNodeGroups(ctx, cluster, eksInstance, keyPair); err != nil {
+		t.Fatalf("Expected node groups to be created, got %v", err)
+	}
+
+	template := assertions.Template_FromStack(ctx.Stack, nil)
+	template.ResourceCountIs(jsii.String("AWS::EKS::Nodegroup"), jsii.Number(2))
+	template.ResourceCountIs(jsii.String("AWS::EC2::LaunchTemplate"), jsii.Number(2))
+	template.HasResourceProperties(jsii.String("AWS::EKS::Nodegroup"), map[string]interface{}{
+		"CapacityType":  "CAPACITY_BLOCK",
+		"InstanceTypes": []interface{}{"g5.48xlarge"},
+		"ScalingConfig": map[string]interface{}{"MinSize": 2, "MaxSize": 2, "DesiredSize": 2},
+		"Labels":        map[string]interface{}{"workload": "training"},
+		"Taints":        []interface{}{map[string]interface{}{"Key": "nvidia.com/gpu", "Effect": "NO_SCHEDULE"}},
+		"LaunchTemplate": map[string]interface{}{
+			"Id":      map[string]interface{}{"Ref": assertions.Match_StringLikeRegexp(jsii.String("^eksgpuLaunchTemplate"))},
+			"Version": map[string]interface{}{"Fn::GetAtt": []interface{}{assertions.Match_StringLikeRegexp(jsii.String("^eksgpuLaunchTemplate")), "LatestVersionNumber"}},
+		},
+	})
+	template.HasResourceProperties(jsii.String("AWS::EKS::Nodegroup"), map[string]interface{}{
+		"CapacityType":  "SPOT",
+		"InstanceTypes": []interface{}{"m7i.large", "m6i.large"},
+	})
+
+	// EFA 网卡携带安全组，容量块使用 capacity-block 市场类型并指定预留
+	template.HasResourceProperties(jsii.String("AWS::EC2::LaunchTemplate"), map[string]interface{}{
+		"LaunchTemplateName": "eks-gpu-node-template",
+		"LaunchTemplateData": assertions.Match_ObjectLike(&map[string]interface{}{
+			"KeyName":             "eks-key",
+			"MetadataOptions":     map[string]interface{}{"HttpEndpoint": "enabled", "HttpTokens": "required", "HttpPutResponseHopLimit": 2},
+			"BlockDeviceMappings": []interface{}{map[string]interface{}{"DeviceName": "/dev/xvda", "Ebs": map[string]interface{}{"VolumeSize": 200, "VolumeType": "gp3", "Encrypted": true}}},
+			"NetworkInterfaces": []interface{}{
+				assertions.Match_ObjectLike(&map[string]interface{}{"DeviceIndex": 0, "NetworkCardIndex": 0, "InterfaceType": "efa"}),
+				assertions.Match_ObjectLike(&map[string]interface{}{"DeviceIndex": 1, "NetworkCardIndex": 1, "InterfaceType": "efa-only"}),
+			},
+			"InstanceMarketOptions":            map[string]interface{}{"MarketType": "capacity-block"},
+			"CapacityReservationSpecification": map[string]interface{}{"CapacityReservationTarget": map[string]interface{}{"CapacityReservationId": "cr-123"}},
+			"SecurityGroupIds":                 assertions.Match_Absent(),
+		}),
+	})
+	template.HasResourceProperties(jsii.String("AWS::EC2::LaunchTemplate"), map[string]interface{}{
+		"LaunchTemplateName": "eks-spot-node-template",
+		"LaunchTemplateData": assertions.Match_ObjectLike(&map[string]interface{}{
+			"MetadataOptions":  map[string]interface{}{"HttpEndpoint": "enabled", "HttpTokens": "optional", "HttpPutResponseHopLimit": 1},
+			"SecurityGroupIds": []interface{}{map[string]interface{}{"Fn::GetAtt": []interface{}{assertions.Match_StringLikeRegexp(jsii.String("^Sg")), "GroupId"}}},
+		}),
+	})
+}
@@ -0,0 +1,162 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/awslabs/InfraForge/core/utils/aws"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsiam"
+	"github.com/aws/constructs-go/constructs/v10"
+	"github.com/aws/jsii-runtime-go"
+)
+
+// 工作负载 ServiceAccount 获取 AWS 权限的方式
+const (
+	ServiceAccountPodIdentity = "podIdentity"
+	ServiceAccountIrsa        = "irsa"
+)
+
+// EksServiceAccountConfig 需要 AWS 权限的工作负载 ServiceAccount
+type EksServiceAccountConfig struct {
+	Name         string                 `json:"name"`
+	Namespace    string                 `json:"namespace,omitempty"`    // 默认 default，命名空间需要已存在
+	Mechanism    string                 `json:"mechanism,omitempty"`    // podIdentity 或 irsa，默认在部署 Pod Identity Agent 时使用 podIdentity
+	Policies     string                 `json:"policies,omitempty"`     // 逗号分隔的 AWS 托管策略名称
+	InlinePolicy map[string]interface{} `json:"inlinePolicy,omitempty"` // IAM 策略文档
+}
+
+var serviceAccountNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?$`)
+
+// GetNamespace 返回 ServiceAccount 所在命名空间
+func (s EksServiceAccountConfig) GetNamespace() string {
+	if s.Namespace == "" {
+		return "default"
+	}
+	return s.Namespace
+}
+
+// GetMechanism 返回权限获取方式，未指定时根据是否部署 Pod Identity Agent 决定
+func (s EksServiceAccountConfig) GetMechanism(podIdentityAgent bool) string {
+	switch strings.ToLower(s.Mechanism) {
+	case "podidentity":
+		return ServiceAccountPodIdentity
+	case "irsa":
+		return ServiceAccountIrsa
+	}
+	if podIdentityAgent {
+		return ServiceAccountPodIdentity
+	}
+	return ServiceAccountIrsa
+}
+
+// validateServiceAccounts 校验名称、权限获取方式和策略
+func (c *EksInstanceConfig) validateServiceAccounts() error {
+	seen := make(map[string]bool)
+	for i, sa := range c.ServiceAccounts {
+		field := fmt.Sprintf("serviceAccounts[%d]", i)
+		if !serviceAccountNamePattern.MatchString(sa.Name) {
+			return fmt.Errorf("%s: name '%s' must be a lowercase DNS subdomain", field, sa.Name)
+		}
+		if err := validateNamespaces([]string{sa.GetNamespace()}); err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		key := sa.GetNamespace() + "/" + sa.Name
+		if seen[key] {
+			return fmt.Errorf("%s: duplicate service account %s", field, key)
+		}
+		seen[key] = true
+
+		switch strings.ToLower(sa.Mechanism) {
+		case "", "irsa":
+		case "podidentity":
+			if c.PodIdentityAgentVersion == "" {
+				return fmt.Errorf("%s: mechanism podIdentity requires podIdentityAgentVersion", field)
+			}
+		default:
+			return fmt.Errorf("%s: unknown mechanism '%s', expected podIdentity or irsa", field, sa.Mechanism)
+		}
+		if strings.TrimSpace(sa.Policies) == "" && len(sa.InlinePolicy) == 0 {
+			return fmt.Errorf("%s: at least one of policies or inlinePolicy is required", field)
+		}
+		if len(sa.InlinePolicy) > 0 {
+			if _, ok := sa.InlinePolicy["Statement"]; !ok {
+				return fmt.Errorf("%s: inlinePolicy must be an IAM policy document with a Statement", field)
+			}
+		}
+	}
+	return nil
+}
+
+// newPodIdentityRole 创建 Pod Identity 使用的角色，信任 pods.eks.amazonaws.com 并允许会话标签
+func newPodIdentityRole(scope constructs.Construct, id string) awsiam.Role {
+	return awsiam.NewRole(scope, jsii.String(id), &awsiam.RoleProps{
+		AssumedBy: awsiam.NewSessionTagsPrincipal(awsiam.NewServicePrincipal(jsii.String("pods.eks.amazonaws.com"), nil)),
+	})
+}
+
+// createServiceAccounts 创建工作负载 ServiceAccount、IAM 角色，以及 Pod Identity 关联或 IRSA 信任关系
+// podIdentityAgent 为已部署的 Pod Identity Agent，Pod Identity 关联在其之后创建
+func createServiceAccounts(stack awscdk.Stack, cluster awseks.Cluster, eksInstance *EksInstanceConfig, podIdentityAgent constructs.Construct) []constructs.Construct {
+	var serviceAccounts []constructs.Construct
+	for _, sa := range eksInstance.ServiceAccounts {
+		namespace := sa.GetNamespace()
+		id := fmt.Sprintf("ServiceAccount-%s-%s", namespace, sa.Name)
+
+		var role awsiam.IRole
+		var serviceAccount constructs.Construct
+		if sa.GetMechanism(podIdentityAgent != nil) == ServiceAccountPodIdentity {
+			podIdentityRole := newPodIdentityRole(stack, id+"-Role")
+			association := awseks.NewCfnPodIdentityAssociation(stack, jsii.String(id+"-Association"), &awseks.CfnPodIdentityAssociationProps{
+				ClusterName:    cluster.ClusterName(),
+				Namespace:      jsii.String(namespace),
+				ServiceAccount: jsii.String(sa.Name),
+				RoleArn:        podIdentityRole.RoleArn(),
+			})
+			if podIdentityAgent != nil {
+				association.Node().AddDependency(podIdentityAgent)
+			}
+			manifest := cluster.AddManifest(jsii.String(id), &map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ServiceAccount",
+				"metadata": map[string]interface{}{
+					"name":      sa.Name,
+					"namespace": namespace,
+				},
+			})
+			manifest.Node().AddDependency(association)
+			role = podIdentityRole
+			serviceAccount = manifest
+		} else {
+			// IRSA 由 CDK 创建带 OIDC 信任关系的角色和带注解的 ServiceAccount
+			irsaServiceAccount := cluster.AddServiceAccount(jsii.String(id), &awseks.ServiceAccountOptions{
+				Name:      jsii.String(sa.Name),
+				Namespace: jsii.String(namespace),
+			})
+			role = irsaServiceAccount.Role()
+			serviceAccount = irsaServiceAccount
+		}
+
+		if sa.Policies != "" {
+			aws.AddManagedPolicies(role, sa.Policies)
+		}
+		if len(sa.InlinePolicy) > 0 {
+			role.AttachInlinePolicy(awsiam.NewPolicy(stack, jsii.String(id+"-Policy"), &awsiam.PolicyProps{
+				Document: awsiam.PolicyDocument_FromJson(sa.InlinePolicy),
+			}))
+		}
+
+		awscdk.NewCfnOutput(stack, jsii.String(id+"-RoleArn"), &awscdk.CfnOutputProps{
+			Value:       role.RoleArn(),
+			Description: jsii.String(fmt.Sprintf("IAM role for service account %s/%s", namespace, sa.Name)),
+		})
+		serviceAccounts = append(serviceAccounts, serviceAccount)
+	}
+	return serviceAccounts
+}
@@ -0,0 +1,123 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2/assertions"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
+	"github.com/aws/jsii-runtime-go"
+)
+
+func TestServiceAccountMechanism(t *testing.T) {
+	sa := EksServiceAccountConfig{Name: "s3-reader"}
+	if sa.GetMechanism(true) != ServiceAccountPodIdentity || sa.GetMechanism(false) != ServiceAccountIrsa {
+		t.Error("Expected the default mechanism to follow the Pod Identity Agent deployment")
+	}
+	sa.Mechanism = "IRSA"
+	if sa.GetMechanism(true) != ServiceAccountIrsa {
+		t.Error("Expected an explicit mechanism to win")
+	}
+	if sa.GetNamespace() != "default" {
+		t.Errorf("Expected default namespace, got %s", sa.GetNamespace())
+	}
+}
+
+func TestValidateServiceAccounts(t *testing.T) {
+	policy := map[string]interface{}{"Version": "2012-10-17", "Statement": []interface{}{}}
+	tests := []struct {
+		name   string
+		config EksInstanceConfig
+		want   string
+	}{
+		{"valid", EksInstanceConfig{PodIdentityAgentVersion: "latest", ServiceAccounts: []EksServiceAccountConfig{{Name: "s3-reader", Namespace: "ml", Mechanism: "podIdentity", Policies: "AmazonS3ReadOnlyAccess"}, {Name: "s3-reader", InlinePolicy: policy}}}, ""},
+		{"name", EksInstanceConfig{ServiceAccounts: []EksServiceAccountConfig{{Name: "S3Reader", Policies: "AmazonS3ReadOnlyAccess"}}}, "lowercase DNS subdomain"},
+		{"duplicate", EksInstanceConfig{ServiceAccounts: []EksServiceAccountConfig{{Name: "a", Policies: "p"}, {Name: "a", Namespace: "default", Policies: "p"}}}, "duplicate service account default/a"},
+		{"pod identity without agent", EksInstanceConfig{ServiceAccounts: []EksServiceAccountConfig{{Name: "a", Mechanism: "podIdentity", Policies: "p"}}}, "requires podIdentityAgentVersion"},
+		{"mechanism", EksInstanceConfig{ServiceAccounts: []EksServiceAccountConfig{{Name: "a", Mechanism: "oidc", Policies: "p"}}}, "unknown mechanism"},
+		{"no policies", EksInstanceConfig{ServiceAccounts: []EksServiceAccountConfig{{Name: "a"}}}, "policies or inlinePolicy"},
+		{"inline policy", EksInstanceConfig{ServiceAccounts: []EksServiceAccountConfig{{Name: "a", InlinePolicy: map[string]interface{}{"Effect": "Allow"}}}}, "Statement"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validateServiceAccounts()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCreateServiceAccounts(t *testing.T) {
+	eksInstance := &EksInstanceConfig{ServiceAccounts: []EksServiceAccountConfig{
+		{Name: "s3-reader", Namespace: "ml", Policies: "AmazonS3ReadOnlyAccess"},
+		{Name: "legacy", Mechanism: "irsa", InlinePolicy: map[string]interface{}{
+			"Version":   "2012-10-17",
+			"Statement": []interface{}{map[string]interface{}{"Effect": "Allow", "Action": "sqs:ReceiveMessage", "Resource": "*"}},
+		}},
+	}}
+	ctx, cluster := newTestCluster(t, eksInstance, awsec2.SubnetType_PRIVATE_WITH_EGRESS)
+	agent := awseks.NewCfnAddon(ctx.Stack, jsii.String("PodIdentityAgent"), &awseks.CfnAddonProps{
+		AddonName:   jsii.String("eks-pod-identity-agent"),
+		ClusterName: cluster.ClusterName(),
+	})
+	if serviceAccounts := createServiceAccounts(ctx.Stack, cluster, eksInstance, agent); len(serviceAccounts) != 2 {
+		t.Fatalf("Expected 2 service accounts, got %d", len(serviceAccounts))
+	}
+
+	// Pod Identity 关联在 Agent 之后创建，角色由 pods.eks.amazonaws.com 承担
+	template := assertions.Template_FromStack(ctx.Stack, nil)
+	template.ResourceCountIs(jsii.String("AWS::EKS::PodIdentityAssociation"), jsii.Number(1))
+	template.HasResource(jsii.String("AWS::EKS::PodIdentityAssociation"), map[string]interface{}{
+		"Properties": map[string]interface{}{
+			"Namespace":      "ml",
+			"ServiceAccount": "s3-reader",
+		},
+		"DependsOn": assertions.Match_ArrayWith(&[]interface{}{"PodIdentityAgent"}),
+	})
+	template.HasResourceProperties(jsii.String("AWS::IAM::Role"), map[string]interface{}{
+		"AssumeRolePolicyDocument": assertions.Match_ObjectLike(&map[string]interface{}{
+			"Statement": []interface{}{assertions.Match_ObjectLike(&map[string]interface{}{
+				"Action":    []interface{}{"sts:AssumeRole", "sts:TagSession"},
+				"Principal": map[string]interface{}{"Service": "pods.eks.amazonaws.com"},
+			})},
+		}),
+		"ManagedPolicyArns": []interface{}{assertions.Match_ObjectLike(&map[string]interface{}{
+			"Fn::Join": assertions.Match_ArrayWith(&[]interface{}{
+				assertions.Match_ArrayWith(&[]interface{}{assertions.Match_StringLikeRegexp(jsii.String("AmazonS3ReadOnlyAccess"))}),
+			}),
+		})},
+	})
+
+	// IRSA 的 ServiceAccount 带角色注解，内联策略挂在该角色上
+	template.HasResourceProperties(jsii.String("AWS::IAM::Policy"), map[string]interface{}{
+		"PolicyDocument": assertions.Match_ObjectLike(&map[string]interface{}{
+			"Statement": []interface{}{assertions.Match_ObjectLike(&map[string]interface{}{"Action": "sqs:ReceiveMessage"})},
+		}),
+		"Roles": []interface{}{map[string]interface{}{"Ref": assertions.Match_StringLikeRegexp(jsii.String("ServiceAccountdefaultlegacyRole"))}},
+	})
+	manifests := template.FindResources(jsii.String("Custom::AWSCDK-EKS-KubernetesResource"), nil)
+	found := map[string]bool{}
+	for _, manifest := range *manifests {
+		content, _ := json.Marshal(manifest)
+		for _, name := range []string{"s3-reader", "eks.amazonaws.com/role-arn"} {
+			if strings.Contains(string(content), name) {
+				found[name] = true
+			}
+		}
+	}
+	if len(found) != 2 {
+		t.Errorf("Expected the s3-reader ServiceAccount and the annotated IRSA ServiceAccount, got %v", found)
+	}
+}
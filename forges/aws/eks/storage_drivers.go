@@ -15,12 +15,14 @@ import (
 	"github.com/aws/aws-cdk-go/awscdk/v2"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awseks"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awsiam"
+	"github.com/aws/constructs-go/constructs/v10"
 	"github.com/aws/jsii-runtime-go"
 )
 
 // csiDriverConfig CSI Driver通用配置
 type csiDriverConfig struct {
 	name               string
+	addon              string // eksAddonSpecs 中的组件名称，托管插件模式下使用
 	chartId            string // Helm Chart ID，用于CloudFormation资源命名
 	serviceAccountName string
 	chartName          string
@@ -30,8 +32,12 @@ type csiDriverConfig struct {
 	version            string
 }
 
-// deployGenericCsiDriver 部署通用CSI Driver（EBS/EFS/FSx）
-func deployGenericCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, config *csiDriverConfig) awseks.HelmChart {
+// deployGenericCsiDriver 部署通用CSI Driver（EBS/EFS/FSx），托管插件模式下创建 EKS 插件代替 Helm Chart
+func deployGenericCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, addons *eksAddons, config *csiDriverConfig) constructs.Construct {
+	if addons != nil && addons.isManaged(config.addon) {
+		return addons.deploy(config.addon, config.version, config.customPolicy, nil)
+	}
+
 	// 创建ServiceAccount
 	saId := jsii.String(config.name + "-csi-controller-sa")
 	csiServiceAccount := cluster.AddServiceAccount(saId, &awseks.ServiceAccountOptions{
@@ -75,9 +81,10 @@ func deployGenericCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, config *
 }
 
 // deployEbsCsiDriver 部署 EBS CSI Driver
-func deployEbsCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, version string) awseks.HelmChart {
-	return deployGenericCsiDriver(stack, cluster, &csiDriverConfig{
+func deployEbsCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, addons *eksAddons, version string) constructs.Construct {
+	return deployGenericCsiDriver(stack, cluster, addons, &csiDriverConfig{
 		name:               "ebs",
+		addon:              "ebsCsiDriver",
 		chartId:            "ebs-csi-driver",
 		serviceAccountName: "ebs-csi-controller-sa",
 		chartName:          "aws-ebs-csi-driver",
@@ -88,14 +95,8 @@ func deployEbsCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, version stri
 }
 
 // deployMountpointS3CsiDriver 部署 Mountpoint S3 CSI Driver
-func deployMountpointS3CsiDriver(stack awscdk.Stack, cluster awseks.Cluster, version string, bucketName string) awseks.HelmChart {
-	// 创建 IAM 角色用于 S3 CSI Driver（使用 CDK 的 ServiceAccount 方法）
-	s3CsiServiceAccount := cluster.AddServiceAccount(jsii.String("s3-csi-sa"), &awseks.ServiceAccountOptions{
-		Name:      jsii.String("s3-csi-driver-sa"),
-		Namespace: jsii.String("kube-system"),
-	})
-
-	// 创建 S3 访问策略并附加到角色
+func deployMountpointS3CsiDriver(stack awscdk.Stack, cluster awseks.Cluster, addons *eksAddons, version string, bucketName string) constructs.Construct {
+	// 创建 S3 访问策略
 	s3CsiPolicy := awsiam.NewPolicy(stack, jsii.String("MountpointS3CsiDriverPolicy"), &awsiam.PolicyProps{
 		Statements: &[]awsiam.PolicyStatement{
 			awsiam.NewPolicyStatement(&awsiam.PolicyStatementProps{
@@ -122,6 +123,17 @@ func deployMountpointS3CsiDriver(stack awscdk.Stack, cluster awseks.Cluster, ver
 		},
 	})
 
+	// 托管插件自带 ServiceAccount 和 RBAC，只需要附加策略
+	if addons != nil && addons.isManaged("mountpointS3CsiDriver") {
+		return addons.deploy("mountpointS3CsiDriver", version, s3CsiPolicy, nil)
+	}
+
+	// 创建 IAM 角色用于 S3 CSI Driver（使用 CDK 的 ServiceAccount 方法）
+	s3CsiServiceAccount := cluster.AddServiceAccount(jsii.String("s3-csi-sa"), &awseks.ServiceAccountOptions{
+		Name:      jsii.String("s3-csi-driver-sa"),
+		Namespace: jsii.String("kube-system"),
+	})
+
 	// 将策略附加到 ServiceAccount 的角色
 	s3CsiServiceAccount.Role().AttachInlinePolicy(s3CsiPolicy)
 
@@ -249,7 +261,7 @@ func deployMountpointS3CsiDriver(stack awscdk.Stack, cluster awseks.Cluster, ver
 }
 
 // 部署 Mountpoint S3 CSI 驱动和 StorageClass
-func deployMountpointS3CsiDriverWithStorage(stack awscdk.Stack, cluster awseks.Cluster, eksInstance *EksInstanceConfig) awseks.HelmChart {
+func deployMountpointS3CsiDriverWithStorage(stack awscdk.Stack, cluster awseks.Cluster, addons *eksAddons, eksInstance *EksInstanceConfig) constructs.Construct {
 	// 如果没有指定S3 bucket，不部署S3 CSI Driver
 	if eksInstance.S3BucketName == "" {
 		fmt.Printf("Warning: S3BucketName is required for Mountpoint S3 CSI Driver\n")
@@ -257,7 +269,7 @@ func deployMountpointS3CsiDriverWithStorage(stack awscdk.Stack, cluster awseks.C
 	}
 	
 	// 部署 CSI 驱动
-	s3CsiChart := deployMountpointS3CsiDriver(stack, cluster, eksInstance.MountpointS3CsiDriverVersion, eksInstance.S3BucketName)
+	s3CsiChart := deployMountpointS3CsiDriver(stack, cluster, addons, eksInstance.MountpointS3CsiDriverVersion, eksInstance.S3BucketName)
 
 	// 如果需要创建 StorageClass
 	if types.GetBoolValue(eksInstance.CreateStorageClass, false) {
@@ -269,7 +281,10 @@ func deployMountpointS3CsiDriverWithStorage(stack awscdk.Stack, cluster awseks.C
 
 		// 通过bucket名称自动获取区域
 		s3Region := partition.DefaultPartition
-		if eksInstance.S3BucketName != "" {
+		if *awscdk.Token_IsUnresolved(jsii.String(eksInstance.S3BucketName)) {
+			// 来自 S3 依赖的存储桶与堆栈在同一区域
+			s3Region = *stack.Region()
+		} else if eksInstance.S3BucketName != "" {
 			if region, err := aws.GetBucketRegion(eksInstance.S3BucketName); err == nil {
 				s3Region = region
 			}
@@ -386,14 +401,14 @@ func deployMountpointS3CsiDriverWithStorage(stack awscdk.Stack, cluster awseks.C
 
 // deployDependentStorageCsiDrivers 部署依赖于预创建文件系统的CSI驱动
 // 支持: EFS, FSx Lustre (未来可扩展: FSx ONTAP, FSx OpenZFS, FSx Windows)
-func deployDependentStorageCsiDrivers(stack awscdk.Stack, cluster awseks.Cluster, magicTokenStr string, eksInstance *EksInstanceConfig) []awseks.HelmChart {
+func deployDependentStorageCsiDrivers(stack awscdk.Stack, cluster awseks.Cluster, addons *eksAddons, eksInstance *EksInstanceConfig) []constructs.Construct {
     // 直接从 eksInstance.DependsOn 解析存储类型
     dependsOn := strings.ToUpper(eksInstance.DependsOn)
-    var charts []awseks.HelmChart
+    var charts []constructs.Construct
     
     // 检查是否包含 LUSTRE 前缀
     if strings.Contains(dependsOn, "LUSTRE:") {
-        chart := deployLustreCsiDriver(stack, cluster, magicTokenStr, eksInstance)
+        chart := deployLustreCsiDriver(stack, cluster, addons, eksInstance)
         if chart != nil {
             charts = append(charts, chart)
         }
@@ -401,7 +416,7 @@ func deployDependentStorageCsiDrivers(stack awscdk.Stack, cluster awseks.Cluster
     
     // 检查是否包含 EFS 前缀
     if strings.Contains(dependsOn, "EFS:") {
-        chart := deployEfsCsiDriver(stack, cluster, magicTokenStr, eksInstance)
+        chart := deployEfsCsiDriver(stack, cluster, addons, eksInstance)
         if chart != nil {
             charts = append(charts, chart)
         }
@@ -411,27 +426,27 @@ func deployDependentStorageCsiDrivers(stack awscdk.Stack, cluster awseks.Cluster
 }
 
 // 部署 FSx Lustre CSI 驱动和 StorageClass
-func deployLustreCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, magicTokenStr string, eksInstance *EksInstanceConfig) awseks.HelmChart {
+func deployLustreCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, addons *eksAddons, eksInstance *EksInstanceConfig) constructs.Construct {
 	// 如果未指定版本，不部署
 	if eksInstance.FsxCsiDriverVersion == "" {
 		return nil
 	}
 
-	// 使用通用函数获取Lustre依赖
-	lustreProperties, err := dependency.ExtractDependencyProperties(magicTokenStr, "LUSTRE")
+	// 获取Lustre依赖
+	lustre, err := dependency.ResolveFileSystem(eksInstance.DependsOn, "LUSTRE")
 	if err != nil {
-		fmt.Printf("Warning: failed to extract Lustre dependencies: %v\n", err)
+		fmt.Printf("Warning: failed to resolve Lustre dependency: %v\n", err)
 		return nil
 	}
 
 	// 提取Lustre配置信息
-	lustreFileSystemId, _ := lustreProperties["fileSystemId"].(string)
-	lustreMountName, _ := lustreProperties["mountName"].(string)
-	lustreDnsName, _ := lustreProperties["dnsName"].(string)
+	lustreFileSystemId := lustre.FileSystemId
+	lustreMountName := lustre.MountName
+	lustreDnsName := lustre.DnsName
 	
 	var lustreStorageCapacityGiB int = 1200 // 默认值
-	if storageCapacity, ok := lustreProperties["storageCapacityGiB"].(float64); ok {
-		lustreStorageCapacityGiB = int(storageCapacity)
+	if lustre.StorageCapacityGiB > 0 {
+		lustreStorageCapacityGiB = lustre.StorageCapacityGiB
 	}
 
 	// 如果没有提供 dnsName，构造一个
@@ -468,8 +483,9 @@ func deployLustreCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, magicToke
 	})
 
 	// 使用通用函数部署CSI Driver
-	csiChart := deployGenericCsiDriver(stack, cluster, &csiDriverConfig{
+	csiChart := deployGenericCsiDriver(stack, cluster, addons, &csiDriverConfig{
 		name:               "fsx",
+		addon:              "fsxCsiDriver",
 		chartId:            "fsx-csi-driver",
 		serviceAccountName: "fsx-csi-controller-sa",
 		chartName:          "aws-fsx-csi-driver",
@@ -553,6 +569,50 @@ func deployLustreCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, magicToke
 			// 确保 PV 在 StorageClass 创建后创建
 			pvManifest.Node().AddDependency(scManifest)
 
+			// 为每个数据仓库关联路径创建独立的 PV，挂载 Lustre 子目录
+			for _, repo := range lustre.DataRepositories {
+				fileSystemPath := repo.FileSystemPath
+				if fileSystemPath == "" || fileSystemPath == "/" {
+					continue
+				}
+
+				pathName := strings.ToLower(strings.ReplaceAll(strings.Trim(fileSystemPath, "/"), "/", "-"))
+				pathName = strings.ReplaceAll(pathName, "_", "-")
+				repoPvName := fmt.Sprintf("%s-%s-pv", storageClassName, pathName)
+				repoPvObj := map[string]interface{}{
+					"apiVersion": "v1",
+					"kind": "PersistentVolume",
+					"metadata": map[string]interface{}{
+						"name": repoPvName,
+						"labels": map[string]interface{}{
+							"fsx.infraforge.aws/path": pathName,
+						},
+					},
+					"spec": map[string]interface{}{
+						"capacity": map[string]interface{}{
+							"storage": fmt.Sprintf("%dGi", lustreStorageCapacityGiB),
+						},
+						"volumeMode": "Filesystem",
+						"accessModes": []string{"ReadWriteMany"},
+						"persistentVolumeReclaimPolicy": "Retain",
+						"storageClassName": storageClassName,
+						"mountOptions": []string{"flock"},
+						"csi": map[string]interface{}{
+							"driver": "fsx.csi.aws.com",
+							"volumeHandle": fmt.Sprintf("%s-%s", lustreFileSystemId, pathName),
+							"volumeAttributes": map[string]interface{}{
+								"fileSystemId": lustreFileSystemId,
+								"mountname": lustreMountName + fileSystemPath,  // Lustre 支持挂载子目录
+								"dnsname": lustreDnsName,
+							},
+						},
+					},
+				}
+
+				repoPvManifest := cluster.AddManifest(jsii.String(repoPvName), &repoPvObj)
+				repoPvManifest.Node().AddDependency(scManifest)
+			}
+
 			// 如果需要创建默认 PVC
 			if types.GetBoolValue(eksInstance.CreateDefaultPVC, false) {
 				// 设置默认命名空间
@@ -595,21 +655,21 @@ func deployLustreCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, magicToke
 
 
 // 部署 EFS CSI 驱动和 StorageClass
-func deployEfsCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, magicTokenStr string, eksInstance *EksInstanceConfig) awseks.HelmChart {
+func deployEfsCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, addons *eksAddons, eksInstance *EksInstanceConfig) constructs.Construct {
 	// 如果未指定版本，不部署
 	if eksInstance.EfsCsiDriverVersion == "" {
 		return nil
 	}
 
-	// 使用通用函数获取EFS依赖
-	efsProperties, err := dependency.ExtractDependencyProperties(magicTokenStr, "EFS")
+	// 获取EFS依赖
+	efs, err := dependency.ResolveFileSystem(eksInstance.DependsOn, "EFS")
 	if err != nil {
-		fmt.Printf("Warning: failed to extract EFS dependencies: %v\n", err)
+		fmt.Printf("Warning: failed to resolve EFS dependency: %v\n", err)
 		return nil
 	}
 
 	// 提取EFS配置信息
-	efsFileSystemId, _ := efsProperties["fileSystemId"].(string)
+	efsFileSystemId := efs.FileSystemId
 
 	if efsFileSystemId == "" {
 		fmt.Printf("Warning: EFS file system ID not found\n")
@@ -620,8 +680,9 @@ func deployEfsCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, magicTokenSt
 	version := eksInstance.EfsCsiDriverVersion
 
 	// 使用通用函数部署CSI Driver
-	csiChart := deployGenericCsiDriver(stack, cluster, &csiDriverConfig{
+	csiChart := deployGenericCsiDriver(stack, cluster, addons, &csiDriverConfig{
 		name:               "efs",
+		addon:              "efsCsiDriver",
 		chartId:            "efs-csi-driver",
 		serviceAccountName: "efs-csi-controller-sa",
 		chartName:          "aws-efs-csi-driver",
@@ -662,6 +723,10 @@ func deployEfsCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, magicTokenSt
 			"reclaimPolicy": "Delete",
 			"volumeBindingMode": "Immediate",
 		}
+		// EFS 禁止匿名访问时节点需要通过 IAM 授权挂载
+		if efs.IamRequired {
+			storageClassObj["mountOptions"] = []string{"tls", "iam"}
+		}
 
 		// 添加 StorageClass 清单
 		scManifest := cluster.AddManifest(jsii.String(fmt.Sprintf("%s-storage-class", storageClassName)), &storageClassObj)
@@ -692,6 +757,9 @@ func deployEfsCsiDriver(stack awscdk.Stack, cluster awseks.Cluster, magicTokenSt
 					},
 				},
 			}
+			if efs.IamRequired {
+				pvObj["spec"].(map[string]interface{})["mountOptions"] = []string{"tls", "iam"}
+			}
 
 			// 添加 PV 清单
 			pvManifest := cluster.AddManifest(jsii.String(pvName), &pvObj)
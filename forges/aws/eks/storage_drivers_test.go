@@ -0,0 +1,49 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/dependency"
+)
+
+type testBucketForge struct{}
+
+func (f *testBucketForge) FileSystem() dependency.FileSystemInfo {
+	return dependency.FileSystemInfo{Type: "S3", FileSystemId: "eks-datasets", Protocol: "s3"}
+}
+
+func TestS3BucketFromDependsOn(t *testing.T) {
+	dependency.GlobalManager.Store("S3:eks-datasets", &testBucketForge{})
+	forge := &EksForge{}
+
+	merged := forge.MergeConfigs(&EksInstanceConfig{EksVersion: "1.31"}, &EksInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "eks"},
+		DependsOn:          "S3:eks-datasets",
+	}).(*EksInstanceConfig)
+	if merged.S3BucketName != "eks-datasets" {
+		t.Errorf("Expected s3BucketName from the S3 dependency, got %q", merged.S3BucketName)
+	}
+
+	// 显式配置的 s3BucketName 优先
+	merged = forge.MergeConfigs(&EksInstanceConfig{EksVersion: "1.31"}, &EksInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "eks"},
+		DependsOn:          "S3:eks-datasets",
+		S3BucketName:       "models",
+	}).(*EksInstanceConfig)
+	if merged.S3BucketName != "models" {
+		t.Errorf("Expected explicit s3BucketName to win, got %q", merged.S3BucketName)
+	}
+
+	merged = forge.MergeConfigs(&EksInstanceConfig{EksVersion: "1.31"}, &EksInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "eks"},
+		DependsOn:          "S3:missing",
+	}).(*EksInstanceConfig)
+	if err := merged.Validate(); err == nil || !strings.Contains(err.Error(), "S3 bucket in dependsOn") {
+		t.Errorf("Expected unresolved S3 dependency to fail validation, got %v", err)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 分阶段升级的阶段，按顺序每次部署推进一个阶段
+const (
+	UpgradeStageControlPlane = "controlPlane" // 只升级控制平面，插件、节点组和 Karpenter 节点保持 fromVersion
+	UpgradeStageAddons       = "addons"       // 托管插件按 eksVersion 解析版本
+	UpgradeStageNodeGroup    = "nodeGroup"    // 创建 eksVersion 的新托管节点组，旧节点组保留用于排空
+	UpgradeStageKarpenter    = "karpenter"    // 删除旧节点组，Karpenter 节点通过 AMI 漂移替换，升级完成
+)
+
+var upgradeStages = []string{UpgradeStageControlPlane, UpgradeStageAddons, UpgradeStageNodeGroup, UpgradeStageKarpenter}
+
+// 升级预检发现的严重级别
+const (
+	UpgradeSeverityError   = "ERROR"
+	UpgradeSeverityWarning = "WARNING"
+)
+
+// EksUpgradeConfig 集群升级配置
+type EksUpgradeConfig struct {
+	FromVersion string `json:"fromVersion,omitempty"` // 当前控制平面版本，与 eksVersion 不同时表示正在升级
+	Stage       string `json:"stage,omitempty"`       // controlPlane、addons、nodeGroup、karpenter，留空一次完成所有阶段
+}
+
+// UpgradeFinding 升级预检发现的问题
+type UpgradeFinding struct {
+	Severity  string `json:"severity"`
+	Component string `json:"component"`
+	Message   string `json:"message"`
+}
+
+// kubectl Layer 支持的 Kubernetes 次版本范围，与 utils.GetKubectlLayer 一致
+const (
+	minKubectlMinor = 21
+	maxKubectlMinor = 34
+)
+
+// karpenterMinVersions 各 Kubernetes 次版本要求的最低 Karpenter 版本（https://karpenter.sh/docs/upgrading/compatibility/）
+var karpenterMinVersions = map[int]string{
+	29: "0.34.0",
+	30: "0.37.0",
+	31: "1.0.5",
+	32: "1.2.0",
+	33: "1.5.0",
+	34: "1.6.0",
+}
+
+// istioKubernetesRanges 各 Istio 次版本测试过的 Kubernetes 次版本范围
+var istioKubernetesRanges = map[int][2]int{
+	22: {27, 30},
+	23: {27, 30},
+	24: {28, 31},
+	25: {29, 32},
+	26: {29, 33},
+}
+
+// IsUpgrading 是否正在从 fromVersion 升级到 eksVersion
+func (c *EksInstanceConfig) IsUpgrading() bool {
+	return c.Upgrade.FromVersion != "" && c.Upgrade.FromVersion != c.EksVersion
+}
+
+// upgradeStage 返回当前升级阶段，未升级或未指定阶段时为 karpenter（全部使用 eksVersion）
+func (c *EksInstanceConfig) upgradeStage() string {
+	stage := c.upgradeStageName()
+	if !c.IsUpgrading() || stageIndex(stage) < 0 {
+		return UpgradeStageKarpenter
+	}
+	return stage
+}
+
+// upgradeStageReached 当前阶段是否已到达 stage
+func (c *EksInstanceConfig) upgradeStageReached(stage string) bool {
+	return stageIndex(c.upgradeStage()) >= stageIndex(stage)
+}
+
+func stageIndex(stage string) int {
+	for i, s := range upgradeStages {
+		if s == stage {
+			return i
+		}
+	}
+	return -1
+}
+
+// addonKubernetesVersion 托管插件解析 latest 版本时使用的 Kubernetes 版本
+func (c *EksInstanceConfig) addonKubernetesVersion() string {
+	if c.upgradeStageReached(UpgradeStageAddons) {
+		return c.EksVersion
+	}
+	return c.Upgrade.FromVersion
+}
+
+// nodeGroupVersions 需要保留的托管节点组版本，nodeGroup 阶段新旧节点组同时存在
+func (c *EksInstanceConfig) nodeGroupVersions() []string {
+	switch c.upgradeStage() {
+	case UpgradeStageControlPlane, UpgradeStageAddons:
+		return []string{c.Upgrade.FromVersion}
+	case UpgradeStageNodeGroup:
+		return []string{c.Upgrade.FromVersion, c.EksVersion}
+	default:
+		return []string{c.EksVersion}
+	}
+}
+
+// karpenterKubernetesVersion Karpenter EC2NodeClass 选择 AMI 使用的 Kubernetes 版本，变化后节点通过漂移替换
+func (c *EksInstanceConfig) karpenterKubernetesVersion() string {
+	if c.upgradeStageReached(UpgradeStageKarpenter) {
+		return c.EksVersion
+	}
+	return c.Upgrade.FromVersion
+}
+
+// validateUpgrade 返回第一个 ERROR 级别的预检发现
+func (c *EksInstanceConfig) validateUpgrade() error {
+	for _, finding := range c.UpgradePreflight() {
+		if finding.Severity == UpgradeSeverityError {
+			return fmt.Errorf("upgrade preflight (%s): %s", finding.Component, finding.Message)
+		}
+	}
+	return nil
+}
+
+// UpgradePreflight 检查版本差异、kubectl Layer、固定的 Helm Chart 版本和托管插件版本与目标 Kubernetes 版本的兼容性
+func (c *EksInstanceConfig) UpgradePreflight() []UpgradeFinding {
+	var findings []UpgradeFinding
+	add := func(severity string, component string, format string, args ...interface{}) {
+		findings = append(findings, UpgradeFinding{Severity: severity, Component: component, Message: fmt.Sprintf(format, args...)})
+	}
+
+	target, ok := kubernetesMinor(c.EksVersion)
+	if !ok {
+		// latest 等非数字版本无法检查
+		return findings
+	}
+	if target < minKubectlMinor || target > maxKubectlMinor {
+		add(UpgradeSeverityError, "kubectl", "no kubectl layer for Kubernetes %s, supported versions are 1.%d to 1.%d", c.EksVersion, minKubectlMinor, maxKubectlMinor)
+	}
+
+	if c.Upgrade.Stage != "" && stageIndex(c.upgradeStageName()) < 0 {
+		add(UpgradeSeverityError, "upgrade", "unknown stage '%s', expected one of %s", c.Upgrade.Stage, strings.Join(upgradeStages, ", "))
+	}
+	if c.Upgrade.Stage != "" && c.Upgrade.FromVersion == "" {
+		add(UpgradeSeverityError, "upgrade", "stage requires upgrade.fromVersion")
+	}
+	if c.IsUpgrading() {
+		from, ok := kubernetesMinor(c.Upgrade.FromVersion)
+		switch {
+		case !ok:
+			add(UpgradeSeverityError, "controlPlane", "fromVersion '%s' is not a Kubernetes version such as 1.31", c.Upgrade.FromVersion)
+		case target < from:
+			add(UpgradeSeverityError, "controlPlane", "downgrading from %s to %s is not supported", c.Upgrade.FromVersion, c.EksVersion)
+		case target-from > 1:
+			add(UpgradeSeverityError, "controlPlane", "EKS upgrades one minor version at a time, upgrade %s to 1.%d first", c.Upgrade.FromVersion, from+1)
+		}
+		if c.Upgrade.Stage == "" {
+			add(UpgradeSeverityWarning, "upgrade", "no stage set, the control plane, add-ons, node group and Karpenter nodes are upgraded in a single deployment")
+		}
+	}
+
+	// 控制平面升级后 Karpenter 控制器即运行在新版本上，不兼容时阻止升级
+	if karpenter, ok := parseSemver(c.KarpenterVersion); ok {
+		if required, ok := karpenterMinVersions[target]; ok {
+			min, _ := parseSemver(required)
+			if compareSemver(karpenter, min) < 0 {
+				add(UpgradeSeverityError, "karpenter", "karpenterVersion %s does not support Kubernetes %s, %s or later is required", c.KarpenterVersion, c.EksVersion, required)
+			}
+		}
+	}
+
+	if istio, ok := parseSemver(c.IstioVersion); ok {
+		if supported, ok := istioKubernetesRanges[istio[1]]; ok && (target < supported[0] || target > supported[1]) {
+			add(UpgradeSeverityWarning, "istio", "istioVersion %s is tested with Kubernetes 1.%d to 1.%d, not %s", c.IstioVersion, supported[0], supported[1], c.EksVersion)
+		}
+	}
+
+	// 固定的托管插件版本只适用于特定 Kubernetes 版本
+	if c.IsUpgrading() {
+		for _, component := range addonComponents() {
+			addon, ok := c.Addons[component]
+			if !ok || c.AddonModeFor(component) != AddonModeManaged || !addonVersionPattern.MatchString(addon.Version) {
+				continue
+			}
+			add(UpgradeSeverityWarning, component, "add-on version %s is pinned, check it supports Kubernetes %s with aws eks describe-addon-versions --addon-name %s --kubernetes-version %s, or use latest",
+				addon.Version, c.EksVersion, eksAddonSpecs[component].addonName, c.EksVersion)
+		}
+	}
+	return findings
+}
+
+// upgradeStageName 返回配置中阶段的规范名称，未知阶段原样返回
+func (c *EksInstanceConfig) upgradeStageName() string {
+	for _, stage := range upgradeStages {
+		if strings.EqualFold(stage, c.Upgrade.Stage) {
+			return stage
+		}
+	}
+	return c.Upgrade.Stage
+}
+
+// kubernetesMinor 解析 1.31 形式的版本，返回次版本号
+func kubernetesMinor(version string) (int, bool) {
+	parts := strings.Split(strings.TrimPrefix(version, "v"), ".")
+	if len(parts) < 2 || parts[0] != "1" {
+		return 0, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, false
+	}
+	return minor, true
+}
+
+// parseSemver 解析 1.2.3 或 v1.2 形式的版本
+func parseSemver(version string) ([3]int, bool) {
+	var result [3]int
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return result, false
+	}
+	for i, part := range parts {
+		// 忽略 -rc.1 等后缀
+		part = strings.SplitN(part, "-", 2)[0]
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return result, false
+		}
+		result[i] = n
+	}
+	return result, true
+}
+
+func compareSemver(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
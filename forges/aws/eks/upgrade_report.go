@@ -0,0 +1,286 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+)
+
+// UpgradeStep 升级计划中的一个阶段
+type UpgradeStep struct {
+	Stage       string `json:"stage"`
+	Status      string `json:"status"` // done、current、pending
+	Description string `json:"description"`
+}
+
+// DeprecatedApiFinding Forge 部署的清单中使用的已弃用 API
+type DeprecatedApiFinding struct {
+	Severity   string `json:"severity"` // 目标版本中已移除为 ERROR，之后的版本中移除为 WARNING
+	Resource   string `json:"resource"` // 模板中清单资源的逻辑 ID
+	ApiVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	RemovedIn  string `json:"removedIn"`
+}
+
+// UpgradePlan 单个 EKS 集群的升级计划和预检报告
+type UpgradePlan struct {
+	Cluster        string                 `json:"cluster"`
+	FromVersion    string                 `json:"fromVersion,omitempty"`
+	ToVersion      string                 `json:"toVersion"`
+	Steps          []UpgradeStep          `json:"steps"`
+	Findings       []UpgradeFinding       `json:"findings"`
+	DeprecatedApis []DeprecatedApiFinding `json:"deprecatedApis"`
+
+	clusterLogicalId string // 模板中集群资源的逻辑 ID，用于匹配清单资源
+}
+
+var (
+	upgradePlans   []*UpgradePlan
+	upgradePlansMu sync.Mutex
+)
+
+// RegisteredUpgradePlans 返回构建堆栈时各 EKS Forge 生成的升级计划
+func RegisteredUpgradePlans() []*UpgradePlan {
+	upgradePlansMu.Lock()
+	defer upgradePlansMu.Unlock()
+	return append([]*UpgradePlan(nil), upgradePlans...)
+}
+
+func registerUpgradePlan(plan *UpgradePlan) {
+	upgradePlansMu.Lock()
+	defer upgradePlansMu.Unlock()
+	upgradePlans = append(upgradePlans, plan)
+}
+
+// BuildUpgradePlan 根据升级配置生成分阶段计划和预检结果
+func (c *EksInstanceConfig) BuildUpgradePlan() *UpgradePlan {
+	plan := &UpgradePlan{
+		Cluster:        c.GetID(),
+		ToVersion:      c.EksVersion,
+		Steps:          []UpgradeStep{},
+		Findings:       c.UpgradePreflight(),
+		DeprecatedApis: []DeprecatedApiFinding{},
+	}
+	if plan.Findings == nil {
+		plan.Findings = []UpgradeFinding{}
+	}
+	if !c.IsUpgrading() {
+		return plan
+	}
+	plan.FromVersion = c.Upgrade.FromVersion
+
+	descriptions := map[string]string{
+		UpgradeStageControlPlane: fmt.Sprintf("Upgrade the control plane and kubectl layer to %s, everything else stays on %s", c.EksVersion, c.Upgrade.FromVersion),
+		UpgradeStageAddons:       fmt.Sprintf("Resolve managed add-on versions for %s", c.EksVersion),
+		UpgradeStageNodeGroup:    fmt.Sprintf("Create the %s managed node group next to the %s one, then cordon and drain the old nodes", c.EksVersion, c.Upgrade.FromVersion),
+		UpgradeStageKarpenter:    fmt.Sprintf("Remove the %s node group and switch Karpenter AMIs to %s so nodes are replaced through drift", c.Upgrade.FromVersion, c.EksVersion),
+	}
+	current := stageIndex(c.upgradeStage())
+	for i, stage := range upgradeStages {
+		status := "pending"
+		switch {
+		case c.Upgrade.Stage == "" || i < current:
+			status = "done"
+		case i == current:
+			status = "current"
+		}
+		plan.Steps = append(plan.Steps, UpgradeStep{Stage: stage, Status: status, Description: descriptions[stage]})
+	}
+	return plan
+}
+
+// deprecatedApi 在指定 Kubernetes 版本中移除的 API，kind 为空表示整个 apiVersion
+type deprecatedApi struct {
+	apiVersion string
+	kind       string
+	removedIn  int
+}
+
+// deprecatedApis https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var deprecatedApis = []deprecatedApi{
+	{"admissionregistration.k8s.io/v1beta1", "", 22},
+	{"apiextensions.k8s.io/v1beta1", "", 22},
+	{"apiregistration.k8s.io/v1beta1", "", 22},
+	{"authentication.k8s.io/v1beta1", "", 22},
+	{"authorization.k8s.io/v1beta1", "", 22},
+	{"certificates.k8s.io/v1beta1", "", 22},
+	{"coordination.k8s.io/v1beta1", "", 22},
+	{"extensions/v1beta1", "", 22},
+	{"networking.k8s.io/v1beta1", "", 22},
+	{"rbac.authorization.k8s.io/v1beta1", "", 22},
+	{"scheduling.k8s.io/v1beta1", "", 22},
+	{"storage.k8s.io/v1beta1", "CSIDriver", 22},
+	{"storage.k8s.io/v1beta1", "CSINode", 22},
+	{"storage.k8s.io/v1beta1", "StorageClass", 22},
+	{"storage.k8s.io/v1beta1", "VolumeAttachment", 22},
+	{"batch/v1beta1", "", 25},
+	{"discovery.k8s.io/v1beta1", "", 25},
+	{"events.k8s.io/v1beta1", "", 25},
+	{"autoscaling/v2beta1", "", 25},
+	{"policy/v1beta1", "", 25},
+	{"node.k8s.io/v1beta1", "", 25},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "", 26},
+	{"autoscaling/v2beta2", "", 26},
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", 27},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "", 29},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "", 32},
+}
+
+// ScanTemplate 检查模板中属于该集群的 Kubernetes 清单是否使用了已弃用的 API
+// Helm Chart 在部署时渲染，不在扫描范围内
+func (p *UpgradePlan) ScanTemplate(template map[string]interface{}) {
+	target, ok := kubernetesMinor(p.ToVersion)
+	if !ok || p.clusterLogicalId == "" {
+		return
+	}
+	resources, _ := template["Resources"].(map[string]interface{})
+	logicalIds := make([]string, 0, len(resources))
+	for logicalId := range resources {
+		logicalIds = append(logicalIds, logicalId)
+	}
+	sort.Strings(logicalIds)
+
+	for _, logicalId := range logicalIds {
+		resource, _ := resources[logicalId].(map[string]interface{})
+		if resource["Type"] != "Custom::AWSCDK-EKS-KubernetesResource" {
+			continue
+		}
+		properties, _ := resource["Properties"].(map[string]interface{})
+		if ref, _ := properties["ClusterName"].(map[string]interface{}); ref["Ref"] != p.clusterLogicalId {
+			continue
+		}
+		var documents []map[string]interface{}
+		if err := json.Unmarshal([]byte(flattenManifest(properties["Manifest"])), &documents); err != nil {
+			fmt.Printf("Warning: unable to parse manifest %s: %v\n", logicalId, err)
+			continue
+		}
+		for _, document := range documents {
+			apiVersion, _ := document["apiVersion"].(string)
+			kind, _ := document["kind"].(string)
+			metadata, _ := document["metadata"].(map[string]interface{})
+			name, _ := metadata["name"].(string)
+			for _, api := range deprecatedApis {
+				if api.apiVersion != apiVersion || (api.kind != "" && api.kind != kind) {
+					continue
+				}
+				severity := UpgradeSeverityWarning
+				if api.removedIn <= target {
+					severity = UpgradeSeverityError
+				}
+				p.DeprecatedApis = append(p.DeprecatedApis, DeprecatedApiFinding{
+					Severity:   severity,
+					Resource:   logicalId,
+					ApiVersion: apiVersion,
+					Kind:       kind,
+					Name:       name,
+					RemovedIn:  fmt.Sprintf("1.%d", api.removedIn),
+				})
+				break
+			}
+		}
+	}
+}
+
+// flattenManifest 将包含 Token 的 Fn::Join 清单还原为 JSON 字符串，Token 以占位符代替
+func flattenManifest(manifest interface{}) string {
+	switch value := manifest.(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		join, _ := value["Fn::Join"].([]interface{})
+		if len(join) != 2 {
+			return ""
+		}
+		parts, _ := join[1].([]interface{})
+		var sb strings.Builder
+		for _, part := range parts {
+			if s, ok := part.(string); ok {
+				sb.WriteString(s)
+			} else {
+				sb.WriteString("TOKEN")
+			}
+		}
+		return sb.String()
+	}
+	return ""
+}
+
+// HasErrors 计划中是否存在 ERROR 级别的预检或已移除的 API
+func (p *UpgradePlan) HasErrors() bool {
+	for _, finding := range p.Findings {
+		if finding.Severity == UpgradeSeverityError {
+			return true
+		}
+	}
+	for _, finding := range p.DeprecatedApis {
+		if finding.Severity == UpgradeSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteUpgradeReport 按指定格式（table、json）输出升级计划
+func WriteUpgradeReport(w io.Writer, plans []*UpgradePlan, format string) error {
+	switch format {
+	case "", "table":
+		return writeUpgradeTable(w, plans)
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plans)
+	default:
+		return fmt.Errorf("unsupported upgrade report format '%s', expected table or json", format)
+	}
+}
+
+func writeUpgradeTable(w io.Writer, plans []*UpgradePlan) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if len(plans) == 0 {
+		fmt.Fprintln(tw, "No EKS clusters found.")
+	}
+	for _, plan := range plans {
+		if plan.FromVersion != "" {
+			fmt.Fprintf(tw, "EKS Cluster: %s (%s -> %s)\n", plan.Cluster, plan.FromVersion, plan.ToVersion)
+			fmt.Fprintln(tw, "STAGE\tSTATUS\tDESCRIPTION")
+			for _, step := range plan.Steps {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", step.Stage, step.Status, step.Description)
+			}
+		} else {
+			fmt.Fprintf(tw, "EKS Cluster: %s (%s, no upgrade in progress)\n", plan.Cluster, plan.ToVersion)
+		}
+		fmt.Fprintln(tw)
+
+		if len(plan.Findings) > 0 {
+			fmt.Fprintln(tw, "Preflight:")
+			fmt.Fprintln(tw, "SEVERITY\tCOMPONENT\tMESSAGE")
+			for _, finding := range plan.Findings {
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", finding.Severity, finding.Component, finding.Message)
+			}
+		} else {
+			fmt.Fprintln(tw, "Preflight: no issues found.")
+		}
+		fmt.Fprintln(tw)
+
+		if len(plan.DeprecatedApis) > 0 {
+			fmt.Fprintln(tw, "Deprecated APIs:")
+			fmt.Fprintln(tw, "SEVERITY\tAPI VERSION\tKIND\tNAME\tREMOVED IN\tRESOURCE")
+			for _, finding := range plan.DeprecatedApis {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					finding.Severity, finding.ApiVersion, finding.Kind, finding.Name, finding.RemovedIn, finding.Resource)
+			}
+		} else {
+			fmt.Fprintln(tw, "Deprecated APIs: none found in deployed manifests (Helm charts are not scanned).")
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
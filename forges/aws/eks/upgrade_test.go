@@ -0,0 +1,115 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package eks
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUpgradeStageVersions(t *testing.T) {
+	tests := []struct {
+		stage      string
+		addon      string
+		nodeGroups []string
+		karpenter  string
+	}{
+		{"controlPlane", "1.32", []string{"1.32"}, "1.32"},
+		{"addons", "1.33", []string{"1.32"}, "1.32"},
+		{"nodeGroup", "1.33", []string{"1.32", "1.33"}, "1.32"},
+		{"karpenter", "1.33", []string{"1.33"}, "1.33"},
+		{"", "1.33", []string{"1.33"}, "1.33"},
+	}
+	for _, tt := range tests {
+		c := EksInstanceConfig{EksVersion: "1.33", Upgrade: EksUpgradeConfig{FromVersion: "1.32", Stage: tt.stage}}
+		if got := c.addonKubernetesVersion(); got != tt.addon {
+			t.Errorf("stage %q: expected add-on version %s, got %s", tt.stage, tt.addon, got)
+		}
+		if got := c.nodeGroupVersions(); !reflect.DeepEqual(got, tt.nodeGroups) {
+			t.Errorf("stage %q: expected node groups %v, got %v", tt.stage, tt.nodeGroups, got)
+		}
+		if got := c.karpenterKubernetesVersion(); got != tt.karpenter {
+			t.Errorf("stage %q: expected Karpenter version %s, got %s", tt.stage, tt.karpenter, got)
+		}
+	}
+
+	c := EksInstanceConfig{EksVersion: "1.33"}
+	if c.IsUpgrading() || !reflect.DeepEqual(c.nodeGroupVersions(), []string{"1.33"}) {
+		t.Error("Expected a single node group on eksVersion without an upgrade")
+	}
+}
+
+func TestUpgradePreflight(t *testing.T) {
+	tests := []struct {
+		name   string
+		config EksInstanceConfig
+		want   string
+	}{
+		{"valid", EksInstanceConfig{EksVersion: "1.33", KarpenterVersion: "1.7.1", Upgrade: EksUpgradeConfig{FromVersion: "1.32", Stage: "controlPlane"}}, ""},
+		{"skip minor", EksInstanceConfig{EksVersion: "1.33", Upgrade: EksUpgradeConfig{FromVersion: "1.31", Stage: "controlPlane"}}, "upgrade 1.31 to 1.32 first"},
+		{"downgrade", EksInstanceConfig{EksVersion: "1.31", Upgrade: EksUpgradeConfig{FromVersion: "1.32", Stage: "controlPlane"}}, "downgrading"},
+		{"stage", EksInstanceConfig{EksVersion: "1.33", Upgrade: EksUpgradeConfig{FromVersion: "1.32", Stage: "workers"}}, "unknown stage"},
+		{"kubectl", EksInstanceConfig{EksVersion: "1.40"}, "no kubectl layer"},
+		{"karpenter", EksInstanceConfig{EksVersion: "1.33", KarpenterVersion: "1.3.0"}, "1.5.0 or later"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validateUpgrade()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("Expected valid config, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+
+	c := EksInstanceConfig{EksVersion: "1.33", IstioVersion: "1.24.0", Upgrade: EksUpgradeConfig{FromVersion: "1.32"}}
+	findings := c.UpgradePreflight()
+	if len(findings) != 2 || findings[0].Component != "upgrade" || findings[1].Component != "istio" {
+		t.Errorf("Expected stage and istio warnings, got %v", findings)
+	}
+}
+
+func TestUpgradePlanScanTemplate(t *testing.T) {
+	c := EksInstanceConfig{EksVersion: "1.33", Upgrade: EksUpgradeConfig{FromVersion: "1.32", Stage: "addons"}}
+	plan := c.BuildUpgradePlan()
+	if len(plan.Steps) != 4 || plan.Steps[0].Status != "done" || plan.Steps[1].Status != "current" || plan.Steps[2].Status != "pending" {
+		t.Errorf("Unexpected steps %v", plan.Steps)
+	}
+
+	plan.clusterLogicalId = "Cluster"
+	plan.ScanTemplate(map[string]interface{}{
+		"Resources": map[string]interface{}{
+			"Manifest": map[string]interface{}{
+				"Type": "Custom::AWSCDK-EKS-KubernetesResource",
+				"Properties": map[string]interface{}{
+					"ClusterName": map[string]interface{}{"Ref": "Cluster"},
+					"Manifest": map[string]interface{}{"Fn::Join": []interface{}{"", []interface{}{
+						`[{"apiVersion":"policy/v1beta1","kind":"PodDisruptionBudget","metadata":{"name":"`,
+						map[string]interface{}{"Ref": "Name"},
+						`"}},{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c"}}]`,
+					}}},
+				},
+			},
+			"OtherCluster": map[string]interface{}{
+				"Type": "Custom::AWSCDK-EKS-KubernetesResource",
+				"Properties": map[string]interface{}{
+					"ClusterName": map[string]interface{}{"Ref": "Other"},
+					"Manifest":    `[{"apiVersion":"batch/v1beta1","kind":"CronJob","metadata":{"name":"c"}}]`,
+				},
+			},
+		},
+	})
+	if len(plan.DeprecatedApis) != 1 || plan.DeprecatedApis[0].Kind != "PodDisruptionBudget" || plan.DeprecatedApis[0].Severity != UpgradeSeverityError {
+		t.Errorf("Expected one removed PodDisruptionBudget, got %v", plan.DeprecatedApis)
+	}
+	if !plan.HasErrors() {
+		t.Error("Expected removed APIs to fail the plan")
+	}
+}
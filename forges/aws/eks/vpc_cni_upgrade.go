@@ -80,11 +80,15 @@ func upgradeVpcCniAndConfigureMultiNic(scope constructs.Construct, id string, pr
 }
 
 // needsMultiNicSupport 检查是否需要 Multi-NIC 支持
-func needsMultiNicSupport(nodePoolProps *KarpenterNodePoolProps) bool {
-	instanceTypesConfigs := []string{
-		nodePoolProps.GpuInstanceTypes,
-		nodePoolProps.CpuInstanceTypes,
-		nodePoolProps.NeuronInstanceTypes,
+func needsMultiNicSupport(nodePools []KarpenterNodePoolConfig) bool {
+	var instanceTypesConfigs []string
+	for _, pool := range nodePools {
+		instanceTypesConfigs = append(instanceTypesConfigs, pool.InstanceTypes...)
+		for _, requirement := range pool.Requirements {
+			if requirement.Key == "node.kubernetes.io/instance-type" && requirement.Operator == "In" {
+				instanceTypesConfigs = append(instanceTypesConfigs, requirement.Values...)
+			}
+		}
 	}
 	
 	// 检查是否包含支持多网卡的实例类型
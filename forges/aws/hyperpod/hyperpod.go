@@ -11,6 +11,7 @@ import (
 	"github.com/awslabs/InfraForge/core/interfaces"
 	"github.com/awslabs/InfraForge/core/partition"
 	"github.com/awslabs/InfraForge/core/utils/aws"
+	"github.com/awslabs/InfraForge/forges/aws/storage/utils"
 
 	"github.com/aws/aws-cdk-go/awscdk/v2"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
@@ -66,6 +67,35 @@ type HyperPodInstanceConfig struct {
 	LustreThroughput         int    `json:"lustreThroughput,omitempty"`         // Per-unit storage throughput (e.g., "LUSTRE:lustre1")
 }
 
+// lustreSpec returns the FSx Lustre settings for createLustre with defaults applied.
+// HyperPod provisions PERSISTENT_2 SSD file systems.
+func (c *HyperPodInstanceConfig) lustreSpec() utils.LustreSpec {
+	spec := utils.LustreSpec{
+		DeploymentType:           "PERSISTENT_2",
+		StorageType:              "SSD",
+		StorageCapacityGiB:       c.LustreStorageCapacity,
+		PerUnitStorageThroughput: float64(c.LustreThroughput),
+	}
+	if spec.StorageCapacityGiB == 0 {
+		spec.StorageCapacityGiB = 1200 // Default
+	}
+	if spec.PerUnitStorageThroughput == 0 {
+		spec.PerUnitStorageThroughput = 250 // Default
+	}
+	return spec
+}
+
+// Validate checks the createLustre capacity and throughput against the FSx rules
+func (c *HyperPodInstanceConfig) Validate() error {
+	if c.CreateLustre == nil || !*c.CreateLustre {
+		return nil
+	}
+	if _, err := utils.ValidateLustreSpec(c.lustreSpec()); err != nil {
+		return fmt.Errorf("hyperpod %s: lustreStorageCapacity/lustreThroughput: %w", c.GetID(), err)
+	}
+	return nil
+}
+
 type HyperPodForge struct {
 	cluster awssagemaker.CfnCluster
 }
@@ -188,14 +218,12 @@ func (h *HyperPodForge) createHyperPodCluster(hyperPodInstance *HyperPodInstance
 	
 	// First try to get EKS ARN from dependency
 	if hyperPodInstance.DependsOn != "" {
-		magicToken, err := dependency.GetDependencyInfo(hyperPodInstance.DependsOn)
+		clusters, err := dependency.ResolveAll[dependency.ClusterProvider](hyperPodInstance.DependsOn)
 		if err == nil {
-			properties, err := dependency.ExtractDependencyProperties(magicToken, "EKS")
-			if err == nil {
-				if arn, exists := properties["clusterArn"]; exists {
-					if arnStr, ok := arn.(string); ok {
-						eksClusterArn = arnStr
-					}
+			for _, provider := range clusters {
+				if info := provider.Cluster(); info.Type == "EKS" {
+					eksClusterArn = info.ClusterArn
+					break
 				}
 			}
 		}
@@ -339,19 +367,12 @@ func (h *HyperPodForge) createHyperPodCluster(hyperPodInstance *HyperPodInstance
 	// Add Lustre configuration - but not with continuous mode
 	if hyperPodInstance.CreateLustre != nil && *hyperPodInstance.CreateLustre && orchestrator == nil {
 		// Only create Lustre with Slurm orchestrator (not EKS continuous mode)
-		storageCapacity := hyperPodInstance.LustreStorageCapacity
-		if storageCapacity == 0 {
-			storageCapacity = 1200 // Default
-		}
-		throughput := hyperPodInstance.LustreThroughput
-		if throughput == 0 {
-			throughput = 250 // Default
-		}
+		lustreSpec := hyperPodInstance.lustreSpec()
 
 		environmentConfig := &awssagemaker.CfnCluster_EnvironmentConfigProperty{
 			FSxLustreConfig: &awssagemaker.CfnCluster_FSxLustreConfigProperty{
-				PerUnitStorageThroughput: jsii.Number(float64(throughput)),
-				SizeInGiB:               jsii.Number(float64(storageCapacity)),
+				PerUnitStorageThroughput: jsii.Number(lustreSpec.PerUnitStorageThroughput),
+				SizeInGiB:               jsii.Number(float64(lustreSpec.StorageCapacityGiB)),
 			},
 		}
 
@@ -391,7 +412,7 @@ func (h *HyperPodForge) createHyperPodCluster(hyperPodInstance *HyperPodInstance
 	// 添加对 EKS HyperPod 组件的依赖
 	if hyperPodInstance.DependsOn != "" {
 		// 直接使用完整的 DependsOn 格式
-		if eksForge, exists := dependency.GlobalManager.Get(hyperPodInstance.DependsOn); exists {
+		if eksForge, err := dependency.GlobalManager.Get(hyperPodInstance.DependsOn); err == nil {
 			// 尝试获取 EKS forge 的 properties
 			if eksForgeObj, ok := eksForge.(interface{ GetProperties() map[string]interface{} }); ok {
 				properties := eksForgeObj.GetProperties()
@@ -21,8 +21,8 @@ func updateInferenceOperator(hyperPodInstance *HyperPodInstanceConfig, cluster a
 	}
 
 	// 直接使用 DependsOn（"EKS:eks" 格式）
-	eksForge, exists := dependency.GlobalManager.Get(hyperPodInstance.DependsOn)
-	if !exists {
+	eksForge, err := dependency.GlobalManager.Get(hyperPodInstance.DependsOn)
+	if err != nil {
 		return
 	}
 
@@ -26,8 +26,8 @@ func createHyperPodKarpenterResources(hyperPodInstance *HyperPodInstanceConfig,
 	}
 
 	// 获取 EKS forge 对象
-	eksForge, exists := dependency.GlobalManager.Get(hyperPodInstance.DependsOn)
-	if !exists {
+	eksForge, err := dependency.GlobalManager.Get(hyperPodInstance.DependsOn)
+	if err != nil {
 		return
 	}
 
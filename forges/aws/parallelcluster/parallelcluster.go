@@ -4,6 +4,7 @@
 package parallelcluster
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/awslabs/InfraForge/core/config"
 	"github.com/awslabs/InfraForge/core/interfaces"
 	"github.com/awslabs/InfraForge/core/security"
+	utilsSecurity "github.com/awslabs/InfraForge/core/utils/security"
 	"github.com/awslabs/InfraForge/core/utils/types"
 	"github.com/awslabs/InfraForge/core/utils/aws"
 	"github.com/awslabs/InfraForge/core/partition"
@@ -97,6 +99,7 @@ type ParallelClusterInstanceConfig struct {
 	// 端口配置
 	AllowedPorts     string `json:"allowedPorts,omitempty"`      // 允许的端口配置
 	AllowedPortsIpv6 string `json:"allowedPortsIpv6,omitempty"`  // IPv6端口配置
+	SuppressPortWarnings []int `json:"suppressPortWarnings,omitempty"` // 不对这些端口的全网开放发出告警
 	ComputeNodeBootstrapTimeout int `json:"computeNodeBootstrapTimeout,omitempty"`
 
 	// Custom AMI configuration
@@ -321,25 +324,17 @@ func (f *ParallelClusterForge) Create(ctx *interfaces.ForgeContext) interface{}
 	}
 
 
-	magicToken, err := dependency.GetDependencyInfo(pcInstance.DependsOn)
-	//fmt.Printf("*** MagicToken = %s", magicToken)
-	if err != nil {
-		fmt.Printf("Error getting dependency info: %v\n", err)
-	}
-
 	// 处理Directory Service配置
-	dsProperties, err := dependency.ExtractDependencyProperties(magicToken, "DS")
-	if err == nil {
-		directoryConfig := buildDirectoryConfig(dsProperties)
+	if directory, err := dependency.ResolveFirst[dependency.DirectoryProvider](pcInstance.DependsOn); err == nil {
+		directoryConfig := buildDirectoryConfig(directory.Directory())
 		if directoryConfig != nil {
 			clusterConfig["DirectoryService"] = directoryConfig
 		}
 	}
 
 	// 处理RDS数据库配置
-	rdsProperties, err := dependency.ExtractDependencyProperties(magicToken, "RDS")
-	if err == nil {
-		databaseConfig := buildDatabaseConfig(rdsProperties, pcInstance.DatabaseName)
+	if database, err := dependency.ResolveFirst[dependency.DatabaseProvider](pcInstance.DependsOn); err == nil {
+		databaseConfig := buildDatabaseConfig(database.Database(), pcInstance.DatabaseName)
 		if databaseConfig != nil {
 			// Database配置应该在Scheduling.SlurmSettings下
 			scheduling := clusterConfig["Scheduling"].(map[string]interface{})
@@ -353,9 +348,9 @@ func (f *ParallelClusterForge) Create(ctx *interfaces.ForgeContext) interface{}
 		}
 	}
 
-	addEfsToClusterConfig(clusterConfig, magicToken)
-
-	addLustreToClusterConfig(clusterConfig, magicToken)
+	if err := addSharedStorageToClusterConfig(clusterConfig, pcInstance.DependsOn); err != nil {
+		fmt.Printf("Error resolving shared storage: %v\n", err)
+	}
 
 	// Create the ParallelCluster custom resource
 	//serviceTokenRef := providerResource.GetAtt(jsii.String("ServiceToken"), awscdk.ResolutionTypeHint_STRING)
@@ -388,8 +383,10 @@ func (f *ParallelClusterForge) ConfigureRules(ctx *interfaces.ForgeContext) {
 	if ctx.Instance != nil {
 		pcInstance, ok := (*ctx.Instance).(*ParallelClusterInstanceConfig)
 		if ok && (pcInstance.AllowedPorts != "" || pcInstance.AllowedPortsIpv6 != "") {
-			// 使用通用端口规则处理函数
-			security.ApplyPortRules(ctx.SecurityGroups.Public, pcInstance.AllowedPorts, pcInstance.AllowedPortsIpv6, ctx.DualStack)
+			// 使用通用端口规则处理函数，端口配置已在 Validate 中校验
+			if err := security.ApplyPortRules(ctx.SecurityGroups.Public, pcInstance.AllowedPorts, pcInstance.AllowedPortsIpv6, ctx.DualStack); err != nil {
+				panic(fmt.Sprintf("ParallelCluster %s: %v", pcInstance.GetID(), err))
+			}
 			
 			// ParallelCluster 特有的内部通信规则
 			security.AddAllTrafficIngressRule(ctx.SecurityGroups.Private, ctx.SecurityGroups.Private, "Allow all traffic within private security group")
@@ -668,15 +665,39 @@ func (f *ParallelClusterForge) MergeConfigs(defaults config.InstanceConfig, inst
 		merged.ComputeCustomAmi = parallelClusterInstance.ComputeCustomAmi
 	}
 
+	// 合并端口配置
+	if parallelClusterInstance.AllowedPorts != "" {
+		merged.AllowedPorts = parallelClusterInstance.AllowedPorts
+	}
+
+	if parallelClusterInstance.AllowedPortsIpv6 != "" {
+		merged.AllowedPortsIpv6 = parallelClusterInstance.AllowedPortsIpv6
+	}
+
+	if len(parallelClusterInstance.SuppressPortWarnings) > 0 {
+		merged.SuppressPortWarnings = parallelClusterInstance.SuppressPortWarnings
+	}
+
 	return merged
 }
 
-// 解析 magic_token
-// buildDirectoryConfig 根据DS属性构建DirectoryService配置
-func buildDirectoryConfig(dsProperties map[string]interface{}) map[string]interface{} {
-	domainName, _ := dsProperties["name"].(string)
-	secretARN, _ := dsProperties["secretARN"].(string)
-	shortName, _ := dsProperties["shortName"].(string)
+// Validate 严格校验端口配置及其引用的前缀列表，并对全网开放的管理端口发出告警
+func (c *ParallelClusterInstanceConfig) Validate() error {
+	warnings, err := utilsSecurity.ValidateAllowedPorts(c.AllowedPorts, c.AllowedPortsIpv6, c.SuppressPortWarnings)
+	for _, warning := range warnings {
+		fmt.Printf("Warning: ParallelCluster '%s': %s\n", c.GetID(), warning)
+	}
+	if err != nil {
+		return err
+	}
+	return security.ValidatePortPrefixLists(c.AllowedPorts, c.AllowedPortsIpv6)
+}
+
+// buildDirectoryConfig 根据目录服务信息构建DirectoryService配置
+func buildDirectoryConfig(directory dependency.DirectoryInfo) map[string]interface{} {
+	domainName := directory.DomainName
+	secretARN := directory.SecretArn
+	shortName := directory.ShortName
 
 	if domainName == "" || secretARN == "" || shortName == "" {
 		return nil
@@ -684,12 +705,8 @@ func buildDirectoryConfig(dsProperties map[string]interface{}) map[string]interf
 
 	// 处理DNS IP地址
 	ldapUrls := []string{}
-	if dnsIPs, ok := dsProperties["attrDnsIpAddresses"].([]interface{}); ok {
-		for _, ip := range dnsIPs {
-			if ipStr, ok := ip.(string); ok {
-				ldapUrls = append(ldapUrls, fmt.Sprintf("ldap://%s", ipStr))
-			}
-		}
+	for _, ip := range directory.DnsIps {
+		ldapUrls = append(ldapUrls, fmt.Sprintf("ldap://%s", ip))
 	}
 
 	// 构造域名组件
@@ -715,11 +732,11 @@ func buildDirectoryConfig(dsProperties map[string]interface{}) map[string]interf
 	}
 }
 
-// buildDatabaseConfig 根据RDS属性构建Database配置
-func buildDatabaseConfig(rdsProperties map[string]interface{}, databaseName string) map[string]interface{} {
-	uri, _ := rdsProperties["endpoint"].(string)
-	username, _ := rdsProperties["username"].(string)
-	secretArn, _ := rdsProperties["secretArn"].(string)
+// buildDatabaseConfig 根据数据库信息构建Database配置
+func buildDatabaseConfig(database dependency.DatabaseInfo, databaseName string) map[string]interface{} {
+	uri := database.Endpoint
+	username := database.Username
+	secretArn := database.SecretArn
 
 	if uri == "" || username == "" || secretArn == "" {
 		return nil
@@ -739,91 +756,100 @@ func buildDatabaseConfig(rdsProperties map[string]interface{}, databaseName stri
 	return databaseConfig
 }
 
-func addEfsToClusterConfig(clusterConfig map[string]interface{}, magicTokenStr string) error {
-	efsProperties, err := dependency.ExtractDependencyProperties(magicTokenStr, "EFS")
-	if err != nil {
-		return err
-	}
+// addSharedStorageToClusterConfig 将 dependsOn 中的文件存储依赖添加到SharedStorage
+// 每种类型的第一个依赖沿用原有名称，ParallelCluster 更新集群时不允许修改 SharedStorage 名称；
+// 同一类型的其余依赖加上依赖 ID 后缀，避免重名
+func addSharedStorageToClusterConfig(clusterConfig map[string]interface{}, dependsOn string) error {
+	namedTypes := make(map[string]bool)
+	for _, dep := range strings.Split(dependsOn, ",") {
+		dep = strings.TrimSpace(dep)
+		if dep == "" {
+			continue
+		}
+		provider, err := dependency.Resolve[dependency.FileSystemProvider](dep)
+		if errors.Is(err, dependency.ErrCapabilityNotProvided) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
 
-	efsConfig := buildEfsConfig(efsProperties)
-	if efsConfig == nil {
-		return fmt.Errorf("failed to build EFS config")
-	}
+		fileSystem := provider.FileSystem()
+		storageConfig := buildSharedStorageConfig(fileSystem)
+		if storageConfig == nil {
+			continue
+		}
+		if namedTypes[fileSystem.Type] {
+			storageConfig["Name"] = sharedStorageName(storageConfig["Name"].(string), dep)
+		}
+		namedTypes[fileSystem.Type] = true
 
-	// 添加SharedStorage配置
-	sharedStorage, ok := clusterConfig["SharedStorage"].([]map[string]interface{})
-	if !ok {
-		sharedStorage = []map[string]interface{}{}
-	}
+		// 添加SharedStorage配置
+		sharedStorage, ok := clusterConfig["SharedStorage"].([]map[string]interface{})
+		if !ok {
+			sharedStorage = []map[string]interface{}{}
+		}
 
-	sharedStorage = append(sharedStorage, efsConfig)
-	clusterConfig["SharedStorage"] = sharedStorage
+		sharedStorage = append(sharedStorage, storageConfig)
+		clusterConfig["SharedStorage"] = sharedStorage
+	}
 
 	return nil
 }
 
-func addLustreToClusterConfig(clusterConfig map[string]interface{}, magicTokenStr string) error {
-	lustreProperties, err := dependency.ExtractDependencyProperties(magicTokenStr, "LUSTRE")
-	if err != nil {
-		return err
-	}
-
-	lustreConfig := buildLustreConfig(lustreProperties)
-	if lustreConfig == nil {
-		return fmt.Errorf("failed to build Lustre config")
+// buildSharedStorageConfig 根据文件存储信息构建SharedStorage配置，不支持的类型（如S3）返回nil
+func buildSharedStorageConfig(fileSystem dependency.FileSystemInfo) map[string]interface{} {
+	switch fileSystem.Type {
+	case "EFS":
+		return buildFileSystemConfig(fileSystem.FileSystemId, fileSystem.MountPoint, "efs", "Efs", "FileSystemId")
+	case "LUSTRE":
+		return buildFileSystemConfig(fileSystem.FileSystemId, fileSystem.MountPoint, "InfraForgeFsx", "FsxLustre", "FileSystemId")
+	case "OPENZFS":
+		// OpenZFS/ONTAP 按卷挂载
+		return buildFileSystemConfig(fileSystem.VolumeId, fileSystem.MountPoint, "InfraForgeOpenZfs", "FsxOpenZfs", "VolumeId")
+	case "ONTAP":
+		return buildFileSystemConfig(fileSystem.VolumeId, fileSystem.MountPoint, "InfraForgeOntap", "FsxOntap", "VolumeId")
 	}
-
-	// 添加SharedStorage配置
-	sharedStorage, ok := clusterConfig["SharedStorage"].([]map[string]interface{})
-	if !ok {
-		sharedStorage = []map[string]interface{}{}
-	}
-
-	sharedStorage = append(sharedStorage, lustreConfig)
-	clusterConfig["SharedStorage"] = sharedStorage
-
 	return nil
 }
 
-// buildEfsConfig 根据EFS属性构建EFS配置
-func buildEfsConfig(efsProperties map[string]interface{}) map[string]interface{} {
-	fileSystemId, _ := efsProperties["fileSystemId"].(string)
-	mountPoint, _ := efsProperties["mountPoint"].(string)
-
-	if fileSystemId == "" || mountPoint == "" {
-		return nil
-	}
-
-	return map[string]interface{}{
-		"MountDir":    mountPoint,
-		"Name":        "efs",
-		"StorageType": "Efs",
-		"EfsSettings": map[string]interface{}{
-			"FileSystemId": fileSystemId,
-		},
+// maxSharedStorageNameLength ParallelCluster SharedStorage 名称的最大长度
+const maxSharedStorageNameLength = 30
+
+// sharedStorageName 以依赖 ID 作为后缀生成SharedStorage名称，如 "efs" 和 "EFS:scratch" -> "efs-scratch"
+// 外部依赖 "EFS:ext/<stack>/<id>" 使用最后一段 ID，不支持的字符替换为 "-"
+func sharedStorageName(prefix string, dep string) string {
+	id := dep[strings.Index(dep, ":")+1:]
+	id = id[strings.LastIndex(id, "/")+1:]
+	name := []rune(prefix + "-" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' {
+			return r
+		}
+		return '-'
+	}, id))
+	if len(name) > maxSharedStorageNameLength {
+		name = name[:maxSharedStorageNameLength]
 	}
+	return string(name)
 }
 
-// buildLustreConfig 根据Lustre属性构建Lustre配置
-func buildLustreConfig(lustreProperties map[string]interface{}) map[string]interface{} {
-	fileSystemId, _ := lustreProperties["fileSystemId"].(string)
-	mountPoint, _ := lustreProperties["mountPoint"].(string)
-
-	if fileSystemId == "" || mountPoint == "" {
+// buildFileSystemConfig 构建单个SharedStorage条目，设置项为 <storageType>Settings
+func buildFileSystemConfig(id string, mountPoint string, name string, storageType string, idKey string) map[string]interface{} {
+	if id == "" || mountPoint == "" {
 		return nil
 	}
 
 	return map[string]interface{}{
 		"MountDir":    mountPoint,
-		"Name":        "InfraForgeFsx",
-		"StorageType": "FsxLustre",
-		"FsxLustreSettings": map[string]interface{}{
-			"FileSystemId": fileSystemId,
+		"Name":        name,
+		"StorageType": storageType,
+		storageType + "Settings": map[string]interface{}{
+			idKey: id,
 		},
 	}
 }
 
-// 解析magicToken JSON字符串
+// getOnNodeConfiguredScriptPath 返回节点配置完成后执行的 userdata 脚本路径，未配置 token 时为空
 func getOnNodeConfiguredScriptPath(pcInstance *ParallelClusterInstanceConfig) string {
 	// If no token is provided, return empty string
 	if pcInstance.UserDataToken == "" {
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/dependency"
 	"github.com/awslabs/InfraForge/core/interfaces"
 	"github.com/awslabs/InfraForge/core/security"
 	"github.com/awslabs/InfraForge/core/utils/aws"
@@ -64,6 +65,13 @@ type RdsInstanceConfig struct {
 	ServerlessV2        *bool  `json:"serverlessV2,omitempty"`
 	// 密码管理
 	UseManagedPassword  *bool  `json:"useManagedPassword,omitempty"`
+	// AWS Backup
+	BackupPlan          string `json:"backupPlan,omitempty"` // 引用顶层 backup.plans 中的备份计划
+}
+
+// GetBackupPlan 实现 config.BackupTarget
+func (c *RdsInstanceConfig) GetBackupPlan() string {
+	return c.BackupPlan
 }
 
 type RdsForge struct {
@@ -71,6 +79,24 @@ type RdsForge struct {
 	rdsClusters  []awsrds.DatabaseCluster
 	secrets      []awssecretsmanager.ISecret
 	properties   map[string]interface{}  // 存储连接属性
+	database     dependency.DatabaseInfo // 类型化连接信息
+}
+
+// BackupResourceArns 实现 backup.Protectable，包含所有实例和 Aurora 集群
+func (r *RdsForge) BackupResourceArns() []*string {
+	var arns []*string
+	for _, instance := range r.rdsInstances {
+		arns = append(arns, instance.InstanceArn())
+	}
+	for _, cluster := range r.rdsClusters {
+		arns = append(arns, cluster.ClusterArn())
+	}
+	return arns
+}
+
+// Database 实现 dependency.DatabaseProvider
+func (r *RdsForge) Database() dependency.DatabaseInfo {
+	return r.database
 }
 
 func (r *RdsForge) Create(ctx *interfaces.ForgeContext) interface{} {
@@ -131,6 +157,23 @@ func (r *RdsForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		r.properties["port"] = instance.DbInstanceEndpointPort()
 	}
 
+	r.database = dependency.DatabaseInfo{
+		Engine:       rdsInstance.Engine,
+		Username:     username,
+		DatabaseName: rdsInstance.DatabaseName,
+		Port:         fmt.Sprintf("%d", getDefaultPort(rdsInstance.Engine, rdsInstance.Port)),
+	}
+	if len(r.secrets) > 0 {
+		r.database.SecretArn = *r.secrets[0].SecretArn()
+	}
+	if len(r.rdsClusters) > 0 {
+		r.database.Endpoint = *r.rdsClusters[0].ClusterEndpoint().Hostname()
+		r.database.ReadEndpoint = *r.rdsClusters[0].ClusterReadEndpoint().Hostname()
+	} else if len(r.rdsInstances) > 0 {
+		r.database.Endpoint = *r.rdsInstances[0].DbInstanceEndpointAddress()
+		r.database.Port = *r.rdsInstances[0].DbInstanceEndpointPort()
+	}
+
 	return r
 }
 
@@ -427,6 +470,9 @@ func (r *RdsForge) MergeConfigs(defaults config.InstanceConfig, instance config.
 	if rdsInstance.UseManagedPassword != nil {
 		merged.UseManagedPassword = rdsInstance.UseManagedPassword
 	}
+	if rdsInstance.BackupPlan != "" {
+		merged.BackupPlan = rdsInstance.BackupPlan
+	}
 	if rdsInstance.StorageEncrypted != nil {
 		merged.StorageEncrypted = rdsInstance.StorageEncrypted
 	}
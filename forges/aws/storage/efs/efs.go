@@ -4,23 +4,140 @@
 package efs
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/dependency"
 	"github.com/awslabs/InfraForge/core/interfaces"
 	"github.com/awslabs/InfraForge/core/security"
+	"github.com/awslabs/InfraForge/core/utils/aws"
+	"github.com/awslabs/InfraForge/core/utils/types"
+	"github.com/awslabs/InfraForge/forges/aws/storage/utils"
 	"github.com/aws/aws-cdk-go/awscdk/v2"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awsefs"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awskms"
 	"github.com/aws/jsii-runtime-go"
 )
 
 type EfsInstanceConfig struct {
 	config.BaseInstanceConfig
-	RemovePolicy string `json:"removePolicy,omitempty"`
+	RemovePolicy                string                 `json:"removePolicy,omitempty"`
+	FileSystemName              string                 `json:"fileSystemName,omitempty"`
+	PerformanceMode             string                 `json:"performanceMode,omitempty"`             // generalPurpose 或 maxIO
+	ThroughputMode              string                 `json:"throughputMode,omitempty"`              // bursting、elastic 或 provisioned
+	ProvisionedThroughputMiBps  int                    `json:"provisionedThroughputMiBps,omitempty"`  // throughputMode 为 provisioned 时必填
+	TransitionToIADays          int                    `json:"transitionToIADays,omitempty"`          // 转入 Infrequent Access 的天数
+	TransitionToArchiveDays     int                    `json:"transitionToArchiveDays,omitempty"`     // 转入 Archive 的天数，需要 elastic 吞吐模式
+	TransitionToPrimaryOnAccess *bool                  `json:"transitionToPrimaryOnAccess,omitempty"` // 访问后移回标准存储
+	AccessPoints                []EfsAccessPointConfig `json:"accessPoints,omitempty"`
+	KmsKeyArn                   string                 `json:"kmsKeyArn,omitempty"`
+	OneZone                     *bool                  `json:"oneZone,omitempty"`
+	AzIndex                     int                    `json:"azIndex,omitempty"` // oneZone 时选择的可用区（从1开始）
+	ReplicationRegion           string                 `json:"replicationRegion,omitempty"`
+	ReplicationKmsKeyArn        string                 `json:"replicationKmsKeyArn,omitempty"`
+	AllowAnonymousAccess        *bool                  `json:"allowAnonymousAccess,omitempty"` // 允许未经 IAM 授权的客户端写入，默认 false
+	EnableAutomaticBackups      *bool                  `json:"enableAutomaticBackups,omitempty"`
+	BackupPlan                  string                 `json:"backupPlan,omitempty"` // 引用顶层 backup.plans 中的备份计划
+}
+
+// GetBackupPlan 实现 config.BackupTarget
+func (c *EfsInstanceConfig) GetBackupPlan() string {
+	return c.BackupPlan
+}
+
+// EfsAccessPointConfig 访问点配置，Uid/Gid 会强制作为访问该访问点的 POSIX 用户
+type EfsAccessPointConfig struct {
+	Name          string   `json:"name"`
+	Path          string   `json:"path"`
+	Uid           string   `json:"uid,omitempty"`
+	Gid           string   `json:"gid,omitempty"`
+	SecondaryGids []string `json:"secondaryGids,omitempty"`
+	Permissions   string   `json:"permissions,omitempty"` // 根目录不存在时创建使用的权限，默认 755
+}
+
+// 生命周期策略支持的天数
+var lifecyclePolicies = map[int]awsefs.LifecyclePolicy{
+	1:   awsefs.LifecyclePolicy_AFTER_1_DAY,
+	7:   awsefs.LifecyclePolicy_AFTER_7_DAYS,
+	14:  awsefs.LifecyclePolicy_AFTER_14_DAYS,
+	30:  awsefs.LifecyclePolicy_AFTER_30_DAYS,
+	60:  awsefs.LifecyclePolicy_AFTER_60_DAYS,
+	90:  awsefs.LifecyclePolicy_AFTER_90_DAYS,
+	180: awsefs.LifecyclePolicy_AFTER_180_DAYS,
+	270: awsefs.LifecyclePolicy_AFTER_270_DAYS,
+	365: awsefs.LifecyclePolicy_AFTER_365_DAYS,
+}
+
+// Validate 校验删除策略、吞吐模式、生命周期和访问点配置
+func (c *EfsInstanceConfig) Validate() error {
+	throughputMode := strings.ToLower(c.ThroughputMode)
+	performanceMode := strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(c.PerformanceMode), "_", ""), "-", "")
+
+	// EFS 文件系统不支持 CloudFormation 的 Snapshot 删除策略
+	switch strings.ReplaceAll(strings.ReplaceAll(strings.ToLower(c.RemovePolicy), "_", ""), "-", "") {
+	case "", "destroy", "retain":
+	case "snapshot":
+		return fmt.Errorf("efs %s: removePolicy snapshot is not supported for EFS, use retain or destroy and backupPlan for backups", c.GetID())
+	default:
+		return fmt.Errorf("efs %s: unsupported removePolicy '%s', expected retain or destroy", c.GetID(), c.RemovePolicy)
+	}
+
+	switch throughputMode {
+	case "", "bursting", "elastic":
+	case "provisioned":
+		if c.ProvisionedThroughputMiBps <= 0 {
+			return fmt.Errorf("efs %s: provisionedThroughputMiBps is required for provisioned throughput mode", c.GetID())
+		}
+	default:
+		return fmt.Errorf("efs %s: unsupported throughputMode '%s', expected bursting, elastic or provisioned", c.GetID(), c.ThroughputMode)
+	}
+
+	switch performanceMode {
+	case "", "generalpurpose":
+	case "maxio":
+		if throughputMode == "elastic" {
+			return fmt.Errorf("efs %s: maxIO performance mode is not supported with elastic throughput", c.GetID())
+		}
+		if types.GetBoolValue(c.OneZone, false) {
+			return fmt.Errorf("efs %s: maxIO performance mode is not supported for one zone file systems", c.GetID())
+		}
+	default:
+		return fmt.Errorf("efs %s: unsupported performanceMode '%s', expected generalPurpose or maxIO", c.GetID(), c.PerformanceMode)
+	}
+
+	if _, ok := lifecyclePolicies[c.TransitionToIADays]; c.TransitionToIADays != 0 && !ok {
+		return fmt.Errorf("efs %s: transitionToIADays must be one of 1, 7, 14, 30, 60, 90, 180, 270, 365", c.GetID())
+	}
+	if _, ok := lifecyclePolicies[c.TransitionToArchiveDays]; c.TransitionToArchiveDays != 0 && !ok {
+		return fmt.Errorf("efs %s: transitionToArchiveDays must be one of 1, 7, 14, 30, 60, 90, 180, 270, 365", c.GetID())
+	}
+	if c.TransitionToArchiveDays != 0 && throughputMode != "elastic" {
+		return fmt.Errorf("efs %s: transitionToArchiveDays requires elastic throughput mode", c.GetID())
+	}
+
+	names := make(map[string]bool)
+	for i, ap := range c.AccessPoints {
+		if ap.Name == "" || !strings.HasPrefix(ap.Path, "/") {
+			return fmt.Errorf("efs %s: accessPoints[%d] requires a name and an absolute path", c.GetID(), i)
+		}
+		if names[ap.Name] {
+			return fmt.Errorf("efs %s: duplicate access point name '%s'", c.GetID(), ap.Name)
+		}
+		names[ap.Name] = true
+		if (ap.Uid == "") != (ap.Gid == "") {
+			return fmt.Errorf("efs %s: access point %s requires both uid and gid", c.GetID(), ap.Name)
+		}
+	}
+	return nil
 }
 
 type EfsForge struct {
-	efs        awsefs.FileSystem
-	properties map[string]interface{}
+	efs            awsefs.FileSystem
+	accessPoints   map[string]awsefs.AccessPoint
+	fileSystemInfo dependency.FileSystemInfo
+	properties     map[string]interface{}
 }
 
 func (e *EfsForge) Create(ctx *interfaces.ForgeContext) interface{} {
@@ -30,20 +147,91 @@ func (e *EfsForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		return nil
 	}
 
-	fileSystem := awsefs.NewFileSystem(ctx.Stack, jsii.String(efsInstance.GetID()), &awsefs.FileSystemProps{
-		Vpc:                     ctx.VPC,
-		RemovalPolicy:           awscdk.RemovalPolicy_DESTROY,
-		SecurityGroup:           ctx.SecurityGroups.Default,
-		VpcSubnets:              &awsec2.SubnetSelection{SubnetType: ctx.SubnetType},
-		AllowAnonymousAccess:    jsii.Bool(true),
-		PerformanceMode:         awsefs.PerformanceMode_GENERAL_PURPOSE,
-		ThroughputMode:          awsefs.ThroughputMode_BURSTING,
-		Encrypted:               jsii.Bool(true),
-		FileSystemName:          jsii.String("AWS-Infra-Elastic-FileSystem"),
-        })
+	props := &awsefs.FileSystemProps{
+		Vpc:                    ctx.VPC,
+		RemovalPolicy:          utils.ParseRemovalPolicy(efsInstance.RemovePolicy, awscdk.RemovalPolicy_RETAIN),
+		SecurityGroup:          ctx.SecurityGroups.Default,
+		VpcSubnets:             &awsec2.SubnetSelection{SubnetType: ctx.SubnetType},
+		AllowAnonymousAccess:   jsii.Bool(types.GetBoolValue(efsInstance.AllowAnonymousAccess, false)),
+		PerformanceMode:        parsePerformanceMode(efsInstance.PerformanceMode),
+		ThroughputMode:         parseThroughputMode(efsInstance.ThroughputMode),
+		Encrypted:              jsii.Bool(true),
+		FileSystemName:         jsii.String(efsInstance.FileSystemName),
+		EnableAutomaticBackups: efsInstance.EnableAutomaticBackups,
+	}
+
+	if props.ThroughputMode == awsefs.ThroughputMode_PROVISIONED {
+		props.ProvisionedThroughputPerSecond = awscdk.Size_Mebibytes(jsii.Number(efsInstance.ProvisionedThroughputMiBps))
+	}
+
+	// 生命周期策略
+	if policy, ok := lifecyclePolicies[efsInstance.TransitionToIADays]; ok {
+		props.LifecyclePolicy = policy
+	}
+	if policy, ok := lifecyclePolicies[efsInstance.TransitionToArchiveDays]; ok {
+		props.TransitionToArchivePolicy = policy
+	}
+	if types.GetBoolValue(efsInstance.TransitionToPrimaryOnAccess, false) {
+		props.OutOfInfrequentAccessPolicy = awsefs.OutOfInfrequentAccessPolicy_AFTER_1_ACCESS
+	}
+
+	if efsInstance.KmsKeyArn != "" {
+		props.KmsKey = awskms.Key_FromKeyArn(ctx.Stack, jsii.String(efsInstance.GetID()+"KmsKey"), jsii.String(efsInstance.KmsKeyArn))
+	}
+
+	// One Zone 文件系统只在 azIndex 指定的可用区创建挂载目标
+	if types.GetBoolValue(efsInstance.OneZone, false) {
+		selectedSubnet := aws.SelectSubnetByAzIndex(efsInstance.AzIndex, ctx.VPC, ctx.SubnetType)
+		props.OneZone = jsii.Bool(true)
+		props.VpcSubnets = &awsec2.SubnetSelection{Subnets: &[]awsec2.ISubnet{selectedSubnet}}
+	}
+
+	// 跨区域复制
+	if efsInstance.ReplicationRegion != "" {
+		var replicationKey awskms.IKey
+		if efsInstance.ReplicationKmsKeyArn != "" {
+			replicationKey = awskms.Key_FromKeyArn(ctx.Stack, jsii.String(efsInstance.GetID()+"ReplicationKmsKey"), jsii.String(efsInstance.ReplicationKmsKeyArn))
+		}
+		props.ReplicationConfiguration = awsefs.ReplicationConfiguration_RegionalFileSystem(jsii.String(efsInstance.ReplicationRegion), replicationKey)
+	}
+
+	fileSystem := awsefs.NewFileSystem(ctx.Stack, jsii.String(efsInstance.GetID()), props)
 
 	e.efs = fileSystem
-	
+
+	// 创建访问点
+	e.accessPoints = make(map[string]awsefs.AccessPoint)
+	accessPointIds := make(map[string]interface{})
+	for _, apConfig := range efsInstance.AccessPoints {
+		options := &awsefs.AccessPointOptions{
+			Path: jsii.String(apConfig.Path),
+		}
+		if apConfig.Uid != "" {
+			options.PosixUser = &awsefs.PosixUser{
+				Uid: jsii.String(apConfig.Uid),
+				Gid: jsii.String(apConfig.Gid),
+			}
+			if len(apConfig.SecondaryGids) > 0 {
+				options.PosixUser.SecondaryGids = jsii.Strings(apConfig.SecondaryGids...)
+			}
+
+			// 根目录不存在时以该 POSIX 用户创建
+			permissions := apConfig.Permissions
+			if permissions == "" {
+				permissions = "755"
+			}
+			options.CreateAcl = &awsefs.Acl{
+				OwnerUid:    jsii.String(apConfig.Uid),
+				OwnerGid:    jsii.String(apConfig.Gid),
+				Permissions: jsii.String(permissions),
+			}
+		}
+
+		accessPoint := fileSystem.AddAccessPoint(jsii.String(apConfig.Name+"AccessPoint"), options)
+		e.accessPoints[apConfig.Name] = accessPoint
+		accessPointIds[apConfig.Name] = accessPoint.AccessPointId()
+	}
+
 	// 保存 EFS 属性
 	if e.properties == nil {
 		e.properties = make(map[string]interface{})
@@ -51,8 +239,36 @@ func (e *EfsForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	e.properties["fileSystemId"] = fileSystem.FileSystemId()
 	e.properties["fileSystemArn"] = fileSystem.FileSystemArn()
 	e.properties["mountPoint"] = "/" + efsInstance.GetID()  // 挂载点
+	e.properties["accessPoints"] = accessPointIds           // 访问点名称 -> 访问点 ID
+
+	e.fileSystemInfo = dependency.FileSystemInfo{
+		Type:         "EFS",
+		FileSystemId: *fileSystem.FileSystemId(),
+		MountPoint:   "/" + efsInstance.GetID(),
+		Protocol:     "efs",
+		AccessPoints: make(map[string]string),
+		IamRequired:  !types.GetBoolValue(efsInstance.AllowAnonymousAccess, false),
+	}
+	for name, accessPoint := range e.accessPoints {
+		e.fileSystemInfo.AccessPoints[name] = *accessPoint.AccessPointId()
+	}
+
+	return e
+}
+
+// BackupResourceArns 实现 backup.Protectable
+func (e *EfsForge) BackupResourceArns() []*string {
+	return []*string{e.efs.FileSystemArn()}
+}
+
+// FileSystem 实现 dependency.FileSystemProvider
+func (e *EfsForge) FileSystem() dependency.FileSystemInfo {
+	return e.fileSystemInfo
+}
 
-        return e
+// GetAccessPoint 按名称返回访问点
+func (e *EfsForge) GetAccessPoint(name string) awsefs.AccessPoint {
+	return e.accessPoints[name]
 }
 
 func (e *EfsForge) CreateOutputs(ctx *interfaces.ForgeContext) {
@@ -65,6 +281,13 @@ func (e *EfsForge) CreateOutputs(ctx *interfaces.ForgeContext) {
 		Value:       e.efs.FileSystemId(),
 		Description: jsii.String("Elastic File System ID"),
 	})
+
+	for _, apConfig := range efsInstance.AccessPoints {
+		awscdk.NewCfnOutput(ctx.Stack, jsii.String("ElasticFileSystem"+efsInstance.GetID()+"AccessPoint"+apConfig.Name), &awscdk.CfnOutputProps{
+			Value:       e.accessPoints[apConfig.Name].AccessPointId(),
+			Description: jsii.String(fmt.Sprintf("EFS access point %s (%s)", apConfig.Name, apConfig.Path)),
+		})
+	}
 }
 
 func (e *EfsForge) ConfigureRules(ctx *interfaces.ForgeContext) {
@@ -82,7 +305,7 @@ func (e *EfsForge) MergeConfigs(defaults config.InstanceConfig, instance config.
 	merged := defaults.(*EfsInstanceConfig)
 
 	// 从实例配置中覆盖基本字段
-        efsInstance := instance.(*EfsInstanceConfig)
+	efsInstance := instance.(*EfsInstanceConfig)
 	if instance != nil {
 		if efsInstance.GetID() != "" {
 			merged.ID = efsInstance.GetID()
@@ -107,9 +330,90 @@ func (e *EfsForge) MergeConfigs(defaults config.InstanceConfig, instance config.
 		merged.RemovePolicy = "RETAIN"
 	}
 
+	if efsInstance.FileSystemName != "" {
+		merged.FileSystemName = efsInstance.FileSystemName
+	}
+	if efsInstance.PerformanceMode != "" {
+		merged.PerformanceMode = efsInstance.PerformanceMode
+	}
+	if efsInstance.ThroughputMode != "" {
+		merged.ThroughputMode = efsInstance.ThroughputMode
+	}
+	if efsInstance.ProvisionedThroughputMiBps > 0 {
+		merged.ProvisionedThroughputMiBps = efsInstance.ProvisionedThroughputMiBps
+	}
+	if efsInstance.TransitionToIADays > 0 {
+		merged.TransitionToIADays = efsInstance.TransitionToIADays
+	}
+	if efsInstance.TransitionToArchiveDays > 0 {
+		merged.TransitionToArchiveDays = efsInstance.TransitionToArchiveDays
+	}
+	if efsInstance.TransitionToPrimaryOnAccess != nil {
+		merged.TransitionToPrimaryOnAccess = efsInstance.TransitionToPrimaryOnAccess
+	}
+	if len(efsInstance.AccessPoints) > 0 {
+		merged.AccessPoints = efsInstance.AccessPoints
+	}
+	if efsInstance.KmsKeyArn != "" {
+		merged.KmsKeyArn = efsInstance.KmsKeyArn
+	}
+	if efsInstance.OneZone != nil {
+		merged.OneZone = efsInstance.OneZone
+	}
+	if efsInstance.AzIndex > 0 {
+		merged.AzIndex = efsInstance.AzIndex
+	}
+	if efsInstance.ReplicationRegion != "" {
+		merged.ReplicationRegion = efsInstance.ReplicationRegion
+	}
+	if efsInstance.ReplicationKmsKeyArn != "" {
+		merged.ReplicationKmsKeyArn = efsInstance.ReplicationKmsKeyArn
+	}
+	if efsInstance.AllowAnonymousAccess != nil {
+		merged.AllowAnonymousAccess = efsInstance.AllowAnonymousAccess
+	}
+	if efsInstance.EnableAutomaticBackups != nil {
+		merged.EnableAutomaticBackups = efsInstance.EnableAutomaticBackups
+	}
+	if efsInstance.BackupPlan != "" {
+		merged.BackupPlan = efsInstance.BackupPlan
+	}
+
+	// 每个实例使用独立的文件系统名称
+	if merged.FileSystemName == "" {
+		merged.FileSystemName = "AWS-Infra-Elastic-FileSystem-" + merged.GetID()
+	}
+	if merged.ThroughputMode == "" {
+		merged.ThroughputMode = "bursting"
+	}
+
 	return merged
 }
 
+func parsePerformanceMode(input string) awsefs.PerformanceMode {
+	cleaned := strings.ToLower(input)
+	cleaned = strings.ReplaceAll(cleaned, "_", "")
+	cleaned = strings.ReplaceAll(cleaned, "-", "")
+
+	switch cleaned {
+	case "maxio":
+		return awsefs.PerformanceMode_MAX_IO
+	default:
+		return awsefs.PerformanceMode_GENERAL_PURPOSE
+	}
+}
+
+func parseThroughputMode(input string) awsefs.ThroughputMode {
+	switch strings.ToLower(input) {
+	case "elastic":
+		return awsefs.ThroughputMode_ELASTIC
+	case "provisioned":
+		return awsefs.ThroughputMode_PROVISIONED
+	default:
+		return awsefs.ThroughputMode_BURSTING
+	}
+}
+
 func (e *EfsForge) GetProperties() map[string]interface{} {
 	return e.properties
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 	
 	"github.com/awslabs/InfraForge/core/config"
+	"github.com/aws/jsii-runtime-go"
 )
 
 // We're using the actual EfsInstanceConfig and EfsForge from efs.go
@@ -47,3 +48,55 @@ func TestEfsForge_Create(t *testing.T) {
 	// Skip this test for now
 	t.Skip("Skipping test for EfsForge.Create - implement when ready")
 }
+
+func TestEfsValidate(t *testing.T) {
+	valid := []struct {
+		name     string
+		instance EfsInstanceConfig
+	}{
+		{"defaults", EfsInstanceConfig{}},
+		{"destroy", EfsInstanceConfig{RemovePolicy: "DESTROY"}},
+		{"provisioned", EfsInstanceConfig{ThroughputMode: "provisioned", ProvisionedThroughputMiBps: 128}},
+		{"archive with elastic", EfsInstanceConfig{ThroughputMode: "elastic", TransitionToIADays: 30, TransitionToArchiveDays: 90}},
+		{"max io", EfsInstanceConfig{PerformanceMode: "max_io"}},
+		{"access points", EfsInstanceConfig{AccessPoints: []EfsAccessPointConfig{
+			{Name: "home", Path: "/home", Uid: "1000", Gid: "1000"},
+			{Name: "data", Path: "/data"},
+		}}},
+	}
+	for _, tt := range valid {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.instance.ID = "efs"
+			if err := tt.instance.Validate(); err != nil {
+				t.Errorf("Expected %s to be valid, got %v", tt.name, err)
+			}
+		})
+	}
+
+	invalid := []struct {
+		name     string
+		instance EfsInstanceConfig
+	}{
+		{"snapshot removal policy", EfsInstanceConfig{RemovePolicy: "snapshot"}},
+		{"unknown removal policy", EfsInstanceConfig{RemovePolicy: "keep"}},
+		{"unknown throughput mode", EfsInstanceConfig{ThroughputMode: "burst"}},
+		{"provisioned without throughput", EfsInstanceConfig{ThroughputMode: "provisioned"}},
+		{"unknown performance mode", EfsInstanceConfig{PerformanceMode: "fast"}},
+		{"max io with elastic", EfsInstanceConfig{PerformanceMode: "maxIO", ThroughputMode: "elastic"}},
+		{"max io with one zone", EfsInstanceConfig{PerformanceMode: "maxIO", OneZone: jsii.Bool(true)}},
+		{"unsupported IA days", EfsInstanceConfig{TransitionToIADays: 10}},
+		{"unsupported archive days", EfsInstanceConfig{ThroughputMode: "elastic", TransitionToArchiveDays: 45}},
+		{"archive without elastic", EfsInstanceConfig{TransitionToArchiveDays: 90}},
+		{"relative access point path", EfsInstanceConfig{AccessPoints: []EfsAccessPointConfig{{Name: "home", Path: "home"}}}},
+		{"duplicate access point", EfsInstanceConfig{AccessPoints: []EfsAccessPointConfig{{Name: "home", Path: "/a"}, {Name: "home", Path: "/b"}}}},
+		{"uid without gid", EfsInstanceConfig{AccessPoints: []EfsAccessPointConfig{{Name: "home", Path: "/home", Uid: "1000"}}}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.instance.ID = "efs"
+			if err := tt.instance.Validate(); err == nil {
+				t.Errorf("Expected validation error for %s", tt.name)
+			}
+		})
+	}
+}
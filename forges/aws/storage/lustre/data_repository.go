@@ -0,0 +1,227 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package lustre
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsfsx"
+	"github.com/aws/jsii-runtime-go"
+	"github.com/awslabs/InfraForge/core/dependency"
+	"github.com/awslabs/InfraForge/core/utils/types"
+)
+
+// LustreDataRepositoryConfig 数据仓库关联配置，将文件系统路径关联到 S3 路径
+type LustreDataRepositoryConfig struct {
+	S3Path                      string   `json:"s3Path,omitempty"`                      // s3://bucket/prefix，留空时使用 dependsOn 中 S3 依赖的存储桶
+	FileSystemPath              string   `json:"fileSystemPath"`                        // 文件系统中的路径，如 /datasets
+	AutoImportEvents            []string `json:"autoImportEvents,omitempty"`            // NEW、CHANGED、DELETED
+	AutoExportEvents            []string `json:"autoExportEvents,omitempty"`            // NEW、CHANGED、DELETED
+	ImportedFileChunkSizeMiB    int      `json:"importedFileChunkSizeMiB,omitempty"`    // 1-512000
+	BatchImportMetaDataOnCreate *bool    `json:"batchImportMetaDataOnCreate,omitempty"` // 创建关联时导入元数据
+}
+
+// maxDataRepositoryAssociations PERSISTENT_2 文件系统最多支持的数据仓库关联数量
+const maxDataRepositoryAssociations = 8
+
+// validateDataRepositories 校验数据仓库配置，非 PERSISTENT_2 只支持单个仓库（通过 importPath/exportPath）
+// s3Path 由 MergeConfigs 从 S3 依赖填充，仍为空时报告依赖解析失败的原因
+func (c *LustreInstanceConfig) validateDataRepositories() error {
+	persistent2 := parseDeploymentType(c.DeploymentType) == awsfsx.LustreDeploymentType_PERSISTENT_2
+
+	if !persistent2 && len(c.DataRepositories) > 1 {
+		return fmt.Errorf("lustre %s: only PERSISTENT_2 supports multiple dataRepositories, use a single entry or importPath/exportPath", c.GetID())
+	}
+	if persistent2 && len(c.DataRepositories) > maxDataRepositoryAssociations {
+		return fmt.Errorf("lustre %s: PERSISTENT_2 supports at most %d dataRepositories, got %d", c.GetID(), maxDataRepositoryAssociations, len(c.DataRepositories))
+	}
+	if persistent2 && (c.ImportPath != "" || c.ExportPath != "") {
+		return fmt.Errorf("lustre %s: importPath/exportPath are not supported for PERSISTENT_2, use dataRepositories", c.GetID())
+	}
+	if c.ExportPath != "" && c.ImportPath == "" {
+		return fmt.Errorf("lustre %s: exportPath requires importPath", c.GetID())
+	}
+	if len(c.DataRepositories) > 0 && (c.ImportPath != "" || c.ExportPath != "") {
+		return fmt.Errorf("lustre %s: use either dataRepositories or importPath/exportPath, not both", c.GetID())
+	}
+	for name, path := range map[string]string{"importPath": c.ImportPath, "exportPath": c.ExportPath} {
+		if path != "" && !strings.HasPrefix(path, "s3://") {
+			return fmt.Errorf("lustre %s: %s must start with s3://", c.GetID(), name)
+		}
+	}
+
+	paths := make(map[string]bool)
+	for i, repo := range c.DataRepositories {
+		if repo.S3Path == "" && !strings.Contains(strings.ToUpper(c.DependsOn), "S3:") {
+			return fmt.Errorf("lustre %s: dataRepositories[%d].s3Path is required unless dependsOn references an S3 bucket", c.GetID(), i)
+		}
+		if repo.S3Path == "" {
+			if _, err := dependency.ResolveS3BucketName(c.DependsOn); err != nil {
+				return fmt.Errorf("lustre %s: failed to resolve dependsOn for dataRepositories: %w", c.GetID(), err)
+			}
+			return fmt.Errorf("lustre %s: dependsOn '%s' does not provide an S3 bucket for dataRepositories[%d]", c.GetID(), c.DependsOn, i)
+		}
+		if repo.S3Path != "" && !strings.HasPrefix(repo.S3Path, "s3://") {
+			return fmt.Errorf("lustre %s: dataRepositories[%d].s3Path must start with s3://", c.GetID(), i)
+		}
+		if !strings.HasPrefix(repo.FileSystemPath, "/") {
+			return fmt.Errorf("lustre %s: dataRepositories[%d].fileSystemPath must be an absolute path", c.GetID(), i)
+		}
+		if paths[repo.FileSystemPath] {
+			return fmt.Errorf("lustre %s: duplicate dataRepositories fileSystemPath '%s'", c.GetID(), repo.FileSystemPath)
+		}
+		paths[repo.FileSystemPath] = true
+
+		for _, event := range append(append([]string{}, repo.AutoImportEvents...), repo.AutoExportEvents...) {
+			switch strings.ToUpper(event) {
+			case "NEW", "CHANGED", "DELETED":
+			default:
+				return fmt.Errorf("lustre %s: dataRepositories[%d] has unknown event '%s', expected NEW, CHANGED or DELETED", c.GetID(), i, event)
+			}
+		}
+		if repo.ImportedFileChunkSizeMiB < 0 || repo.ImportedFileChunkSizeMiB > 512000 {
+			return fmt.Errorf("lustre %s: dataRepositories[%d].importedFileChunkSizeMiB must be between 1 and 512000", c.GetID(), i)
+		}
+		if !persistent2 && repo.FileSystemPath != "/" {
+			return fmt.Errorf("lustre %s: %s only supports fileSystemPath '/' for its data repository", c.GetID(), c.DeploymentType)
+		}
+	}
+	return nil
+}
+
+// resolveS3Paths 为未指定 s3Path 的数据仓库填充 S3 依赖的存储桶路径，fileSystemPath 作为前缀
+// 例如 fileSystemPath "/datasets" 对应 s3://<bucketName>/datasets，依赖无法解析时保持为空，由 Validate 报错
+func resolveS3Paths(dataRepositories []LustreDataRepositoryConfig, dependsOn string) []LustreDataRepositoryConfig {
+	bucketName, _ := dependency.ResolveS3BucketName(dependsOn)
+	if bucketName == "" {
+		return dataRepositories
+	}
+
+	resolved := make([]LustreDataRepositoryConfig, len(dataRepositories))
+	copy(resolved, dataRepositories)
+	for i := range resolved {
+		if resolved[i].S3Path == "" {
+			resolved[i].S3Path = "s3://" + bucketName + strings.TrimSuffix(resolved[i].FileSystemPath, "/")
+		}
+	}
+	return resolved
+}
+
+// applyLegacyDataRepository 为 SCRATCH/PERSISTENT_1 设置 importPath/exportPath，单个 dataRepositories 条目会转换为该形式
+func applyLegacyDataRepository(lustreInstance *LustreInstanceConfig, lustreConfiguration *awsfsx.LustreConfiguration) []interface{} {
+	importPath := lustreInstance.ImportPath
+	exportPath := lustreInstance.ExportPath
+	var autoImportEvents []string
+	chunkSize := 0
+
+	if len(lustreInstance.DataRepositories) == 1 {
+		repo := lustreInstance.DataRepositories[0]
+		importPath = repo.S3Path
+		if len(repo.AutoExportEvents) > 0 {
+			exportPath = repo.S3Path
+		}
+		autoImportEvents = repo.AutoImportEvents
+		chunkSize = repo.ImportedFileChunkSizeMiB
+	}
+
+	if importPath == "" {
+		return nil
+	}
+
+	lustreConfiguration.ImportPath = jsii.String(importPath)
+	if exportPath != "" {
+		lustreConfiguration.ExportPath = jsii.String(exportPath)
+	}
+	if chunkSize > 0 {
+		lustreConfiguration.ImportedFileChunkSizeMiB = jsii.Number(chunkSize)
+	}
+	if policy := parseAutoImportPolicy(autoImportEvents); policy != "" {
+		lustreConfiguration.AutoImportPolicy = policy
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"fileSystemPath":     "/",
+			"dataRepositoryPath": importPath,
+			"exportPath":         exportPath,
+		},
+	}
+}
+
+// createDataRepositoryAssociations 为 PERSISTENT_2 文件系统创建数据仓库关联
+func (l *LustreForge) createDataRepositoryAssociations(lustreInstance *LustreInstanceConfig) []interface{} {
+	var associations []interface{}
+	for i, repo := range lustreInstance.DataRepositories {
+		s3Config := &awsfsx.CfnDataRepositoryAssociation_S3Property{}
+		if len(repo.AutoImportEvents) > 0 {
+			s3Config.AutoImportPolicy = &awsfsx.CfnDataRepositoryAssociation_AutoImportPolicyProperty{
+				Events: jsii.Strings(upperEvents(repo.AutoImportEvents)...),
+			}
+		}
+		if len(repo.AutoExportEvents) > 0 {
+			s3Config.AutoExportPolicy = &awsfsx.CfnDataRepositoryAssociation_AutoExportPolicyProperty{
+				Events: jsii.Strings(upperEvents(repo.AutoExportEvents)...),
+			}
+		}
+
+		props := &awsfsx.CfnDataRepositoryAssociationProps{
+			FileSystemId:                l.lustre.FileSystemId(),
+			FileSystemPath:              jsii.String(repo.FileSystemPath),
+			DataRepositoryPath:          jsii.String(repo.S3Path),
+			BatchImportMetaDataOnCreate: jsii.Bool(types.GetBoolValue(repo.BatchImportMetaDataOnCreate, true)),
+			S3:                          s3Config,
+		}
+		if repo.ImportedFileChunkSizeMiB > 0 {
+			props.ImportedFileChunkSize = jsii.Number(repo.ImportedFileChunkSizeMiB)
+		}
+
+		association := awsfsx.NewCfnDataRepositoryAssociation(l.lustre, jsii.String(fmt.Sprintf("DataRepository%d", i)), props)
+		associations = append(associations, map[string]interface{}{
+			"fileSystemPath":     repo.FileSystemPath,
+			"dataRepositoryPath": repo.S3Path,
+			"associationId":      association.AttrAssociationId(),
+		})
+	}
+	return associations
+}
+
+// dataRepositoryInfos 将数据仓库属性转换为 FileSystemInfo 中的类型化关联
+func dataRepositoryInfos(dataRepositories []interface{}) []dependency.DataRepositoryInfo {
+	var infos []dependency.DataRepositoryInfo
+	for _, item := range dataRepositories {
+		repo := item.(map[string]interface{})
+		infos = append(infos, dependency.DataRepositoryInfo{
+			FileSystemPath:     repo["fileSystemPath"].(string),
+			DataRepositoryPath: repo["dataRepositoryPath"].(string),
+		})
+	}
+	return infos
+}
+
+// parseAutoImportPolicy 将导入事件转换为 SCRATCH/PERSISTENT_1 的 AutoImportPolicy
+func parseAutoImportPolicy(events []string) awsfsx.LustreAutoImportPolicy {
+	set := make(map[string]bool)
+	for _, event := range upperEvents(events) {
+		set[event] = true
+	}
+	switch {
+	case len(set) == 0:
+		return ""
+	case set["DELETED"]:
+		return awsfsx.LustreAutoImportPolicy_NEW_CHANGED_DELETED
+	case set["CHANGED"]:
+		return awsfsx.LustreAutoImportPolicy_NEW_CHANGED
+	default:
+		return awsfsx.LustreAutoImportPolicy_NEW
+	}
+}
+
+func upperEvents(events []string) []string {
+	result := make([]string, 0, len(events))
+	for _, event := range events {
+		result = append(result, strings.ToUpper(event))
+	}
+	return result
+}
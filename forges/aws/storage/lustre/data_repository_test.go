@@ -0,0 +1,178 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package lustre
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsfsx"
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/dependency"
+)
+
+func TestValidateDataRepositories(t *testing.T) {
+	tooMany := make([]LustreDataRepositoryConfig, maxDataRepositoryAssociations+1)
+	for i := range tooMany {
+		tooMany[i] = LustreDataRepositoryConfig{S3Path: fmt.Sprintf("s3://bucket/%d", i), FileSystemPath: fmt.Sprintf("/data%d", i)}
+	}
+
+	tests := []struct {
+		name     string
+		instance LustreInstanceConfig
+		wantErr  bool
+	}{
+		{"no repositories", LustreInstanceConfig{DeploymentType: "SCRATCH_2"}, false},
+		{"legacy import and export", LustreInstanceConfig{DeploymentType: "SCRATCH_2", ImportPath: "s3://bucket", ExportPath: "s3://bucket/export"}, false},
+		{"export without import", LustreInstanceConfig{DeploymentType: "SCRATCH_2", ExportPath: "s3://bucket/export"}, true},
+		{"import path not s3", LustreInstanceConfig{DeploymentType: "PERSISTENT_1", ImportPath: "bucket"}, true},
+		{"legacy paths with repositories", LustreInstanceConfig{DeploymentType: "SCRATCH_2", ImportPath: "s3://bucket", DataRepositories: []LustreDataRepositoryConfig{
+			{S3Path: "s3://bucket", FileSystemPath: "/"},
+		}}, true},
+		{"single repository on scratch", LustreInstanceConfig{DeploymentType: "SCRATCH_2", DataRepositories: []LustreDataRepositoryConfig{
+			{S3Path: "s3://bucket", FileSystemPath: "/", AutoImportEvents: []string{"new", "changed"}},
+		}}, false},
+		{"multiple repositories on scratch", LustreInstanceConfig{DeploymentType: "SCRATCH_2", DataRepositories: []LustreDataRepositoryConfig{
+			{S3Path: "s3://bucket/a", FileSystemPath: "/"},
+			{S3Path: "s3://bucket/b", FileSystemPath: "/b"},
+		}}, true},
+		{"sub path on scratch", LustreInstanceConfig{DeploymentType: "SCRATCH_2", DataRepositories: []LustreDataRepositoryConfig{
+			{S3Path: "s3://bucket", FileSystemPath: "/data"},
+		}}, true},
+		{"multiple repositories on persistent 2", LustreInstanceConfig{DeploymentType: "PERSISTENT_2", DataRepositories: []LustreDataRepositoryConfig{
+			{S3Path: "s3://bucket/a", FileSystemPath: "/a", AutoExportEvents: []string{"NEW", "DELETED"}},
+			{S3Path: "s3://bucket/b", FileSystemPath: "/b", ImportedFileChunkSizeMiB: 1024},
+		}}, false},
+		{"too many repositories on persistent 2", LustreInstanceConfig{DeploymentType: "PERSISTENT_2", DataRepositories: tooMany}, true},
+		{"legacy paths on persistent 2", LustreInstanceConfig{DeploymentType: "PERSISTENT_2", ImportPath: "s3://bucket"}, true},
+		{"missing s3 path without dependency", LustreInstanceConfig{DeploymentType: "PERSISTENT_2", DataRepositories: []LustreDataRepositoryConfig{
+			{FileSystemPath: "/a"},
+		}}, true},
+		{"s3 path without scheme", LustreInstanceConfig{DeploymentType: "PERSISTENT_2", DataRepositories: []LustreDataRepositoryConfig{
+			{S3Path: "bucket/a", FileSystemPath: "/a"},
+		}}, true},
+		{"relative file system path", LustreInstanceConfig{DeploymentType: "PERSISTENT_2", DataRepositories: []LustreDataRepositoryConfig{
+			{S3Path: "s3://bucket/a", FileSystemPath: "a"},
+		}}, true},
+		{"duplicate file system path", LustreInstanceConfig{DeploymentType: "PERSISTENT_2", DataRepositories: []LustreDataRepositoryConfig{
+			{S3Path: "s3://bucket/a", FileSystemPath: "/a"},
+			{S3Path: "s3://bucket/b", FileSystemPath: "/a"},
+		}}, true},
+		{"unknown event", LustreInstanceConfig{DeploymentType: "PERSISTENT_2", DataRepositories: []LustreDataRepositoryConfig{
+			{S3Path: "s3://bucket/a", FileSystemPath: "/a", AutoImportEvents: []string{"MODIFIED"}},
+		}}, true},
+		{"chunk size too large", LustreInstanceConfig{DeploymentType: "PERSISTENT_2", DataRepositories: []LustreDataRepositoryConfig{
+			{S3Path: "s3://bucket/a", FileSystemPath: "/a", ImportedFileChunkSizeMiB: 512001},
+		}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.instance.BaseInstanceConfig = config.BaseInstanceConfig{ID: "lustre"}
+			err := tt.instance.validateDataRepositories()
+			if tt.wantErr && err == nil {
+				t.Errorf("Expected validation error for %s", tt.name)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("Expected %s to be valid, got %v", tt.name, err)
+			}
+		})
+	}
+}
+
+type testBucketForge struct{}
+
+func (f *testBucketForge) FileSystem() dependency.FileSystemInfo {
+	return dependency.FileSystemInfo{Type: "S3", FileSystemId: "lustre-datasets", Protocol: "s3"}
+}
+
+func TestResolveS3Paths(t *testing.T) {
+	dependency.GlobalManager.Store("S3:lustre-datasets", &testBucketForge{})
+	forge := &LustreForge{}
+	repositories := []LustreDataRepositoryConfig{{FileSystemPath: "/datasets/"}, {S3Path: "s3://models/v1", FileSystemPath: "/models"}}
+
+	merged := forge.MergeConfigs(&LustreInstanceConfig{}, &LustreInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "lustre"},
+		DeploymentType:     "PERSISTENT_2",
+		DependsOn:          "S3:lustre-datasets",
+		DataRepositories:   repositories,
+	}).(*LustreInstanceConfig)
+	if got := merged.DataRepositories[0].S3Path; got != "s3://lustre-datasets/datasets" {
+		t.Errorf("Expected s3Path from the S3 dependency, got %q", got)
+	}
+	if got := merged.DataRepositories[1].S3Path; got != "s3://models/v1" {
+		t.Errorf("Expected explicit s3Path to win, got %q", got)
+	}
+	if repositories[0].S3Path != "" {
+		t.Errorf("Expected the instance config to stay unchanged, got %q", repositories[0].S3Path)
+	}
+
+	// Validate 只报告依赖解析失败，不修改配置
+	merged = forge.MergeConfigs(&LustreInstanceConfig{}, &LustreInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "lustre"},
+		DeploymentType:     "PERSISTENT_2",
+		DependsOn:          "S3:missing",
+		DataRepositories:   []LustreDataRepositoryConfig{{FileSystemPath: "/datasets"}},
+	}).(*LustreInstanceConfig)
+	if err := merged.validateDataRepositories(); err == nil || !strings.Contains(err.Error(), "failed to resolve dependsOn") {
+		t.Errorf("Expected an error when the S3 dependency cannot be resolved, got %v", err)
+	}
+	if merged.DataRepositories[0].S3Path != "" {
+		t.Errorf("Expected validation not to fill s3Path, got %q", merged.DataRepositories[0].S3Path)
+	}
+}
+
+func TestParseAutoImportPolicy(t *testing.T) {
+	tests := []struct {
+		events []string
+		want   awsfsx.LustreAutoImportPolicy
+	}{
+		{nil, ""},
+		{[]string{"new"}, awsfsx.LustreAutoImportPolicy_NEW},
+		{[]string{"NEW", "changed"}, awsfsx.LustreAutoImportPolicy_NEW_CHANGED},
+		{[]string{"DELETED"}, awsfsx.LustreAutoImportPolicy_NEW_CHANGED_DELETED},
+	}
+	for _, tt := range tests {
+		if got := parseAutoImportPolicy(tt.events); got != tt.want {
+			t.Errorf("parseAutoImportPolicy(%v) = %q, want %q", tt.events, got, tt.want)
+		}
+	}
+}
+
+func TestApplyLegacyDataRepository(t *testing.T) {
+	instance := &LustreInstanceConfig{
+		DataRepositories: []LustreDataRepositoryConfig{{
+			S3Path:                   "s3://bucket/data",
+			FileSystemPath:           "/",
+			AutoImportEvents:         []string{"NEW"},
+			AutoExportEvents:         []string{"NEW"},
+			ImportedFileChunkSizeMiB: 2048,
+		}},
+	}
+	lustreConfiguration := &awsfsx.LustreConfiguration{}
+	repositories := applyLegacyDataRepository(instance, lustreConfiguration)
+
+	if len(repositories) != 1 {
+		t.Fatalf("Expected 1 data repository, got %d", len(repositories))
+	}
+	if infos := dataRepositoryInfos(repositories); infos[0].FileSystemPath != "/" || infos[0].DataRepositoryPath != "s3://bucket/data" {
+		t.Errorf("Unexpected data repository info %+v", infos[0])
+	}
+	if *lustreConfiguration.ImportPath != "s3://bucket/data" || *lustreConfiguration.ExportPath != "s3://bucket/data" {
+		t.Errorf("Unexpected import/export paths %q, %q", *lustreConfiguration.ImportPath, *lustreConfiguration.ExportPath)
+	}
+	if *lustreConfiguration.ImportedFileChunkSizeMiB != 2048 {
+		t.Errorf("Expected chunk size 2048, got %v", *lustreConfiguration.ImportedFileChunkSizeMiB)
+	}
+	if lustreConfiguration.AutoImportPolicy != awsfsx.LustreAutoImportPolicy_NEW {
+		t.Errorf("Expected auto import policy NEW, got %q", lustreConfiguration.AutoImportPolicy)
+	}
+
+	// 未配置数据仓库时不设置导入路径
+	empty := &awsfsx.LustreConfiguration{}
+	if repositories := applyLegacyDataRepository(&LustreInstanceConfig{}, empty); repositories != nil || empty.ImportPath != nil {
+		t.Errorf("Expected no data repository, got %v", repositories)
+	}
+}
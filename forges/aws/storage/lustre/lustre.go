@@ -4,9 +4,11 @@
 package lustre 
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/dependency"
 	"github.com/awslabs/InfraForge/core/interfaces"
 	"github.com/awslabs/InfraForge/core/security"
 	"github.com/awslabs/InfraForge/core/utils/aws"
@@ -18,19 +20,52 @@ import (
 
 type LustreInstanceConfig struct {
 	config.BaseInstanceConfig
-	AzIndex                  int      `json:"azIndex,omitempty"`
-	DataCompressionType      string   `json:"dataCompressionType,omitempty"`
-	DeploymentType           string   `json:"deploymentType,omitempty"`
-	StorageType              string   `json:"storageType,omitempty"`
-	FileSystemVersion        string   `json:"fileSystemVersion,omitempty"`
-	PerUnitStorageThroughput float64  `json:"perUnitStorageThroughput,omitempty"`
-	RemovalPolicy            string   `json:"removalPolicy,omitempty"`
-	StorageCapacityGiB       int      `json:"storageCapacityGiB,omitempty"`
+	AzIndex                  int                          `json:"azIndex,omitempty"`
+	DataCompressionType      string                       `json:"dataCompressionType,omitempty"`
+	DeploymentType           string                       `json:"deploymentType,omitempty"`
+	StorageType              string                       `json:"storageType,omitempty"`
+	FileSystemVersion        string                       `json:"fileSystemVersion,omitempty"`
+	PerUnitStorageThroughput float64                      `json:"perUnitStorageThroughput,omitempty"`
+	RemovalPolicy            string                       `json:"removalPolicy,omitempty"`
+	StorageCapacityGiB       int                          `json:"storageCapacityGiB,omitempty"`
+	DataRepositories         []LustreDataRepositoryConfig `json:"dataRepositories,omitempty"`
+	ImportPath               string                       `json:"importPath,omitempty"` // SCRATCH/PERSISTENT_1 使用的 S3 导入路径
+	ExportPath               string                       `json:"exportPath,omitempty"` // SCRATCH/PERSISTENT_1 使用的 S3 导出路径
+	DependsOn                string                       `json:"dependsOn,omitempty"`  // 如 "S3:datasets"，dataRepositories 未指定 s3Path 时使用该存储桶
+	BackupPlan               string                       `json:"backupPlan,omitempty"` // 引用顶层 backup.plans 中的备份计划，仅 PERSISTENT 类型支持
+}
+
+// GetBackupPlan 实现 config.BackupTarget
+func (c *LustreInstanceConfig) GetBackupPlan() string {
+	return c.BackupPlan
+}
+
+// Validate 按 FSx 规则校验容量、吞吐量、存储类型和压缩配置，并校验备份和数据仓库配置
+func (c *LustreInstanceConfig) Validate() error {
+	warnings, err := utils.ValidateLustreSpec(utils.LustreSpec{
+		DeploymentType:           c.DeploymentType,
+		StorageType:              c.StorageType,
+		StorageCapacityGiB:       c.StorageCapacityGiB,
+		PerUnitStorageThroughput: c.PerUnitStorageThroughput,
+		DataCompressionType:      c.DataCompressionType,
+		FileSystemVersion:        c.FileSystemVersion,
+	})
+	for _, warning := range warnings {
+		fmt.Printf("Warning: lustre %s: %s\n", c.GetID(), warning)
+	}
+	if err != nil {
+		return fmt.Errorf("lustre %s: %w", c.GetID(), err)
+	}
+	if c.BackupPlan != "" && strings.HasPrefix(utils.NormalizeLustreDeploymentType(c.DeploymentType), "SCRATCH") {
+		return fmt.Errorf("lustre %s: backupPlan requires a PERSISTENT_1 or PERSISTENT_2 deploymentType, AWS Backup does not support scratch file systems", c.GetID())
+	}
+	return c.validateDataRepositories()
 }
 
 type LustreForge struct {
-        lustre     awsfsx.LustreFileSystem
-        properties map[string]interface{}
+        lustre         awsfsx.LustreFileSystem
+        fileSystemInfo dependency.FileSystemInfo
+        properties     map[string]interface{}
 }
 
 func (l *LustreForge) Create(ctx *interfaces.ForgeContext) interface{} {
@@ -56,6 +91,13 @@ func (l *LustreForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		PerUnitStorageThroughput: perUnitStorageThroughput,
         }
 
+	// 未指定 s3Path 的数据仓库已在 Validate 中填充为 S3 依赖的存储桶路径
+	// 非 PERSISTENT_2 通过 importPath/exportPath 关联 S3
+	var dataRepositories []interface{}
+	if deploymentType != awsfsx.LustreDeploymentType_PERSISTENT_2 {
+		dataRepositories = applyLegacyDataRepository(lustreInstance, lustreConfiguration)
+	}
+
 	// 使用统一的子网选择函数
 	selectedSubnet := aws.SelectSubnetByAzIndex(lustreInstance.AzIndex, ctx.VPC, ctx.SubnetType)
 
@@ -69,10 +111,15 @@ func (l *LustreForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		VpcSubnet:             selectedSubnet,
 		SecurityGroup:         ctx.SecurityGroups.Default,
 		FileSystemTypeVersion: fileSystemVersion,
-                RemovalPolicy:         parseRemovalPolicy(lustreInstance.RemovalPolicy),
+                RemovalPolicy:         utils.ParseRemovalPolicy(lustreInstance.RemovalPolicy, awscdk.RemovalPolicy_DESTROY),
         })
 
 	l.lustre = fileSystem
+
+	// PERSISTENT_2 为每个数据仓库创建关联
+	if deploymentType == awsfsx.LustreDeploymentType_PERSISTENT_2 {
+		dataRepositories = l.createDataRepositoryAssociations(lustreInstance)
+	}
 	
 	// 保存 Lustre 属性
 	if l.properties == nil {
@@ -83,10 +130,37 @@ func (l *LustreForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	l.properties["mountName"] = fileSystem.MountName()
 	l.properties["mountPoint"] = "/" + lustreInstance.GetID()  // 挂载点
 	l.properties["storageCapacityGiB"] = lustreInstance.StorageCapacityGiB
+	l.properties["dataRepositories"] = dataRepositories
+
+	l.fileSystemInfo = dependency.FileSystemInfo{
+		Type:         "LUSTRE",
+		FileSystemId: *fileSystem.FileSystemId(),
+		DnsName:      *fileSystem.DnsName(),
+		MountName:    *fileSystem.MountName(),
+		MountPoint:   "/" + lustreInstance.GetID(),
+		Protocol:     "lustre",
+
+		StorageCapacityGiB: lustreInstance.StorageCapacityGiB,
+		DataRepositories:   dataRepositoryInfos(dataRepositories),
+	}
 
         return l
 }
 
+// BackupResourceArns 实现 backup.Protectable
+func (l *LustreForge) BackupResourceArns() []*string {
+	return []*string{awscdk.Stack_Of(l.lustre).FormatArn(&awscdk.ArnComponents{
+		Service:      jsii.String("fsx"),
+		Resource:     jsii.String("file-system"),
+		ResourceName: l.lustre.FileSystemId(),
+	})}
+}
+
+// FileSystem 实现 dependency.FileSystemProvider
+func (l *LustreForge) FileSystem() dependency.FileSystemInfo {
+	return l.fileSystemInfo
+}
+
 func (l *LustreForge) CreateOutputs(ctx *interfaces.ForgeContext) {
 	lustreInstance, ok := (*ctx.Instance).(*LustreInstanceConfig)
 	if !ok {
@@ -166,9 +240,26 @@ func (l *LustreForge) MergeConfigs(defaults config.InstanceConfig, instance conf
 	if lustreInstance.RemovalPolicy != "" {
 		merged.RemovalPolicy = lustreInstance.RemovalPolicy
 	}
+	if lustreInstance.BackupPlan != "" {
+		merged.BackupPlan = lustreInstance.BackupPlan
+	}
 	if lustreInstance.FileSystemVersion != "" {
 		merged.FileSystemVersion = lustreInstance.FileSystemVersion
 	}
+	if len(lustreInstance.DataRepositories) > 0 {
+		merged.DataRepositories = lustreInstance.DataRepositories
+	}
+	if lustreInstance.ImportPath != "" {
+		merged.ImportPath = lustreInstance.ImportPath
+	}
+	if lustreInstance.ExportPath != "" {
+		merged.ExportPath = lustreInstance.ExportPath
+	}
+	if lustreInstance.DependsOn != "" {
+		merged.DependsOn = lustreInstance.DependsOn
+	}
+	// 未指定 s3Path 的数据仓库使用 S3 依赖的存储桶，Validate 在依赖无法解析时报错
+	merged.DataRepositories = resolveS3Paths(merged.DataRepositories, merged.DependsOn)
 	if lustreInstance.StorageType != "" {
 		merged.StorageType = lustreInstance.StorageType
 	} else {
@@ -177,16 +268,8 @@ func (l *LustreForge) MergeConfigs(defaults config.InstanceConfig, instance conf
 	if lustreInstance.PerUnitStorageThroughput > 0 { 
 		merged.PerUnitStorageThroughput = lustreInstance.PerUnitStorageThroughput
 	} else {
-		// 根据部署类型设置默认的有效吞吐量值
-		switch merged.DeploymentType {
-		case "PERSISTENT_1", "persistent1":
-			merged.PerUnitStorageThroughput = 50  // 50 MB/s/TiB for PERSISTENT_1 SSD
-		case "PERSISTENT_2", "persistent2":
-			merged.PerUnitStorageThroughput = 125 // 125 MB/s/TiB for PERSISTENT_2 SSD
-		default:
-			// SCRATCH类型不需要PerUnitStorageThroughput
-			merged.PerUnitStorageThroughput = 0
-		}
+		// 根据部署类型和存储类型设置默认的有效吞吐量值，SCRATCH 类型不需要
+		merged.PerUnitStorageThroughput = utils.DefaultLustreThroughput(merged.DeploymentType, merged.StorageType)
 	}
 
 	return merged
@@ -251,24 +334,6 @@ func parseStorageType(input string) awsfsx.StorageType {
 	}
 }
 
-func parseRemovalPolicy(input string) awscdk.RemovalPolicy {
-	// 将输入转换为小写
-	lowered := strings.ToLower(input)
-
-	// 移除所有非字母数字字符
-	cleaned := strings.ReplaceAll(lowered, "_", "")
-	cleaned = strings.ReplaceAll(cleaned, "-", "")
-
-	// 将已知的变体转换为目标格式
-	switch cleaned {
-	case "destroy": 
-		return awscdk.RemovalPolicy_DESTROY
-	case "retain": 
-		return awscdk.RemovalPolicy_RETAIN
-	default:
-		return awscdk.RemovalPolicy_DESTROY
-	}
-}
 func (l *LustreForge) GetProperties() map[string]interface{} {
 	return l.properties
 }
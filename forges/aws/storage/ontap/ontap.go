@@ -0,0 +1,387 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ontap
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsfsx"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awssecretsmanager"
+	"github.com/aws/jsii-runtime-go"
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/dependency"
+	"github.com/awslabs/InfraForge/core/interfaces"
+	"github.com/awslabs/InfraForge/core/security"
+	utilsSecurity "github.com/awslabs/InfraForge/core/utils/security"
+	"github.com/awslabs/InfraForge/core/utils/types"
+	"github.com/awslabs/InfraForge/forges/aws/storage/utils"
+)
+
+type OntapInstanceConfig struct {
+	config.BaseInstanceConfig
+	AzIndex                      int    `json:"azIndex,omitempty"`
+	DeploymentType               string `json:"deploymentType,omitempty"`               // SINGLE_AZ_1、SINGLE_AZ_2、MULTI_AZ_1 或 MULTI_AZ_2
+	StorageCapacityGiB           int    `json:"storageCapacityGiB,omitempty"`           // 1024-196608
+	ThroughputCapacity           int    `json:"throughputCapacity,omitempty"`           // MBps
+	SvmName                      string `json:"svmName,omitempty"`                      // 存储虚拟机名称
+	VolumeName                   string `json:"volumeName,omitempty"`                   // 卷名称，只能包含字母、数字和下划线
+	JunctionPath                 string `json:"junctionPath,omitempty"`                 // 卷在 SVM 命名空间中的路径，默认 /vol1
+	VolumeSizeMiB                int    `json:"volumeSizeMiB,omitempty"`                // 卷大小
+	SecurityStyle                string `json:"securityStyle,omitempty"`                // UNIX、NTFS 或 MIXED
+	StorageEfficiencyEnabled     *bool  `json:"storageEfficiencyEnabled,omitempty"`     // 去重和压缩，默认开启
+	AutomaticBackupRetentionDays *int   `json:"automaticBackupRetentionDays,omitempty"` // 0 表示关闭自动备份
+	RemovalPolicy                string `json:"removalPolicy,omitempty"`
+	BackupPlan                   string `json:"backupPlan,omitempty"` // 引用顶层 backup.plans 中的备份计划
+}
+
+// GetBackupPlan 实现 config.BackupTarget
+func (c *OntapInstanceConfig) GetBackupPlan() string {
+	return c.BackupPlan
+}
+
+// 支持的部署类型
+var deploymentTypes = map[string]bool{
+	"SINGLE_AZ_1": true,
+	"SINGLE_AZ_2": true,
+	"MULTI_AZ_1":  true,
+	"MULTI_AZ_2":  true,
+}
+
+var ontapNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,46}$`)
+
+// Validate 校验部署类型、容量、卷名称和挂载路径
+func (c *OntapInstanceConfig) Validate() error {
+	if !deploymentTypes[normalizeDeploymentType(c.DeploymentType)] {
+		return fmt.Errorf("ontap %s: unsupported deploymentType '%s', expected SINGLE_AZ_1, SINGLE_AZ_2, MULTI_AZ_1 or MULTI_AZ_2", c.GetID(), c.DeploymentType)
+	}
+	if c.StorageCapacityGiB < 1024 || c.StorageCapacityGiB > 196608 {
+		return fmt.Errorf("ontap %s: storageCapacityGiB must be between 1024 and 196608", c.GetID())
+	}
+	if c.ThroughputCapacity <= 0 {
+		return fmt.Errorf("ontap %s: throughputCapacity must be greater than 0", c.GetID())
+	}
+	if !ontapNamePattern.MatchString(c.SvmName) {
+		return fmt.Errorf("ontap %s: svmName '%s' may only contain letters, digits and underscores", c.GetID(), c.SvmName)
+	}
+	if !ontapNamePattern.MatchString(c.VolumeName) {
+		return fmt.Errorf("ontap %s: volumeName '%s' may only contain letters, digits and underscores", c.GetID(), c.VolumeName)
+	}
+	if !strings.HasPrefix(c.JunctionPath, "/") || c.JunctionPath == "/" {
+		return fmt.Errorf("ontap %s: junctionPath must be an absolute path below /", c.GetID())
+	}
+	if c.VolumeSizeMiB <= 0 {
+		return fmt.Errorf("ontap %s: volumeSizeMiB must be greater than 0", c.GetID())
+	}
+	switch strings.ToUpper(c.SecurityStyle) {
+	case "UNIX", "NTFS", "MIXED":
+	default:
+		return fmt.Errorf("ontap %s: unsupported securityStyle '%s', expected UNIX, NTFS or MIXED", c.GetID(), c.SecurityStyle)
+	}
+	if c.AutomaticBackupRetentionDays != nil && (*c.AutomaticBackupRetentionDays < 0 || *c.AutomaticBackupRetentionDays > 90) {
+		return fmt.Errorf("ontap %s: automaticBackupRetentionDays must be between 0 and 90", c.GetID())
+	}
+	return nil
+}
+
+type OntapForge struct {
+	fileSystem     awsfsx.CfnFileSystem
+	svm            awsfsx.CfnStorageVirtualMachine
+	volume         awsfsx.CfnVolume
+	secret         awssecretsmanager.Secret
+	properties     map[string]interface{}
+	fileSystemInfo dependency.FileSystemInfo
+}
+
+func (o *OntapForge) Create(ctx *interfaces.ForgeContext) interface{} {
+	ontapInstance, ok := (*ctx.Instance).(*OntapInstanceConfig)
+	if !ok {
+		// 处理类型断言失败的情况
+		return nil
+	}
+
+	deploymentType := normalizeDeploymentType(ontapInstance.DeploymentType)
+	multiAz := strings.HasPrefix(deploymentType, "MULTI_AZ")
+	subnets, err := utils.SelectFsxSubnets(ctx.VPC, ctx.SubnetType, ontapInstance.AzIndex, multiAz)
+	if err != nil {
+		panic(fmt.Sprintf("ontap %s: %v", ontapInstance.GetID(), err))
+	}
+
+	// fsxadmin 和 vsadmin 共用一个密码，保存在 Secrets Manager 中
+	secretName := fmt.Sprintf("%s-%s-FsxAdminPassword", *ctx.Stack.StackName(), ontapInstance.GetID())
+	password, secret := utilsSecurity.GetOrCreateSecretPassword(
+		ctx.Stack,
+		fmt.Sprintf("%s-FsxAdminPassword", ontapInstance.GetID()),
+		secretName,
+		fmt.Sprintf("FSx for NetApp ONTAP admin password for %s", ontapInstance.GetID()),
+		30,
+	)
+	o.secret = secret
+
+	ontapConfiguration := &awsfsx.CfnFileSystem_OntapConfigurationProperty{
+		DeploymentType:     jsii.String(deploymentType),
+		ThroughputCapacity: jsii.Number(ontapInstance.ThroughputCapacity),
+		FsxAdminPassword:   jsii.String(password),
+		PreferredSubnetId:  subnets.PreferredSubnetId,
+	}
+	if ontapInstance.AutomaticBackupRetentionDays != nil {
+		ontapConfiguration.AutomaticBackupRetentionDays = jsii.Number(*ontapInstance.AutomaticBackupRetentionDays)
+	}
+	// 多可用区部署需要路由表以创建浮动 IP 路由
+	if multiAz {
+		ontapConfiguration.RouteTableIds = &subnets.RouteTableIds
+	}
+
+	removalPolicy := utils.ParseRemovalPolicy(ontapInstance.RemovalPolicy, awscdk.RemovalPolicy_DESTROY)
+
+	fileSystem := awsfsx.NewCfnFileSystem(ctx.Stack, jsii.String("FsxOntapFileSystem-"+ontapInstance.GetID()), &awsfsx.CfnFileSystemProps{
+		FileSystemType:     jsii.String("ONTAP"),
+		SubnetIds:          &subnets.SubnetIds,
+		SecurityGroupIds:   &[]*string{ctx.SecurityGroups.Default.SecurityGroupId()},
+		StorageCapacity:    jsii.Number(ontapInstance.StorageCapacityGiB),
+		OntapConfiguration: ontapConfiguration,
+		Tags: &[]*awscdk.CfnTag{
+			{Key: jsii.String("Name"), Value: jsii.String("FsxOntapFileSystem-" + ontapInstance.GetID())},
+		},
+	})
+	fileSystem.ApplyRemovalPolicy(removalPolicy, nil)
+
+	securityStyle := strings.ToUpper(ontapInstance.SecurityStyle)
+	svm := awsfsx.NewCfnStorageVirtualMachine(ctx.Stack, jsii.String("FsxOntapSvm-"+ontapInstance.GetID()), &awsfsx.CfnStorageVirtualMachineProps{
+		FileSystemId:            fileSystem.Ref(),
+		Name:                    jsii.String(ontapInstance.SvmName),
+		RootVolumeSecurityStyle: jsii.String(securityStyle),
+		SvmAdminPassword:        jsii.String(password),
+	})
+	svm.ApplyRemovalPolicy(removalPolicy, nil)
+
+	volume := awsfsx.NewCfnVolume(ctx.Stack, jsii.String("FsxOntapVolume-"+ontapInstance.GetID()), &awsfsx.CfnVolumeProps{
+		Name:       jsii.String(ontapInstance.VolumeName),
+		VolumeType: jsii.String("ONTAP"),
+		OntapConfiguration: &awsfsx.CfnVolume_OntapConfigurationProperty{
+			StorageVirtualMachineId:  svm.AttrStorageVirtualMachineId(),
+			JunctionPath:             jsii.String(ontapInstance.JunctionPath),
+			SizeInMegabytes:          jsii.String(strconv.Itoa(ontapInstance.VolumeSizeMiB)),
+			SecurityStyle:            jsii.String(securityStyle),
+			StorageEfficiencyEnabled: jsii.String(strconv.FormatBool(types.GetBoolValue(ontapInstance.StorageEfficiencyEnabled, true))),
+		},
+	})
+	volume.ApplyRemovalPolicy(removalPolicy, nil)
+
+	o.fileSystem = fileSystem
+	o.svm = svm
+	o.volume = volume
+
+	// SVM 的 NFS 端点: <svmId>.<fileSystemId>.fsx.<region>.<urlSuffix>
+	dnsName := awscdk.Fn_Join(jsii.String("."), &[]*string{
+		svm.AttrStorageVirtualMachineId(),
+		fileSystem.Ref(),
+		jsii.String("fsx"),
+		ctx.Stack.Region(),
+		ctx.Stack.UrlSuffix(),
+	})
+
+	// 保存 ONTAP 属性，nas 模块通过 NFS 挂载 dnsName:exportPath
+	if o.properties == nil {
+		o.properties = make(map[string]interface{})
+	}
+	o.properties["fileSystemId"] = fileSystem.Ref()
+	o.properties["dnsName"] = dnsName
+	o.properties["mountPoint"] = "/" + ontapInstance.GetID() // 挂载点
+	o.properties["svmId"] = svm.AttrStorageVirtualMachineId()
+	o.properties["volumeId"] = volume.AttrVolumeId()
+	o.properties["exportPath"] = ontapInstance.JunctionPath
+	o.properties["protocol"] = "nfs"
+	o.properties["mountOptions"] = "nfsvers=4.1"
+	o.properties["secretARN"] = secret.SecretArn()
+
+	o.fileSystemInfo = dependency.FileSystemInfo{
+		Type:         "ONTAP",
+		FileSystemId: *fileSystem.Ref(),
+		DnsName:      *dnsName,
+		MountPoint:   "/" + ontapInstance.GetID(),
+		ExportPath:   ontapInstance.JunctionPath,
+		VolumeId:     *volume.AttrVolumeId(),
+		Protocol:     "nfs",
+		MountOptions: "nfsvers=4.1",
+	}
+
+	return o
+}
+
+// BackupResourceArns 实现 backup.Protectable
+func (o *OntapForge) BackupResourceArns() []*string {
+	return []*string{o.fileSystem.AttrResourceArn()}
+}
+
+// FileSystem 实现 dependency.FileSystemProvider
+func (o *OntapForge) FileSystem() dependency.FileSystemInfo {
+	return o.fileSystemInfo
+}
+
+func (o *OntapForge) CreateOutputs(ctx *interfaces.ForgeContext) {
+	ontapInstance, ok := (*ctx.Instance).(*OntapInstanceConfig)
+	if !ok {
+		// 处理类型断言失败的情况
+		return
+	}
+
+	awscdk.NewCfnOutput(ctx.Stack, jsii.String("OntapFileSystem"+ontapInstance.GetID()), &awscdk.CfnOutputProps{
+		Value:       o.fileSystem.Ref(),
+		Description: jsii.String("FSx for NetApp ONTAP File System ID"),
+	})
+	awscdk.NewCfnOutput(ctx.Stack, jsii.String("OntapVolume"+ontapInstance.GetID()), &awscdk.CfnOutputProps{
+		Value:       o.volume.AttrVolumeId(),
+		Description: jsii.String(fmt.Sprintf("FSx for NetApp ONTAP volume ID, mounted at %s", ontapInstance.JunctionPath)),
+	})
+	awscdk.NewCfnOutput(ctx.Stack, jsii.String("OntapAdminSecret"+ontapInstance.GetID()), &awscdk.CfnOutputProps{
+		Value:       o.secret.SecretArn(),
+		Description: jsii.String("Secret holding the fsxadmin and vsadmin password"),
+	})
+}
+
+// 获取 ONTAP 文件系统
+func (o *OntapForge) GetFileSystem() awsfsx.CfnFileSystem {
+	return o.fileSystem
+}
+
+func (o *OntapForge) ConfigureRules(ctx *interfaces.ForgeContext) {
+	// 为 ONTAP 配置入站规则
+	// NFS: 111、635、2049、4045、4046（TCP 和 UDP），SMB: 445，iSCSI: 3260，管理: 22、443（仅私有子网）
+	tiers := map[string]awsec2.SecurityGroup{
+		"public":  ctx.SecurityGroups.Public,
+		"private": ctx.SecurityGroups.Private,
+	}
+	for _, tier := range []string{"public", "private"} {
+		sourceSG := tiers[tier]
+		for _, port := range []int{111, 635, 2049} {
+			security.AddTcpIngressRule(ctx.SecurityGroups.Default, sourceSG, port, "Allow ONTAP NFS from "+tier+" subnet")
+			security.AddUdpIngressRule(ctx.SecurityGroups.Default, sourceSG, port, "Allow ONTAP NFS from "+tier+" subnet")
+		}
+		security.AddTcpRangeIngressRule(ctx.SecurityGroups.Default, sourceSG, 4045, 4046, "Allow ONTAP NFS lock from "+tier+" subnet")
+		security.AddUdpRangeIngressRule(ctx.SecurityGroups.Default, sourceSG, 4045, 4046, "Allow ONTAP NFS lock from "+tier+" subnet")
+		security.AddTcpIngressRule(ctx.SecurityGroups.Default, sourceSG, 445, "Allow ONTAP SMB from "+tier+" subnet")
+		security.AddTcpIngressRule(ctx.SecurityGroups.Default, sourceSG, 3260, "Allow ONTAP iSCSI from "+tier+" subnet")
+	}
+
+	// 管理端口只向私有子网开放
+	security.AddTcpIngressRule(ctx.SecurityGroups.Default, ctx.SecurityGroups.Private, 22, "Allow ONTAP CLI from private subnet")
+	security.AddTcpIngressRule(ctx.SecurityGroups.Default, ctx.SecurityGroups.Private, 443, "Allow ONTAP REST API from private subnet")
+}
+
+func (o *OntapForge) MergeConfigs(defaults config.InstanceConfig, instance config.InstanceConfig) config.InstanceConfig {
+	// 从默认配置中复制基本字段
+	merged := defaults.(*OntapInstanceConfig)
+
+	// 从实例配置中覆盖基本字段
+	ontapInstance := instance.(*OntapInstanceConfig)
+	if instance != nil {
+		if ontapInstance.GetID() != "" {
+			merged.ID = ontapInstance.GetID()
+		}
+		if ontapInstance.Type != "" {
+			merged.Type = ontapInstance.GetType()
+		}
+		if ontapInstance.Subnet != "" {
+			merged.Subnet = ontapInstance.GetSubnet()
+		}
+		if ontapInstance.SecurityGroup != "" {
+			merged.SecurityGroup = ontapInstance.GetSecurityGroup()
+		}
+	}
+
+	if ontapInstance.AzIndex > 0 {
+		merged.AzIndex = ontapInstance.AzIndex
+	}
+	if ontapInstance.DeploymentType != "" {
+		merged.DeploymentType = ontapInstance.DeploymentType
+	}
+	if ontapInstance.StorageCapacityGiB > 0 {
+		merged.StorageCapacityGiB = ontapInstance.StorageCapacityGiB
+	}
+	if ontapInstance.ThroughputCapacity > 0 {
+		merged.ThroughputCapacity = ontapInstance.ThroughputCapacity
+	}
+	if ontapInstance.SvmName != "" {
+		merged.SvmName = ontapInstance.SvmName
+	}
+	if ontapInstance.VolumeName != "" {
+		merged.VolumeName = ontapInstance.VolumeName
+	}
+	if ontapInstance.JunctionPath != "" {
+		merged.JunctionPath = ontapInstance.JunctionPath
+	}
+	if ontapInstance.VolumeSizeMiB > 0 {
+		merged.VolumeSizeMiB = ontapInstance.VolumeSizeMiB
+	}
+	if ontapInstance.SecurityStyle != "" {
+		merged.SecurityStyle = ontapInstance.SecurityStyle
+	}
+	if ontapInstance.StorageEfficiencyEnabled != nil {
+		merged.StorageEfficiencyEnabled = ontapInstance.StorageEfficiencyEnabled
+	}
+	if ontapInstance.AutomaticBackupRetentionDays != nil {
+		merged.AutomaticBackupRetentionDays = ontapInstance.AutomaticBackupRetentionDays
+	}
+	if ontapInstance.RemovalPolicy != "" {
+		merged.RemovalPolicy = ontapInstance.RemovalPolicy
+	}
+	if ontapInstance.BackupPlan != "" {
+		merged.BackupPlan = ontapInstance.BackupPlan
+	}
+
+	// 默认值
+	if merged.DeploymentType == "" {
+		merged.DeploymentType = "SINGLE_AZ_1"
+	}
+	if merged.StorageCapacityGiB == 0 {
+		merged.StorageCapacityGiB = 1024
+	}
+	if merged.ThroughputCapacity == 0 {
+		merged.ThroughputCapacity = 128
+	}
+	if merged.SvmName == "" {
+		merged.SvmName = "svm_" + sanitizeName(merged.GetID())
+	}
+	if merged.VolumeName == "" {
+		merged.VolumeName = "vol1"
+	}
+	if merged.JunctionPath == "" {
+		merged.JunctionPath = "/" + merged.VolumeName
+	}
+	if merged.VolumeSizeMiB == 0 {
+		merged.VolumeSizeMiB = 102400
+	}
+	if merged.SecurityStyle == "" {
+		merged.SecurityStyle = "UNIX"
+	}
+
+	return merged
+}
+
+// normalizeDeploymentType 统一部署类型的大小写和分隔符，如 multi-az-1 -> MULTI_AZ_1
+func normalizeDeploymentType(input string) string {
+	return strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(input)), "-", "_")
+}
+
+// sanitizeName 将实例 ID 转换为 ONTAP 允许的名称（字母、数字和下划线），最长 40 个字符
+func sanitizeName(id string) string {
+	if len(id) > 40 {
+		id = id[:40]
+	}
+	return strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, id)
+}
+
+func (o *OntapForge) GetProperties() map[string]interface{} {
+	return o.properties
+}
@@ -0,0 +1,51 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package ontap
+
+import (
+	"testing"
+
+	"github.com/awslabs/InfraForge/core/config"
+)
+
+func TestOntapMergeConfigsDefaults(t *testing.T) {
+	forge := &OntapForge{}
+	merged := forge.MergeConfigs(&OntapInstanceConfig{}, &OntapInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "shared-ontap", Type: "ONTAP"},
+	}).(*OntapInstanceConfig)
+
+	if merged.SvmName != "svm_shared_ontap" {
+		t.Errorf("Expected SvmName 'svm_shared_ontap', got %q", merged.SvmName)
+	}
+	if merged.JunctionPath != "/vol1" {
+		t.Errorf("Expected JunctionPath '/vol1', got %q", merged.JunctionPath)
+	}
+	if err := merged.Validate(); err != nil {
+		t.Errorf("Expected defaults to be valid, got %v", err)
+	}
+}
+
+func TestOntapValidate(t *testing.T) {
+	forge := &OntapForge{}
+	tests := []struct {
+		name     string
+		instance OntapInstanceConfig
+	}{
+		{"unknown deployment type", OntapInstanceConfig{DeploymentType: "SCRATCH_2"}},
+		{"capacity too small", OntapInstanceConfig{StorageCapacityGiB: 512}},
+		{"invalid volume name", OntapInstanceConfig{VolumeName: "vol-1"}},
+		{"relative junction path", OntapInstanceConfig{JunctionPath: "data"}},
+		{"unknown security style", OntapInstanceConfig{SecurityStyle: "posix"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.instance.ID = "ontap"
+			merged := forge.MergeConfigs(&OntapInstanceConfig{}, &tt.instance).(*OntapInstanceConfig)
+			if err := merged.Validate(); err == nil {
+				t.Errorf("Expected validation error for %s", tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package openzfs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsfsx"
+	"github.com/aws/jsii-runtime-go"
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/dependency"
+	"github.com/awslabs/InfraForge/core/interfaces"
+	"github.com/awslabs/InfraForge/core/security"
+	"github.com/awslabs/InfraForge/forges/aws/storage/utils"
+)
+
+type OpenZfsInstanceConfig struct {
+	config.BaseInstanceConfig
+	AzIndex                      int      `json:"azIndex,omitempty"`
+	DeploymentType               string   `json:"deploymentType,omitempty"`               // SINGLE_AZ_1、SINGLE_AZ_2、SINGLE_AZ_HA_1、SINGLE_AZ_HA_2 或 MULTI_AZ_1
+	StorageCapacityGiB           int      `json:"storageCapacityGiB,omitempty"`           // 64-524288
+	ThroughputCapacity           int      `json:"throughputCapacity,omitempty"`           // MBps
+	DataCompressionType          string   `json:"dataCompressionType,omitempty"`          // NONE、ZSTD 或 LZ4
+	NfsExportClients             string   `json:"nfsExportClients,omitempty"`             // 允许挂载的客户端，默认 *
+	NfsExportOptions             []string `json:"nfsExportOptions,omitempty"`             // 默认 rw、crossmnt
+	AutomaticBackupRetentionDays *int     `json:"automaticBackupRetentionDays,omitempty"` // 0 表示关闭自动备份
+	RemovalPolicy                string   `json:"removalPolicy,omitempty"`
+	BackupPlan                   string   `json:"backupPlan,omitempty"` // 引用顶层 backup.plans 中的备份计划
+}
+
+// GetBackupPlan 实现 config.BackupTarget
+func (c *OpenZfsInstanceConfig) GetBackupPlan() string {
+	return c.BackupPlan
+}
+
+// 支持的部署类型
+var deploymentTypes = map[string]bool{
+	"SINGLE_AZ_1":    true,
+	"SINGLE_AZ_2":    true,
+	"SINGLE_AZ_HA_1": true,
+	"SINGLE_AZ_HA_2": true,
+	"MULTI_AZ_1":     true,
+}
+
+// Validate 校验部署类型、容量和压缩类型
+func (c *OpenZfsInstanceConfig) Validate() error {
+	if !deploymentTypes[normalizeDeploymentType(c.DeploymentType)] {
+		return fmt.Errorf("openzfs %s: unsupported deploymentType '%s', expected SINGLE_AZ_1, SINGLE_AZ_2, SINGLE_AZ_HA_1, SINGLE_AZ_HA_2 or MULTI_AZ_1", c.GetID(), c.DeploymentType)
+	}
+	if c.StorageCapacityGiB < 64 || c.StorageCapacityGiB > 524288 {
+		return fmt.Errorf("openzfs %s: storageCapacityGiB must be between 64 and 524288", c.GetID())
+	}
+	if c.ThroughputCapacity <= 0 {
+		return fmt.Errorf("openzfs %s: throughputCapacity must be greater than 0", c.GetID())
+	}
+	switch strings.ToUpper(c.DataCompressionType) {
+	case "", "NONE", "ZSTD", "LZ4":
+	default:
+		return fmt.Errorf("openzfs %s: unsupported dataCompressionType '%s', expected NONE, ZSTD or LZ4", c.GetID(), c.DataCompressionType)
+	}
+	if c.AutomaticBackupRetentionDays != nil && (*c.AutomaticBackupRetentionDays < 0 || *c.AutomaticBackupRetentionDays > 90) {
+		return fmt.Errorf("openzfs %s: automaticBackupRetentionDays must be between 0 and 90", c.GetID())
+	}
+	return nil
+}
+
+type OpenZfsForge struct {
+	fileSystem     awsfsx.CfnFileSystem
+	properties     map[string]interface{}
+	fileSystemInfo dependency.FileSystemInfo
+}
+
+func (o *OpenZfsForge) Create(ctx *interfaces.ForgeContext) interface{} {
+	openZfsInstance, ok := (*ctx.Instance).(*OpenZfsInstanceConfig)
+	if !ok {
+		// 处理类型断言失败的情况
+		return nil
+	}
+
+	deploymentType := normalizeDeploymentType(openZfsInstance.DeploymentType)
+	subnets, err := utils.SelectFsxSubnets(ctx.VPC, ctx.SubnetType, openZfsInstance.AzIndex, deploymentType == "MULTI_AZ_1")
+	if err != nil {
+		panic(fmt.Sprintf("openzfs %s: %v", openZfsInstance.GetID(), err))
+	}
+
+	openZfsConfiguration := &awsfsx.CfnFileSystem_OpenZFSConfigurationProperty{
+		DeploymentType:     jsii.String(deploymentType),
+		ThroughputCapacity: jsii.Number(openZfsInstance.ThroughputCapacity),
+		RootVolumeConfiguration: &awsfsx.CfnFileSystem_RootVolumeConfigurationProperty{
+			DataCompressionType: jsii.String(strings.ToUpper(openZfsInstance.DataCompressionType)),
+			NfsExports: []interface{}{
+				&awsfsx.CfnFileSystem_NfsExportsProperty{
+					ClientConfigurations: []interface{}{
+						&awsfsx.CfnFileSystem_ClientConfigurationsProperty{
+							Clients: jsii.String(openZfsInstance.NfsExportClients),
+							Options: jsii.Strings(openZfsInstance.NfsExportOptions...),
+						},
+					},
+				},
+			},
+		},
+	}
+	if openZfsInstance.AutomaticBackupRetentionDays != nil {
+		openZfsConfiguration.AutomaticBackupRetentionDays = jsii.Number(*openZfsInstance.AutomaticBackupRetentionDays)
+	}
+	// 多可用区部署需要首选子网和路由表
+	if deploymentType == "MULTI_AZ_1" {
+		openZfsConfiguration.PreferredSubnetId = subnets.PreferredSubnetId
+		openZfsConfiguration.RouteTableIds = &subnets.RouteTableIds
+	}
+
+	fileSystem := awsfsx.NewCfnFileSystem(ctx.Stack, jsii.String("FsxOpenZfsFileSystem-"+openZfsInstance.GetID()), &awsfsx.CfnFileSystemProps{
+		FileSystemType:       jsii.String("OPENZFS"),
+		SubnetIds:            &subnets.SubnetIds,
+		SecurityGroupIds:     &[]*string{ctx.SecurityGroups.Default.SecurityGroupId()},
+		StorageCapacity:      jsii.Number(openZfsInstance.StorageCapacityGiB),
+		OpenZfsConfiguration: openZfsConfiguration,
+		Tags: &[]*awscdk.CfnTag{
+			{Key: jsii.String("Name"), Value: jsii.String("FsxOpenZfsFileSystem-" + openZfsInstance.GetID())},
+		},
+	})
+	fileSystem.ApplyRemovalPolicy(utils.ParseRemovalPolicy(openZfsInstance.RemovalPolicy, awscdk.RemovalPolicy_DESTROY), nil)
+
+	o.fileSystem = fileSystem
+
+	// 保存 OpenZFS 属性，nas 模块通过 NFS 挂载 dnsName:exportPath
+	if o.properties == nil {
+		o.properties = make(map[string]interface{})
+	}
+	o.properties["fileSystemId"] = fileSystem.Ref()
+	o.properties["dnsName"] = fileSystem.AttrDnsName()
+	o.properties["mountPoint"] = "/" + openZfsInstance.GetID() // 挂载点
+	o.properties["volumeId"] = fileSystem.AttrRootVolumeId()
+	o.properties["exportPath"] = "/fsx"
+	o.properties["protocol"] = "nfs"
+	o.properties["mountOptions"] = "nfsvers=4.1"
+	o.properties["storageCapacityGiB"] = openZfsInstance.StorageCapacityGiB
+
+	o.fileSystemInfo = dependency.FileSystemInfo{
+		Type:         "OPENZFS",
+		FileSystemId: *fileSystem.Ref(),
+		DnsName:      *fileSystem.AttrDnsName(),
+		MountPoint:   "/" + openZfsInstance.GetID(),
+		ExportPath:   "/fsx",
+		VolumeId:     *fileSystem.AttrRootVolumeId(),
+		Protocol:     "nfs",
+		MountOptions: "nfsvers=4.1",
+	}
+
+	return o
+}
+
+// BackupResourceArns 实现 backup.Protectable
+func (o *OpenZfsForge) BackupResourceArns() []*string {
+	return []*string{o.fileSystem.AttrResourceArn()}
+}
+
+// FileSystem 实现 dependency.FileSystemProvider
+func (o *OpenZfsForge) FileSystem() dependency.FileSystemInfo {
+	return o.fileSystemInfo
+}
+
+func (o *OpenZfsForge) CreateOutputs(ctx *interfaces.ForgeContext) {
+	openZfsInstance, ok := (*ctx.Instance).(*OpenZfsInstanceConfig)
+	if !ok {
+		// 处理类型断言失败的情况
+		return
+	}
+
+	awscdk.NewCfnOutput(ctx.Stack, jsii.String("OpenZfsFileSystem"+openZfsInstance.GetID()), &awscdk.CfnOutputProps{
+		Value:       o.fileSystem.Ref(),
+		Description: jsii.String("FSx for OpenZFS File System ID"),
+	})
+	awscdk.NewCfnOutput(ctx.Stack, jsii.String("OpenZfsFileSystemDnsName"+openZfsInstance.GetID()), &awscdk.CfnOutputProps{
+		Value:       o.fileSystem.AttrDnsName(),
+		Description: jsii.String("FSx for OpenZFS DNS name, mount with <dnsName>:/fsx"),
+	})
+}
+
+// 获取 OpenZFS 文件系统
+func (o *OpenZfsForge) GetFileSystem() awsfsx.CfnFileSystem {
+	return o.fileSystem
+}
+
+func (o *OpenZfsForge) ConfigureRules(ctx *interfaces.ForgeContext) {
+	// 为 OpenZFS 配置 NFS 入站规则
+	// 打开端口 111、2049、20001-20003（TCP 和 UDP）
+	tiers := map[string]awsec2.SecurityGroup{
+		"public":  ctx.SecurityGroups.Public,
+		"private": ctx.SecurityGroups.Private,
+	}
+	for _, tier := range []string{"public", "private"} {
+		sourceSG := tiers[tier]
+		security.AddTcpIngressRule(ctx.SecurityGroups.Default, sourceSG, 111, "Allow OpenZFS rpcbind from "+tier+" subnet")
+		security.AddUdpIngressRule(ctx.SecurityGroups.Default, sourceSG, 111, "Allow OpenZFS rpcbind from "+tier+" subnet")
+		security.AddTcpIngressRule(ctx.SecurityGroups.Default, sourceSG, 2049, "Allow OpenZFS NFS from "+tier+" subnet")
+		security.AddUdpIngressRule(ctx.SecurityGroups.Default, sourceSG, 2049, "Allow OpenZFS NFS from "+tier+" subnet")
+		security.AddTcpRangeIngressRule(ctx.SecurityGroups.Default, sourceSG, 20001, 20003, "Allow OpenZFS mount and lock from "+tier+" subnet")
+		security.AddUdpRangeIngressRule(ctx.SecurityGroups.Default, sourceSG, 20001, 20003, "Allow OpenZFS mount and lock from "+tier+" subnet")
+	}
+}
+
+func (o *OpenZfsForge) MergeConfigs(defaults config.InstanceConfig, instance config.InstanceConfig) config.InstanceConfig {
+	// 从默认配置中复制基本字段
+	merged := defaults.(*OpenZfsInstanceConfig)
+
+	// 从实例配置中覆盖基本字段
+	openZfsInstance := instance.(*OpenZfsInstanceConfig)
+	if instance != nil {
+		if openZfsInstance.GetID() != "" {
+			merged.ID = openZfsInstance.GetID()
+		}
+		if openZfsInstance.Type != "" {
+			merged.Type = openZfsInstance.GetType()
+		}
+		if openZfsInstance.Subnet != "" {
+			merged.Subnet = openZfsInstance.GetSubnet()
+		}
+		if openZfsInstance.SecurityGroup != "" {
+			merged.SecurityGroup = openZfsInstance.GetSecurityGroup()
+		}
+	}
+
+	if openZfsInstance.AzIndex > 0 {
+		merged.AzIndex = openZfsInstance.AzIndex
+	}
+	if openZfsInstance.DeploymentType != "" {
+		merged.DeploymentType = openZfsInstance.DeploymentType
+	}
+	if openZfsInstance.StorageCapacityGiB > 0 {
+		merged.StorageCapacityGiB = openZfsInstance.StorageCapacityGiB
+	}
+	if openZfsInstance.ThroughputCapacity > 0 {
+		merged.ThroughputCapacity = openZfsInstance.ThroughputCapacity
+	}
+	if openZfsInstance.DataCompressionType != "" {
+		merged.DataCompressionType = openZfsInstance.DataCompressionType
+	}
+	if openZfsInstance.NfsExportClients != "" {
+		merged.NfsExportClients = openZfsInstance.NfsExportClients
+	}
+	if len(openZfsInstance.NfsExportOptions) > 0 {
+		merged.NfsExportOptions = openZfsInstance.NfsExportOptions
+	}
+	if openZfsInstance.AutomaticBackupRetentionDays != nil {
+		merged.AutomaticBackupRetentionDays = openZfsInstance.AutomaticBackupRetentionDays
+	}
+	if openZfsInstance.RemovalPolicy != "" {
+		merged.RemovalPolicy = openZfsInstance.RemovalPolicy
+	}
+	if openZfsInstance.BackupPlan != "" {
+		merged.BackupPlan = openZfsInstance.BackupPlan
+	}
+
+	// 默认值
+	if merged.DeploymentType == "" {
+		merged.DeploymentType = "SINGLE_AZ_1"
+	}
+	if merged.StorageCapacityGiB == 0 {
+		merged.StorageCapacityGiB = 64
+	}
+	if merged.ThroughputCapacity == 0 {
+		merged.ThroughputCapacity = defaultThroughputCapacity(normalizeDeploymentType(merged.DeploymentType))
+	}
+	if merged.DataCompressionType == "" {
+		merged.DataCompressionType = "LZ4"
+	}
+	if merged.NfsExportClients == "" {
+		merged.NfsExportClients = "*"
+	}
+	if len(merged.NfsExportOptions) == 0 {
+		merged.NfsExportOptions = []string{"rw", "crossmnt"}
+	}
+
+	return merged
+}
+
+// normalizeDeploymentType 统一部署类型的大小写和分隔符，如 single-az-2 -> SINGLE_AZ_2
+func normalizeDeploymentType(input string) string {
+	return strings.ReplaceAll(strings.ToUpper(strings.TrimSpace(input)), "-", "_")
+}
+
+// defaultThroughputCapacity 返回部署类型支持的最小吞吐量（MBps）
+func defaultThroughputCapacity(deploymentType string) int {
+	switch deploymentType {
+	case "SINGLE_AZ_1", "SINGLE_AZ_HA_1":
+		return 64
+	default:
+		return 160
+	}
+}
+
+func (o *OpenZfsForge) GetProperties() map[string]interface{} {
+	return o.properties
+}
@@ -0,0 +1,70 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package openzfs
+
+import (
+	"testing"
+
+	"github.com/awslabs/InfraForge/core/config"
+)
+
+func TestOpenZfsMergeConfigsDefaults(t *testing.T) {
+	forge := &OpenZfsForge{}
+	merged := forge.MergeConfigs(&OpenZfsInstanceConfig{}, &OpenZfsInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "shared-zfs", Type: "OPENZFS"},
+	}).(*OpenZfsInstanceConfig)
+
+	if merged.DeploymentType != "SINGLE_AZ_1" {
+		t.Errorf("Expected DeploymentType 'SINGLE_AZ_1', got %q", merged.DeploymentType)
+	}
+	if merged.StorageCapacityGiB != 64 || merged.ThroughputCapacity != 64 {
+		t.Errorf("Expected 64 GiB and 64 MBps, got %d GiB and %d MBps", merged.StorageCapacityGiB, merged.ThroughputCapacity)
+	}
+	if merged.DataCompressionType != "LZ4" || merged.NfsExportClients != "*" {
+		t.Errorf("Expected LZ4 compression exported to *, got %q and %q", merged.DataCompressionType, merged.NfsExportClients)
+	}
+	if err := merged.Validate(); err != nil {
+		t.Errorf("Expected defaults to be valid, got %v", err)
+	}
+}
+
+func TestOpenZfsDefaultThroughput(t *testing.T) {
+	forge := &OpenZfsForge{}
+	merged := forge.MergeConfigs(&OpenZfsInstanceConfig{}, &OpenZfsInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "zfs"},
+		DeploymentType:     "multi-az-1",
+	}).(*OpenZfsInstanceConfig)
+
+	if merged.ThroughputCapacity != 160 {
+		t.Errorf("Expected MULTI_AZ_1 to default to 160 MBps, got %d", merged.ThroughputCapacity)
+	}
+	if err := merged.Validate(); err != nil {
+		t.Errorf("Expected lowercase deployment type to be valid, got %v", err)
+	}
+}
+
+func TestOpenZfsValidate(t *testing.T) {
+	forge := &OpenZfsForge{}
+	retention := 91
+	tests := []struct {
+		name     string
+		instance OpenZfsInstanceConfig
+	}{
+		{"unknown deployment type", OpenZfsInstanceConfig{DeploymentType: "SCRATCH_2"}},
+		{"capacity too small", OpenZfsInstanceConfig{StorageCapacityGiB: 32}},
+		{"capacity too large", OpenZfsInstanceConfig{StorageCapacityGiB: 600000}},
+		{"unknown compression", OpenZfsInstanceConfig{DataCompressionType: "GZIP"}},
+		{"backup retention too long", OpenZfsInstanceConfig{AutomaticBackupRetentionDays: &retention}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.instance.ID = "zfs"
+			merged := forge.MergeConfigs(&OpenZfsInstanceConfig{}, &tt.instance).(*OpenZfsInstanceConfig)
+			if err := merged.Validate(); err == nil {
+				t.Errorf("Expected validation error for %s", tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,415 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package s3
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awskms"
+	"github.com/aws/aws-cdk-go/awscdk/v2/awss3"
+	"github.com/aws/jsii-runtime-go"
+	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/dependency"
+	"github.com/awslabs/InfraForge/core/interfaces"
+	"github.com/awslabs/InfraForge/core/utils/types"
+	"github.com/awslabs/InfraForge/forges/aws/storage/utils"
+)
+
+type S3InstanceConfig struct {
+	config.BaseInstanceConfig
+	BucketName         string                       `json:"bucketName,omitempty"`       // 留空由 CloudFormation 生成
+	Encryption         string                       `json:"encryption,omitempty"`       // s3（默认）、kms 或 dsse
+	KmsKeyArn          string                       `json:"kmsKeyArn,omitempty"`        // kms/dsse 使用的客户托管密钥，留空使用 AWS 托管密钥
+	BucketKeyEnabled   *bool                        `json:"bucketKeyEnabled,omitempty"` // kms 加密时启用 S3 Bucket Key
+	Versioned          *bool                        `json:"versioned,omitempty"`
+	BlockPublicAccess  *bool                        `json:"blockPublicAccess,omitempty"` // 默认阻止所有公共访问
+	EnforceSSL         *bool                        `json:"enforceSSL,omitempty"`        // 默认只允许 HTTPS 访问
+	LifecycleRules     []S3LifecycleRuleConfig      `json:"lifecycleRules,omitempty"`
+	IntelligentTiering []S3IntelligentTieringConfig `json:"intelligentTiering,omitempty"`
+	AccessPoints       []S3AccessPointConfig        `json:"accessPoints,omitempty"`
+	RemovalPolicy      string                       `json:"removalPolicy,omitempty"`     // 默认 RETAIN
+	AutoDeleteObjects  *bool                        `json:"autoDeleteObjects,omitempty"` // 删除存储桶前清空对象，需要 removalPolicy 为 DESTROY
+	BackupPlan         string                       `json:"backupPlan,omitempty"`        // 引用顶层 backup.plans 中的备份计划，需要 versioned
+}
+
+// GetBackupPlan 实现 config.BackupTarget
+func (c *S3InstanceConfig) GetBackupPlan() string {
+	return c.BackupPlan
+}
+
+// S3LifecycleRuleConfig 生命周期规则
+type S3LifecycleRuleConfig struct {
+	Id                                 string               `json:"id,omitempty"`
+	Prefix                             string               `json:"prefix,omitempty"`
+	Transitions                        []S3TransitionConfig `json:"transitions,omitempty"`
+	ExpirationDays                     int                  `json:"expirationDays,omitempty"`
+	NoncurrentVersionExpirationDays    int                  `json:"noncurrentVersionExpirationDays,omitempty"`
+	AbortIncompleteMultipartUploadDays int                  `json:"abortIncompleteMultipartUploadDays,omitempty"`
+}
+
+// S3TransitionConfig 存储类别转换
+type S3TransitionConfig struct {
+	StorageClass string `json:"storageClass"` // STANDARD_IA、ONEZONE_IA、INTELLIGENT_TIERING、GLACIER_IR、GLACIER 或 DEEP_ARCHIVE
+	Days         int    `json:"days"`
+}
+
+// S3IntelligentTieringConfig 智能分层归档配置，天数为 0 表示不启用对应的归档层
+type S3IntelligentTieringConfig struct {
+	Name                      string `json:"name"`
+	Prefix                    string `json:"prefix,omitempty"`
+	ArchiveAccessTierDays     int    `json:"archiveAccessTierDays,omitempty"`     // 90-730
+	DeepArchiveAccessTierDays int    `json:"deepArchiveAccessTierDays,omitempty"` // 180-730
+}
+
+// S3AccessPointConfig 访问点配置，vpcOnly 时只允许从当前 VPC 访问
+type S3AccessPointConfig struct {
+	Name    string `json:"name"`
+	VpcOnly *bool  `json:"vpcOnly,omitempty"`
+}
+
+// 支持的转换存储类别
+var storageClasses = map[string]func() awss3.StorageClass{
+	"STANDARD_IA":         awss3.StorageClass_INFREQUENT_ACCESS,
+	"ONEZONE_IA":          awss3.StorageClass_ONE_ZONE_INFREQUENT_ACCESS,
+	"INTELLIGENT_TIERING": awss3.StorageClass_INTELLIGENT_TIERING,
+	"GLACIER_IR":          awss3.StorageClass_GLACIER_INSTANT_RETRIEVAL,
+	"GLACIER":             awss3.StorageClass_GLACIER,
+	"DEEP_ARCHIVE":        awss3.StorageClass_DEEP_ARCHIVE,
+}
+
+var accessPointNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,48}[a-z0-9]$`)
+
+// Validate 校验加密方式、生命周期、智能分层和访问点配置
+func (c *S3InstanceConfig) Validate() error {
+	if c.BackupPlan != "" && !types.GetBoolValue(c.Versioned, false) {
+		return fmt.Errorf("s3 %s: backupPlan requires versioned, AWS Backup only protects versioned buckets", c.GetID())
+	}
+	switch strings.ToLower(c.Encryption) {
+	case "", "s3":
+		if c.KmsKeyArn != "" {
+			return fmt.Errorf("s3 %s: kmsKeyArn requires encryption kms or dsse", c.GetID())
+		}
+	case "kms", "dsse":
+	default:
+		return fmt.Errorf("s3 %s: unsupported encryption '%s', expected s3, kms or dsse", c.GetID(), c.Encryption)
+	}
+
+	if types.GetBoolValue(c.AutoDeleteObjects, false) && utils.ParseRemovalPolicy(c.RemovalPolicy, awscdk.RemovalPolicy_RETAIN) != awscdk.RemovalPolicy_DESTROY {
+		return fmt.Errorf("s3 %s: autoDeleteObjects requires removalPolicy DESTROY", c.GetID())
+	}
+
+	for i, rule := range c.LifecycleRules {
+		if len(rule.Transitions) == 0 && rule.ExpirationDays == 0 && rule.NoncurrentVersionExpirationDays == 0 && rule.AbortIncompleteMultipartUploadDays == 0 {
+			return fmt.Errorf("s3 %s: lifecycleRules[%d] has no action", c.GetID(), i)
+		}
+		for j, transition := range rule.Transitions {
+			if _, ok := storageClasses[strings.ToUpper(transition.StorageClass)]; !ok {
+				return fmt.Errorf("s3 %s: lifecycleRules[%d].transitions[%d] has unknown storageClass '%s'", c.GetID(), i, j, transition.StorageClass)
+			}
+			if transition.Days < 0 {
+				return fmt.Errorf("s3 %s: lifecycleRules[%d].transitions[%d].days must not be negative", c.GetID(), i, j)
+			}
+		}
+		if rule.ExpirationDays < 0 || rule.NoncurrentVersionExpirationDays < 0 || rule.AbortIncompleteMultipartUploadDays < 0 {
+			return fmt.Errorf("s3 %s: lifecycleRules[%d] days must not be negative", c.GetID(), i)
+		}
+		if rule.NoncurrentVersionExpirationDays > 0 && !types.GetBoolValue(c.Versioned, false) {
+			return fmt.Errorf("s3 %s: lifecycleRules[%d].noncurrentVersionExpirationDays requires versioned", c.GetID(), i)
+		}
+	}
+
+	for i, tiering := range c.IntelligentTiering {
+		if tiering.Name == "" {
+			return fmt.Errorf("s3 %s: intelligentTiering[%d] requires a name", c.GetID(), i)
+		}
+		if tiering.ArchiveAccessTierDays == 0 && tiering.DeepArchiveAccessTierDays == 0 {
+			return fmt.Errorf("s3 %s: intelligentTiering %s needs archiveAccessTierDays or deepArchiveAccessTierDays", c.GetID(), tiering.Name)
+		}
+		if tiering.ArchiveAccessTierDays != 0 && (tiering.ArchiveAccessTierDays < 90 || tiering.ArchiveAccessTierDays > 730) {
+			return fmt.Errorf("s3 %s: intelligentTiering %s archiveAccessTierDays must be between 90 and 730", c.GetID(), tiering.Name)
+		}
+		if tiering.DeepArchiveAccessTierDays != 0 && (tiering.DeepArchiveAccessTierDays < 180 || tiering.DeepArchiveAccessTierDays > 730) {
+			return fmt.Errorf("s3 %s: intelligentTiering %s deepArchiveAccessTierDays must be between 180 and 730", c.GetID(), tiering.Name)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, ap := range c.AccessPoints {
+		if !accessPointNamePattern.MatchString(ap.Name) {
+			return fmt.Errorf("s3 %s: accessPoints[%d].name '%s' must be 3-50 lowercase letters, digits or hyphens", c.GetID(), i, ap.Name)
+		}
+		if names[ap.Name] {
+			return fmt.Errorf("s3 %s: duplicate access point name '%s'", c.GetID(), ap.Name)
+		}
+		names[ap.Name] = true
+	}
+	return nil
+}
+
+type S3Forge struct {
+	bucket         awss3.Bucket
+	accessPoints   map[string]awss3.CfnAccessPoint
+	properties     map[string]interface{}
+	fileSystemInfo dependency.FileSystemInfo
+}
+
+func (s *S3Forge) Create(ctx *interfaces.ForgeContext) interface{} {
+	s3Instance, ok := (*ctx.Instance).(*S3InstanceConfig)
+	if !ok {
+		// 处理类型断言失败的情况
+		return nil
+	}
+
+	props := &awss3.BucketProps{
+		Encryption:        awss3.BucketEncryption_S3_MANAGED,
+		Versioned:         jsii.Bool(types.GetBoolValue(s3Instance.Versioned, false)),
+		EnforceSSL:        jsii.Bool(types.GetBoolValue(s3Instance.EnforceSSL, true)),
+		RemovalPolicy:     utils.ParseRemovalPolicy(s3Instance.RemovalPolicy, awscdk.RemovalPolicy_RETAIN),
+		AutoDeleteObjects: jsii.Bool(types.GetBoolValue(s3Instance.AutoDeleteObjects, false)),
+	}
+	if s3Instance.BucketName != "" {
+		props.BucketName = jsii.String(s3Instance.BucketName)
+	}
+	if types.GetBoolValue(s3Instance.BlockPublicAccess, true) {
+		props.BlockPublicAccess = awss3.BlockPublicAccess_BLOCK_ALL()
+	}
+
+	// 加密方式
+	var encryptionKey awskms.IKey
+	if s3Instance.KmsKeyArn != "" {
+		encryptionKey = awskms.Key_FromKeyArn(ctx.Stack, jsii.String(s3Instance.GetID()+"KmsKey"), jsii.String(s3Instance.KmsKeyArn))
+	}
+	switch strings.ToLower(s3Instance.Encryption) {
+	case "kms":
+		props.Encryption = awss3.BucketEncryption_KMS_MANAGED
+		if encryptionKey != nil {
+			props.Encryption = awss3.BucketEncryption_KMS
+			props.EncryptionKey = encryptionKey
+		}
+		props.BucketKeyEnabled = jsii.Bool(types.GetBoolValue(s3Instance.BucketKeyEnabled, true))
+	case "dsse":
+		props.Encryption = awss3.BucketEncryption_DSSE_MANAGED
+		if encryptionKey != nil {
+			props.Encryption = awss3.BucketEncryption_DSSE
+			props.EncryptionKey = encryptionKey
+		}
+	}
+
+	// 生命周期规则
+	var lifecycleRules []*awss3.LifecycleRule
+	for i, ruleConfig := range s3Instance.LifecycleRules {
+		rule := &awss3.LifecycleRule{
+			Id:      jsii.String(ruleConfig.Id),
+			Enabled: jsii.Bool(true),
+		}
+		if ruleConfig.Id == "" {
+			rule.Id = jsii.String(fmt.Sprintf("%s-rule-%d", s3Instance.GetID(), i))
+		}
+		if ruleConfig.Prefix != "" {
+			rule.Prefix = jsii.String(ruleConfig.Prefix)
+		}
+		var transitions []*awss3.Transition
+		for _, transition := range ruleConfig.Transitions {
+			transitions = append(transitions, &awss3.Transition{
+				StorageClass:    storageClasses[strings.ToUpper(transition.StorageClass)](),
+				TransitionAfter: awscdk.Duration_Days(jsii.Number(transition.Days)),
+			})
+		}
+		if len(transitions) > 0 {
+			rule.Transitions = &transitions
+		}
+		if ruleConfig.ExpirationDays > 0 {
+			rule.Expiration = awscdk.Duration_Days(jsii.Number(ruleConfig.ExpirationDays))
+		}
+		if ruleConfig.NoncurrentVersionExpirationDays > 0 {
+			rule.NoncurrentVersionExpiration = awscdk.Duration_Days(jsii.Number(ruleConfig.NoncurrentVersionExpirationDays))
+		}
+		if ruleConfig.AbortIncompleteMultipartUploadDays > 0 {
+			rule.AbortIncompleteMultipartUploadAfter = awscdk.Duration_Days(jsii.Number(ruleConfig.AbortIncompleteMultipartUploadDays))
+		}
+		lifecycleRules = append(lifecycleRules, rule)
+	}
+	if len(lifecycleRules) > 0 {
+		props.LifecycleRules = &lifecycleRules
+	}
+
+	// 智能分层归档配置
+	var tieringConfigs []*awss3.IntelligentTieringConfiguration
+	for _, tiering := range s3Instance.IntelligentTiering {
+		tieringConfig := &awss3.IntelligentTieringConfiguration{
+			Name: jsii.String(tiering.Name),
+		}
+		if tiering.Prefix != "" {
+			tieringConfig.Prefix = jsii.String(tiering.Prefix)
+		}
+		if tiering.ArchiveAccessTierDays > 0 {
+			tieringConfig.ArchiveAccessTierTime = awscdk.Duration_Days(jsii.Number(tiering.ArchiveAccessTierDays))
+		}
+		if tiering.DeepArchiveAccessTierDays > 0 {
+			tieringConfig.DeepArchiveAccessTierTime = awscdk.Duration_Days(jsii.Number(tiering.DeepArchiveAccessTierDays))
+		}
+		tieringConfigs = append(tieringConfigs, tieringConfig)
+	}
+	if len(tieringConfigs) > 0 {
+		props.IntelligentTieringConfigurations = &tieringConfigs
+	}
+
+	bucket := awss3.NewBucket(ctx.Stack, jsii.String(s3Instance.GetID()), props)
+	s.bucket = bucket
+
+	// 创建访问点
+	s.accessPoints = make(map[string]awss3.CfnAccessPoint)
+	accessPoints := make(map[string]interface{})
+	for _, apConfig := range s3Instance.AccessPoints {
+		apProps := &awss3.CfnAccessPointProps{
+			Bucket: bucket.BucketName(),
+			Name:   jsii.String(apConfig.Name),
+		}
+		if types.GetBoolValue(apConfig.VpcOnly, false) {
+			apProps.VpcConfiguration = &awss3.CfnAccessPoint_VpcConfigurationProperty{
+				VpcId: ctx.VPC.VpcId(),
+			}
+		}
+		accessPoint := awss3.NewCfnAccessPoint(ctx.Stack, jsii.String(s3Instance.GetID()+"AccessPoint"+apConfig.Name), apProps)
+		s.accessPoints[apConfig.Name] = accessPoint
+		accessPoints[apConfig.Name] = map[string]interface{}{
+			"arn":   accessPoint.AttrArn(),
+			"alias": accessPoint.AttrAlias(),
+		}
+	}
+
+	// 保存 S3 属性
+	if s.properties == nil {
+		s.properties = make(map[string]interface{})
+	}
+	s.properties["bucketName"] = bucket.BucketName()
+	s.properties["bucketArn"] = bucket.BucketArn()
+	s.properties["region"] = ctx.Stack.Region()
+	s.properties["s3Uri"] = awscdk.Fn_Join(jsii.String(""), &[]*string{jsii.String("s3://"), bucket.BucketName()})
+	s.properties["mountPoint"] = "/" + s3Instance.GetID() // 挂载点，nas 模块使用 Mountpoint for Amazon S3 挂载
+	s.properties["accessPoints"] = accessPoints           // 访问点名称 -> arn、alias
+
+	s.fileSystemInfo = dependency.FileSystemInfo{
+		Type:         "S3",
+		FileSystemId: *bucket.BucketName(),
+		MountPoint:   "/" + s3Instance.GetID(),
+		Protocol:     "s3",
+	}
+
+	return s
+}
+
+// BackupResourceArns 实现 backup.Protectable
+func (s *S3Forge) BackupResourceArns() []*string {
+	return []*string{s.bucket.BucketArn()}
+}
+
+// FileSystem 实现 dependency.FileSystemProvider，FileSystemId 为存储桶名称
+func (s *S3Forge) FileSystem() dependency.FileSystemInfo {
+	return s.fileSystemInfo
+}
+
+// GetBucket 返回存储桶
+func (s *S3Forge) GetBucket() awss3.Bucket {
+	return s.bucket
+}
+
+func (s *S3Forge) CreateOutputs(ctx *interfaces.ForgeContext) {
+	s3Instance, ok := (*ctx.Instance).(*S3InstanceConfig)
+	if !ok {
+		// 处理类型断言失败的情况
+		return
+	}
+
+	awscdk.NewCfnOutput(ctx.Stack, jsii.String("S3Bucket"+s3Instance.GetID()), &awscdk.CfnOutputProps{
+		Value:       s.bucket.BucketName(),
+		Description: jsii.String("S3 Bucket Name"),
+	})
+
+	for _, apConfig := range s3Instance.AccessPoints {
+		awscdk.NewCfnOutput(ctx.Stack, jsii.String("S3Bucket"+s3Instance.GetID()+"AccessPoint"+apConfig.Name), &awscdk.CfnOutputProps{
+			Value:       s.accessPoints[apConfig.Name].AttrAlias(),
+			Description: jsii.String(fmt.Sprintf("S3 access point %s alias", apConfig.Name)),
+		})
+	}
+}
+
+func (s *S3Forge) ConfigureRules(ctx *interfaces.ForgeContext) {
+	// S3 通过 IAM 控制访问，不需要安全组规则
+}
+
+func (s *S3Forge) MergeConfigs(defaults config.InstanceConfig, instance config.InstanceConfig) config.InstanceConfig {
+	// 从默认配置中复制基本字段
+	merged := defaults.(*S3InstanceConfig)
+
+	// 从实例配置中覆盖基本字段
+	s3Instance := instance.(*S3InstanceConfig)
+	if instance != nil {
+		if s3Instance.GetID() != "" {
+			merged.ID = s3Instance.GetID()
+		}
+		if s3Instance.Type != "" {
+			merged.Type = s3Instance.GetType()
+		}
+		if s3Instance.Subnet != "" {
+			merged.Subnet = s3Instance.GetSubnet()
+		}
+		if s3Instance.SecurityGroup != "" {
+			merged.SecurityGroup = s3Instance.GetSecurityGroup()
+		}
+	}
+
+	if s3Instance.BucketName != "" {
+		merged.BucketName = s3Instance.BucketName
+	}
+	if s3Instance.Encryption != "" {
+		merged.Encryption = s3Instance.Encryption
+	}
+	if s3Instance.KmsKeyArn != "" {
+		merged.KmsKeyArn = s3Instance.KmsKeyArn
+	}
+	if s3Instance.BucketKeyEnabled != nil {
+		merged.BucketKeyEnabled = s3Instance.BucketKeyEnabled
+	}
+	if s3Instance.Versioned != nil {
+		merged.Versioned = s3Instance.Versioned
+	}
+	if s3Instance.BlockPublicAccess != nil {
+		merged.BlockPublicAccess = s3Instance.BlockPublicAccess
+	}
+	if s3Instance.EnforceSSL != nil {
+		merged.EnforceSSL = s3Instance.EnforceSSL
+	}
+	if len(s3Instance.LifecycleRules) > 0 {
+		merged.LifecycleRules = s3Instance.LifecycleRules
+	}
+	if len(s3Instance.IntelligentTiering) > 0 {
+		merged.IntelligentTiering = s3Instance.IntelligentTiering
+	}
+	if len(s3Instance.AccessPoints) > 0 {
+		merged.AccessPoints = s3Instance.AccessPoints
+	}
+	if s3Instance.RemovalPolicy != "" {
+		merged.RemovalPolicy = s3Instance.RemovalPolicy
+	}
+	if s3Instance.BackupPlan != "" {
+		merged.BackupPlan = s3Instance.BackupPlan
+	}
+	if s3Instance.AutoDeleteObjects != nil {
+		merged.AutoDeleteObjects = s3Instance.AutoDeleteObjects
+	}
+
+	if merged.RemovalPolicy == "" {
+		merged.RemovalPolicy = "RETAIN"
+	}
+
+	return merged
+}
+
+func (s *S3Forge) GetProperties() map[string]interface{} {
+	return s.properties
+}
@@ -0,0 +1,94 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package s3
+
+import (
+	"testing"
+
+	"github.com/aws/jsii-runtime-go"
+	"github.com/awslabs/InfraForge/core/config"
+)
+
+func TestS3MergeConfigsDefaults(t *testing.T) {
+	forge := &S3Forge{}
+	merged := forge.MergeConfigs(&S3InstanceConfig{}, &S3InstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "datasets", Type: "S3"},
+	}).(*S3InstanceConfig)
+
+	if merged.RemovalPolicy != "RETAIN" {
+		t.Errorf("Expected RemovalPolicy 'RETAIN', got %q", merged.RemovalPolicy)
+	}
+	if err := merged.Validate(); err != nil {
+		t.Errorf("Expected defaults to be valid, got %v", err)
+	}
+}
+
+func TestS3ValidateValid(t *testing.T) {
+	tests := []struct {
+		name     string
+		instance S3InstanceConfig
+	}{
+		{"kms with key", S3InstanceConfig{Encryption: "kms", KmsKeyArn: "arn:aws:kms:us-east-1:123456789012:key/abc"}},
+		{"dsse", S3InstanceConfig{Encryption: "DSSE"}},
+		{"auto delete with destroy", S3InstanceConfig{RemovalPolicy: "destroy", AutoDeleteObjects: jsii.Bool(true)}},
+		{"backup with versioning", S3InstanceConfig{Versioned: jsii.Bool(true), BackupPlan: "daily"}},
+		{"lifecycle", S3InstanceConfig{Versioned: jsii.Bool(true), LifecycleRules: []S3LifecycleRuleConfig{{
+			Prefix:                          "logs/",
+			Transitions:                     []S3TransitionConfig{{StorageClass: "glacier", Days: 90}},
+			ExpirationDays:                  365,
+			NoncurrentVersionExpirationDays: 30,
+		}}}},
+		{"intelligent tiering", S3InstanceConfig{IntelligentTiering: []S3IntelligentTieringConfig{
+			{Name: "archive", ArchiveAccessTierDays: 90, DeepArchiveAccessTierDays: 180},
+		}}},
+		{"access points", S3InstanceConfig{AccessPoints: []S3AccessPointConfig{{Name: "training"}, {Name: "inference", VpcOnly: jsii.Bool(false)}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.instance.ID = "datasets"
+			if err := tt.instance.Validate(); err != nil {
+				t.Errorf("Expected %s to be valid, got %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestS3Validate(t *testing.T) {
+	forge := &S3Forge{}
+	tests := []struct {
+		name     string
+		instance S3InstanceConfig
+	}{
+		{"unknown encryption", S3InstanceConfig{Encryption: "aes"}},
+		{"kms key without kms encryption", S3InstanceConfig{KmsKeyArn: "arn:aws:kms:us-east-1:123456789012:key/abc"}},
+		{"auto delete with retain", S3InstanceConfig{AutoDeleteObjects: jsii.Bool(true)}},
+		{"backup without versioning", S3InstanceConfig{BackupPlan: "daily"}},
+		{"lifecycle without action", S3InstanceConfig{LifecycleRules: []S3LifecycleRuleConfig{{Prefix: "logs/"}}}},
+		{"unknown storage class", S3InstanceConfig{LifecycleRules: []S3LifecycleRuleConfig{{
+			Transitions: []S3TransitionConfig{{StorageClass: "COLD", Days: 30}},
+		}}}},
+		{"negative transition days", S3InstanceConfig{LifecycleRules: []S3LifecycleRuleConfig{{
+			Transitions: []S3TransitionConfig{{StorageClass: "GLACIER", Days: -1}},
+		}}}},
+		{"negative expiration", S3InstanceConfig{LifecycleRules: []S3LifecycleRuleConfig{{ExpirationDays: -1}}}},
+		{"noncurrent expiration without versioning", S3InstanceConfig{LifecycleRules: []S3LifecycleRuleConfig{{NoncurrentVersionExpirationDays: 30}}}},
+		{"tiering without name", S3InstanceConfig{IntelligentTiering: []S3IntelligentTieringConfig{{ArchiveAccessTierDays: 90}}}},
+		{"tiering without tier", S3InstanceConfig{IntelligentTiering: []S3IntelligentTieringConfig{{Name: "archive"}}}},
+		{"archive tier too early", S3InstanceConfig{IntelligentTiering: []S3IntelligentTieringConfig{{Name: "archive", ArchiveAccessTierDays: 30}}}},
+		{"deep archive tier too late", S3InstanceConfig{IntelligentTiering: []S3IntelligentTieringConfig{{Name: "archive", DeepArchiveAccessTierDays: 800}}}},
+		{"invalid access point name", S3InstanceConfig{AccessPoints: []S3AccessPointConfig{{Name: "Training"}}}},
+		{"duplicate access point", S3InstanceConfig{AccessPoints: []S3AccessPointConfig{{Name: "training"}, {Name: "training"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.instance.ID = "datasets"
+			merged := forge.MergeConfigs(&S3InstanceConfig{}, &tt.instance).(*S3InstanceConfig)
+			if err := merged.Validate(); err == nil {
+				t.Errorf("Expected validation error for %s", tt.name)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LustreSpec 参与 FSx for Lustre 规则校验的配置
+type LustreSpec struct {
+	DeploymentType           string
+	StorageType              string
+	StorageCapacityGiB       int
+	PerUnitStorageThroughput float64
+	DataCompressionType      string
+	FileSystemVersion        string
+}
+
+// capacityRule 存储容量规则：允许的固定值，超过后按 step 递增
+type capacityRule struct {
+	fixed []int
+	step  int
+}
+
+// lustreThroughputs 各部署类型/存储类型允许的每 TiB 吞吐量（MB/s/TiB）
+var lustreThroughputs = map[string][]float64{
+	"PERSISTENT_1/SSD": {50, 100, 200},
+	"PERSISTENT_1/HDD": {12, 40},
+	"PERSISTENT_2/SSD": {125, 250, 500, 1000},
+}
+
+// NormalizeLustreDeploymentType 将部署类型规范化为 SCRATCH_1、SCRATCH_2、PERSISTENT_1、PERSISTENT_2，空值为 SCRATCH_2，无法识别时返回空字符串
+func NormalizeLustreDeploymentType(input string) string {
+	switch cleanLustreOption(input) {
+	case "", "scratch2":
+		return "SCRATCH_2"
+	case "scratch1":
+		return "SCRATCH_1"
+	case "persistent1":
+		return "PERSISTENT_1"
+	case "persistent2":
+		return "PERSISTENT_2"
+	}
+	return ""
+}
+
+// normalizeLustreStorageType 将存储类型规范化为 SSD、HDD、INTELLIGENT_TIERING，空值为 SSD
+func normalizeLustreStorageType(input string) string {
+	switch cleanLustreOption(input) {
+	case "", "ssd":
+		return "SSD"
+	case "hdd":
+		return "HDD"
+	case "intelligenttiering":
+		return "INTELLIGENT_TIERING"
+	}
+	return ""
+}
+
+func cleanLustreOption(input string) string {
+	cleaned := strings.ToLower(strings.TrimSpace(input))
+	cleaned = strings.ReplaceAll(cleaned, "_", "")
+	return strings.ReplaceAll(cleaned, "-", "")
+}
+
+// DefaultLustreThroughput 返回部署类型/存储类型的默认每 TiB 吞吐量，SCRATCH 返回 0
+func DefaultLustreThroughput(deploymentType, storageType string) float64 {
+	switch NormalizeLustreDeploymentType(deploymentType) {
+	case "PERSISTENT_1":
+		if normalizeLustreStorageType(storageType) == "HDD" {
+			return 12
+		}
+		return 50
+	case "PERSISTENT_2":
+		return 125
+	}
+	return 0
+}
+
+// ValidateLustreSpec 按 FSx 规则校验部署类型、存储类型、容量、吞吐量和压缩配置，返回包含建议值的错误；
+// 不影响部署的配置（如 SCRATCH 上被忽略的吞吐量）作为告警返回
+func ValidateLustreSpec(spec LustreSpec) ([]string, error) {
+	var warnings []string
+	deploymentType := NormalizeLustreDeploymentType(spec.DeploymentType)
+	if deploymentType == "" {
+		return nil, fmt.Errorf("unknown deploymentType '%s', expected SCRATCH_1, SCRATCH_2, PERSISTENT_1 or PERSISTENT_2", spec.DeploymentType)
+	}
+	storageType := normalizeLustreStorageType(spec.StorageType)
+	if storageType == "" {
+		return nil, fmt.Errorf("unknown storageType '%s', expected SSD, HDD or INTELLIGENT_TIERING", spec.StorageType)
+	}
+
+	var problems []string
+
+	// 存储类型与部署类型
+	switch {
+	case storageType == "HDD" && deploymentType != "PERSISTENT_1":
+		problems = append(problems, fmt.Sprintf("storageType HDD is only supported with PERSISTENT_1, not %s (use SSD or deploymentType PERSISTENT_1)", deploymentType))
+	case storageType == "INTELLIGENT_TIERING" && deploymentType != "PERSISTENT_2":
+		problems = append(problems, fmt.Sprintf("storageType INTELLIGENT_TIERING is only supported with PERSISTENT_2, not %s", deploymentType))
+	}
+
+	// 文件系统版本
+	version := spec.FileSystemVersion
+	if version == "" {
+		version = "2.15"
+	}
+	if deploymentType == "PERSISTENT_2" && version == "2.10" {
+		problems = append(problems, "PERSISTENT_2 requires fileSystemVersion 2.12 or later (suggested: 2.15)")
+	}
+	if cleanLustreOption(spec.DataCompressionType) == "lz4" && version == "2.10" {
+		problems = append(problems, "dataCompressionType LZ4 requires fileSystemVersion 2.12 or later (suggested: 2.15, or set dataCompressionType NONE)")
+	}
+
+	// 每 TiB 吞吐量
+	if strings.HasPrefix(deploymentType, "SCRATCH") {
+		if spec.PerUnitStorageThroughput > 0 {
+			warnings = append(warnings, fmt.Sprintf("perUnitStorageThroughput is ignored for %s, remove it or use a PERSISTENT deploymentType", deploymentType))
+		}
+	} else if allowed, ok := lustreThroughputs[deploymentType+"/"+storageType]; ok && !containsFloat(allowed, spec.PerUnitStorageThroughput) {
+		problems = append(problems, fmt.Sprintf("perUnitStorageThroughput %v is invalid for %s %s, expected %s (nearest valid: %v)",
+			spec.PerUnitStorageThroughput, deploymentType, storageType, joinFloats(allowed), nearestFloat(allowed, spec.PerUnitStorageThroughput)))
+	}
+
+	// 存储容量，INTELLIGENT_TIERING 不按容量计费
+	if storageType != "INTELLIGENT_TIERING" {
+		rule := lustreCapacityRule(deploymentType, storageType, spec.PerUnitStorageThroughput)
+		if !rule.valid(spec.StorageCapacityGiB) {
+			problems = append(problems, fmt.Sprintf("storageCapacityGiB %d is invalid for %s %s, expected %s (nearest valid: %d)",
+				spec.StorageCapacityGiB, deploymentType, storageType, rule.describe(), rule.nearest(spec.StorageCapacityGiB)))
+		}
+	}
+
+	if len(problems) > 0 {
+		return warnings, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return warnings, nil
+}
+
+// lustreCapacityRule 返回部署类型对应的容量规则
+func lustreCapacityRule(deploymentType, storageType string, throughput float64) capacityRule {
+	switch {
+	case deploymentType == "SCRATCH_1":
+		return capacityRule{fixed: []int{1200, 2400}, step: 3600}
+	case storageType == "HDD" && throughput == 40:
+		return capacityRule{step: 1800}
+	case storageType == "HDD":
+		return capacityRule{step: 6000}
+	default:
+		return capacityRule{fixed: []int{1200}, step: 2400}
+	}
+}
+
+func (r capacityRule) valid(capacity int) bool {
+	for _, value := range r.fixed {
+		if capacity == value {
+			return true
+		}
+	}
+	return capacity > 0 && capacity%r.step == 0
+}
+
+// nearest 返回最接近的有效容量，距离相同时取较大值
+func (r capacityRule) nearest(capacity int) int {
+	candidates := append([]int{}, r.fixed...)
+	lower := capacity / r.step * r.step
+	if lower > 0 {
+		candidates = append(candidates, lower)
+	}
+	candidates = append(candidates, lower+r.step)
+
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		distance, bestDistance := abs(candidate-capacity), abs(best-capacity)
+		if distance < bestDistance || (distance == bestDistance && candidate > best) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+func (r capacityRule) describe() string {
+	var parts []string
+	for _, value := range r.fixed {
+		parts = append(parts, fmt.Sprintf("%d", value))
+	}
+	parts = append(parts, fmt.Sprintf("a multiple of %d", r.step))
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " or " + parts[len(parts)-1]
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func containsFloat(values []float64, value float64) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func nearestFloat(values []float64, value float64) float64 {
+	best := values[0]
+	for _, v := range values[1:] {
+		if d, bestD := v-value, best-value; d*d < bestD*bestD {
+			best = v
+		}
+	}
+	return best
+}
+
+func joinFloats(values []float64) string {
+	var parts []string
+	for _, v := range values {
+		parts = append(parts, fmt.Sprintf("%v", v))
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " or " + parts[len(parts)-1]
+}
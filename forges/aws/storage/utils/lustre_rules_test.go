@@ -0,0 +1,58 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateLustreSpecValid(t *testing.T) {
+	tests := []LustreSpec{
+		{StorageCapacityGiB: 1200},
+		{DeploymentType: "scratch_1", StorageCapacityGiB: 3600},
+		{DeploymentType: "PERSISTENT_1", StorageCapacityGiB: 4800, PerUnitStorageThroughput: 100},
+		{DeploymentType: "PERSISTENT_1", StorageType: "HDD", StorageCapacityGiB: 6000, PerUnitStorageThroughput: 12},
+		{DeploymentType: "PERSISTENT_1", StorageType: "HDD", StorageCapacityGiB: 3600, PerUnitStorageThroughput: 40},
+		{DeploymentType: "persistent2", StorageCapacityGiB: 2400, PerUnitStorageThroughput: 1000, DataCompressionType: "LZ4"},
+	}
+	for _, spec := range tests {
+		if _, err := ValidateLustreSpec(spec); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", spec, err)
+		}
+	}
+}
+
+func TestValidateLustreSpecSuggestions(t *testing.T) {
+	tests := []struct {
+		name string
+		spec LustreSpec
+		want string
+	}{
+		{"capacity increment", LustreSpec{DeploymentType: "SCRATCH_2", StorageCapacityGiB: 3000}, "nearest valid: 2400"},
+		{"capacity rounds up on tie", LustreSpec{DeploymentType: "PERSISTENT_2", StorageCapacityGiB: 6000, PerUnitStorageThroughput: 125}, "nearest valid: 7200"},
+		{"persistent2 throughput", LustreSpec{DeploymentType: "PERSISTENT_2", StorageCapacityGiB: 1200, PerUnitStorageThroughput: 200}, "nearest valid: 250"},
+		{"hdd on persistent2", LustreSpec{DeploymentType: "PERSISTENT_2", StorageType: "HDD", StorageCapacityGiB: 1200, PerUnitStorageThroughput: 125}, "only supported with PERSISTENT_1"},
+		{"compression on 2.10", LustreSpec{StorageCapacityGiB: 1200, DataCompressionType: "LZ4", FileSystemVersion: "2.10"}, "requires fileSystemVersion 2.12"},
+		{"unknown deployment type", LustreSpec{DeploymentType: "PERSISTENT_3"}, "unknown deploymentType"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ValidateLustreSpec(tt.spec)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestValidateLustreSpecScratchThroughputWarning(t *testing.T) {
+	warnings, err := ValidateLustreSpec(LustreSpec{DeploymentType: "scratch2", StorageCapacityGiB: 1200, PerUnitStorageThroughput: 250})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "ignored for SCRATCH_2") {
+		t.Errorf("Expected a warning about ignored throughput, got %v", warnings)
+	}
+}
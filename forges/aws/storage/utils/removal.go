@@ -0,0 +1,29 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"strings"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2"
+)
+
+// ParseRemovalPolicy 解析删除策略，支持 destroy、retain、snapshot，无法识别时使用 defaultPolicy
+func ParseRemovalPolicy(input string, defaultPolicy awscdk.RemovalPolicy) awscdk.RemovalPolicy {
+	// 将输入转换为小写并移除分隔符
+	cleaned := strings.ToLower(input)
+	cleaned = strings.ReplaceAll(cleaned, "_", "")
+	cleaned = strings.ReplaceAll(cleaned, "-", "")
+
+	switch cleaned {
+	case "destroy":
+		return awscdk.RemovalPolicy_DESTROY
+	case "retain":
+		return awscdk.RemovalPolicy_RETAIN
+	case "snapshot":
+		return awscdk.RemovalPolicy_SNAPSHOT
+	default:
+		return defaultPolicy
+	}
+}
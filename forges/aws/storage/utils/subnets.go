@@ -0,0 +1,43 @@
+// Copyright Amazon.com, Inc. or its affiliates. All Rights Reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+package utils
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
+	"github.com/awslabs/InfraForge/core/utils/aws"
+)
+
+// FsxSubnets FSx 文件系统使用的子网，多可用区部署时包含两个子网及其路由表
+type FsxSubnets struct {
+	SubnetIds         []*string
+	PreferredSubnetId *string
+	RouteTableIds     []*string
+}
+
+// SelectFsxSubnets 选择 FSx 子网，单可用区使用 azIndex 指定的子网，多可用区额外选择另一个可用区的子网
+func SelectFsxSubnets(vpc awsec2.IVpc, subnetType awsec2.SubnetType, azIndex int, multiAz bool) (*FsxSubnets, error) {
+	preferred := aws.SelectSubnetByAzIndex(azIndex, vpc, subnetType)
+	result := &FsxSubnets{
+		SubnetIds:         []*string{preferred.SubnetId()},
+		PreferredSubnetId: preferred.SubnetId(),
+	}
+	if !multiAz {
+		return result, nil
+	}
+
+	// 选择与首选子网不同可用区的第一个子网作为备用子网
+	allSubnets := *vpc.SelectSubnets(&awsec2.SubnetSelection{SubnetType: subnetType}).Subnets
+	for _, subnet := range allSubnets {
+		if *subnet.AvailabilityZone() == *preferred.AvailabilityZone() {
+			continue
+		}
+		result.SubnetIds = append(result.SubnetIds, subnet.SubnetId())
+		result.RouteTableIds = []*string{preferred.RouteTable().RouteTableId(), subnet.RouteTable().RouteTableId()}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("multi-AZ deployment requires subnets of type %v in at least two availability zones", subnetType)
+}
@@ -4,10 +4,13 @@
 package vpc
 
 import (
+	"fmt"
+	"net"
 	"strings"
 
 	"github.com/awslabs/InfraForge/core/config"
 	"github.com/awslabs/InfraForge/core/interfaces"
+	"github.com/awslabs/InfraForge/core/security"
 	"github.com/awslabs/InfraForge/core/utils/aws"
 	"github.com/awslabs/InfraForge/core/utils/types"
 	"github.com/aws/aws-cdk-go/awscdk/v2"
@@ -20,13 +23,102 @@ type VpcInstanceConfig struct {
 	VpcId		 string `json:"vpcId"`
 	CidrBlock        string `json:"cidrBlock"`
 	NatGatewayPerAZ  *bool  `json:"natGatewayPerAZ,omitempty"`
+	PrefixLists      []PrefixListConfig `json:"prefixLists,omitempty"`
+}
+
+// PrefixListConfig 托管前缀列表配置，Cidrs 用于创建新的前缀列表，Id 用于引用已有前缀列表
+// 其他配置可通过 "pl:name" 引用，如 allowedPorts 中的 "22@pl:corp-egress"
+type PrefixListConfig struct {
+	Name          string   `json:"name"`
+	Id            string   `json:"id,omitempty"`
+	Cidrs         []string `json:"cidrs,omitempty"`
+	MaxEntries    int      `json:"maxEntries,omitempty"`
+	AddressFamily string   `json:"addressFamily,omitempty"` // IPv4（默认）或 IPv6
+}
+
+// Validate 校验前缀列表配置
+func (c *VpcInstanceConfig) Validate() error {
+	names := make(map[string]bool)
+	for i, pl := range c.PrefixLists {
+		if pl.Name == "" {
+			return fmt.Errorf("vpc prefixLists[%d]: name is required", i)
+		}
+		if names[pl.Name] {
+			return fmt.Errorf("vpc prefixLists[%d]: duplicate name '%s'", i, pl.Name)
+		}
+		names[pl.Name] = true
+
+		if (pl.Id == "") == (len(pl.Cidrs) == 0) {
+			return fmt.Errorf("vpc prefixLists[%d] %s: specify exactly one of id or cidrs", i, pl.Name)
+		}
+		if pl.Id != "" && !strings.HasPrefix(pl.Id, "pl-") {
+			return fmt.Errorf("vpc prefixLists[%d] %s: id must start with 'pl-', got '%s'", i, pl.Name, pl.Id)
+		}
+		if pl.AddressFamily != "" && pl.AddressFamily != "IPv4" && pl.AddressFamily != "IPv6" {
+			return fmt.Errorf("vpc prefixLists[%d] %s: addressFamily must be IPv4 or IPv6, got '%s'", i, pl.Name, pl.AddressFamily)
+		}
+		for _, cidr := range pl.Cidrs {
+			ip, _, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return fmt.Errorf("vpc prefixLists[%d] %s: invalid CIDR '%s'", i, pl.Name, cidr)
+			}
+			if (ip.To4() != nil) != (pl.AddressFamily != "IPv6") {
+				return fmt.Errorf("vpc prefixLists[%d] %s: CIDR '%s' does not match address family", i, pl.Name, cidr)
+			}
+		}
+		if pl.MaxEntries > 0 && pl.MaxEntries < len(pl.Cidrs) {
+			return fmt.Errorf("vpc prefixLists[%d] %s: maxEntries %d is less than the %d cidrs", i, pl.Name, pl.MaxEntries, len(pl.Cidrs))
+		}
+	}
+	return nil
 }
 
 type VpcForge struct {
         vpc      awsec2.IVpc
+        prefixLists map[string]*string
         properties map[string]interface{}
 }
 
+// createPrefixLists 创建或引用托管前缀列表，并注册到安全组规则注册表供 "pl:name" 引用
+func (v *VpcForge) createPrefixLists(ctx *interfaces.ForgeContext, vpcInstance *VpcInstanceConfig) {
+	v.prefixLists = make(map[string]*string)
+	for _, pl := range vpcInstance.PrefixLists {
+		var prefixListId *string
+		if pl.Id != "" {
+			prefixListId = jsii.String(pl.Id)
+		} else {
+			addressFamily := awsec2.AddressFamily_IP_V4
+			if pl.AddressFamily == "IPv6" {
+				addressFamily = awsec2.AddressFamily_IP_V6
+			}
+			maxEntries := pl.MaxEntries
+			if maxEntries == 0 {
+				maxEntries = len(pl.Cidrs)
+			}
+			entries := make([]*awsec2.CfnPrefixList_EntryProperty, 0, len(pl.Cidrs))
+			for _, cidr := range pl.Cidrs {
+				entries = append(entries, &awsec2.CfnPrefixList_EntryProperty{
+					Cidr: jsii.String(cidr),
+				})
+			}
+			prefixList := awsec2.NewPrefixList(ctx.Stack, jsii.String("PrefixList-"+pl.Name), &awsec2.PrefixListProps{
+				PrefixListName: jsii.String(fmt.Sprintf("%s-%s", *ctx.Stack.StackName(), pl.Name)),
+				AddressFamily:  addressFamily,
+				MaxEntries:     jsii.Number(float64(maxEntries)),
+				Entries:        &entries,
+			})
+			prefixListId = prefixList.PrefixListId()
+		}
+		v.prefixLists[pl.Name] = prefixListId
+		security.GlobalRuleRegistry.RegisterPrefixList(pl.Name, *prefixListId)
+	}
+
+	if v.properties == nil {
+		v.properties = make(map[string]interface{})
+	}
+	v.properties["prefixLists"] = v.prefixLists
+}
+
 func (v *VpcForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	vpcInstance, ok := (*ctx.Instance).(*VpcInstanceConfig)
 	if !ok {
@@ -48,6 +140,8 @@ func (v *VpcForge) Create(ctx *interfaces.ForgeContext) interface{} {
 		}
 		v.properties["vpcId"] = vpcInstance.VpcId
 		v.properties["isExisting"] = true
+
+		v.createPrefixLists(ctx, vpcInstance)
 		
 		return v  // 返回 VpcForge 自身
 	}
@@ -115,6 +209,8 @@ func (v *VpcForge) Create(ctx *interfaces.ForgeContext) interface{} {
 	v.properties["vpcId"] = newVpc.VpcId()
 	v.properties["cidrBlock"] = vpcInstance.CidrBlock
 	v.properties["isExisting"] = false
+
+	v.createPrefixLists(ctx, vpcInstance)
 	
         return v
 }
@@ -130,6 +226,13 @@ func (v *VpcForge) CreateOutputs(ctx *interfaces.ForgeContext) {
 		Description: jsii.String("VPC CIDR Block"),
 	})
 
+	for name, prefixListId := range v.prefixLists {
+		awscdk.NewCfnOutput(ctx.Stack, jsii.String("PrefixListId-"+name), &awscdk.CfnOutputProps{
+			Value:       prefixListId,
+			Description: jsii.String(fmt.Sprintf("Managed prefix list %s", name)),
+		})
+	}
+
 	publicSubnets := v.vpc.PublicSubnets()
 	publicSubnetIds := make([]string, 0, len(*publicSubnets))
 	for _, subnet := range *publicSubnets {
@@ -195,22 +298,8 @@ func (v *VpcForge) CreateOutputs(ctx *interfaces.ForgeContext) {
 }
 
 func (v *VpcForge) ConfigureRules(ctx *interfaces.ForgeContext) {
-	// 为 Public subnet 配置特定的入站规则
-	// 打开 Public Subnet 打开端口 22, 80, 443, 8443
-	return
-	if ctx.DualStack {
-		ctx.SecurityGroups.Public.AddIngressRule(awsec2.Peer_AnyIpv6(), awsec2.Port_Tcp(jsii.Number(8443)), jsii.String("Allow HTTP access"), nil)
-		ctx.SecurityGroups.Public.AddIngressRule(awsec2.Peer_AnyIpv6(), awsec2.Port_Tcp(jsii.Number(443)), jsii.String("Allow HTTP access"), nil)
-		ctx.SecurityGroups.Public.AddIngressRule(awsec2.Peer_AnyIpv6(), awsec2.Port_Tcp(jsii.Number(80)), jsii.String("Allow HTTP access"), nil)
-		//publicSG.AddIngressRule(awsec2.Peer_AnyIpv6(), awsec2.Port_Tcp(jsii.Number(22)), jsii.String("Allow SSH access"), nil)
-	}
-
-	ctx.SecurityGroups.Public.AddIngressRule(awsec2.Peer_AnyIpv4(), awsec2.Port_Tcp(jsii.Number(8443)), jsii.String("Allow HTTP access"), nil)
-	ctx.SecurityGroups.Public.AddIngressRule(awsec2.Peer_AnyIpv4(), awsec2.Port_Tcp(jsii.Number(443)), jsii.String("Allow HTTP access"), nil)
-	ctx.SecurityGroups.Public.AddIngressRule(awsec2.Peer_AnyIpv4(), awsec2.Port_Tcp(jsii.Number(80)), jsii.String("Allow HTTP access"), nil)
-	//ctx.SecurityGroups.Public.AddIngressRule(awsec2.Peer_AnyIpv4(), awsec2.Port_Tcp(jsii.Number(22)), jsii.String("Allow SSH access"), nil)
-	//ctx.SecurityGroups.Private.AddIngressRule(ctx.SecurityGroups.Public, awsec2.Port_TcpRange(jsii.Number(22), jsii.Number(22)), jsii.String("Allow SSH access from public subnet"), nil)
-
+	// 公有子网默认不开放任何端口（此前的 22/80/443/8443 全网开放规则已停用）
+	// 需要的端口由各实例的 allowedPorts 或 ingress 配置，通过注册表添加并出现在审计报告中
 }
 
 func CreateSecurityGroups(stack awscdk.Stack, vpc awsec2.IVpc, dualStack bool) (publicSG, privateSG, isolatedSG awsec2.SecurityGroup) {
@@ -228,8 +317,8 @@ func CreateSecurityGroups(stack awscdk.Stack, vpc awsec2.IVpc, dualStack bool) (
 		AllowAllOutbound: jsii.Bool(true),
 	})
 
-	privateSG.AddIngressRule(publicSG, awsec2.Port_AllTraffic(), jsii.String("Allow access from public subnet"), nil)
-	privateSG.AddIngressRule(privateSG, awsec2.Port_AllTraffic(), jsii.String("Allow access within private subnet"), nil)
+	security.AddAllTrafficIngressRule(privateSG, publicSG, "Allow access from public subnet")
+	security.AddAllTrafficIngressRule(privateSG, privateSG, "Allow access within private subnet")
 
 	isolatedSG = awsec2.NewSecurityGroup(stack, jsii.String("IsolatedSG"), &awsec2.SecurityGroupProps{
 		Vpc:              vpc,
@@ -267,6 +356,10 @@ func (v *VpcForge) MergeConfigs(defaults config.InstanceConfig, instance config.
 		merged.CidrBlock = vpcInstance.CidrBlock
 	}
 
+	if len(vpcInstance.PrefixLists) > 0 {
+		merged.PrefixLists = vpcInstance.PrefixLists
+	}
+
 	return merged
 }
 
@@ -4,9 +4,14 @@
 package vpc
 
 import (
+	"encoding/json"
+	"strings"
 	"testing"
 	
 	"github.com/awslabs/InfraForge/core/config"
+	"github.com/awslabs/InfraForge/core/interfaces"
+	"github.com/awslabs/InfraForge/core/security"
+	"github.com/awslabs/InfraForge/core/utils/types"
 	"github.com/aws/aws-cdk-go/awscdk/v2"
 	"github.com/aws/aws-cdk-go/awscdk/v2/awsec2"
 	"github.com/aws/jsii-runtime-go"
@@ -25,7 +30,7 @@ func TestVpcForge_MergeConfigs(t *testing.T) {
 			SecurityGroup: "default",
 		},
 		CidrBlock:       "10.0.0.0/16",
-		NatGatewayPerAZ: false,
+		NatGatewayPerAZ: jsii.Bool(false),
 	}
 	
 	// 测试用例1: 空实例配置，应该返回默认配置
@@ -48,7 +53,7 @@ func TestVpcForge_MergeConfigs(t *testing.T) {
 			SecurityGroup: "custom",
 		},
 		CidrBlock:       "172.16.0.0/16",
-		NatGatewayPerAZ: true,
+		NatGatewayPerAZ: jsii.Bool(true),
 	}
 	
 	merged = forge.MergeConfigs(defaults, instance).(*VpcInstanceConfig)
@@ -64,7 +69,7 @@ func TestVpcForge_MergeConfigs(t *testing.T) {
 	}
 	// 注意：这个测试可能会失败，取决于MergeConfigs的实际实现
 	// 如果MergeConfigs没有正确处理NatGatewayPerAZ字段，请修改此断言或修复MergeConfigs方法
-	if !merged.NatGatewayPerAZ {
+	if !types.GetBoolValue(merged.NatGatewayPerAZ, false) {
 		t.Logf("Note: NatGatewayPerAZ is false, expected true. This may be correct depending on implementation.")
 	}
 	
@@ -135,7 +140,15 @@ func TestVpcForge_ConfigureRules(t *testing.T) {
 	forge := &VpcForge{}
 	
 	// 测试配置规则
-	forge.ConfigureRules(nil, publicSG, privateSG, isolatedSG, false, nil)
+	forge.ConfigureRules(&interfaces.ForgeContext{
+		Stack: stack,
+		VPC:   vpc,
+		SecurityGroups: &interfaces.SecurityGroups{
+			Public:   publicSG,
+			Private:  privateSG,
+			Isolated: isolatedSG,
+		},
+	})
 	
 	// 测试双栈模式
 	// 注意：我们不能在同一个测试中再次创建相同名称的安全组，所以这里跳过双栈测试
@@ -143,26 +156,28 @@ func TestVpcForge_ConfigureRules(t *testing.T) {
 }
 
 func TestVpcForge_Create(t *testing.T) {
-	// 创建一个测试堆栈
+	// 创建一个测试堆栈，引用已有 VPC 时需要指定账号和区域
 	app := awscdk.NewApp(nil)
-	stack := awscdk.NewStack(app, jsii.String("TestStack3"), &awscdk.StackProps{})
+	stack := awscdk.NewStack(app, jsii.String("TestStack3"), &awscdk.StackProps{
+		Env: &awscdk.Environment{Account: jsii.String("123456789012"), Region: jsii.String("us-east-1")},
+	})
 	
 	// 创建一个VPC forge实例
 	forge := &VpcForge{}
 	
-	// 创建一个实例配置
+	// 创建一个实例配置，引用已有 VPC 避免查询可用区
 	instance := &VpcInstanceConfig{
 		BaseInstanceConfig: config.BaseInstanceConfig{
 			ID:   "test-vpc",
 			Type: "vpc",
 		},
-		CidrBlock:       "10.0.0.0/16",
-		NatGatewayPerAZ: false,
+		VpcId:     "vpc-0123456789abcdef0",
+		CidrBlock: "10.0.0.0/16",
 	}
 	
 	// 测试创建VPC
 	var instanceConfig config.InstanceConfig = instance
-	result := forge.Create(stack, &instanceConfig, nil, awsec2.SubnetType_PUBLIC, nil, nil, false)
+	result := forge.Create(&interfaces.ForgeContext{Stack: stack, Instance: &instanceConfig})
 	
 	// 验证结果不为nil
 	if result == nil {
@@ -170,9 +185,12 @@ func TestVpcForge_Create(t *testing.T) {
 	}
 	
 	// 验证结果类型
-	_, ok := result.(awsec2.Vpc)
+	_, ok := result.(*VpcForge)
 	if !ok {
-		t.Errorf("Expected result to be of type awsec2.Vpc, got %T", result)
+		t.Errorf("Expected result to be of type *VpcForge, got %T", result)
+	}
+	if forge.GetVpc() == nil {
+		t.Error("Expected Create to set the VPC")
 	}
 }
 
@@ -199,13 +217,82 @@ func TestVpcForge_CreateOutputs(t *testing.T) {
 			Type: "vpc",
 		},
 		CidrBlock:       "10.0.0.0/16",
-		NatGatewayPerAZ: false,
+		NatGatewayPerAZ: jsii.Bool(false),
 	}
 	
 	// 测试创建输出
 	var instanceConfig config.InstanceConfig = instance
-	forge.CreateOutputs(stack, &instanceConfig)
+	forge.CreateOutputs(&interfaces.ForgeContext{Stack: stack, Instance: &instanceConfig, VPC: vpc})
 	
 	// 注意：由于CDK的合成过程，我们无法直接验证输出
 	// 这个测试主要是确保方法不会抛出异常
 }
+
+func TestVpcForge_PrefixListOutputs(t *testing.T) {
+	security.GlobalRuleRegistry.Reset()
+
+	app := awscdk.NewApp(nil)
+	stack := awscdk.NewStack(app, jsii.String("TestStack5"), &awscdk.StackProps{
+		Env: &awscdk.Environment{Account: jsii.String("123456789012"), Region: jsii.String("us-east-1")},
+	})
+
+	forge := &VpcForge{}
+	var instanceConfig config.InstanceConfig = &VpcInstanceConfig{
+		BaseInstanceConfig: config.BaseInstanceConfig{ID: "test-vpc", Type: "vpc"},
+		VpcId:              "vpc-0123456789abcdef0",
+		PrefixLists: []PrefixListConfig{
+			{Name: "corp", Cidrs: []string{"10.1.0.0/16", "10.2.0.0/16"}},
+			{Name: "partner", Id: "pl-0123456789abcdef0"},
+		},
+	}
+	ctx := &interfaces.ForgeContext{Stack: stack, Instance: &instanceConfig}
+	forge.Create(ctx)
+	// 前缀列表资源和输出不能使用相同的构造 ID
+	forge.CreateOutputs(ctx)
+
+	template, err := json.Marshal(app.Synth(nil).GetStackByName(jsii.String("TestStack5")).Template())
+	if err != nil {
+		t.Fatalf("Failed to marshal template: %v", err)
+	}
+	var parsed struct {
+		Resources map[string]struct {
+			Type string
+		}
+		Outputs map[string]interface{}
+	}
+	if err := json.Unmarshal(template, &parsed); err != nil {
+		t.Fatalf("Failed to parse template: %v", err)
+	}
+
+	prefixLists := 0
+	for _, resource := range parsed.Resources {
+		if resource.Type == "AWS::EC2::PrefixList" {
+			prefixLists++
+		}
+	}
+	if prefixLists != 1 {
+		t.Errorf("Expected 1 prefix list resource, got %d", prefixLists)
+	}
+	for _, name := range []string{"corp", "partner"} {
+		found := false
+		for id := range parsed.Outputs {
+			if strings.HasPrefix(id, "PrefixListId"+name) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected an output for prefix list %s, got %v", name, parsed.Outputs)
+		}
+	}
+
+	// 前缀列表注册后可被 "pl:name" 引用
+	if id, err := security.ResolvePrefixList("pl:partner"); err != nil || id != "pl-0123456789abcdef0" {
+		t.Errorf("Expected pl:partner to resolve, got %q, %v", id, err)
+	}
+	if err := security.ValidatePortPrefixLists("22@pl:corp", ""); err != nil {
+		t.Errorf("Expected pl:corp to be valid, got %v", err)
+	}
+	if err := security.ValidatePortPrefixLists("22@pl:unknown", ""); err == nil {
+		t.Errorf("Expected an error for an undefined prefix list")
+	}
+}
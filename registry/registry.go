@@ -13,6 +13,9 @@ import (
 	"github.com/awslabs/InfraForge/core/partition"
 	"github.com/awslabs/InfraForge/forges/aws/storage/efs"
 	"github.com/awslabs/InfraForge/forges/aws/storage/lustre"
+	"github.com/awslabs/InfraForge/forges/aws/storage/openzfs"
+	"github.com/awslabs/InfraForge/forges/aws/storage/ontap"
+	"github.com/awslabs/InfraForge/forges/aws/storage/s3"
 	"github.com/awslabs/InfraForge/forges/aws/ec2"
 	"github.com/awslabs/InfraForge/forges/aws/ecs"
 	"github.com/awslabs/InfraForge/forges/aws/eks"
@@ -81,6 +84,15 @@ func init() {
         RegisterInstanceCreator("lustre", func() config.InstanceConfig {
                 return &lustre.LustreInstanceConfig{}
         })
+        RegisterInstanceCreator("openzfs", func() config.InstanceConfig {
+                return &openzfs.OpenZfsInstanceConfig{}
+        })
+        RegisterInstanceCreator("ontap", func() config.InstanceConfig {
+                return &ontap.OntapInstanceConfig{}
+        })
+        RegisterInstanceCreator("s3", func() config.InstanceConfig {
+                return &s3.S3InstanceConfig{}
+        })
         RegisterInstanceCreator("ec2", func() config.InstanceConfig {
                 return &ec2.Ec2InstanceConfig{}
         })
@@ -110,6 +122,9 @@ func init() {
         RegisterForge("vpc", func() interfaces.Forge { return &vpc.VpcForge{} })
         RegisterForge("efs", func() interfaces.Forge { return &efs.EfsForge{} })
         RegisterForge("lustre", func() interfaces.Forge { return &lustre.LustreForge{} })
+        RegisterForge("openzfs", func() interfaces.Forge { return &openzfs.OpenZfsForge{} })
+        RegisterForge("ontap", func() interfaces.Forge { return &ontap.OntapForge{} })
+        RegisterForge("s3", func() interfaces.Forge { return &s3.S3Forge{} })
         RegisterForge("ec2", func() interfaces.Forge { return &ec2.Ec2Forge{} })
         RegisterForge("ecs", func() interfaces.Forge { return &ecs.EcsForge{} })
         RegisterForge("eks", func() interfaces.Forge { return &eks.EksForge{} })